@@ -8,9 +8,21 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig   `json:"server"`
-	Database DatabaseConfig `json:"database"`
-	Sentry   SentryConfig   `json:"sentry"`
+	Server      ServerConfig      `json:"server"`
+	Database    DatabaseConfig    `json:"database"`
+	Sentry      SentryConfig      `json:"sentry"`
+	WebSocket   WebSocketConfig   `json:"websocket"`
+	Retention   RetentionConfig   `json:"retention"`
+	Archive     ArchiveConfig     `json:"archive"`
+	Ingest      IngestConfig      `json:"ingest"`
+	Syslog      SyslogConfig      `json:"syslog"`
+	Idempotency IdempotencyConfig `json:"idempotency"`
+	Redaction   RedactionConfig   `json:"redaction"`
+	LogPolicy   LogPolicyConfig   `json:"log_policy"`
+	Health      HealthConfig      `json:"health"`
+	Teams       TeamsConfig       `json:"teams"`
+	Deployments DeploymentsConfig `json:"deployments"`
+	Hosts       HostsConfig       `json:"hosts"`
 }
 
 type SentryConfig struct {
@@ -19,6 +31,156 @@ type SentryConfig struct {
 	Release     string
 }
 
+type WebSocketConfig struct {
+	SendBufferSize   int      `json:"send_buffer_size"`
+	MaxSubscriptions int      `json:"max_subscriptions"`
+	MaxMessageBytes  int64    `json:"max_message_bytes"`
+	WriteTimeoutSec  int      `json:"write_timeout_sec"`
+	PongTimeoutSec   int      `json:"pong_timeout_sec"`
+	AllowedOrigins   []string `json:"allowed_origins"`
+}
+
+// RetentionConfig управляет секциями таблицы logs и сроком хранения логов.
+// DaysByLevel — срок хранения в днях для каждого уровня; 0 или отсутствие уровня — хранить бессрочно.
+type RetentionConfig struct {
+	PartitionsAhead int            `json:"partitions_ahead"`
+	IntervalMin     int            `json:"interval_min"`
+	DeleteBatchSize int            `json:"delete_batch_size"`
+	DaysByLevel     map[string]int `json:"days_by_level"`
+}
+
+// ArchiveConfig управляет выгрузкой логов в холодный архив перед их удалением.
+// Storage: "local" (каталог LocalDir) или "s3" (S3-совместимое хранилище).
+type ArchiveConfig struct {
+	Enabled  bool            `json:"enabled"`
+	Storage  string          `json:"storage"`
+	LocalDir string          `json:"local_dir"`
+	S3       ArchiveS3Config `json:"s3"`
+}
+
+type ArchiveS3Config struct {
+	Endpoint  string `json:"endpoint"`
+	Region    string `json:"region"`
+	Bucket    string `json:"bucket"`
+	Prefix    string `json:"prefix"`
+	UseSSL    bool   `json:"use_ssl"`
+	AccessKey string
+	SecretKey string
+}
+
+// IngestConfig — параметры приёма логов.
+// MaxBodyBytes ограничивает размер тела запроса после распаковки.
+// MaxPastSkewSec и MaxFutureSkewSec — насколько переданное клиентом время лога может отличаться от времени приёма.
+type IngestConfig struct {
+	MaxBodyBytes     int64             `json:"max_body_bytes"`
+	MaxPastSkewSec   int64             `json:"max_past_skew_sec"`
+	MaxFutureSkewSec int64             `json:"max_future_skew_sec"`
+	Async            IngestAsyncConfig `json:"async"`
+}
+
+// IngestAsyncConfig — асинхронная запись логов: запрос ставит лог в очередь и сразу получает 202,
+// фоновые воркеры сохраняют очередь пачками через COPY.
+// Overflow — поведение при заполненной очереди: "block" (ждать места), "drop_debug" (отбрасывать Debug, остальные ждут)
+// или "spill" (дописывать в журнал на диске в WALDir, который сохраняется в БД при следующей возможности и при перезапуске).
+type IngestAsyncConfig struct {
+	Enabled         bool   `json:"enabled"`
+	QueueSize       int    `json:"queue_size"`
+	Workers         int    `json:"workers"`
+	BatchSize       int    `json:"batch_size"`
+	FlushIntervalMs int    `json:"flush_interval_ms"`
+	Overflow        string `json:"overflow"`
+	WALDir          string `json:"wal_dir"`
+	DrainTimeoutSec int    `json:"drain_timeout_sec"`
+}
+
+// SyslogConfig — приёмник syslog (RFC 5424/3164). Пустой адрес отключает соответствующий транспорт.
+// Mappings сопоставляют hostname и/или app-name сообщения с кодом бота; побеждает первое совпадение.
+type SyslogConfig struct {
+	Enabled         bool            `json:"enabled"`
+	UDPAddr         string          `json:"udp_addr"`
+	TCPAddr         string          `json:"tcp_addr"`
+	TLSAddr         string          `json:"tls_addr"`
+	TLSCertFile     string          `json:"tls_cert_file"`
+	TLSKeyFile      string          `json:"tls_key_file"`
+	MaxMessageBytes int             `json:"max_message_bytes"`
+	QueueSize       int             `json:"queue_size"`
+	BatchSize       int             `json:"batch_size"`
+	FlushIntervalMs int             `json:"flush_interval_ms"`
+	DefaultBotCode  string          `json:"default_bot_code"`
+	Mappings        []SyslogMapping `json:"mappings"`
+}
+
+type SyslogMapping struct {
+	Hostname string `json:"hostname"`
+	AppName  string `json:"app_name"`
+	BotCode  string `json:"bot_code"`
+}
+
+// IdempotencyConfig — ключи идемпотентности запросов на создание.
+// TTLHours — сколько часов повтор с тем же ключом возвращает исходную запись; CleanupIntervalMin — период удаления истёкших ключей.
+type IdempotencyConfig struct {
+	TTLHours           int `json:"ttl_hours"`
+	CleanupIntervalMin int `json:"cleanup_interval_min"`
+}
+
+// RedactionConfig — маскирование персональных данных в логах при приёме.
+// Detectors — включённые встроенные детекторы (email, phone, card, passport); пустой список — все.
+// Правила администраторов перечитываются из БД раз в RefreshIntervalSec, счётчики замен сохраняются раз в StatsFlushIntervalSec.
+type RedactionConfig struct {
+	Enabled               bool     `json:"enabled"`
+	Detectors             []string `json:"detectors"`
+	RefreshIntervalSec    int      `json:"refresh_interval_sec"`
+	StatsFlushIntervalSec int      `json:"stats_flush_interval_sec"`
+}
+
+// LogPolicyConfig — применение политик хранения логов ботов (минимальный уровень, семплирование).
+// Политики перечитываются из БД раз в RefreshIntervalSec, счётчики отброшенных логов сохраняются раз в StatsFlushIntervalSec.
+type LogPolicyConfig struct {
+	RefreshIntervalSec    int `json:"refresh_interval_sec"`
+	StatsFlushIntervalSec int `json:"stats_flush_interval_sec"`
+}
+
+// HealthConfig — пороги состояния ботов. Бот silent, если сигналов (логов или запусков) нет дольше SilentAfterMinutes;
+// failing — при FailingErrorLogs логах Error/Critical за WindowMinutes или FailingRuns запусках с ошибкой среди RecentRuns последних;
+// degraded — при DegradedErrorLogs логах Error/Critical или хотя бы одном запуске warning/error среди последних.
+// Состояния пересчитываются раз в RefreshIntervalSec.
+type HealthConfig struct {
+	RefreshIntervalSec int `json:"refresh_interval_sec"`
+	WindowMinutes      int `json:"window_minutes"`
+	RecentRuns         int `json:"recent_runs"`
+	SilentAfterMinutes int `json:"silent_after_minutes"`
+	DegradedErrorLogs  int `json:"degraded_error_logs"`
+	FailingErrorLogs   int `json:"failing_error_logs"`
+	FailingRuns        int `json:"failing_runs"`
+}
+
+// TeamsConfig — отправка уведомлений команд о смене состояния их ботов: запрос на webhook команды
+// прерывается через WebhookTimeoutSec.
+type TeamsConfig struct {
+	WebhookTimeoutSec int `json:"webhook_timeout_sec"`
+}
+
+// DeploymentsConfig — версии ботов, которые проставляются новым логам и запускам по времени события.
+// История версий за ingest.max_past_skew_sec перечитывается из БД раз в RefreshIntervalSec
+// (для выкладок, записанных другими экземплярами сервиса).
+type DeploymentsConfig struct {
+	RefreshIntervalSec int `json:"refresh_interval_sec"`
+}
+
+// HostsConfig — реестр хостов из eff_runs.host: появления хостов сохраняются раз в FlushIntervalSec,
+// молчание хостов проверяется раз в CheckIntervalSec. Хост с не менее чем MinRuns запусков считается замолчавшим,
+// если от него нет запусков дольше SilentFactor его обычных интервалов, но не меньше SilentMinMinutes минут.
+// О смене состояния сообщается POST-запросом на WebhookURL (пусто — только в лог), запрос прерывается через WebhookTimeoutSec.
+type HostsConfig struct {
+	FlushIntervalSec  int     `json:"flush_interval_sec"`
+	CheckIntervalSec  int     `json:"check_interval_sec"`
+	MinRuns           int64   `json:"min_runs"`
+	SilentFactor      float64 `json:"silent_factor"`
+	SilentMinMinutes  int     `json:"silent_min_minutes"`
+	WebhookURL        string  `json:"webhook_url"`
+	WebhookTimeoutSec int     `json:"webhook_timeout_sec"`
+}
+
 type ServerConfig struct {
 	Port int    `json:"port"`
 	Host string `json:"host"`
@@ -54,5 +216,151 @@ func LoadConfig() (*Config, error) {
 	}
 	config.Sentry.Release = os.Getenv("SENTRY_RELEASE")
 
+	if config.WebSocket.SendBufferSize <= 0 {
+		config.WebSocket.SendBufferSize = 256
+	}
+	if config.WebSocket.MaxSubscriptions <= 0 {
+		config.WebSocket.MaxSubscriptions = 20
+	}
+	if config.WebSocket.MaxMessageBytes <= 0 {
+		config.WebSocket.MaxMessageBytes = 4096
+	}
+	if config.WebSocket.WriteTimeoutSec <= 0 {
+		config.WebSocket.WriteTimeoutSec = 10
+	}
+	if config.WebSocket.PongTimeoutSec <= 0 {
+		config.WebSocket.PongTimeoutSec = 60
+	}
+
+	if config.Retention.PartitionsAhead <= 0 {
+		config.Retention.PartitionsAhead = 3
+	}
+	if config.Retention.IntervalMin <= 0 {
+		config.Retention.IntervalMin = 60
+	}
+	if config.Retention.DeleteBatchSize <= 0 {
+		config.Retention.DeleteBatchSize = 10000
+	}
+
+	if config.Archive.LocalDir == "" {
+		config.Archive.LocalDir = "archive"
+	}
+	config.Archive.S3.AccessKey = os.Getenv("ARCHIVE_S3_ACCESS_KEY")
+	config.Archive.S3.SecretKey = os.Getenv("ARCHIVE_S3_SECRET_KEY")
+
+	if config.Ingest.MaxBodyBytes <= 0 {
+		config.Ingest.MaxBodyBytes = 10 << 20
+	}
+	if config.Ingest.MaxPastSkewSec <= 0 {
+		config.Ingest.MaxPastSkewSec = 7 * 24 * 60 * 60
+	}
+	if config.Ingest.MaxFutureSkewSec <= 0 {
+		config.Ingest.MaxFutureSkewSec = 5 * 60
+	}
+	if config.Ingest.Async.QueueSize <= 0 {
+		config.Ingest.Async.QueueSize = 50000
+	}
+	if config.Ingest.Async.Workers <= 0 {
+		config.Ingest.Async.Workers = 2
+	}
+	if config.Ingest.Async.BatchSize <= 0 {
+		config.Ingest.Async.BatchSize = 1000
+	}
+	if config.Ingest.Async.FlushIntervalMs <= 0 {
+		config.Ingest.Async.FlushIntervalMs = 500
+	}
+	if config.Ingest.Async.Overflow == "" {
+		config.Ingest.Async.Overflow = "block"
+	}
+	if config.Ingest.Async.WALDir == "" {
+		config.Ingest.Async.WALDir = "wal"
+	}
+	if config.Ingest.Async.DrainTimeoutSec <= 0 {
+		config.Ingest.Async.DrainTimeoutSec = 30
+	}
+
+	if config.Syslog.MaxMessageBytes <= 0 {
+		config.Syslog.MaxMessageBytes = 64 << 10
+	}
+	if config.Syslog.QueueSize <= 0 {
+		config.Syslog.QueueSize = 10000
+	}
+	if config.Syslog.BatchSize <= 0 {
+		config.Syslog.BatchSize = 500
+	}
+	if config.Syslog.FlushIntervalMs <= 0 {
+		config.Syslog.FlushIntervalMs = 1000
+	}
+
+	if config.Idempotency.TTLHours <= 0 {
+		config.Idempotency.TTLHours = 24
+	}
+	if config.Idempotency.CleanupIntervalMin <= 0 {
+		config.Idempotency.CleanupIntervalMin = 60
+	}
+
+	if config.Redaction.RefreshIntervalSec <= 0 {
+		config.Redaction.RefreshIntervalSec = 60
+	}
+	if config.Redaction.StatsFlushIntervalSec <= 0 {
+		config.Redaction.StatsFlushIntervalSec = 60
+	}
+
+	if config.LogPolicy.RefreshIntervalSec <= 0 {
+		config.LogPolicy.RefreshIntervalSec = 30
+	}
+	if config.LogPolicy.StatsFlushIntervalSec <= 0 {
+		config.LogPolicy.StatsFlushIntervalSec = 60
+	}
+
+	if config.Health.RefreshIntervalSec <= 0 {
+		config.Health.RefreshIntervalSec = 60
+	}
+	if config.Health.WindowMinutes <= 0 {
+		config.Health.WindowMinutes = 60
+	}
+	if config.Health.RecentRuns <= 0 {
+		config.Health.RecentRuns = 5
+	}
+	if config.Health.SilentAfterMinutes <= 0 {
+		config.Health.SilentAfterMinutes = 1440
+	}
+	if config.Health.DegradedErrorLogs <= 0 {
+		config.Health.DegradedErrorLogs = 10
+	}
+	if config.Health.FailingErrorLogs <= 0 {
+		config.Health.FailingErrorLogs = 100
+	}
+	if config.Health.FailingRuns <= 0 {
+		config.Health.FailingRuns = 3
+	}
+
+	if config.Teams.WebhookTimeoutSec <= 0 {
+		config.Teams.WebhookTimeoutSec = 10
+	}
+
+	if config.Deployments.RefreshIntervalSec <= 0 {
+		config.Deployments.RefreshIntervalSec = 30
+	}
+
+	if config.Hosts.FlushIntervalSec <= 0 {
+		config.Hosts.FlushIntervalSec = 10
+	}
+	if config.Hosts.CheckIntervalSec <= 0 {
+		config.Hosts.CheckIntervalSec = 60
+	}
+	if config.Hosts.MinRuns <= 0 {
+		config.Hosts.MinRuns = 10
+	}
+	if config.Hosts.SilentFactor <= 0 {
+		config.Hosts.SilentFactor = 3
+	}
+	if config.Hosts.SilentMinMinutes <= 0 {
+		config.Hosts.SilentMinMinutes = 30
+	}
+	if config.Hosts.WebhookTimeoutSec <= 0 {
+		config.Hosts.WebhookTimeoutSec = 10
+	}
+
 	return &config, nil
 }
@@ -15,37 +15,31 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/v1/auth/me": {
+        "/es": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Возвращает информацию о токене из заголовка Authorization",
+                "description": "Ответ в формате GET / Elasticsearch, нужен агентам (Filebeat, Fluent Bit) для проверки версии при подключении",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "elasticsearch"
                 ],
-                "summary": "Получить информацию о токене",
+                "summary": "Информация о кластере (совместимость с Elasticsearch)",
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.Token"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -54,29 +48,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/bots": {
-            "get": {
+        "/es/_bulk": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Возвращает список всех ботов (требуется админский токен)",
+                "description": "NDJSON тело _bulk (можно gzip). Поддерживаются действия index и create; message (или log) — текст лога,\nlog.level — уровень, @timestamp — время события (created_at), остальные поля документа сохраняются в attributes.\nОтвет — по элементу на каждое действие, как в Elasticsearch; _id есть только у уже сохранённых логов\n(при асинхронной записи его нет), логи, отброшенные политикой хранения, возвращаются с result noop.\nЛоги привязываются к боту токена; для админского токена бот определяется по полю service.name (код бота).",
+                "consumes": [
+                    "application/x-ndjson"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "bots"
+                    "elasticsearch"
                 ],
-                "summary": "Получить всех ботов",
+                "summary": "Приём логов в формате Elasticsearch _bulk",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Bot"
-                            }
+                            "$ref": "#/definitions/es_handler.BulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/es_handler.ErrorResponse"
                         }
                     },
                     "401": {
@@ -86,62 +86,58 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "413": {
+                        "description": "Request Entity Too Large",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/es_handler.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/es_handler.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/es/{index}/_bulk": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Создаёт нового бота/робота (требуется админский токен)",
+                "description": "NDJSON тело _bulk (можно gzip). Поддерживаются действия index и create; message (или log) — текст лога,\nlog.level — уровень, @timestamp — время события (created_at), остальные поля документа сохраняются в attributes.\nОтвет — по элементу на каждое действие, как в Elasticsearch; _id есть только у уже сохранённых логов\n(при асинхронной записи его нет), логи, отброшенные политикой хранения, возвращаются с result noop.\nЛоги привязываются к боту токена; для админского токена бот определяется по полю service.name (код бота).",
                 "consumes": [
-                    "application/json"
+                    "application/x-ndjson"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "bots"
+                    "elasticsearch"
                 ],
-                "summary": "Создать бота",
+                "summary": "Приём логов в формате Elasticsearch _bulk",
                 "parameters": [
                     {
-                        "description": "Данные бота",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/bot_handler.CreateBotRequest"
-                        }
+                        "type": "string",
+                        "description": "Индекс по умолчанию для действий без _index",
+                        "name": "index",
+                        "in": "path"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Bot"
+                            "$ref": "#/definitions/es_handler.BulkResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/es_handler.ErrorResponse"
                         }
                     },
                     "401": {
@@ -151,52 +147,46 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "413": {
+                        "description": "Request Entity Too Large",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/es_handler.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/es_handler.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/v1/bots/{bot_id}": {
-            "get": {
+        "/loki/api/v1/push": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Возвращает информацию о боте по ID (требуется админский токен)",
-                "produces": [
+                "description": "Совместим с POST /loki/api/v1/push: snappy-сжатый protobuf (application/x-protobuf) или JSON (application/json, можно gzip).\nУровень берётся из метки level, метки потока и structured metadata сохраняются в attributes, время записи становится created_at.\nЗаписи со временем вне допустимого отклонения отклоняются ответом 400, остальные сохраняются.\nЛоги привязываются к боту токена; для админского токена бот определяется по метке bot_code.",
+                "consumes": [
+                    "application/x-protobuf",
                     "application/json"
                 ],
                 "tags": [
-                    "bots"
-                ],
-                "summary": "Получить бота",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID бота (UUID)",
-                        "name": "bot_id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "loki"
                 ],
+                "summary": "Приём логов в формате Loki",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "Логи приняты"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.Bot"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "401": {
@@ -206,15 +196,15 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "413": {
+                        "description": "Request Entity Too Large",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "415": {
+                        "description": "Unsupported Media Type",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -228,14 +218,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/v1/admin/archives/import": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Обновляет данные бота (требуется админский токен)",
+                "description": "Загружает архивные логи за диапазон дат (UTC) во временную таблицу archive_import_* для расследования (требуется админский токен)",
                 "consumes": [
                     "application/json"
                 ],
@@ -243,32 +235,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "bots"
+                    "admin"
                 ],
-                "summary": "Обновить бота",
+                "summary": "Загрузить архив во временную таблицу",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID бота (UUID)",
-                        "name": "bot_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Обновлённые данные",
+                        "description": "Диапазон и фильтры",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/bot_handler.UpdateBotRequest"
+                            "$ref": "#/definitions/archive_handler.ImportArchiveRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Bot"
+                            "$ref": "#/definitions/models.ArchiveImport"
                         }
                     },
                     "400": {
@@ -292,13 +277,6 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -307,26 +285,28 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/admin/archives/imports/{table}": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Полностью удаляет бота из базы данных (требуется админский токен)",
+                "description": "Удаляет таблицу archive_import_*, созданную при загрузке архива (требуется админский токен)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "bots"
+                    "admin"
                 ],
-                "summary": "Удалить бота",
+                "summary": "Удалить временную таблицу импорта",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID бота (UUID)",
-                        "name": "bot_id",
+                        "description": "Имя таблицы импорта",
+                        "name": "table",
                         "in": "path",
                         "required": true
                     }
@@ -353,6 +333,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -363,40 +350,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/eff-runs": {
-            "post": {
+        "/v1/admin/archives/manifests": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Создаёт новую запись о запуске бота (требуется авторизация, только для обычных токенов с bot_id)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает манифесты архивных файлов (по боту и дню) за диапазон дат в UTC (требуется админский токен)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "eff_runs"
+                    "admin"
                 ],
-                "summary": "Создать запись о запуске",
+                "summary": "Манифесты архива",
                 "parameters": [
                     {
-                        "description": "Данные о запуске",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/eff_run_handler.CreateEffRunRequest"
-                        }
+                        "type": "string",
+                        "description": "Начало диапазона (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец диапазона включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.EffRun"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ArchiveManifest"
+                            }
                         }
                     },
                     "400": {
@@ -430,40 +428,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/logs": {
-            "post": {
+        "/v1/admin/log-drops": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Создаёт новый лог от имени текущего бота (требуется авторизация)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает число логов, отброшенных политиками хранения, по ботам, уровням и причинам за период\n(level — ниже минимального уровня, sampled — не попал в выборку; требуется админский токен).\nСчётчики сохраняются периодически (log_policy.stats_flush_interval_sec), последние могут появиться с задержкой.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "logs"
+                    "log-policies"
                 ],
-                "summary": "Создать лог",
+                "summary": "Отброшенные логи",
                 "parameters": [
                     {
-                        "description": "Данные лога",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/log_handler.CreateLogRequest"
-                        }
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Первый день периода (YYYY-MM-DD, UTC)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Последний день периода (YYYY-MM-DD, UTC)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Log"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.LogDropCount"
+                            }
                         }
                     },
                     "400": {
@@ -480,6 +487,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -490,29 +504,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/owners": {
+        "/v1/admin/partitions": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Возвращает список всех владельцев (требуется админский токен)",
+                "description": "Возвращает месячные секции таблицы logs с размерами и текущую политику хранения по уровням (требуется админский токен)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "owners"
+                    "admin"
                 ],
-                "summary": "Получить всех владельцев",
+                "summary": "Секции таблицы логов",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Owner"
-                            }
+                            "$ref": "#/definitions/partition_handler.PartitionsResponse"
                         }
                     },
                     "401": {
@@ -537,47 +548,28 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/v1/auth/me": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Создаёт нового владельца (требуется админский токен)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает информацию о токене из заголовка Authorization",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "owners"
-                ],
-                "summary": "Создать владельца",
-                "parameters": [
-                    {
-                        "description": "Данные владельца",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/owner_handler.CreateOwnerRequest"
-                        }
-                    }
+                    "auth"
                 ],
+                "summary": "Получить информацию о токене",
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/models.Owner"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Token"
                         }
                     },
                     "401": {
@@ -587,13 +579,6 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -604,35 +589,94 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/owners/{owner_id}": {
+        "/v1/bot-languages": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Возвращает информацию о владельце по ID (требуется админский токен)",
+                "description": "Возвращает справочник языков ботов с числом ботов, использующих каждое значение (требуется админский токен)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "owners"
+                    "bot-languages"
                 ],
-                "summary": "Получить владельца",
+                "summary": "Получить языки ботов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BotReference"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Добавляет значение в справочник языков ботов (требуется админский токен). Код уникален без учёта регистра.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bot-languages"
+                ],
+                "summary": "Добавить язык бота",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID владельца (UUID)",
-                        "name": "owner_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Код и описание",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bot_reference_handler.CreateBotReferenceRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Owner"
+                            "$ref": "#/definitions/models.BotReference"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "401": {
@@ -649,8 +693,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -664,14 +708,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/bot-languages/{code}": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Обновляет данные владельца (требуется админский токен)",
+                "description": "Меняет код и описание значения справочника языков ботов (требуется админский токен).\nНовый код переносится на всех ботов с прежним кодом.",
                 "consumes": [
                     "application/json"
                 ],
@@ -679,24 +725,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "owners"
+                    "bot-languages"
                 ],
-                "summary": "Обновить владельца",
+                "summary": "Изменить язык бота",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID владельца (UUID)",
-                        "name": "owner_id",
+                        "description": "Язык бота",
+                        "name": "code",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Обновлённые данные",
+                        "description": "Новые код и описание",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/owner_handler.UpdateOwnerRequest"
+                            "$ref": "#/definitions/bot_reference_handler.UpdateBotReferenceRequest"
                         }
                     }
                 ],
@@ -704,7 +750,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Owner"
+                            "$ref": "#/definitions/models.BotReference"
                         }
                     },
                     "400": {
@@ -735,6 +781,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -750,19 +803,19 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Полностью удаляет владельца из базы данных (требуется админский токен)",
+                "description": "Удаляет значение из справочника языков ботов (требуется админский токен).\nЗначение, которое используют боты (в том числе в архиве), удалить нельзя.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "owners"
+                    "bot-languages"
                 ],
-                "summary": "Удалить владельца",
+                "summary": "Удалить язык бота",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID владельца (UUID)",
-                        "name": "owner_id",
+                        "description": "Язык бота",
+                        "name": "code",
                         "in": "path",
                         "required": true
                     }
@@ -789,6 +842,20 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -799,14 +866,61 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/tokens": {
+        "/v1/bot-types": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает справочник типов ботов с числом ботов, использующих каждое значение (требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bot-types"
+                ],
+                "summary": "Получить типы ботов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BotReference"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Создаёт новый токен аутентификации для указанного бота (требуется админский токен)",
+                "description": "Добавляет значение в справочник типов ботов (требуется админский токен). Код уникален без учёта регистра.",
                 "consumes": [
                     "application/json"
                 ],
@@ -814,17 +928,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tokens"
+                    "bot-types"
                 ],
-                "summary": "Создать новый токен",
+                "summary": "Добавить тип бота",
                 "parameters": [
                     {
-                        "description": "Данные для создания токена",
+                        "description": "Код и описание",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth_handler.CreateTokenRequest"
+                            "$ref": "#/definitions/bot_reference_handler.CreateBotReferenceRequest"
                         }
                     }
                 ],
@@ -832,7 +946,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/auth_handler.TokenResponse"
+                            "$ref": "#/definitions/models.BotReference"
                         }
                     },
                     "400": {
@@ -856,8 +970,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -873,14 +987,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/tokens/{token_id}": {
+        "/v1/bot-types/{code}": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Обновляет название существующего токена (требуется админский токен)",
+                "description": "Меняет код и описание значения справочника типов ботов (требуется админский токен).\nНовый код переносится на всех ботов с прежним кодом.",
                 "consumes": [
                     "application/json"
                 ],
@@ -888,24 +1002,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tokens"
+                    "bot-types"
                 ],
-                "summary": "Обновить токен",
+                "summary": "Изменить тип бота",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID токена (UUID)",
-                        "name": "token_id",
+                        "description": "Тип бота",
+                        "name": "code",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Новое название токена",
+                        "description": "Новые код и описание",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth_handler.UpdateTokenRequest"
+                            "$ref": "#/definitions/bot_reference_handler.UpdateBotReferenceRequest"
                         }
                     }
                 ],
@@ -913,8 +1027,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.BotReference"
                         }
                     },
                     "400": {
@@ -938,6 +1051,20 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -953,19 +1080,19 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Полностью удаляет токен из базы данных (требуется админский токен)",
+                "description": "Удаляет значение из справочника типов ботов (требуется админский токен).\nЗначение, которое используют боты (в том числе в архиве), удалить нельзя.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tokens"
+                    "bot-types"
                 ],
-                "summary": "Удалить токен",
+                "summary": "Удалить тип бота",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID токена (UUID)",
-                        "name": "token_id",
+                        "description": "Тип бота",
+                        "name": "code",
                         "in": "path",
                         "required": true
                     }
@@ -992,6 +1119,20 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1002,33 +1143,133 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/tokens/{token_id}/deactivate": {
-            "patch": {
+        "/v1/bots": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Деактивирует токен (мягкое удаление, токен перестаёт работать, требуется админский токен)",
+                "description": "Возвращает ботов с фильтрами и постраничной выдачей (требуется админский токен).\ntotal — число ботов, подходящих под фильтр. По умолчанию новые первыми; code и name сортируются по возрастанию.\nБез limit и cursor ответ — массив всех подходящих ботов (прежний формат, для совместимости со старыми клиентами).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tokens"
+                    "bots"
                 ],
-                "summary": "Деактивировать токен",
+                "summary": "Получить ботов",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID токена (UUID)",
-                        "name": "token_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Тип бота из справочника /v1/bot-types",
+                        "name": "bot_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык из справочника /v1/bot-languages",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID владельца (UUID)",
+                        "name": "owner_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID команды (UUID)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Активность",
+                        "name": "is_active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Теги",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "any — хотя бы один из тегов (по умолчанию), all — все теги",
+                        "name": "tags_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока в code, name или description (без учёта регистра)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exclude",
+                            "include",
+                            "only"
+                        ],
+                        "type": "string",
+                        "description": "Боты в архиве: exclude — скрыть (по умолчанию), include — показать вместе с остальными, only — только они",
+                        "name": "archived",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "updated_at",
+                            "code",
+                            "name"
+                        ],
+                        "type": "string",
+                        "description": "Поле сортировки (по умолчанию created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Направление сортировки",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 100, максимум 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor (с теми же sort и order)",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BotPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1056,323 +1297,7046 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "auth_handler.CreateTokenRequest": {
-            "type": "object",
-            "required": [
-                "token_name"
-            ],
-            "properties": {
-                "bot_id": {
-                    "type": "string",
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт нового бота/робота (требуется админский токен).\nТип и язык должны быть в справочниках /v1/bot-types и /v1/bot-languages.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Создать бота",
+                "parameters": [
+                    {
+                        "description": "Данные бота",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bot_handler.CreateBotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Bot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/bots/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выгружает владельцев и ботов не в архиве (теги, активность, владелец, политика хранения логов) декларативным\nдокументом YAML или JSON — для хранения в git и загрузки через POST /v1/bots/import (требуется админский токен)",
+                "produces": [
+                    "application/yaml",
+                    "application/json"
+                ],
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Выгрузить каталог ботов",
+                "parameters": [
+                    {
+                        "enum": [
+                            "yaml",
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "Формат (по умолчанию yaml)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Catalog"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/bots/health": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сводка состояния ботов по последним логам и запускам (требуется админский токен).\nfailing — много логов Error/Critical за окно или несколько последних запусков с ошибкой;\ndegraded — ошибки в логах или запуски с warning/error среди последних; silent — давно нет логов и запусков;\nunknown — логов и запусков не было. Пороги задаются в секции health конфигурации.\nСначала идут боты, требующие внимания. counts — число ботов по состояниям (без учёта фильтра state).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Состояние ботов",
+                "parameters": [
+                    {
+                        "enum": [
+                            "healthy",
+                            "degraded",
+                            "failing",
+                            "silent",
+                            "unknown"
+                        ],
+                        "type": "string",
+                        "description": "Только боты в этом состоянии",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Только боты команды (UUID)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Включать неактивных ботов (is_active = false)",
+                        "name": "include_inactive",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FleetHealth"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/bots/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Приводит владельцев и ботов к каталогу в формате GET /v1/bots/export (требуется админский токен).\nТело — YAML (Content-Type: application/yaml) или JSON. Владельцы сопоставляются по full_name, боты — по code:\nнедостающие создаются, отличающиеся обновляются (смена владельца записывается в историю передачи).\nС prune=true боты не из файла переносятся в архив. Все изменения применяются одной транзакцией;\nс dry_run=true только возвращается список изменений.",
+                "consumes": [
+                    "application/yaml",
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Загрузить каталог ботов",
+                "parameters": [
+                    {
+                        "description": "Каталог",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Catalog"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только показать изменения",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Перенести в архив ботов, которых нет в файле",
+                        "name": "prune",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CatalogImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/bots/{bot_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает информацию о боте по ID (требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Получить бота",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Bot"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновляет данные бота (требуется админский токен). Смена owner_id — это передача бота: она записывается в историю владельцев.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Обновить бота",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Обновлённые данные",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bot_handler.UpdateBotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Bot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Переносит бота в архив (требуется админский токен): бот скрывается из списков, его токены перестают\nприниматься, а логи и запуски сохраняются вместе с привязкой к боту. Повторный вызов ничего не меняет.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Перенести бота в архив",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Bot"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/bots/{bot_id}/log-policy": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает минимальный уровень, семплирование и временный уровень бота (требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "log-policies"
+                ],
+                "summary": "Получить политику хранения логов бота",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LogPolicy"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Задаёт минимальный сохраняемый уровень и долю сохраняемых логов по уровням (требуется админский токен).\nНапример, {\"min_level\": \"Info\", \"sample_rates\": {\"Info\": 0.01}} — Debug не сохраняется, из Info сохраняется 1%.\nУровень без значения в sample_rates сохраняется полностью. Временный уровень не меняется.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "log-policies"
+                ],
+                "summary": "Задать политику хранения логов бота",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Политика",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/log_policy_handler.SetLogPolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LogPolicy"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет политику: логи бота снова сохраняются полностью (требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "log-policies"
+                ],
+                "summary": "Удалить политику хранения логов бота",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/bots/{bot_id}/log-policy/override": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "На duration_minutes минут (до суток) заменяет минимальный уровень бота на level; логи с этого уровня\nсохраняются без семплирования. Например, {\"level\": \"Debug\", \"duration_minutes\": 120} — сохранять всё\nследующие 2 часа на время разбора проблемы (требуется админский токен).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "log-policies"
+                ],
+                "summary": "Временно снизить уровень логов бота",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Временный уровень",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/log_policy_handler.SetOverrideRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LogPolicy"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Досрочно возвращает боту минимальный уровень и семплирование из политики (требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "log-policies"
+                ],
+                "summary": "Снять временный уровень логов бота",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LogPolicy"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/bots/{bot_id}/owner": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Назначает боту владельца и записывает передачу в историю (требуется админский токен).\nВладелец должен существовать и не быть в архиве; передача текущему владельцу ничего не меняет.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Передать бота владельцу",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый владелец",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bot_handler.TransferBotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Bot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Оставляет бота без владельца и записывает это в историю (требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Снять владельца бота",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Причина",
+                        "name": "reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Bot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/bots/{bot_id}/owners-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает передачи бота между владельцами, новые первыми (требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bots"
+                ],
+                "summary": "История владельцев бота",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BotOwnerChange"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/bots/{bot_id}/purge": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Считает записи, которые будут удалены вместе с ботом (токены, запуски, правила маскирования, политика\nхранения, счётчики и историю владельцев), и логи, которые потеряют привязку к боту. Ничего не удаляет (требуется админский токен).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Что удалит окончательное удаление бота",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BotPurgeReport"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет бота из базы данных вместе с токенами, запусками, правилами маскирования, политикой хранения, счётчиками и историей владельцев;\nлоги остаются без привязки к боту. Удалить можно только бота в архиве (требуется админский токен).\nВозвращает отчёт о фактически удалённом.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Окончательно удалить бота",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BotPurgeReport"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/bots/{bot_id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает бота из архива (требуется админский токен). Для бота не в архиве ничего не меняет.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Восстановить бота из архива",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Bot"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/bots/{bot_id}/team": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Включает бота в команду; бот может состоять только в одной команде (требуется админский токен)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Включить бота в команду",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Команда",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bot_handler.SetBotTeamRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Bot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Оставляет бота без команды (требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Исключить бота из команды",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Bot"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/deployments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает выкладки с фильтрами и постраничной выдачей (новые первыми). Обычные токены видят только выкладки своего бота, токены команды — выкладки ботов команды.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "deployments"
+                ],
+                "summary": "Получить выкладки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID, для админских токенов и токенов команды)",
+                        "name": "bot_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID текущей команды бота (UUID)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода по deployed_at (RFC3339, включительно)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода по deployed_at (RFC3339, не включительно)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 100, максимум 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeploymentPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сохраняет выкладку новой версии бота (только для обычных токенов с bot_id). Версия проставляется\nновым логам и запускам бота, время которых не раньше deployed_at и раньше следующей выкладки.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "deployments"
+                ],
+                "summary": "Зарегистрировать выкладку",
+                "parameters": [
+                    {
+                        "description": "Данные выкладки",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/deployment_handler.CreateDeploymentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Deployment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/deployments/versions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сравнивает последние выложенные версии бота (новые первыми): число выкладок, запусков по статусам и доля успешных,\nчисло логов и доля логов Error и Critical. Учитываются логи и запуски, которым при записи была проставлена версия.\nОбычные токены сравнивают версии своего бота, токены команды — ботов команды; админским токенам и токенам команды нужен bot_id.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "deployments"
+                ],
+                "summary": "Сравнить версии бота",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID, для админских токенов и токенов команды)",
+                        "name": "bot_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Число версий (по умолчанию 5, максимум 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.VersionComparison"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/eff-runs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает записи о запусках с фильтрами и постраничной выдачей (новые первыми). Обычные токены видят только запуски своего бота, токены команды — запуски ботов команды.\nС include_deployments=true в deployments возвращаются выкладки тех же ботов (по bot_id и team_id) за период, который покрывает страница.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eff_runs"
+                ],
+                "summary": "Получить запуски",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID, для админских токенов и токенов команды)",
+                        "name": "bot_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID текущей команды бота (UUID)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID владельца бота на момент запуска (UUID)",
+                        "name": "owner_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "success",
+                                "warning",
+                                "error"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Статусы запуска",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Хост",
+                        "name": "host",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Версия бота на момент запуска",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода по created_at (RFC3339, включительно)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода по created_at (RFC3339, не включительно)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 100, максимум 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Добавить выкладки ботов за период страницы",
+                        "name": "include_deployments",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EffRunPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт новую запись о запуске бота (требуется авторизация, только для обычных токенов с bot_id).\nКлюч идемпотентности (заголовок Idempotency-Key или поле event_id) защищает от дублей при повторах:\nповтор с тем же ключом возвращает ранее созданную запись со статусом 200 и заголовком Idempotent-Replayed: true.\nПовтор ключа с другим телом запроса отклоняется со статусом 422.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eff_runs"
+                ],
+                "summary": "Создать запись о запуске",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности (до 255 символов)",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Данные о запуске",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/eff_run_handler.CreateEffRunRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Повтор запроса: ранее созданная запись",
+                        "schema": {
+                            "$ref": "#/definitions/models.EffRun"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.EffRun"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "422": {
+                        "description": "Ключ идемпотентности уже использован с другим телом запроса",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/eff-runs/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт до 1000 записей о запусках одним запросом (только для обычных токенов с bot_id). Пачка сохраняется целиком или не сохраняется вовсе.\nКлюч идемпотентности записи — её event_id, иначе заголовок Idempotency-Key с номером записи (\":0\", \":1\", ...).\nЗаписи с уже использованным ключом не создаются заново: в items возвращаются ранее созданные записи.\nЕсли ключ записи уже использован с другим телом, пачка отклоняется целиком со статусом 422.\nСтатус 201, если создана хотя бы одна запись, иначе 200.",
+                "consumes": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eff_runs"
+                ],
+                "summary": "Создать пачку записей о запусках",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности пачки (до 255 символов)",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Данные о запусках",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/eff_run_handler.CreateEffRunRequest"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Все записи — повторы",
+                        "schema": {
+                            "$ref": "#/definitions/eff_run_handler.CreateEffRunBatchResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/eff_run_handler.CreateEffRunBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "422": {
+                        "description": "Ключ идемпотентности уже использован с другим телом запроса",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/exports/eff-runs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Потоково выгружает записи о запусках в CSV или NDJSON (по заголовку Accept или параметру format). Фильтры — как у GET /v1/eff-runs.\nПри Accept-Encoding: gzip или gzip=true ответ сжимается. Обычные токены выгружают только запуски своего бота, токены команды — запуски ботов команды.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Выгрузка запусков",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID, для админских токенов и токенов команды)",
+                        "name": "bot_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID текущей команды бота (UUID)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID владельца бота на момент запуска (UUID)",
+                        "name": "owner_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "success",
+                                "warning",
+                                "error"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Статусы запуска",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Хост",
+                        "name": "host",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Версия бота на момент запуска",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода по created_at (RFC3339, включительно)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода по created_at (RFC3339, не включительно)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Формат (переопределяет Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Сжать ответ gzip",
+                        "name": "gzip",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Поток записей",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/exports/logs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Потоково выгружает логи в CSV или NDJSON (по заголовку Accept или параметру format). Фильтры — как у GET /v1/logs.\nПри Accept-Encoding: gzip или gzip=true ответ сжимается. Обычные токены выгружают только логи своего бота, токены команды — логи ботов команды.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Выгрузка логов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID, для админских токенов и токенов команды)",
+                        "name": "bot_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID текущей команды бота (UUID)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "Debug",
+                                "Info",
+                                "Warning",
+                                "Error",
+                                "Critical"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Уровни лога",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Версия бота на момент записи лога",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC3339, включительно)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (RFC3339, не включительно)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Полнотекстовый запрос",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "received_at"
+                        ],
+                        "type": "string",
+                        "description": "Поле времени для сортировки и периода from/to (по умолчанию created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Формат (переопределяет Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Сжать ответ gzip",
+                        "name": "gzip",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Поток записей",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/hosts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает реестр хостов, собранный по полю host запусков (требуется админский токен): сначала замолчавшие, затем по имени",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hosts"
+                ],
+                "summary": "Получить хосты",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Подстрока в имени, описании или IP (без учёта регистра)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только замолчавшие (true) или только присылающие запуски (false) хосты",
+                        "name": "silent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Только хосты, с которых приходили запуски бота (UUID)",
+                        "name": "bot_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Host"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/hosts/{host}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает хост с ботами, запуски которых с него приходили (требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hosts"
+                ],
+                "summary": "Получить хост",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя хоста",
+                        "name": "host",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Host"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет описание, ОС и IP хоста и признак слежения за его молчанием (требуется админский токен).\nЕсли слежение выключено, отметка о молчании снимается.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hosts"
+                ],
+                "summary": "Обновить хост",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя хоста",
+                        "name": "host",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Обновлённые данные",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/host_handler.UpdateHostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Host"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет хост из реестра (требуется админский токен). При следующем запуске с этого хоста он появится снова.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hosts"
+                ],
+                "summary": "Удалить хост",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя хоста",
+                        "name": "host",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/hosts/{host}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает показатели запусков с хоста за период (требуется админский токен): число запусков по статусам,\nдолю запусков с ошибкой, число разных ботов и наибольшее число ботов, работавших одновременно\n(по пересечению period_from–period_to запусков).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hosts"
+                ],
+                "summary": "Показатели хоста",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя хоста",
+                        "name": "host",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода по created_at (RFC3339, включительно)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода по created_at (RFC3339, не включительно)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HostStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/logs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает логи с фильтрами и постраничной выдачей (новые первыми). Обычные токены видят только логи своего бота, токены команды — логи ботов команды.\nПараметр q включает полнотекстовый поиск (русский и английский): слова через пробел — И, \"фраза в кавычках\", OR — ИЛИ, -слово — исключение.\nПри поиске результаты упорядочены по релевантности и содержат rank и headline с подсветкой совпадений (\u003cb\u003e…\u003c/b\u003e).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Получить логи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID, для админских токенов и токенов команды)",
+                        "name": "bot_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID текущей команды бота (UUID)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "Debug",
+                                "Info",
+                                "Warning",
+                                "Error",
+                                "Critical"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Уровни лога",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Версия бота на момент записи лога",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC3339, включительно)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (RFC3339, не включительно)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Полнотекстовый запрос",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "received_at"
+                        ],
+                        "type": "string",
+                        "description": "Поле времени для сортировки и периода from/to (по умолчанию created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 100, максимум 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LogPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт новый лог от имени текущего бота (требуется авторизация).\nНеобязательный timestamp задаёт время события (для логов, накопленных офлайн); время приёма сохраняется в received_at.\nКлюч идемпотентности (заголовок Idempotency-Key или поле event_id) защищает от дублей при повторах:\nповтор с тем же ключом возвращает ранее созданный лог со статусом 200 и заголовком Idempotent-Replayed: true.\nПовтор ключа с другим телом запроса отклоняется со статусом 422.\nПри включённой асинхронной записи лог без ключа ставится в очередь: ответ 202, id ещё не назначен.\nЛог, отброшенный политикой хранения бота (минимальный уровень, семплирование), не сохраняется:\nответ 202 с заголовком X-Log-Dropped: true.",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Создать лог",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности (до 255 символов)",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Данные лога",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/log_handler.CreateLogRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Повтор запроса: ранее созданный лог",
+                        "schema": {
+                            "$ref": "#/definitions/models.Log"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Log"
+                        }
+                    },
+                    "202": {
+                        "description": "Лог поставлен в очередь записи или отброшен политикой хранения",
+                        "schema": {
+                            "$ref": "#/definitions/models.Log"
+                        },
+                        "headers": {
+                            "X-Log-Dropped": {
+                                "type": "string",
+                                "description": "true, если лог отброшен политикой хранения бота"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "422": {
+                        "description": "Ключ идемпотентности уже использован с другим телом запроса",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/logs/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт до 1000 логов одним запросом от имени текущего бота (требуется авторизация). Пачка сохраняется целиком или не сохраняется вовсе.\nКлюч идемпотентности записи — её event_id, иначе заголовок Idempotency-Key с номером записи (\":0\", \":1\", ...).\nЗаписи с уже использованным ключом не создаются заново: в items возвращаются ранее созданные логи.\nЕсли ключ записи уже использован с другим телом, пачка отклоняется целиком со статусом 422.\nСтатус 201, если создана хотя бы одна запись, иначе 200. При включённой асинхронной записи пачка без ключей\nставится в очередь: ответ 202, id записей ещё не назначены. Записи, отброшенные политикой хранения бота,\nне сохраняются и учитываются в dropped; если отброшены все, ответ 202.",
+                "consumes": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Создать пачку логов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности пачки (до 255 символов)",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Логи",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/log_handler.CreateLogRequest"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Все записи — повторы",
+                        "schema": {
+                            "$ref": "#/definitions/log_handler.CreateLogBatchResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/log_handler.CreateLogBatchResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Пачка поставлена в очередь записи",
+                        "schema": {
+                            "$ref": "#/definitions/log_handler.CreateLogBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "422": {
+                        "description": "Ключ идемпотентности уже использован с другим телом запроса",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/otlp/logs": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Принимает ExportLogsServiceRequest в protobuf (application/x-protobuf) или JSON (application/json), тело может быть сжато gzip.\nseverity_number сопоставляется уровню лога, время записи становится created_at, атрибуты записи, ресурса и scope сохраняются в attributes.\nЗаписи без тела и со временем вне допустимого отклонения отклоняются (partialSuccess).\nЛоги привязываются к боту токена; для админского токена бот определяется по атрибуту ресурса service.name (код бота).",
+                "consumes": [
+                    "application/x-protobuf",
+                    "application/json"
+                ],
+                "produces": [
+                    "application/x-protobuf",
+                    "application/json"
+                ],
+                "tags": [
+                    "otlp"
+                ],
+                "summary": "Приём логов OpenTelemetry (OTLP/HTTP)",
+                "responses": {
+                    "200": {
+                        "description": "ExportLogsServiceResponse (partialSuccess при отклонённых записях)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/owners": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает владельцев с фильтрами и постраничной выдачей (требуется админский токен).\ntotal — число владельцев, подходящих под фильтр. По умолчанию новые первыми; full_name сортируется по возрастанию.\nБез limit и cursor ответ — массив всех подходящих владельцев (прежний формат, для совместимости со старыми клиентами).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "owners"
+                ],
+                "summary": "Получить владельцев",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Активность",
+                        "name": "is_active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока в имени (без учёта регистра)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Только участники команды (UUID)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exclude",
+                            "include",
+                            "only"
+                        ],
+                        "type": "string",
+                        "description": "Владельцы в архиве: exclude — скрыть (по умолчанию), include — показать вместе с остальными, only — только они",
+                        "name": "archived",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "full_name"
+                        ],
+                        "type": "string",
+                        "description": "Поле сортировки (по умолчанию created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Направление сортировки",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 100, максимум 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor (с теми же sort и order)",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OwnerPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт нового владельца (требуется админский токен). Для каждого канала из contacts.notify_channels\nдолжен быть указан соответствующий контакт.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "owners"
+                ],
+                "summary": "Создать владельца",
+                "parameters": [
+                    {
+                        "description": "Данные владельца",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/owner_handler.CreateOwnerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Owner"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/owners/{owner_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает информацию о владельце по ID (требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "owners"
+                ],
+                "summary": "Получить владельца",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID владельца (UUID)",
+                        "name": "owner_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Owner"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновляет данные владельца (требуется админский токен). Переданный contacts заменяет все контакты.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "owners"
+                ],
+                "summary": "Обновить владельца",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID владельца (UUID)",
+                        "name": "owner_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Обновлённые данные",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/owner_handler.UpdateOwnerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Owner"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Переносит владельца в архив (требуется админский токен): владелец скрывается из списков, его боты\nсохраняют привязку к нему. Повторный вызов ничего не меняет.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "owners"
+                ],
+                "summary": "Перенести владельца в архив",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID владельца (UUID)",
+                        "name": "owner_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Owner"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/owners/{owner_id}/purge": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Считает ботов, которые потеряют привязку к владельцу. Ничего не удаляет (требуется админский токен).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "owners"
+                ],
+                "summary": "Что изменит окончательное удаление владельца",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID владельца (UUID)",
+                        "name": "owner_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OwnerPurgeReport"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет владельца из базы данных; его боты остаются без владельца (это записывается в историю передачи). Удалить можно только владельца в архиве\n(требуется админский токен). Возвращает отчёт о фактически изменённом.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "owners"
+                ],
+                "summary": "Окончательно удалить владельца",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID владельца (UUID)",
+                        "name": "owner_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OwnerPurgeReport"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/owners/{owner_id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает владельца из архива (требуется админский токен). Для владельца не в архиве ничего не меняет.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "owners"
+                ],
+                "summary": "Восстановить владельца из архива",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID владельца (UUID)",
+                        "name": "owner_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Owner"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает профиль владельца токена (требуется токен владельца)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Получить свой профиль",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Owner"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновляет имя и контакты владельца токена (требуется токен владельца). Переданный contacts заменяет\nвсе контакты; для каждого канала из notify_channels должен быть указан соответствующий контакт.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Обновить свой профиль",
+                "parameters": [
+                    {
+                        "description": "Обновлённые данные",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/profile_handler.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Owner"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/redaction-rules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает правила маскирования (требуется админский токен). С bot_id — правила бота и глобальные правила.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redaction"
+                ],
+                "summary": "Получить правила маскирования",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RedactionRule"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт правило маскирования сообщений логов (требуется админский токен).\nБез bot_id правило применяется ко всем логам. Выражение в синтаксисе RE2; если в нём есть группа (?P\u003cpii\u003e...),\nзаменяется только она. Без replacement совпадение заменяется на [REDACTED:\u003cname\u003e].",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redaction"
+                ],
+                "summary": "Создать правило маскирования",
+                "parameters": [
+                    {
+                        "description": "Данные правила",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/redaction_handler.CreateRedactionRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.RedactionRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/redaction-rules/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает число замаскированных фрагментов по ботам и правилам за период (требуется админский токен).\nСчётчики сохраняются периодически (redaction.stats_flush_interval_sec), последние замены могут появиться с задержкой.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redaction"
+                ],
+                "summary": "Статистика маскирования",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID бота (UUID)",
+                        "name": "bot_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Первый день периода (YYYY-MM-DD, UTC)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Последний день периода (YYYY-MM-DD, UTC)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RedactionCount"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/redaction-rules/{rule_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает правило маскирования по ID (требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redaction"
+                ],
+                "summary": "Получить правило маскирования",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID правила (UUID)",
+                        "name": "rule_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RedactionRule"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновляет правило маскирования (требуется админский токен). Изменения применяются к новым логам.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redaction"
+                ],
+                "summary": "Обновить правило маскирования",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID правила (UUID)",
+                        "name": "rule_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Обновлённые данные",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/redaction_handler.UpdateRedactionRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RedactionRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет правило маскирования (требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redaction"
+                ],
+                "summary": "Удалить правило маскирования",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID правила (UUID)",
+                        "name": "rule_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/teams": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает команды по имени с числом участников и ботов (требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Получить команды",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Подстрока в имени (без учёта регистра)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Только команды, в которых состоит владелец (UUID)",
+                        "name": "owner_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Team"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт команду владельцев (требуется админский токен). Имя команды уникально.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Создать команду",
+                "parameters": [
+                    {
+                        "description": "Данные команды",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/team_handler.CreateTeamRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/teams/{team_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает команду с участниками (требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Получить команду",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID команды (UUID)",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Team"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет имя и описание команды (требуется админский токен)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Обновить команду",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID команды (UUID)",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Обновлённые данные",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/team_handler.UpdateTeamRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет команду (требуется админский токен): её боты остаются без команды, токены команды удаляются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Удалить команду",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID команды (UUID)",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/teams/{team_id}/health": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сводка состояния ботов команды — то же, что /v1/bots/health с team_id.\nДоступна админскому токену и токену этой команды.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Состояние ботов команды",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID команды (UUID)",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "healthy",
+                            "degraded",
+                            "failing",
+                            "silent",
+                            "unknown"
+                        ],
+                        "type": "string",
+                        "description": "Только боты в этом состоянии",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Включать неактивных ботов (is_active = false)",
+                        "name": "include_inactive",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FleetHealth"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/teams/{team_id}/members/{owner_id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Добавляет владельца в команду или меняет его роль (требуется админский токен). Владельца в архиве добавить нельзя.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Добавить владельца в команду",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID команды (UUID)",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID владельца (UUID)",
+                        "name": "owner_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Роль в команде",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/team_handler.SetMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TeamMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Исключает владельца из команды (требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Исключить владельца из команды",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID команды (UUID)",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID владельца (UUID)",
+                        "name": "owner_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/teams/{team_id}/notifications": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Заменяет настройки уведомлений команды (требуется админский токен). После каждого расчёта состояния ботов\nна webhook_url отправляется POST с переходами ботов команды в состояния из states (одно уведомление на команду).\nБез webhook_url уведомления не отправляются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Настроить уведомления команды",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID команды (UUID)",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Настройки уведомлений",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/team_handler.TeamNotificationsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/tokens": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт новый токен аутентификации для указанного бота (требуется админский токен).\nТокен с team_id — токен команды: только чтение логов, запусков и состояния всех ботов команды.\nТокен с owner_id — токен владельца: только просмотр и изменение своего профиля (/v1/profile).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Создать новый токен",
+                "parameters": [
+                    {
+                        "description": "Данные для создания токена",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth_handler.CreateTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/auth_handler.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/tokens/{token_id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновляет название существующего токена (требуется админский токен)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Обновить токен",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID токена (UUID)",
+                        "name": "token_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новое название токена",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth_handler.UpdateTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Полностью удаляет токен из базы данных (требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Удалить токен",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID токена (UUID)",
+                        "name": "token_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/tokens/{token_id}/deactivate": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Деактивирует токен (мягкое удаление, токен перестаёт работать, требуется админский токен)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Деактивировать токен",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID токена (UUID)",
+                        "name": "token_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/ws": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Открывает WebSocket соединение для подписки на события логов (channel=logs) и запусков (channel=eff_runs).\nВходящие сообщения: {\"type\":\"subscribe\",\"id\":\"s1\",\"channel\":\"logs\",\"filter\":{\"bot_ids\":[],\"statuses\":[\"Error\"]}}, {\"type\":\"unsubscribe\",\"id\":\"s1\"}, {\"type\":\"ping\"}.\nИсходящие сообщения: subscribed, unsubscribed, pong, error, event. Клиенты, не успевающие читать события, отключаются с кодом 1013.\nТокен передаётся в заголовке Authorization, а из браузера — подпротоколом: new WebSocket(url, [\"bearer\", token]).\nQuery параметр access_token тоже принимается, но не рекомендуется. Обычные токены видят только события своего бота,\nтокены команды — события ботов, состоявших в команде на момент подключения.",
+                "tags": [
+                    "stream"
+                ],
+                "summary": "Подписка на события (WebSocket)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "bearer, \u003cтокен\u003e (если нельзя передать заголовок Authorization)",
+                        "name": "Sec-WebSocket-Protocol",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен (устаревший способ, лучше подпротокол bearer)",
+                        "name": "access_token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "archive_handler.ImportArchiveRequest": {
+            "type": "object",
+            "required": [
+                "from",
+                "to"
+            ],
+            "properties": {
+                "bot_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-01-01"
+                },
+                "levels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Error",
+                        "Critical"
+                    ]
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-01-31"
+                }
+            }
+        },
+        "auth_handler.CreateTokenRequest": {
+            "type": "object",
+            "required": [
+                "token_name"
+            ],
+            "properties": {
+                "bot_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "is_admin": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "owner_id": {
+                    "type": "string",
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+                },
+                "team_id": {
+                    "type": "string",
+                    "example": "9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"
+                },
+                "token_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3,
+                    "example": "Production Server"
+                }
+            }
+        },
+        "auth_handler.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                }
+            }
+        },
+        "auth_handler.UpdateTokenRequest": {
+            "type": "object",
+            "required": [
+                "token_name"
+            ],
+            "properties": {
+                "token_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3,
+                    "example": "Production Server v2"
+                }
+            }
+        },
+        "bot_handler.CreateBotRequest": {
+            "type": "object",
+            "required": [
+                "bot_type",
+                "code",
+                "language",
+                "name"
+            ],
+            "properties": {
+                "bot_type": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Backend"
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2,
+                    "example": "BOT_001"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Бот для обработки сообщений"
+                },
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "language": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Python"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 2,
+                    "example": "Telegram Bot"
+                },
+                "owner_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "telegram",
+                        "bot"
+                    ]
+                },
+                "team_id": {
+                    "type": "string",
+                    "example": "9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"
+                }
+            }
+        },
+        "bot_handler.SetBotTeamRequest": {
+            "type": "object",
+            "required": [
+                "team_id"
+            ],
+            "properties": {
+                "team_id": {
+                    "type": "string",
+                    "example": "9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"
+                }
+            }
+        },
+        "bot_handler.TransferBotRequest": {
+            "type": "object",
+            "required": [
+                "owner_id"
+            ],
+            "properties": {
+                "owner_id": {
+                    "type": "string",
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Переход в команду платежей"
+                }
+            }
+        },
+        "bot_handler.UpdateBotRequest": {
+            "type": "object",
+            "properties": {
+                "bot_type": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "AI"
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2,
+                    "example": "BOT_002"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Обновлённое описание"
+                },
+                "is_active": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "language": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Go"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 2,
+                    "example": "Discord Bot"
+                },
+                "owner_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "discord",
+                        "ai"
+                    ]
+                },
+                "team_id": {
+                    "type": "string",
+                    "example": "9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"
+                }
+            }
+        },
+        "bot_reference_handler.CreateBotReferenceRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "UiPath"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Роботы на платформе UiPath"
+                }
+            }
+        },
+        "bot_reference_handler.UpdateBotReferenceRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code — новый код; переносится на всех ботов с прежним кодом",
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 1,
+                    "example": "Kotlin"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Сервисы на Kotlin"
+                }
+            }
+        },
+        "deployment_handler.CreateDeploymentRequest": {
+            "type": "object",
+            "required": [
+                "version"
+            ],
+            "properties": {
+                "changelog": {
+                    "type": "string",
+                    "maxLength": 10000,
+                    "example": "Исправлена обработка таймаутов"
+                },
+                "deployed_at": {
+                    "description": "Время выкладки; по умолчанию — время приёма",
+                    "type": "string",
+                    "example": "2025-01-15T12:00:00Z"
+                },
+                "git_sha": {
+                    "type": "string",
+                    "maxLength": 40,
+                    "minLength": 7,
+                    "example": "9fceb02d0ae598e95dc970b74767f19372d61af8"
+                },
+                "host": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "server-01"
+                },
+                "version": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "1.4.2"
+                }
+            }
+        },
+        "eff_run_handler.CreateEffRunBatchResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EffRun"
+                    }
+                },
+                "replayed": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "eff_run_handler.CreateEffRunRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "event_id": {
+                    "description": "Идентификатор запуска на стороне клиента: повтор с тем же event_id вернёт ранее созданную запись",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "run-2024-01-01T00"
+                },
+                "extra": {
+                    "$ref": "#/definitions/models.JSONB"
+                },
+                "host": {
+                    "type": "string",
+                    "example": "server-01"
+                },
+                "period_from": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "period_to": {
+                    "type": "string",
+                    "example": "2024-01-01T01:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "success",
+                        "warning",
+                        "error"
+                    ],
+                    "example": "success"
+                }
+            }
+        },
+        "es_handler.BulkError": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "field [message] is missing"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "mapper_parsing_exception"
+                }
+            }
+        },
+        "es_handler.BulkItemResult": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string",
+                    "example": "12345"
+                },
+                "_index": {
+                    "type": "string",
+                    "example": "filebeat-8.15.0"
+                },
+                "_primary_term": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "_seq_no": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "_shards": {
+                    "$ref": "#/definitions/es_handler.BulkShards"
+                },
+                "_version": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "error": {
+                    "$ref": "#/definitions/es_handler.BulkError"
+                },
+                "result": {
+                    "type": "string",
+                    "example": "created"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 201
+                }
+            }
+        },
+        "es_handler.BulkResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "additionalProperties": {
+                            "$ref": "#/definitions/es_handler.BulkItemResult"
+                        }
+                    }
+                },
+                "took": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "es_handler.BulkShards": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "successful": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "es_handler.ErrorCause": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "некорректная строка действия [1]"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "illegal_argument_exception"
+                }
+            }
+        },
+        "es_handler.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/es_handler.ErrorCause"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                }
+            }
+        },
+        "host_handler.UpdateHostRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Виртуальная машина платёжных ботов"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "10.0.12.5"
+                },
+                "monitored": {
+                    "description": "Следить ли за молчанием хоста",
+                    "type": "boolean",
+                    "example": true
+                },
+                "os": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Ubuntu 22.04"
+                }
+            }
+        },
+        "log_handler.CreateLogBatchResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "dropped": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Log"
+                    }
+                },
+                "queued": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "replayed": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "log_handler.CreateLogRequest": {
+            "type": "object",
+            "required": [
+                "msg",
+                "status"
+            ],
+            "properties": {
+                "event_id": {
+                    "description": "Идентификатор события на стороне клиента: повтор с тем же event_id вернёт ранее созданный лог",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "7f3c1e0a-retry-safe-id"
+                },
+                "msg": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "Операция выполнена успешно"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "Debug",
+                        "Info",
+                        "Warning",
+                        "Error",
+                        "Critical"
+                    ],
+                    "example": "Info"
+                },
+                "timestamp": {
+                    "description": "Время события на стороне клиента (RFC3339); по умолчанию — время приёма",
+                    "type": "string",
+                    "example": "2025-01-15T11:58:00Z"
+                }
+            }
+        },
+        "log_policy_handler.SetLogPolicyRequest": {
+            "type": "object",
+            "properties": {
+                "min_level": {
+                    "type": "string",
+                    "enum": [
+                        "Debug",
+                        "Info",
+                        "Warning",
+                        "Error",
+                        "Critical"
+                    ],
+                    "example": "Info"
+                },
+                "sample_rates": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "log_policy_handler.SetOverrideRequest": {
+            "type": "object",
+            "required": [
+                "duration_minutes",
+                "level"
+            ],
+            "properties": {
+                "duration_minutes": {
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 1,
+                    "example": 120
+                },
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "Debug",
+                        "Info",
+                        "Warning",
+                        "Error",
+                        "Critical"
+                    ],
+                    "example": "Debug"
+                }
+            }
+        },
+        "models.ArchiveFile": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "type": "integer",
+                    "example": 482113
+                },
+                "exported_at": {
+                    "type": "string",
+                    "example": "2025-04-16T03:00:00Z"
+                },
+                "first_created_at": {
+                    "type": "string",
+                    "example": "2025-01-15T00:00:01Z"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "logs/2025/01/15/550e8400-e29b-41d4-a716-446655440000/Info.ndjson.gz"
+                },
+                "last_created_at": {
+                    "type": "string",
+                    "example": "2025-01-15T23:59:58Z"
+                },
+                "level": {
+                    "type": "string",
+                    "example": "Info"
+                },
+                "max_id": {
+                    "type": "integer",
+                    "example": 15329
+                },
+                "min_id": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "rows": {
+                    "type": "integer",
+                    "example": 15230
+                },
+                "sha256": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                }
+            }
+        },
+        "models.ArchiveImport": {
+            "type": "object",
+            "properties": {
+                "files": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "rows": {
+                    "type": "integer",
+                    "example": 182400
+                },
+                "table": {
+                    "type": "string",
+                    "example": "archive_import_1736899200"
+                }
+            }
+        },
+        "models.ArchiveManifest": {
+            "type": "object",
+            "properties": {
+                "bot_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "day": {
+                    "type": "string",
+                    "example": "2025-01-15"
+                },
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ArchiveFile"
+                    }
+                }
+            }
+        },
+        "models.Bot": {
+            "type": "object",
+            "required": [
+                "bot_type",
+                "code",
+                "language",
+                "name"
+            ],
+            "properties": {
+                "archived_at": {
+                    "description": "ArchivedAt — время переноса в архив; ArchivedBy — ID админского токена, которым бот перенесён",
+                    "type": "string",
+                    "example": "2023-02-01T12:00:00Z"
+                },
+                "archived_by": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "bot_type": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Backend"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "BOT_001"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Бот для обработки сообщений"
+                },
+                "health": {
+                    "description": "Health — состояние по последним логам и запускам; не хранится в таблице bots",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.BotHealth"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "language": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Python"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Telegram Bot"
+                },
+                "owner_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "telegram",
+                        "automation"
+                    ]
+                },
+                "team_id": {
+                    "type": "string",
+                    "example": "9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:00Z"
+                }
+            }
+        },
+        "models.BotHealth": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:01:00Z"
+                },
+                "error_logs": {
+                    "description": "ErrorLogs — число логов Error и Critical за окно health.window_minutes",
+                    "type": "integer",
+                    "example": 12
+                },
+                "failed_runs": {
+                    "description": "FailedRuns — число запусков со статусом error среди последних health.recent_runs",
+                    "type": "integer",
+                    "example": 0
+                },
+                "last_log_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:00Z"
+                },
+                "last_run_at": {
+                    "type": "string",
+                    "example": "2023-01-15T11:00:00Z"
+                },
+                "last_run_status": {
+                    "description": "LastRunStatus — статус последнего запуска",
+                    "type": "string",
+                    "enum": [
+                        "success",
+                        "warning",
+                        "error"
+                    ],
+                    "example": "success"
+                },
+                "last_signal_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:00Z"
+                },
+                "reasons": {
+                    "description": "Reasons — почему состояние не healthy",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "12 логов Error/Critical за 60 мин"
+                    ]
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "healthy",
+                        "degraded",
+                        "failing",
+                        "silent",
+                        "unknown"
+                    ],
+                    "example": "degraded"
+                }
+            }
+        },
+        "models.BotHealthItem": {
+            "type": "object",
+            "properties": {
+                "bot_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "BOT_001"
+                },
+                "health": {
+                    "$ref": "#/definitions/models.BotHealth"
+                },
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Telegram Bot"
+                },
+                "team_id": {
+                    "type": "string",
+                    "example": "9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"
+                }
+            }
+        },
+        "models.BotOwnerChange": {
+            "type": "object",
+            "properties": {
+                "bot_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "changed_at": {
+                    "type": "string",
+                    "example": "2023-03-01T09:00:00Z"
+                },
+                "changed_by": {
+                    "description": "ChangedBy — ID админского токена, которым выполнена передача",
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "from_owner_id": {
+                    "description": "FromOwnerID — прежний владелец (нет — у бота не было владельца); ToOwnerID — новый (нет — владелец снят)",
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Переход в команду платежей"
+                },
+                "to_owner_id": {
+                    "type": "string",
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+                }
+            }
+        },
+        "models.BotPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Bot"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyNS0wMS0xNVQxMjowMDowMFoiLCJpZCI6IjEyMzQ1In0"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.BotPurgeReport": {
+            "type": "object",
+            "properties": {
+                "bot_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "BOT_001"
+                },
+                "deployments": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "eff_runs": {
+                    "type": "integer",
+                    "example": 1450
+                },
+                "health_states": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "host_bots": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "log_drop_counts": {
+                    "type": "integer",
+                    "example": 90
+                },
+                "log_policies": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "logs_detached": {
+                    "description": "LogsDetached — логи бота остаются, но теряют привязку к нему (bot_id станет NULL)",
+                    "type": "integer",
+                    "example": 250000
+                },
+                "owner_history": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "purged": {
+                    "description": "Purged — удаление выполнено (false — предварительный отчёт)",
+                    "type": "boolean",
+                    "example": false
+                },
+                "redaction_counts": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "redaction_rules": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "tokens": {
+                    "description": "Удаляемые записи связанных таблиц",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.BotReference": {
+            "type": "object",
+            "properties": {
+                "bot_count": {
+                    "description": "BotCount — число ботов (включая ботов в архиве) с этим значением",
+                    "type": "integer",
+                    "example": 12
+                },
+                "code": {
+                    "type": "string",
+                    "example": "UiPath"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Роботы на платформе UiPath"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:00Z"
+                }
+            }
+        },
+        "models.Catalog": {
+            "type": "object",
+            "properties": {
+                "bots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CatalogBot"
+                    }
+                },
+                "owners": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CatalogOwner"
+                    }
+                }
+            }
+        },
+        "models.CatalogBot": {
+            "type": "object",
+            "required": [
+                "bot_type",
+                "code",
+                "language",
+                "name"
+            ],
+            "properties": {
+                "bot_type": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Backend"
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2,
+                    "example": "BOT_001"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Бот для обработки сообщений"
+                },
+                "is_active": {
+                    "description": "IsActive — по умолчанию true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "language": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Python"
+                },
+                "log_policy": {
+                    "description": "LogPolicy — минимальный уровень и семплирование; нет — логи сохраняются полностью",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CatalogLogPolicy"
+                        }
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 2,
+                    "example": "Telegram Bot"
+                },
+                "owner": {
+                    "description": "Owner — full_name владельца из раздела owners; нет — бот без владельца",
+                    "type": "string",
+                    "example": "Иван Иванов"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "telegram",
+                        "automation"
+                    ]
+                },
+                "team": {
+                    "description": "Team — имя существующей команды; нет — бот вне команд",
+                    "type": "string",
+                    "example": "Платежи"
+                }
+            }
+        },
+        "models.CatalogChange": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "update",
+                        "archive"
+                    ],
+                    "example": "update"
+                },
+                "fields": {
+                    "description": "Fields — изменённые поля (для update)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "owner",
+                        "tags"
+                    ]
+                },
+                "key": {
+                    "description": "Key — full_name владельца или code бота",
+                    "type": "string",
+                    "example": "BOT_001"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "bot"
+                    ],
+                    "example": "bot"
+                }
+            }
+        },
+        "models.CatalogImportResult": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CatalogChange"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "unchanged": {
+                    "description": "Unchanged — число ботов из файла, которые уже совпадают с каталогом",
+                    "type": "integer",
+                    "example": 180
+                }
+            }
+        },
+        "models.CatalogLogPolicy": {
+            "type": "object",
+            "properties": {
+                "min_level": {
+                    "type": "string",
+                    "enum": [
+                        "Debug",
+                        "Info",
+                        "Warning",
+                        "Error",
+                        "Critical"
+                    ],
+                    "example": "Info"
+                },
+                "sample_rates": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "models.CatalogOwner": {
+            "type": "object",
+            "required": [
+                "full_name"
+            ],
+            "properties": {
+                "full_name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 2,
+                    "example": "Иван Иванов"
+                },
+                "is_active": {
+                    "description": "IsActive — по умолчанию true",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.Deployment": {
+            "type": "object",
+            "properties": {
+                "bot_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "changelog": {
+                    "type": "string",
+                    "example": "Исправлена обработка таймаутов"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:05Z"
+                },
+                "deployed_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:00Z"
+                },
+                "git_sha": {
+                    "type": "string",
+                    "example": "9fceb02d0ae598e95dc970b74767f19372d61af8"
+                },
+                "host": {
+                    "type": "string",
+                    "example": "server-01"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "1.4.2"
+                }
+            }
+        },
+        "models.DeploymentPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Deployment"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyNS0wMS0xNVQxMjowMDowMFoiLCJpZCI6IjEyMzQ1In0"
+                }
+            }
+        },
+        "models.EffRun": {
+            "type": "object",
+            "required": [
+                "bot_id"
+            ],
+            "properties": {
+                "bot_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:00Z"
+                },
+                "extra": {
+                    "type": "object"
+                },
+                "host": {
+                    "type": "string",
+                    "example": "server-01"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "owner_id": {
+                    "description": "OwnerID — владелец бота на момент запуска (по истории передачи бота); не хранится в таблице eff_runs",
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "period_from": {
+                    "type": "string",
+                    "example": "2023-01-15T10:00:00Z"
+                },
+                "period_to": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "success",
+                        "warning",
+                        "error"
+                    ],
+                    "example": "success"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "1.4.2"
+                }
+            }
+        },
+        "models.EffRunPage": {
+            "type": "object",
+            "properties": {
+                "deployments": {
+                    "description": "Deployments — выкладки тех же ботов за период страницы (только с include_deployments)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Deployment"
+                    }
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EffRun"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyNS0wMS0xNVQxMjowMDowMFoiLCJpZCI6IjEyMzQ1In0"
+                }
+            }
+        },
+        "models.FleetHealth": {
+            "type": "object",
+            "properties": {
+                "bots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BotHealthItem"
+                    }
+                },
+                "checked_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:01:00Z"
+                },
+                "counts": {
+                    "description": "Counts — число ботов по состояниям",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.Host": {
+            "type": "object",
+            "properties": {
+                "bot_count": {
+                    "description": "BotCount — число ботов, запуски которых приходили с хоста",
+                    "type": "integer",
+                    "example": 3
+                },
+                "bots": {
+                    "description": "Bots — боты хоста; заполняется только при получении одного хоста",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HostBot"
+                    }
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Виртуальная машина платёжных ботов"
+                },
+                "first_seen_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:00Z"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "10.0.12.5"
+                },
+                "last_seen_at": {
+                    "type": "string",
+                    "example": "2023-01-16T12:00:00Z"
+                },
+                "monitored": {
+                    "description": "Monitored — следить ли за молчанием хоста",
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "type": "string",
+                    "example": "server-01"
+                },
+                "os": {
+                    "type": "string",
+                    "example": "Ubuntu 22.04"
+                },
+                "runs_seen": {
+                    "description": "RunsSeen — число запусков с хоста с момента его появления в реестре",
+                    "type": "integer",
+                    "example": 1450
+                },
+                "silent_since": {
+                    "description": "SilentSince — с какого момента хост считается замолчавшим",
+                    "type": "string",
+                    "example": "2023-01-16T13:00:00Z"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:00Z"
+                }
+            }
+        },
+        "models.HostBot": {
+            "type": "object",
+            "properties": {
+                "bot_id": {
+                    "type": "string",
+                    "format": "uuid",
                     "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
-                "is_admin": {
+                "code": {
+                    "type": "string",
+                    "example": "BOT_001"
+                },
+                "first_seen_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:00Z"
+                },
+                "last_seen_at": {
+                    "type": "string",
+                    "example": "2023-01-16T12:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Telegram Bot"
+                },
+                "runs_seen": {
+                    "type": "integer",
+                    "example": 480
+                }
+            }
+        },
+        "models.HostStats": {
+            "type": "object",
+            "properties": {
+                "bots": {
+                    "description": "Bots — число разных ботов, запуски которых приходили с хоста за период",
+                    "type": "integer",
+                    "example": 3
+                },
+                "failure_rate": {
+                    "description": "FailureRate — доля запусков со статусом error; нет — запусков не было",
+                    "type": "number",
+                    "example": 0.0138
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "host": {
+                    "type": "string",
+                    "example": "server-01"
+                },
+                "peak_concurrent_bots": {
+                    "description": "PeakConcurrentBots — наибольшее число ботов, работавших на хосте одновременно\n(по пересечению period_from–period_to запусков)",
+                    "type": "integer",
+                    "example": 2
+                },
+                "runs": {
+                    "type": "integer",
+                    "example": 1450
+                },
+                "runs_error": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "runs_success": {
+                    "type": "integer",
+                    "example": 1400
+                },
+                "runs_warning": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-02-01T00:00:00Z"
+                }
+            }
+        },
+        "models.JSONB": {
+            "type": "object",
+            "additionalProperties": true
+        },
+        "models.Log": {
+            "type": "object",
+            "required": [
+                "msg"
+            ],
+            "properties": {
+                "attributes": {
+                    "type": "object"
+                },
+                "bot_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 12345
+                },
+                "msg": {
+                    "type": "string",
+                    "example": "Операция выполнена успешно"
+                },
+                "received_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:05Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "Debug",
+                        "Info",
+                        "Warning",
+                        "Error",
+                        "Critical"
+                    ],
+                    "example": "Info"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "1.4.2"
+                }
+            }
+        },
+        "models.LogDropCount": {
+            "type": "object",
+            "properties": {
+                "bot_code": {
+                    "type": "string",
+                    "example": "PROJ001"
+                },
+                "bot_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 15000
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "level",
+                        "sampled"
+                    ],
+                    "example": "level"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "Debug"
+                }
+            }
+        },
+        "models.LogHit": {
+            "type": "object",
+            "required": [
+                "msg"
+            ],
+            "properties": {
+                "attributes": {
+                    "type": "object"
+                },
+                "bot_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:00Z"
+                },
+                "headline": {
+                    "type": "string",
+                    "example": "Ошибка \u003cb\u003eподключения\u003c/b\u003e к базе"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 12345
+                },
+                "msg": {
+                    "type": "string",
+                    "example": "Операция выполнена успешно"
+                },
+                "rank": {
+                    "type": "number",
+                    "example": 0.35
+                },
+                "received_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:05Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "Debug",
+                        "Info",
+                        "Warning",
+                        "Error",
+                        "Critical"
+                    ],
+                    "example": "Info"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "1.4.2"
+                }
+            }
+        },
+        "models.LogPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LogHit"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyNS0wMS0xNVQxMjowMDowMFoiLCJpZCI6IjEyMzQ1In0"
+                }
+            }
+        },
+        "models.LogPartition": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "is_default": {
                     "type": "boolean",
                     "example": false
                 },
-                "token_name": {
+                "name": {
+                    "type": "string",
+                    "example": "logs_p202501"
+                },
+                "rows_approx": {
+                    "type": "integer",
+                    "example": 1250000
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-02-01T00:00:00Z"
+                },
+                "total_bytes": {
+                    "type": "integer",
+                    "example": 268435456
+                },
+                "total_size": {
+                    "type": "string",
+                    "example": "256 MB"
+                }
+            }
+        },
+        "models.LogPolicy": {
+            "type": "object",
+            "properties": {
+                "bot_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "min_level": {
+                    "description": "MinLevel — минимальный сохраняемый уровень; nil — все уровни",
+                    "type": "string",
+                    "enum": [
+                        "Debug",
+                        "Info",
+                        "Warning",
+                        "Error",
+                        "Critical"
+                    ],
+                    "example": "Info"
+                },
+                "override_level": {
+                    "description": "OverrideLevel — временный минимальный уровень до OverrideUntil, без семплирования",
+                    "type": "string",
+                    "enum": [
+                        "Debug",
+                        "Info",
+                        "Warning",
+                        "Error",
+                        "Critical"
+                    ],
+                    "example": "Debug"
+                },
+                "override_until": {
                     "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3,
-                    "example": "Production Server"
-                }
-            }
-        },
-        "auth_handler.TokenResponse": {
-            "type": "object",
-            "properties": {
-                "token": {
+                    "example": "2023-01-15T14:00:00Z"
+                },
+                "sample_rates": {
+                    "description": "SampleRates — доля сохраняемых логов по уровням (0..1); уровень без значения сохраняется полностью",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "updated_at": {
                     "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                    "example": "2023-01-15T12:00:00Z"
                 }
             }
         },
-        "auth_handler.UpdateTokenRequest": {
+        "models.Owner": {
             "type": "object",
             "required": [
-                "token_name"
+                "full_name"
             ],
             "properties": {
-                "token_name": {
+                "archived_at": {
+                    "description": "ArchivedAt — время переноса в архив; ArchivedBy — ID админского токена, которым владелец перенесён",
                     "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3,
-                    "example": "Production Server v2"
-                }
-            }
-        },
-        "bot_handler.CreateBotRequest": {
-            "type": "object",
-            "required": [
-                "bot_type",
-                "code",
-                "language",
-                "name"
-            ],
-            "properties": {
-                "bot_type": {
+                    "example": "2023-02-01T12:00:00Z"
+                },
+                "archived_by": {
                     "type": "string",
-                    "enum": [
-                        "AI",
-                        "Backend",
-                        "Frontend",
-                        "Robot"
-                    ],
-                    "example": "Backend"
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
-                "code": {
+                "contacts": {
+                    "$ref": "#/definitions/models.OwnerContacts"
+                },
+                "created_at": {
                     "type": "string",
-                    "maxLength": 50,
-                    "minLength": 2,
-                    "example": "BOT_001"
+                    "example": "2023-01-15T12:00:00Z"
                 },
-                "description": {
+                "full_name": {
                     "type": "string",
-                    "example": "Бот для обработки сообщений"
+                    "example": "Иван Иванов"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
                 "is_active": {
                     "type": "boolean",
                     "example": true
+                }
+            }
+        },
+        "models.OwnerContacts": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "ivanov@example.com"
                 },
-                "language": {
+                "notify_channels": {
+                    "description": "NotifyChannels — каналы в порядке предпочтения; для каждого должен быть указан контакт",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "telegram",
+                        "email"
+                    ]
+                },
+                "phone": {
                     "type": "string",
-                    "enum": [
-                        "Python",
-                        "Go",
-                        "N8N",
-                        "PIX",
-                        "JS",
-                        "C",
-                        "Other"
-                    ],
-                    "example": "Python"
+                    "example": "+79991234567"
                 },
-                "name": {
+                "telegram": {
                     "type": "string",
-                    "maxLength": 255,
-                    "minLength": 2,
-                    "example": "Telegram Bot"
+                    "example": "@ivanov"
                 },
-                "owner_id": {
+                "timezone": {
                     "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                    "example": "Europe/Moscow"
+                }
+            }
+        },
+        "models.OwnerContactsInput": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "ivanov@example.com"
                 },
-                "tags": {
+                "notify_channels": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     },
                     "example": [
                         "telegram",
-                        "bot"
+                        "email"
                     ]
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+79991234567"
+                },
+                "telegram": {
+                    "type": "string",
+                    "maxLength": 33,
+                    "example": "@ivanov"
+                },
+                "timezone": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "Europe/Moscow"
                 }
             }
         },
-        "bot_handler.UpdateBotRequest": {
+        "models.OwnerPage": {
             "type": "object",
             "properties": {
-                "bot_type": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Owner"
+                    }
+                },
+                "next_cursor": {
                     "type": "string",
-                    "enum": [
-                        "AI",
-                        "Backend",
-                        "Frontend",
-                        "Robot"
-                    ],
-                    "example": "AI"
+                    "example": "eyJ0IjoiMjAyNS0wMS0xNVQxMjowMDowMFoiLCJpZCI6IjEyMzQ1In0"
                 },
-                "code": {
+                "total": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "models.OwnerPurgeReport": {
+            "type": "object",
+            "properties": {
+                "bots_detached": {
+                    "description": "BotsDetached — боты владельца остаются, но теряют привязку к нему (owner_id станет NULL)",
+                    "type": "integer",
+                    "example": 3
+                },
+                "full_name": {
                     "type": "string",
-                    "maxLength": 50,
-                    "minLength": 2,
-                    "example": "BOT_002"
+                    "example": "Иван Иванов"
                 },
-                "description": {
+                "owner_id": {
                     "type": "string",
-                    "example": "Обновлённое описание"
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
-                "is_active": {
+                "purged": {
+                    "description": "Purged — удаление выполнено (false — предварительный отчёт)",
                     "type": "boolean",
                     "example": false
                 },
-                "language": {
-                    "type": "string",
-                    "enum": [
-                        "Python",
-                        "Go",
-                        "N8N",
-                        "PIX",
-                        "JS",
-                        "C",
-                        "Other"
-                    ],
-                    "example": "Go"
+                "team_memberships": {
+                    "description": "TeamMemberships — членство владельца в командах, которое удаляется вместе с ним",
+                    "type": "integer",
+                    "example": 1
                 },
-                "name": {
+                "tokens": {
+                    "description": "Tokens — токены владельца, которые удаляются вместе с ним",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.RedactionCount": {
+            "type": "object",
+            "properties": {
+                "bot_code": {
                     "type": "string",
-                    "maxLength": 255,
-                    "minLength": 2,
-                    "example": "Discord Bot"
+                    "example": "PROJ001"
                 },
-                "owner_id": {
+                "bot_id": {
                     "type": "string",
+                    "format": "uuid",
                     "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "discord",
-                        "ai"
-                    ]
+                "count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "rule": {
+                    "type": "string",
+                    "example": "phone"
                 }
             }
         },
-        "eff_run_handler.CreateEffRunRequest": {
+        "models.RedactionRule": {
             "type": "object",
-            "required": [
-                "status"
-            ],
             "properties": {
-                "extra": {
-                    "$ref": "#/definitions/models.JSONB"
+                "bot_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
-                "host": {
+                "created_at": {
                     "type": "string",
-                    "example": "server-01"
+                    "example": "2023-01-15T12:00:00Z"
                 },
-                "period_from": {
+                "id": {
                     "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
-                "period_to": {
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
                     "type": "string",
-                    "example": "2024-01-01T01:00:00Z"
+                    "example": "contract_number"
                 },
-                "status": {
+                "pattern": {
                     "type": "string",
-                    "enum": [
-                        "success",
-                        "warning",
-                        "error"
-                    ],
-                    "example": "success"
+                    "example": "Договор № (?P\u003cpii\u003e\\d{6,})"
+                },
+                "replacement": {
+                    "type": "string",
+                    "example": "[договор]"
                 }
             }
         },
-        "log_handler.CreateLogRequest": {
+        "models.Team": {
             "type": "object",
-            "required": [
-                "msg",
-                "status"
-            ],
             "properties": {
-                "msg": {
+                "bot_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "created_at": {
                     "type": "string",
-                    "minLength": 1,
-                    "example": "Операция выполнена успешно"
+                    "example": "2023-01-15T12:00:00Z"
                 },
-                "status": {
+                "description": {
                     "type": "string",
-                    "enum": [
-                        "Debug",
-                        "Info",
-                        "Warning",
-                        "Error",
-                        "Critical"
-                    ],
-                    "example": "Info"
-                }
-            }
-        },
-        "models.Bot": {
-            "type": "object",
-            "required": [
-                "bot_type",
-                "code",
-                "language",
-                "name"
-            ],
-            "properties": {
-                "bot_type": {
+                    "example": "Боты платёжного контура"
+                },
+                "id": {
                     "type": "string",
-                    "enum": [
-                        "AI",
-                        "Backend",
-                        "Frontend",
-                        "Robot"
-                    ],
-                    "example": "Backend"
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
-                "code": {
+                "member_count": {
+                    "description": "MemberCount и BotCount — число участников и ботов (не в архиве) команды",
+                    "type": "integer",
+                    "example": 4
+                },
+                "members": {
+                    "description": "Members — участники команды; заполняется только при получении одной команды",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TeamMember"
+                    }
+                },
+                "name": {
                     "type": "string",
-                    "example": "BOT_001"
+                    "example": "Платежи"
                 },
-                "created_at": {
+                "notifications": {
+                    "$ref": "#/definitions/models.TeamNotifications"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-15T12:00:00Z"
+                }
+            }
+        },
+        "models.TeamMember": {
+            "type": "object",
+            "properties": {
+                "added_at": {
                     "type": "string",
                     "example": "2023-01-15T12:00:00Z"
                 },
-                "description": {
+                "full_name": {
                     "type": "string",
-                    "example": "Бот для обработки сообщений"
+                    "example": "Иван Иванов"
                 },
-                "id": {
+                "owner_id": {
                     "type": "string",
                     "format": "uuid",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "is_active": {
-                    "type": "boolean",
-                    "example": true
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
                 },
-                "language": {
+                "role": {
                     "type": "string",
                     "enum": [
-                        "Python",
-                        "Go",
-                        "N8N",
-                        "PIX",
-                        "JS",
-                        "C",
-                        "Other"
+                        "lead",
+                        "member"
                     ],
-                    "example": "Python"
-                },
-                "name": {
-                    "type": "string",
-                    "example": "Telegram Bot"
-                },
-                "owner_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "tags": {
+                    "example": "member"
+                }
+            }
+        },
+        "models.TeamNotifications": {
+            "type": "object",
+            "properties": {
+                "states": {
+                    "description": "States — состояния, переход бота в которые вызывает уведомление",
                     "type": "array",
                     "items": {
                         "type": "string"
                     },
                     "example": [
-                        "telegram",
-                        "automation"
+                        "failing",
+                        "silent"
                     ]
                 },
-                "updated_at": {
+                "webhook_url": {
+                    "description": "WebhookURL — адрес для POST-уведомлений; нет — уведомления не отправляются",
                     "type": "string",
-                    "example": "2023-01-15T12:00:00Z"
+                    "example": "https://hooks.example.com/payments"
                 }
             }
         },
-        "models.EffRun": {
+        "models.Token": {
             "type": "object",
             "required": [
-                "bot_id"
+                "name"
             ],
             "properties": {
                 "bot_id": {
@@ -1384,95 +8348,145 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2023-01-15T12:00:00Z"
                 },
-                "extra": {
-                    "type": "object"
-                },
-                "host": {
-                    "type": "string",
-                    "example": "server-01"
-                },
                 "id": {
                     "type": "string",
                     "format": "uuid",
                     "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
-                "period_from": {
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "is_admin": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "name": {
                     "type": "string",
-                    "example": "2023-01-15T10:00:00Z"
+                    "maxLength": 100,
+                    "minLength": 3,
+                    "example": "Production Server"
                 },
-                "period_to": {
+                "owner_id": {
                     "type": "string",
-                    "example": "2023-01-15T12:00:00Z"
+                    "format": "uuid",
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
                 },
-                "status": {
+                "team_id": {
                     "type": "string",
-                    "enum": [
-                        "success",
-                        "warning",
-                        "error"
-                    ],
-                    "example": "success"
+                    "format": "uuid",
+                    "example": "9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"
                 }
             }
         },
-        "models.JSONB": {
-            "type": "object",
-            "additionalProperties": true
-        },
-        "models.Log": {
+        "models.VersionComparison": {
             "type": "object",
-            "required": [
-                "msg"
-            ],
             "properties": {
                 "bot_id": {
                     "type": "string",
-                    "format": "uuid",
                     "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
-                "created_at": {
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VersionStats"
+                    }
+                }
+            }
+        },
+        "models.VersionStats": {
+            "type": "object",
+            "properties": {
+                "deployments": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "error_logs": {
+                    "type": "integer",
+                    "example": 27
+                },
+                "error_rate": {
+                    "description": "ErrorRate — доля логов Error и Critical; нет — логов не было",
+                    "type": "number",
+                    "example": 0.005
+                },
+                "first_deployed_at": {
+                    "description": "FirstDeployedAt и LastDeployedAt — первая и последняя выкладка версии",
                     "type": "string",
                     "example": "2023-01-15T12:00:00Z"
                 },
-                "id": {
+                "last_deployed_at": {
+                    "type": "string",
+                    "example": "2023-01-16T12:00:00Z"
+                },
+                "logs": {
                     "type": "integer",
-                    "example": 12345
+                    "example": 5400
                 },
-                "msg": {
-                    "type": "string",
-                    "example": "Операция выполнена успешно"
+                "runs": {
+                    "type": "integer",
+                    "example": 120
                 },
-                "status": {
+                "runs_error": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "runs_success": {
+                    "type": "integer",
+                    "example": 110
+                },
+                "runs_warning": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "success_rate": {
+                    "description": "SuccessRate — доля успешных запусков; нет — запусков не было",
+                    "type": "number",
+                    "example": 0.9167
+                },
+                "version": {
                     "type": "string",
-                    "enum": [
-                        "Debug",
-                        "Info",
-                        "Warning",
-                        "Error",
-                        "Critical"
-                    ],
-                    "example": "Info"
+                    "example": "1.4.2"
                 }
             }
         },
-        "models.Owner": {
+        "owner_handler.CreateOwnerRequest": {
             "type": "object",
             "required": [
                 "full_name"
             ],
             "properties": {
-                "created_at": {
-                    "type": "string",
-                    "example": "2023-01-15T12:00:00Z"
+                "contacts": {
+                    "$ref": "#/definitions/models.OwnerContactsInput"
                 },
                 "full_name": {
                     "type": "string",
+                    "maxLength": 255,
+                    "minLength": 2,
                     "example": "Иван Иванов"
                 },
-                "id": {
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "owner_handler.UpdateOwnerRequest": {
+            "type": "object",
+            "properties": {
+                "contacts": {
+                    "description": "Contacts — нет: контакты без изменений; есть: заменяют все контакты (не указанные удаляются)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.OwnerContactsInput"
+                        }
+                    ]
+                },
+                "full_name": {
                     "type": "string",
-                    "format": "uuid",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                    "maxLength": 255,
+                    "minLength": 2,
+                    "example": "Иван Петров"
                 },
                 "is_active": {
                     "type": "boolean",
@@ -1480,72 +8494,183 @@ const docTemplate = `{
                 }
             }
         },
-        "models.Token": {
+        "partition_handler.PartitionsResponse": {
+            "type": "object",
+            "properties": {
+                "partitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LogPartition"
+                    }
+                },
+                "retention_days": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "total_bytes": {
+                    "type": "integer",
+                    "example": 1073741824
+                }
+            }
+        },
+        "profile_handler.UpdateProfileRequest": {
+            "type": "object",
+            "properties": {
+                "contacts": {
+                    "description": "Contacts — нет: контакты без изменений; есть: заменяют все контакты (не указанные удаляются)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.OwnerContactsInput"
+                        }
+                    ]
+                },
+                "full_name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 2,
+                    "example": "Иван Петров"
+                }
+            }
+        },
+        "redaction_handler.CreateRedactionRuleRequest": {
             "type": "object",
             "required": [
-                "name"
+                "name",
+                "pattern"
             ],
             "properties": {
                 "bot_id": {
                     "type": "string",
-                    "format": "uuid",
                     "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
-                "created_at": {
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
                     "type": "string",
-                    "example": "2023-01-15T12:00:00Z"
+                    "maxLength": 100,
+                    "minLength": 1,
+                    "example": "contract_number"
                 },
-                "id": {
+                "pattern": {
                     "type": "string",
-                    "format": "uuid",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                    "maxLength": 1000,
+                    "example": "Договор № (?P\u003cpii\u003e\\d{6,})"
                 },
+                "replacement": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "[договор]"
+                }
+            }
+        },
+        "redaction_handler.UpdateRedactionRuleRequest": {
+            "type": "object",
+            "properties": {
                 "is_active": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "is_admin": {
                     "type": "boolean",
                     "example": false
                 },
                 "name": {
                     "type": "string",
                     "maxLength": 100,
-                    "minLength": 3,
-                    "example": "Production Server"
+                    "minLength": 1,
+                    "example": "contract_number"
+                },
+                "pattern": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Договор № (?P\u003cpii\u003e\\d{6,})"
+                },
+                "replacement": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "[договор]"
                 }
             }
         },
-        "owner_handler.CreateOwnerRequest": {
+        "team_handler.CreateTeamRequest": {
             "type": "object",
             "required": [
-                "full_name"
+                "name"
             ],
             "properties": {
-                "full_name": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Боты платёжного контура"
+                },
+                "name": {
                     "type": "string",
                     "maxLength": 255,
                     "minLength": 2,
-                    "example": "Иван Иванов"
+                    "example": "Платежи"
                 },
-                "is_active": {
-                    "type": "boolean",
-                    "example": true
+                "notifications": {
+                    "description": "Notifications — нет: без webhook, уведомления о переходе в failing и silent",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/team_handler.TeamNotificationsRequest"
+                        }
+                    ]
                 }
             }
         },
-        "owner_handler.UpdateOwnerRequest": {
+        "team_handler.SetMemberRequest": {
             "type": "object",
+            "required": [
+                "role"
+            ],
             "properties": {
-                "full_name": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "lead",
+                        "member"
+                    ],
+                    "example": "member"
+                }
+            }
+        },
+        "team_handler.TeamNotificationsRequest": {
+            "type": "object",
+            "required": [
+                "states"
+            ],
+            "properties": {
+                "states": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "failing",
+                        "silent"
+                    ]
+                },
+                "webhook_url": {
+                    "type": "string",
+                    "maxLength": 2048,
+                    "example": "https://hooks.example.com/payments"
+                }
+            }
+        },
+        "team_handler.UpdateTeamRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Боты платёжного контура и биллинга"
+                },
+                "name": {
                     "type": "string",
                     "maxLength": 255,
                     "minLength": 2,
-                    "example": "Иван Петров"
-                },
-                "is_active": {
-                    "type": "boolean",
-                    "example": true
+                    "example": "Платежи и биллинг"
                 }
             }
         }
@@ -0,0 +1,14 @@
+package archive_handler
+
+type ImportArchiveRequest struct {
+	From   string   `json:"from" binding:"required,datetime=2006-01-02" example:"2025-01-01"`
+	To     string   `json:"to" binding:"required,datetime=2006-01-02" example:"2025-01-31"`
+	BotID  *string  `json:"bot_id,omitempty" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Levels []string `json:"levels,omitempty" binding:"omitempty,dive,oneof=Debug Info Warning Error Critical" example:"Error,Critical"`
+}
+
+type GetManifestsQuery struct {
+	From  string  `form:"from" binding:"required,datetime=2006-01-02" example:"2025-01-01"`
+	To    string  `form:"to" binding:"required,datetime=2006-01-02" example:"2025-01-31"`
+	BotID *string `form:"bot_id" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
@@ -0,0 +1,138 @@
+package archive_handler
+
+import (
+	"net/http"
+	"time"
+
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/validator_error_handling"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ArchiveService interface {
+	GetManifests(from, to time.Time, botID *string) ([]*models.ArchiveManifest, error)
+	ImportRange(from, to time.Time, botID *string, levels []string) (*models.ArchiveImport, error)
+	DropImport(table string) error
+}
+
+type ArchiveHandler struct {
+	archiveService ArchiveService
+}
+
+func NewArchiveHandler(archiveService ArchiveService) *ArchiveHandler {
+	return &ArchiveHandler{
+		archiveService: archiveService,
+	}
+}
+
+// @Summary Манифесты архива
+// @Description Возвращает манифесты архивных файлов (по боту и дню) за диапазон дат в UTC (требуется админский токен)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param from query string true "Начало диапазона (YYYY-MM-DD)"
+// @Param to query string true "Конец диапазона включительно (YYYY-MM-DD)"
+// @Param bot_id query string false "ID бота (UUID)"
+// @Success 200 {array} models.ArchiveManifest
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/archives/manifests [get]
+func (h *ArchiveHandler) GetManifests(c *gin.Context) {
+	var query GetManifestsQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	from, _ := time.Parse("2006-01-02", query.From)
+	to, _ := time.Parse("2006-01-02", query.To)
+
+	manifests, err := h.archiveService.GetManifests(from, to, query.BotID)
+	if err != nil {
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, manifests)
+}
+
+// @Summary Загрузить архив во временную таблицу
+// @Description Загружает архивные логи за диапазон дат (UTC) во временную таблицу archive_import_* для расследования (требуется админский токен)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ImportArchiveRequest true "Диапазон и фильтры"
+// @Success 201 {object} models.ArchiveImport
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/archives/import [post]
+func (h *ArchiveHandler) ImportArchive(c *gin.Context) {
+	var request ImportArchiveRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	from, _ := time.Parse("2006-01-02", request.From)
+	to, _ := time.Parse("2006-01-02", request.To)
+
+	result, err := h.archiveService.ImportRange(from, to, request.BotID, request.Levels)
+	if err != nil {
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// @Summary Удалить временную таблицу импорта
+// @Description Удаляет таблицу archive_import_*, созданную при загрузке архива (требуется админский токен)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param table path string true "Имя таблицы импорта"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/archives/imports/{table} [delete]
+func (h *ArchiveHandler) DropImport(c *gin.Context) {
+	table := c.Param("table")
+
+	if err := h.archiveService.DropImport(table); err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "таблица импорта удалена"})
+}
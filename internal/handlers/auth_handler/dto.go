@@ -2,6 +2,8 @@ package auth_handler
 
 type CreateTokenRequest struct {
 	BotID     *string `json:"bot_id,omitempty" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TeamID    *string `json:"team_id,omitempty" binding:"omitempty,uuid" example:"9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"`
+	OwnerID   *string `json:"owner_id,omitempty" binding:"omitempty,uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
 	TokenName string  `json:"token_name" binding:"required,min=3,max=100" example:"Production Server"`
 	IsAdmin   bool    `json:"is_admin" example:"false"`
 }
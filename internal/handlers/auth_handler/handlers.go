@@ -11,7 +11,7 @@ import (
 )
 
 type AuthService interface {
-	CreateToken(botID *string, tokenName string, isAdmin bool) (*models.Token, error)
+	CreateToken(botID, teamID, ownerID *string, tokenName string, isAdmin bool) (*models.Token, error)
 	UpdateToken(tokenID, newName string) (*models.Token, error)
 	DeactivateToken(tokenID string) error
 	DeleteToken(tokenID string) error
@@ -29,7 +29,9 @@ func NewAuthHandler(authService AuthService) *AuthHandler {
 }
 
 // @Summary Создать новый токен
-// @Description Создаёт новый токен аутентификации для указанного бота (требуется админский токен)
+// @Description Создаёт новый токен аутентификации для указанного бота (требуется админский токен).
+// @Description Токен с team_id — токен команды: только чтение логов, запусков и состояния всех ботов команды.
+// @Description Токен с owner_id — токен владельца: только просмотр и изменение своего профиля (/v1/profile).
 // @Tags tokens
 // @Accept json
 // @Produce json
@@ -40,6 +42,7 @@ func NewAuthHandler(authService AuthService) *AuthHandler {
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /v1/tokens [post]
 func (h *AuthHandler) CreateToken(c *gin.Context) {
@@ -59,12 +62,20 @@ func (h *AuthHandler) CreateToken(c *gin.Context) {
 		return
 	}
 
-	token, err := h.authService.CreateToken(request.BotID, request.TokenName, request.IsAdmin)
+	token, err := h.authService.CreateToken(request.BotID, request.TeamID, request.OwnerID, request.TokenName, request.IsAdmin)
 	if err != nil {
 		if customerrors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if customerrors.IsConflict(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -3,22 +3,52 @@ package bot_handler
 type CreateBotRequest struct {
 	Code        string   `json:"code" binding:"required,min=2,max=50" example:"BOT_001"`
 	Name        string   `json:"name" binding:"required,min=2,max=255" example:"Telegram Bot"`
-	BotType     string   `json:"bot_type" binding:"required,oneof=AI Backend Frontend Robot" example:"Backend"`
-	Language    string   `json:"language" binding:"required,oneof=Python Go N8N PIX JS C Other" example:"Python"`
+	BotType     string   `json:"bot_type" binding:"required,max=50" example:"Backend"`
+	Language    string   `json:"language" binding:"required,max=50" example:"Python"`
 	Description *string  `json:"description,omitempty" example:"Бот для обработки сообщений"`
 	Tags        []string `json:"tags,omitempty" example:"telegram,bot"`
 	OwnerID     *string  `json:"owner_id,omitempty" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TeamID      *string  `json:"team_id,omitempty" binding:"omitempty,uuid" example:"9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"`
 	IsActive    bool     `json:"is_active" example:"true"`
 }
 
 type UpdateBotRequest struct {
 	Code        *string  `json:"code,omitempty" binding:"omitempty,min=2,max=50" example:"BOT_002"`
 	Name        *string  `json:"name,omitempty" binding:"omitempty,min=2,max=255" example:"Discord Bot"`
-	BotType     *string  `json:"bot_type,omitempty" binding:"omitempty,oneof=AI Backend Frontend Robot" example:"AI"`
-	Language    *string  `json:"language,omitempty" binding:"omitempty,oneof=Python Go N8N PIX JS C Other" example:"Go"`
+	BotType     *string  `json:"bot_type,omitempty" binding:"omitempty,max=50" example:"AI"`
+	Language    *string  `json:"language,omitempty" binding:"omitempty,max=50" example:"Go"`
 	Description *string  `json:"description,omitempty" example:"Обновлённое описание"`
 	Tags        []string `json:"tags,omitempty" example:"discord,ai"`
 	OwnerID     *string  `json:"owner_id,omitempty" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TeamID      *string  `json:"team_id,omitempty" binding:"omitempty,uuid" example:"9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"`
 	IsActive    *bool    `json:"is_active,omitempty" example:"false"`
 }
 
+type TransferBotRequest struct {
+	OwnerID string  `json:"owner_id" binding:"required,uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Reason  *string `json:"reason,omitempty" binding:"omitempty,max=500" example:"Переход в команду платежей"`
+}
+
+type SetBotTeamRequest struct {
+	TeamID string `json:"team_id" binding:"required,uuid" example:"9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"`
+}
+
+type UnassignOwnerQuery struct {
+	Reason *string `form:"reason" binding:"omitempty,max=500" example:"Владелец уволился"`
+}
+
+type ListBotsQuery struct {
+	BotType   *string  `form:"bot_type" binding:"omitempty,max=50" example:"Backend"`
+	Language  *string  `form:"language" binding:"omitempty,max=50" example:"Python"`
+	OwnerID   *string  `form:"owner_id" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TeamID    *string  `form:"team_id" binding:"omitempty,uuid" example:"9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"`
+	IsActive  *bool    `form:"is_active" example:"true"`
+	Tags      []string `form:"tags" binding:"omitempty,dive,min=1,max=100" example:"telegram"`
+	TagsMatch string   `form:"tags_match" binding:"omitempty,oneof=any all" example:"any"`
+	Q         string   `form:"q" binding:"omitempty,max=255" example:"telegram"`
+	Archived  string   `form:"archived" binding:"omitempty,oneof=exclude include only" example:"exclude"`
+	Sort      string   `form:"sort" binding:"omitempty,oneof=created_at updated_at code name" example:"code"`
+	Order     string   `form:"order" binding:"omitempty,oneof=asc desc" example:"asc"`
+	Limit     int      `form:"limit" binding:"omitempty,min=1,max=1000" example:"100"`
+	Cursor    string   `form:"cursor"`
+}
@@ -14,9 +14,16 @@ type BotService interface {
 	CreateBot(bot *models.Bot) (*models.Bot, error)
 	GetBotByID(botID string) (*models.Bot, error)
 	GetBotByCode(code string) (*models.Bot, error)
-	GetAllBots() ([]*models.Bot, error)
-	UpdateBot(bot *models.Bot) (*models.Bot, error)
-	DeleteBot(botID string) error
+	ListBots(filter *models.BotFilter, cursor string) (*models.BotPage, error)
+	ListAllBots(filter *models.BotFilter) ([]*models.Bot, error)
+	UpdateBot(bot *models.Bot, changedBy string) (*models.Bot, error)
+	TransferBot(botID string, ownerID *string, changedBy string, reason *string) (*models.Bot, error)
+	GetOwnerHistory(botID string) ([]*models.BotOwnerChange, error)
+	SetBotTeam(botID string, teamID *string) (*models.Bot, error)
+	ArchiveBot(botID, archivedBy string) (*models.Bot, error)
+	RestoreBot(botID string) (*models.Bot, error)
+	GetBotPurgeReport(botID string) (*models.BotPurgeReport, error)
+	PurgeBot(botID string) (*models.BotPurgeReport, error)
 }
 
 type BotHandler struct {
@@ -30,7 +37,8 @@ func NewBotHandler(botService BotService) *BotHandler {
 }
 
 // @Summary Создать бота
-// @Description Создаёт нового бота/робота (требуется админский токен)
+// @Description Создаёт нового бота/робота (требуется админский токен).
+// @Description Тип и язык должны быть в справочниках /v1/bot-types и /v1/bot-languages.
 // @Tags bots
 // @Accept json
 // @Produce json
@@ -62,11 +70,16 @@ func (h *BotHandler) CreateBot(c *gin.Context) {
 		Description: request.Description,
 		Tags:        request.Tags,
 		OwnerID:     request.OwnerID,
+		TeamID:      request.TeamID,
 		IsActive:    request.IsActive,
 	}
 
 	createdBot, err := h.botService.CreateBot(bot)
 	if err != nil {
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -102,28 +115,85 @@ func (h *BotHandler) GetBot(c *gin.Context) {
 	c.JSON(http.StatusOK, bot)
 }
 
-// @Summary Получить всех ботов
-// @Description Возвращает список всех ботов (требуется админский токен)
+// @Summary Получить ботов
+// @Description Возвращает ботов с фильтрами и постраничной выдачей (требуется админский токен).
+// @Description total — число ботов, подходящих под фильтр. По умолчанию новые первыми; code и name сортируются по возрастанию.
+// @Description Без limit и cursor ответ — массив всех подходящих ботов (прежний формат, для совместимости со старыми клиентами).
 // @Tags bots
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} models.Bot
+// @Param bot_type query string false "Тип бота из справочника /v1/bot-types"
+// @Param language query string false "Язык из справочника /v1/bot-languages"
+// @Param owner_id query string false "ID владельца (UUID)"
+// @Param team_id query string false "ID команды (UUID)"
+// @Param is_active query bool false "Активность"
+// @Param tags query []string false "Теги" collectionFormat(multi)
+// @Param tags_match query string false "any — хотя бы один из тегов (по умолчанию), all — все теги" Enums(any, all)
+// @Param q query string false "Подстрока в code, name или description (без учёта регистра)"
+// @Param archived query string false "Боты в архиве: exclude — скрыть (по умолчанию), include — показать вместе с остальными, only — только они" Enums(exclude, include, only)
+// @Param sort query string false "Поле сортировки (по умолчанию created_at)" Enums(created_at, updated_at, code, name)
+// @Param order query string false "Направление сортировки" Enums(asc, desc)
+// @Param limit query int false "Размер страницы (по умолчанию 100, максимум 1000)"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor (с теми же sort и order)"
+// @Success 200 {object} models.BotPage
+// @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /v1/bots [get]
-func (h *BotHandler) GetAllBots(c *gin.Context) {
-	bots, err := h.botService.GetAllBots()
+func (h *BotHandler) ListBots(c *gin.Context) {
+	var query ListBotsQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	filter := &models.BotFilter{
+		BotType:  query.BotType,
+		Language: query.Language,
+		OwnerID:  query.OwnerID,
+		TeamID:   query.TeamID,
+		IsActive: query.IsActive,
+		Tags:     query.Tags,
+		TagsAll:  query.TagsMatch == "all",
+		Query:    query.Q,
+		Archived: query.Archived,
+		SortBy:   query.Sort,
+		Order:    query.Order,
+		Limit:    query.Limit,
+	}
+
+	// Без параметров постраничной выдачи — прежний формат ответа: массив всех подходящих записей
+	if query.Limit == 0 && query.Cursor == "" {
+		items, err := h.botService.ListAllBots(filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, items)
+		return
+	}
+
+	page, err := h.botService.ListBots(filter, query.Cursor)
 	if err != nil {
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, bots)
+	c.JSON(http.StatusOK, page)
 }
 
 // @Summary Обновить бота
-// @Description Обновляет данные бота (требуется админский токен)
+// @Description Обновляет данные бота (требуется админский токен). Смена owner_id — это передача бота: она записывается в историю владельцев.
 // @Tags bots
 // @Accept json
 // @Produce json
@@ -135,6 +205,7 @@ func (h *BotHandler) GetAllBots(c *gin.Context) {
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /v1/bots/{bot_id} [put]
 func (h *BotHandler) UpdateBot(c *gin.Context) {
@@ -181,11 +252,113 @@ func (h *BotHandler) UpdateBot(c *gin.Context) {
 	if request.OwnerID != nil {
 		existingBot.OwnerID = request.OwnerID
 	}
+	if request.TeamID != nil {
+		existingBot.TeamID = request.TeamID
+	}
 	if request.IsActive != nil {
 		existingBot.IsActive = *request.IsActive
 	}
 
-	updatedBot, err := h.botService.UpdateBot(existingBot)
+	updatedBot, err := h.botService.UpdateBot(existingBot, c.GetString("token_id"))
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedBot)
+}
+
+// @Summary Передать бота владельцу
+// @Description Назначает боту владельца и записывает передачу в историю (требуется админский токен).
+// @Description Владелец должен существовать и не быть в архиве; передача текущему владельцу ничего не меняет.
+// @Tags bots
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id path string true "ID бота (UUID)"
+// @Param request body TransferBotRequest true "Новый владелец"
+// @Success 200 {object} models.Bot
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bots/{bot_id}/owner [put]
+func (h *BotHandler) TransferBot(c *gin.Context) {
+	botID := c.Param("bot_id")
+
+	var request TransferBotRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	bot, err := h.botService.TransferBot(botID, &request.OwnerID, c.GetString("token_id"), request.Reason)
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bot)
+}
+
+// @Summary Снять владельца бота
+// @Description Оставляет бота без владельца и записывает это в историю (требуется админский токен)
+// @Tags bots
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id path string true "ID бота (UUID)"
+// @Param reason query string false "Причина"
+// @Success 200 {object} models.Bot
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bots/{bot_id}/owner [delete]
+func (h *BotHandler) UnassignOwner(c *gin.Context) {
+	botID := c.Param("bot_id")
+
+	var query UnassignOwnerQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	bot, err := h.botService.TransferBot(botID, nil, c.GetString("token_id"), query.Reason)
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bot)
+}
+
+// @Summary История владельцев бота
+// @Description Возвращает передачи бота между владельцами, новые первыми (требуется админский токен)
+// @Tags bots
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id path string true "ID бота (UUID)"
+// @Success 200 {array} models.BotOwnerChange
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bots/{bot_id}/owners-history [get]
+func (h *BotHandler) GetOwnerHistory(c *gin.Context) {
+	botID := c.Param("bot_id")
+
+	changes, err := h.botService.GetOwnerHistory(botID)
 	if err != nil {
 		if customerrors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
@@ -195,28 +368,201 @@ func (h *BotHandler) UpdateBot(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, updatedBot)
+	c.JSON(http.StatusOK, changes)
 }
 
-// @Summary Удалить бота
-// @Description Полностью удаляет бота из базы данных (требуется админский токен)
+// @Summary Включить бота в команду
+// @Description Включает бота в команду; бот может состоять только в одной команде (требуется админский токен)
 // @Tags bots
+// @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param bot_id path string true "ID бота (UUID)"
-// @Success 200 {object} map[string]interface{}
+// @Param request body SetBotTeamRequest true "Команда"
+// @Success 200 {object} models.Bot
+// @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bots/{bot_id}/team [put]
+func (h *BotHandler) SetTeam(c *gin.Context) {
+	botID := c.Param("bot_id")
+
+	var request SetBotTeamRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	bot, err := h.botService.SetBotTeam(botID, &request.TeamID)
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bot)
+}
+
+// @Summary Исключить бота из команды
+// @Description Оставляет бота без команды (требуется админский токен)
+// @Tags bots
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id path string true "ID бота (UUID)"
+// @Success 200 {object} models.Bot
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bots/{bot_id}/team [delete]
+func (h *BotHandler) RemoveTeam(c *gin.Context) {
+	botID := c.Param("bot_id")
+
+	bot, err := h.botService.SetBotTeam(botID, nil)
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bot)
+}
+
+// writeTransferError пишет ответ для ошибки обновления или передачи бота
+func writeTransferError(c *gin.Context, err error) {
+	switch {
+	case customerrors.IsNotFound(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case customerrors.IsInvalidInput(err):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case customerrors.IsConflict(err):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// @Summary Перенести бота в архив
+// @Description Переносит бота в архив (требуется админский токен): бот скрывается из списков, его токены перестают
+// @Description приниматься, а логи и запуски сохраняются вместе с привязкой к боту. Повторный вызов ничего не меняет.
+// @Tags bots
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id path string true "ID бота (UUID)"
+// @Success 200 {object} models.Bot
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /v1/bots/{bot_id} [delete]
-func (h *BotHandler) DeleteBot(c *gin.Context) {
+func (h *BotHandler) ArchiveBot(c *gin.Context) {
+	botID := c.Param("bot_id")
+
+	bot, err := h.botService.ArchiveBot(botID, c.GetString("token_id"))
+	if err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, bot)
+}
+
+// @Summary Восстановить бота из архива
+// @Description Возвращает бота из архива (требуется админский токен). Для бота не в архиве ничего не меняет.
+// @Tags bots
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id path string true "ID бота (UUID)"
+// @Success 200 {object} models.Bot
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bots/{bot_id}/restore [post]
+func (h *BotHandler) RestoreBot(c *gin.Context) {
+	botID := c.Param("bot_id")
+
+	bot, err := h.botService.RestoreBot(botID)
+	if err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, bot)
+}
+
+// @Summary Что удалит окончательное удаление бота
+// @Description Считает записи, которые будут удалены вместе с ботом (токены, запуски, правила маскирования, политика
+// @Description хранения, счётчики и историю владельцев), и логи, которые потеряют привязку к боту. Ничего не удаляет (требуется админский токен).
+// @Tags bots
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id path string true "ID бота (UUID)"
+// @Success 200 {object} models.BotPurgeReport
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bots/{bot_id}/purge [get]
+func (h *BotHandler) GetPurgeReport(c *gin.Context) {
 	botID := c.Param("bot_id")
 
-	err := h.botService.DeleteBot(botID)
+	report, err := h.botService.GetBotPurgeReport(botID)
 	if err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Окончательно удалить бота
+// @Description Удаляет бота из базы данных вместе с токенами, запусками, правилами маскирования, политикой хранения, счётчиками и историей владельцев;
+// @Description логи остаются без привязки к боту. Удалить можно только бота в архиве (требуется админский токен).
+// @Description Возвращает отчёт о фактически удалённом.
+// @Tags bots
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id path string true "ID бота (UUID)"
+// @Success 200 {object} models.BotPurgeReport
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bots/{bot_id}/purge [delete]
+func (h *BotHandler) PurgeBot(c *gin.Context) {
+	botID := c.Param("bot_id")
+
+	report, err := h.botService.PurgeBot(botID)
+	if err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if customerrors.IsConflict(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "бот удалён"})
+	c.JSON(http.StatusOK, report)
 }
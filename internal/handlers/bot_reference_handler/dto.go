@@ -0,0 +1,12 @@
+package bot_reference_handler
+
+type CreateBotReferenceRequest struct {
+	Code        string  `json:"code" binding:"required,max=50" example:"UiPath"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=1000" example:"Роботы на платформе UiPath"`
+}
+
+type UpdateBotReferenceRequest struct {
+	// Code — новый код; переносится на всех ботов с прежним кодом
+	Code        *string `json:"code,omitempty" binding:"omitempty,min=1,max=50" example:"Kotlin"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=1000" example:"Сервисы на Kotlin"`
+}
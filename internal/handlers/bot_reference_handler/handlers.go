@@ -0,0 +1,246 @@
+package bot_reference_handler
+
+import (
+	"net/http"
+
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/validator_error_handling"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BotReferenceService interface {
+	ListReferences(kind string) ([]*models.BotReference, error)
+	CreateReference(kind, code string, description *string) (*models.BotReference, error)
+	UpdateReference(kind, code string, newCode, description *string) (*models.BotReference, error)
+	DeleteReference(kind, code string) error
+}
+
+// BotReferenceHandler обслуживает справочники типов (/v1/bot-types) и языков (/v1/bot-languages) ботов
+type BotReferenceHandler struct {
+	referenceService BotReferenceService
+}
+
+func NewBotReferenceHandler(referenceService BotReferenceService) *BotReferenceHandler {
+	return &BotReferenceHandler{
+		referenceService: referenceService,
+	}
+}
+
+// @Summary Получить типы ботов
+// @Description Возвращает справочник типов ботов с числом ботов, использующих каждое значение (требуется админский токен)
+// @Tags bot-types
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.BotReference
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bot-types [get]
+func (h *BotReferenceHandler) ListBotTypes(c *gin.Context) {
+	h.list(c, models.BotReferenceTypes)
+}
+
+// @Summary Добавить тип бота
+// @Description Добавляет значение в справочник типов ботов (требуется админский токен). Код уникален без учёта регистра.
+// @Tags bot-types
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateBotReferenceRequest true "Код и описание"
+// @Success 201 {object} models.BotReference
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bot-types [post]
+func (h *BotReferenceHandler) CreateBotType(c *gin.Context) {
+	h.create(c, models.BotReferenceTypes)
+}
+
+// @Summary Изменить тип бота
+// @Description Меняет код и описание значения справочника типов ботов (требуется админский токен).
+// @Description Новый код переносится на всех ботов с прежним кодом.
+// @Tags bot-types
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Тип бота"
+// @Param request body UpdateBotReferenceRequest true "Новые код и описание"
+// @Success 200 {object} models.BotReference
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bot-types/{code} [put]
+func (h *BotReferenceHandler) UpdateBotType(c *gin.Context) {
+	h.update(c, models.BotReferenceTypes)
+}
+
+// @Summary Удалить тип бота
+// @Description Удаляет значение из справочника типов ботов (требуется админский токен).
+// @Description Значение, которое используют боты (в том числе в архиве), удалить нельзя.
+// @Tags bot-types
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Тип бота"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bot-types/{code} [delete]
+func (h *BotReferenceHandler) DeleteBotType(c *gin.Context) {
+	h.delete(c, models.BotReferenceTypes)
+}
+
+// @Summary Получить языки ботов
+// @Description Возвращает справочник языков ботов с числом ботов, использующих каждое значение (требуется админский токен)
+// @Tags bot-languages
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.BotReference
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bot-languages [get]
+func (h *BotReferenceHandler) ListBotLanguages(c *gin.Context) {
+	h.list(c, models.BotReferenceLanguages)
+}
+
+// @Summary Добавить язык бота
+// @Description Добавляет значение в справочник языков ботов (требуется админский токен). Код уникален без учёта регистра.
+// @Tags bot-languages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateBotReferenceRequest true "Код и описание"
+// @Success 201 {object} models.BotReference
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bot-languages [post]
+func (h *BotReferenceHandler) CreateBotLanguage(c *gin.Context) {
+	h.create(c, models.BotReferenceLanguages)
+}
+
+// @Summary Изменить язык бота
+// @Description Меняет код и описание значения справочника языков ботов (требуется админский токен).
+// @Description Новый код переносится на всех ботов с прежним кодом.
+// @Tags bot-languages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Язык бота"
+// @Param request body UpdateBotReferenceRequest true "Новые код и описание"
+// @Success 200 {object} models.BotReference
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bot-languages/{code} [put]
+func (h *BotReferenceHandler) UpdateBotLanguage(c *gin.Context) {
+	h.update(c, models.BotReferenceLanguages)
+}
+
+// @Summary Удалить язык бота
+// @Description Удаляет значение из справочника языков ботов (требуется админский токен).
+// @Description Значение, которое используют боты (в том числе в архиве), удалить нельзя.
+// @Tags bot-languages
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Язык бота"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bot-languages/{code} [delete]
+func (h *BotReferenceHandler) DeleteBotLanguage(c *gin.Context) {
+	h.delete(c, models.BotReferenceLanguages)
+}
+
+func (h *BotReferenceHandler) list(c *gin.Context, kind string) {
+	references, err := h.referenceService.ListReferences(kind)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, references)
+}
+
+func (h *BotReferenceHandler) create(c *gin.Context, kind string) {
+	var request CreateBotReferenceRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	reference, err := h.referenceService.CreateReference(kind, request.Code, request.Description)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, reference)
+}
+
+func (h *BotReferenceHandler) update(c *gin.Context, kind string) {
+	var request UpdateBotReferenceRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	reference, err := h.referenceService.UpdateReference(kind, c.Param("code"), request.Code, request.Description)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reference)
+}
+
+func (h *BotReferenceHandler) delete(c *gin.Context, kind string) {
+	if err := h.referenceService.DeleteReference(kind, c.Param("code")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "значение удалено из справочника"})
+}
+
+// writeError пишет ответ для ошибки сервиса справочников
+func writeError(c *gin.Context, err error) {
+	switch {
+	case customerrors.IsNotFound(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case customerrors.IsInvalidInput(err):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case customerrors.IsConflict(err):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,17 @@
+package catalog_handler
+
+const (
+	formatYAML = "yaml"
+	formatJSON = "json"
+)
+
+type ExportCatalogQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=yaml json" example:"yaml"`
+}
+
+type ImportCatalogQuery struct {
+	// DryRun — только показать изменения, ничего не меняя
+	DryRun bool `form:"dry_run" example:"true"`
+	// Prune — перенести в архив ботов, которых нет в файле
+	Prune bool `form:"prune" example:"false"`
+}
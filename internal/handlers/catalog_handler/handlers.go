@@ -0,0 +1,127 @@
+package catalog_handler
+
+import (
+	"net/http"
+	"strings"
+
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/validator_error_handling"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type CatalogService interface {
+	Export() (*models.Catalog, error)
+	Import(catalog *models.Catalog, dryRun, prune bool, changedBy string) (*models.CatalogImportResult, error)
+}
+
+type CatalogHandler struct {
+	catalogService CatalogService
+}
+
+func NewCatalogHandler(catalogService CatalogService) *CatalogHandler {
+	return &CatalogHandler{
+		catalogService: catalogService,
+	}
+}
+
+// @Summary Выгрузить каталог ботов
+// @Description Выгружает владельцев и ботов не в архиве (теги, активность, владелец, политика хранения логов) декларативным
+// @Description документом YAML или JSON — для хранения в git и загрузки через POST /v1/bots/import (требуется админский токен)
+// @Tags bots
+// @Produce application/yaml
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "Формат (по умолчанию yaml)" Enums(yaml, json)
+// @Success 200 {object} models.Catalog
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bots/export [get]
+func (h *CatalogHandler) ExportCatalog(c *gin.Context) {
+	var query ExportCatalogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	catalog, err := h.catalogService.Export()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if query.Format == formatJSON {
+		c.Header("Content-Disposition", `attachment; filename="bots.json"`)
+		c.JSON(http.StatusOK, catalog)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="bots.yaml"`)
+	c.YAML(http.StatusOK, catalog)
+}
+
+// @Summary Загрузить каталог ботов
+// @Description Приводит владельцев и ботов к каталогу в формате GET /v1/bots/export (требуется админский токен).
+// @Description Тело — YAML (Content-Type: application/yaml) или JSON. Владельцы сопоставляются по full_name, боты — по code:
+// @Description недостающие создаются, отличающиеся обновляются (смена владельца записывается в историю передачи).
+// @Description С prune=true боты не из файла переносятся в архив. Все изменения применяются одной транзакцией;
+// @Description с dry_run=true только возвращается список изменений.
+// @Tags bots
+// @Accept application/yaml
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.Catalog true "Каталог"
+// @Param dry_run query bool false "Только показать изменения"
+// @Param prune query bool false "Перенести в архив ботов, которых нет в файле"
+// @Success 200 {object} models.CatalogImportResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bots/import [post]
+func (h *CatalogHandler) ImportCatalog(c *gin.Context) {
+	var query ImportCatalogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	var bodyBinding binding.BindingBody = binding.JSON
+	if strings.Contains(c.ContentType(), formatYAML) {
+		bodyBinding = binding.YAML
+	}
+
+	var catalog models.Catalog
+	if err := c.ShouldBindBodyWith(&catalog, bodyBinding); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	result, err := h.catalogService.Import(&catalog, query.DryRun, query.Prune, c.GetString("token_id"))
+	if err != nil {
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,26 @@
+package deployment_handler
+
+import "time"
+
+type CreateDeploymentRequest struct {
+	Version   string  `json:"version" binding:"required,max=100" example:"1.4.2"`
+	GitSHA    *string `json:"git_sha,omitempty" binding:"omitempty,hexadecimal,min=7,max=40" example:"9fceb02d0ae598e95dc970b74767f19372d61af8"`
+	Host      *string `json:"host,omitempty" binding:"omitempty,max=255" example:"server-01"`
+	Changelog *string `json:"changelog,omitempty" binding:"omitempty,max=10000" example:"Исправлена обработка таймаутов"`
+	// Время выкладки; по умолчанию — время приёма
+	DeployedAt *time.Time `json:"deployed_at,omitempty" example:"2025-01-15T12:00:00Z"`
+}
+
+type ListDeploymentsQuery struct {
+	BotID  *string    `form:"bot_id" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TeamID *string    `form:"team_id" binding:"omitempty,uuid" example:"9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"`
+	From   *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-01-01T00:00:00Z"`
+	To     *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-02-01T00:00:00Z"`
+	Limit  int        `form:"limit" binding:"omitempty,min=1,max=1000" example:"100"`
+	Cursor string     `form:"cursor"`
+}
+
+type CompareVersionsQuery struct {
+	BotID *string `form:"bot_id" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Limit int     `form:"limit" binding:"omitempty,min=1,max=50" example:"5"`
+}
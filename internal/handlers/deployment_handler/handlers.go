@@ -0,0 +1,197 @@
+package deployment_handler
+
+import (
+	"net/http"
+	"time"
+
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/validator_error_handling"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DeploymentService interface {
+	CreateDeployment(botID, version string, gitSHA, host, changelog *string, deployedAt *time.Time) (*models.Deployment, error)
+	ListDeployments(filter *models.DeploymentFilter, cursor string) (*models.DeploymentPage, error)
+	CompareVersions(botID string, teamID *string, limit int) (*models.VersionComparison, error)
+}
+
+type DeploymentHandler struct {
+	deploymentService DeploymentService
+}
+
+func NewDeploymentHandler(deploymentService DeploymentService) *DeploymentHandler {
+	return &DeploymentHandler{
+		deploymentService: deploymentService,
+	}
+}
+
+// @Summary Зарегистрировать выкладку
+// @Description Сохраняет выкладку новой версии бота (только для обычных токенов с bot_id). Версия проставляется
+// @Description новым логам и запускам бота, время которых не раньше deployed_at и раньше следующей выкладки.
+// @Tags deployments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateDeploymentRequest true "Данные выкладки"
+// @Success 201 {object} models.Deployment
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/deployments [post]
+func (h *DeploymentHandler) CreateDeployment(c *gin.Context) {
+	var request CreateDeploymentRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	botID := c.GetString("bot_id")
+	if botID == "" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "для регистрации выкладки требуется токен с привязкой к боту"})
+		return
+	}
+
+	deployment, err := h.deploymentService.CreateDeployment(botID, request.Version, request.GitSHA, request.Host, request.Changelog, request.DeployedAt)
+	if err != nil {
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, deployment)
+}
+
+// @Summary Получить выкладки
+// @Description Возвращает выкладки с фильтрами и постраничной выдачей (новые первыми). Обычные токены видят только выкладки своего бота, токены команды — выкладки ботов команды.
+// @Tags deployments
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id query string false "ID бота (UUID, для админских токенов и токенов команды)"
+// @Param team_id query string false "ID текущей команды бота (UUID)"
+// @Param from query string false "Начало периода по deployed_at (RFC3339, включительно)"
+// @Param to query string false "Конец периода по deployed_at (RFC3339, не включительно)"
+// @Param limit query int false "Размер страницы (по умолчанию 100, максимум 1000)"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Success 200 {object} models.DeploymentPage
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/deployments [get]
+func (h *DeploymentHandler) ListDeployments(c *gin.Context) {
+	var query ListDeploymentsQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	filter := &models.DeploymentFilter{
+		BotID:  query.BotID,
+		TeamID: query.TeamID,
+		From:   query.From,
+		To:     query.To,
+		Limit:  query.Limit,
+	}
+
+	// Обычные токены видят только выкладки своего бота, токены команды — выкладки ботов команды
+	if teamID := c.GetString("team_id"); teamID != "" {
+		if query.TeamID != nil && *query.TeamID != teamID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "доступ к выкладкам другой команды запрещён"})
+			return
+		}
+		filter.TeamID = &teamID
+	} else if !c.GetBool("is_admin") {
+		botID := c.GetString("bot_id")
+		if botID == "" || (query.BotID != nil && *query.BotID != botID) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "доступ к выкладкам другого бота запрещён"})
+			return
+		}
+		filter.BotID = &botID
+	}
+
+	page, err := h.deploymentService.ListDeployments(filter, query.Cursor)
+	if err != nil {
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// @Summary Сравнить версии бота
+// @Description Сравнивает последние выложенные версии бота (новые первыми): число выкладок, запусков по статусам и доля успешных,
+// @Description число логов и доля логов Error и Critical. Учитываются логи и запуски, которым при записи была проставлена версия.
+// @Description Обычные токены сравнивают версии своего бота, токены команды — ботов команды; админским токенам и токенам команды нужен bot_id.
+// @Tags deployments
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id query string false "ID бота (UUID, для админских токенов и токенов команды)"
+// @Param limit query int false "Число версий (по умолчанию 5, максимум 50)"
+// @Success 200 {object} models.VersionComparison
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/deployments/versions [get]
+func (h *DeploymentHandler) CompareVersions(c *gin.Context) {
+	var query CompareVersionsQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	var teamID *string
+	if team := c.GetString("team_id"); team != "" {
+		teamID = &team
+	} else if !c.GetBool("is_admin") {
+		botID := c.GetString("bot_id")
+		if botID == "" || (query.BotID != nil && *query.BotID != botID) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "доступ к версиям другого бота запрещён"})
+			return
+		}
+		query.BotID = &botID
+	}
+
+	if query.BotID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "не указан bot_id"})
+		return
+	}
+
+	comparison, err := h.deploymentService.CompareVersions(*query.BotID, teamID, query.Limit)
+	if err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
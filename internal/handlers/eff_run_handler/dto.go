@@ -11,4 +11,30 @@ type CreateEffRunRequest struct {
 	Status     string       `json:"status" binding:"required,oneof=success warning error" example:"success"`
 	Host       *string      `json:"host,omitempty" example:"server-01"`
 	Extra      models.JSONB `json:"extra,omitempty"`
+	// Идентификатор запуска на стороне клиента: повтор с тем же event_id вернёт ранее созданную запись
+	EventID *string `json:"event_id,omitempty" binding:"omitempty,max=255" example:"run-2024-01-01T00"`
+}
+
+// maxBatchSize — максимальное число записей в пакетном запросе
+const maxBatchSize = 1000
+
+type CreateEffRunBatchResponse struct {
+	Items    []*models.EffRun `json:"items"`
+	Created  int              `json:"created" example:"2"`
+	Replayed int              `json:"replayed" example:"1"`
+}
+
+type ListEffRunsQuery struct {
+	BotID   *string    `form:"bot_id" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OwnerID *string    `form:"owner_id" binding:"omitempty,uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	TeamID  *string    `form:"team_id" binding:"omitempty,uuid" example:"9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"`
+	Status  []string   `form:"status" binding:"omitempty,dive,oneof=success warning error" example:"error"`
+	Host    *string    `form:"host" binding:"omitempty,max=255" example:"server-01"`
+	Version *string    `form:"version" binding:"omitempty,max=100" example:"1.4.2"`
+	From    *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-01-01T00:00:00Z"`
+	To      *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-02-01T00:00:00Z"`
+	Limit   int        `form:"limit" binding:"omitempty,min=1,max=1000" example:"100"`
+	Cursor  string     `form:"cursor"`
+	// Добавить в страницу выкладки ботов за её период
+	IncludeDeployments bool `form:"include_deployments" example:"true"`
 }
@@ -1,18 +1,22 @@
 package eff_run_handler
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 
 	"logging_api/internal/models"
 	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/idempotency"
 	"logging_api/internal/utils/validator_error_handling"
 
 	"github.com/gin-gonic/gin"
 )
 
 type EffRunService interface {
-	CreateEffRun(botID string, periodFrom, periodTo *time.Time, status string, host *string, extra models.JSONB) (*models.EffRun, error)
+	CreateEffRun(botID string, periodFrom, periodTo *time.Time, status string, host *string, extra models.JSONB, key *models.IdempotencyKey) (*models.EffRun, bool, error)
+	CreateEffRunBatch(effRuns []*models.EffRun, keys []*models.IdempotencyKey) ([]bool, error)
+	ListEffRuns(filter *models.EffRunFilter, cursor string, includeDeployments bool) (*models.EffRunPage, error)
 }
 
 type EffRunHandler struct {
@@ -26,16 +30,22 @@ func NewEffRunHandler(effRunService EffRunService) *EffRunHandler {
 }
 
 // @Summary Создать запись о запуске
-// @Description Создаёт новую запись о запуске бота (требуется авторизация, только для обычных токенов с bot_id)
+// @Description Создаёт новую запись о запуске бота (требуется авторизация, только для обычных токенов с bot_id).
+// @Description Ключ идемпотентности (заголовок Idempotency-Key или поле event_id) защищает от дублей при повторах:
+// @Description повтор с тем же ключом возвращает ранее созданную запись со статусом 200 и заголовком Idempotent-Replayed: true.
+// @Description Повтор ключа с другим телом запроса отклоняется со статусом 422.
 // @Tags eff_runs
-// @Accept json
+// @Accept json,application/msgpack
 // @Produce json
 // @Security BearerAuth
+// @Param Idempotency-Key header string false "Ключ идемпотентности (до 255 символов)"
 // @Param request body CreateEffRunRequest true "Данные о запуске"
+// @Success 200 {object} models.EffRun "Повтор запроса: ранее созданная запись"
 // @Success 201 {object} models.EffRun
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{} "Ключ идемпотентности уже использован с другим телом запроса"
 // @Failure 500 {object} map[string]interface{}
 // @Router /v1/eff-runs [post]
 func (h *EffRunHandler) CreateEffRun(c *gin.Context) {
@@ -56,9 +66,19 @@ func (h *EffRunHandler) CreateEffRun(c *gin.Context) {
 		return
 	}
 
+	key, err := idempotency.Key(c, request.EventID, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	botIDStr := botID.(string)
-	effRun, err := h.effRunService.CreateEffRun(botIDStr, request.PeriodFrom, request.PeriodTo, request.Status, request.Host, request.Extra)
+	effRun, created, err := h.effRunService.CreateEffRun(botIDStr, request.PeriodFrom, request.PeriodTo, request.Status, request.Host, request.Extra, key)
 	if err != nil {
+		if customerrors.IsUnprocessable(err) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
 		if customerrors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -67,5 +87,178 @@ func (h *EffRunHandler) CreateEffRun(c *gin.Context) {
 		return
 	}
 
+	if !created {
+		idempotency.MarkReplayed(c)
+		c.JSON(http.StatusOK, effRun)
+		return
+	}
+
 	c.JSON(http.StatusCreated, effRun)
 }
+
+// @Summary Создать пачку записей о запусках
+// @Description Создаёт до 1000 записей о запусках одним запросом (только для обычных токенов с bot_id). Пачка сохраняется целиком или не сохраняется вовсе.
+// @Description Ключ идемпотентности записи — её event_id, иначе заголовок Idempotency-Key с номером записи (":0", ":1", ...).
+// @Description Записи с уже использованным ключом не создаются заново: в items возвращаются ранее созданные записи.
+// @Description Если ключ записи уже использован с другим телом, пачка отклоняется целиком со статусом 422.
+// @Description Статус 201, если создана хотя бы одна запись, иначе 200.
+// @Tags eff_runs
+// @Accept json,application/x-ndjson,application/msgpack
+// @Produce json
+// @Security BearerAuth
+// @Param Idempotency-Key header string false "Ключ идемпотентности пачки (до 255 символов)"
+// @Param request body []CreateEffRunRequest true "Данные о запусках"
+// @Success 200 {object} CreateEffRunBatchResponse "Все записи — повторы"
+// @Success 201 {object} CreateEffRunBatchResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{} "Ключ идемпотентности уже использован с другим телом запроса"
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/eff-runs/batch [post]
+func (h *EffRunHandler) CreateEffRunBatch(c *gin.Context) {
+	var request []CreateEffRunRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	botID := c.GetString("bot_id")
+	if botID == "" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "для создания записи о запуске требуется токен с привязкой к боту"})
+		return
+	}
+
+	if len(request) == 0 || len(request) > maxBatchSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "пачка должна содержать от 1 до 1000 записей"})
+		return
+	}
+
+	effRuns := make([]*models.EffRun, len(request))
+	keys := make([]*models.IdempotencyKey, len(request))
+	for i, item := range request {
+		key, err := idempotency.ItemKey(c, item.EventID, i, item)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("запись %d: %v", i, err)})
+			return
+		}
+		keys[i] = key
+
+		effRuns[i] = &models.EffRun{
+			BotID:      botID,
+			PeriodFrom: item.PeriodFrom,
+			PeriodTo:   item.PeriodTo,
+			Status:     item.Status,
+			Host:       item.Host,
+			Extra:      item.Extra,
+		}
+	}
+
+	created, err := h.effRunService.CreateEffRunBatch(effRuns, keys)
+	if err != nil {
+		if customerrors.IsUnprocessable(err) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := CreateEffRunBatchResponse{Items: effRuns}
+	for _, ok := range created {
+		if ok {
+			response.Created++
+		} else {
+			response.Replayed++
+		}
+	}
+
+	if response.Created == 0 {
+		idempotency.MarkReplayed(c)
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// @Summary Получить запуски
+// @Description Возвращает записи о запусках с фильтрами и постраничной выдачей (новые первыми). Обычные токены видят только запуски своего бота, токены команды — запуски ботов команды.
+// @Description С include_deployments=true в deployments возвращаются выкладки тех же ботов (по bot_id и team_id) за период, который покрывает страница.
+// @Tags eff_runs
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id query string false "ID бота (UUID, для админских токенов и токенов команды)"
+// @Param team_id query string false "ID текущей команды бота (UUID)"
+// @Param owner_id query string false "ID владельца бота на момент запуска (UUID)"
+// @Param status query []string false "Статусы запуска" collectionFormat(multi) Enums(success, warning, error)
+// @Param host query string false "Хост"
+// @Param version query string false "Версия бота на момент запуска"
+// @Param from query string false "Начало периода по created_at (RFC3339, включительно)"
+// @Param to query string false "Конец периода по created_at (RFC3339, не включительно)"
+// @Param limit query int false "Размер страницы (по умолчанию 100, максимум 1000)"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param include_deployments query bool false "Добавить выкладки ботов за период страницы"
+// @Success 200 {object} models.EffRunPage
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/eff-runs [get]
+func (h *EffRunHandler) ListEffRuns(c *gin.Context) {
+	var query ListEffRunsQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	filter := &models.EffRunFilter{
+		BotID:    query.BotID,
+		OwnerID:  query.OwnerID,
+		TeamID:   query.TeamID,
+		Statuses: query.Status,
+		Host:     query.Host,
+		Version:  query.Version,
+		From:     query.From,
+		To:       query.To,
+		Limit:    query.Limit,
+	}
+
+	// Обычные токены видят только запуски своего бота, токены команды — запуски ботов команды
+	if teamID := c.GetString("team_id"); teamID != "" {
+		if query.TeamID != nil && *query.TeamID != teamID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "доступ к запускам другой команды запрещён"})
+			return
+		}
+		filter.TeamID = &teamID
+	} else if !c.GetBool("is_admin") {
+		botID := c.GetString("bot_id")
+		if botID == "" || (query.BotID != nil && *query.BotID != botID) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "доступ к запускам другого бота запрещён"})
+			return
+		}
+		filter.BotID = &botID
+	}
+
+	page, err := h.effRunService.ListEffRuns(filter, query.Cursor, query.IncludeDeployments)
+	if err != nil {
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
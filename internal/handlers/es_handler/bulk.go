@@ -0,0 +1,183 @@
+package es_handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"logging_api/internal/models"
+	"logging_api/internal/utils/loglevel"
+)
+
+// bulkItem — одна операция из тела _bulk. Если err не пуст, операция отклонена до записи в базу.
+type bulkItem struct {
+	action  string
+	index   string
+	log     *models.Log
+	botCode string
+	err     *BulkError
+}
+
+type bulkAction struct {
+	Index string `json:"_index"`
+}
+
+// parseBulk разбирает NDJSON тела _bulk: строка действия, затем (кроме delete) строка документа.
+// Поддерживаются index и create; остальные действия возвращаются с ошибкой.
+func parseBulk(data []byte, defaultIndex string) ([]*bulkItem, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64<<10), len(data)+1)
+
+	nextLine := func() ([]byte, bool) {
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) > 0 {
+				return line, true
+			}
+		}
+		return nil, false
+	}
+
+	var items []*bulkItem
+	for {
+		line, ok := nextLine()
+		if !ok {
+			break
+		}
+
+		var header map[string]bulkAction
+		if err := json.Unmarshal(line, &header); err != nil || len(header) != 1 {
+			return nil, fmt.Errorf("некорректная строка действия [%d]: ожидается объект с одним действием", len(items)+1)
+		}
+
+		item := &bulkItem{index: defaultIndex}
+		for name, action := range header {
+			item.action = name
+			if action.Index != "" {
+				item.index = action.Index
+			}
+		}
+
+		if item.action == "delete" {
+			item.err = &BulkError{Type: "illegal_argument_exception", Reason: "delete is not supported", Status: 400}
+			items = append(items, item)
+			continue
+		}
+
+		source, ok := nextLine()
+		if !ok {
+			return nil, fmt.Errorf("нет документа для действия [%d]", len(items)+1)
+		}
+
+		switch item.action {
+		case "index", "create":
+			if item.index == "" {
+				item.err = &BulkError{Type: "action_request_validation_exception", Reason: "index is missing", Status: 400}
+				break
+			}
+			item.log, item.botCode, item.err = parseDocument(source, item.index)
+		default:
+			item.err = &BulkError{Type: "illegal_argument_exception", Reason: fmt.Sprintf("%s is not supported", item.action), Status: 400}
+		}
+		items = append(items, item)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// parseDocument превращает документ в лог: message (или log) — текст, log.level — уровень,
+// @timestamp — время события, service.name — код бота; остальные поля документа сохраняются в attributes
+func parseDocument(source []byte, index string) (*models.Log, string, *BulkError) {
+	decoder := json.NewDecoder(bytes.NewReader(source))
+	decoder.UseNumber()
+
+	var doc map[string]interface{}
+	if err := decoder.Decode(&doc); err != nil || doc == nil {
+		return nil, "", &BulkError{Type: "mapper_parsing_exception", Reason: "failed to parse document", Status: 400}
+	}
+
+	messageField := "message"
+	msg, _ := doc[messageField].(string)
+	if msg == "" {
+		// Fluent Bit по умолчанию кладёт строку лога в поле log
+		messageField = "log"
+		msg, _ = doc[messageField].(string)
+	}
+	if msg == "" {
+		return nil, "", &BulkError{Type: "mapper_parsing_exception", Reason: "field [message] is missing", Status: 400}
+	}
+
+	var createdAt time.Time
+	if value, ok := doc["@timestamp"]; ok {
+		ts, err := parseTimestamp(value)
+		if err != nil {
+			return nil, "", &BulkError{Type: "mapper_parsing_exception", Reason: "failed to parse field [@timestamp] of type [date]", Status: 400}
+		}
+		createdAt = ts
+	}
+
+	level, _ := lookup(doc, "log.level").(string)
+	serviceName, _ := lookup(doc, "service.name").(string)
+
+	attributes := models.JSONB(doc)
+	delete(attributes, messageField)
+	delete(attributes, "@timestamp")
+	attributes["es.index"] = index
+
+	return &models.Log{
+		Status:     loglevel.FromText(level, "Info"),
+		Msg:        msg,
+		Attributes: attributes,
+		CreatedAt:  createdAt,
+	}, serviceName, nil
+}
+
+// timestampLayouts — форматы @timestamp, как strict_date_optional_time в Elasticsearch; время без зоны — UTC
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// parseTimestamp разбирает @timestamp: строку даты или число миллисекунд с начала эпохи (epoch_millis)
+func parseTimestamp(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case string:
+		for _, layout := range timestampLayouts {
+			if ts, err := time.Parse(layout, v); err == nil {
+				return ts, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("invalid date %q", v)
+	case json.Number:
+		millis, err := v.Int64()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.UnixMilli(millis).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %v", value)
+}
+
+// lookup ищет поле как по плоскому ключу с точками ("log.level"), так и по вложенным объектам
+func lookup(doc map[string]interface{}, path string) interface{} {
+	if value, ok := doc[path]; ok {
+		return value
+	}
+
+	head, rest, found := strings.Cut(path, ".")
+	if !found {
+		return nil
+	}
+	nested, ok := doc[head].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return lookup(nested, rest)
+}
@@ -0,0 +1,216 @@
+package es_handler
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"logging_api/internal/models"
+)
+
+func TestParseBulk(t *testing.T) {
+	type result struct {
+		action  string
+		index   string
+		msg     string
+		botCode string
+		errType string
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		defaultIndex string
+		want         []result
+	}{
+		{
+			name: "index and create",
+			body: `{"index":{"_index":"app-logs"}}
+{"message":"first","service":{"name":"BOT_001"}}
+{"create":{"_index":"app-logs"}}
+{"log":"second","service.name":"BOT_002"}
+`,
+			want: []result{
+				{action: "index", index: "app-logs", msg: "first", botCode: "BOT_001"},
+				{action: "create", index: "app-logs", msg: "second", botCode: "BOT_002"},
+			},
+		},
+		{
+			name:         "index from path",
+			body:         "{\"index\":{}}\n{\"message\":\"hello\"}",
+			defaultIndex: "path-index",
+			want:         []result{{action: "index", index: "path-index", msg: "hello"}},
+		},
+		{
+			name: "blank lines and crlf",
+			body: "\r\n{\"index\":{\"_index\":\"i\"}}\r\n\r\n{\"message\":\"hello\"}\r\n\r\n",
+			want: []result{{action: "index", index: "i", msg: "hello"}},
+		},
+		{
+			name: "delete has no document",
+			body: `{"delete":{"_index":"i","_id":"1"}}
+{"index":{"_index":"i"}}
+{"message":"kept"}`,
+			want: []result{
+				{action: "delete", index: "i", errType: "illegal_argument_exception"},
+				{action: "index", index: "i", msg: "kept"},
+			},
+		},
+		{
+			name: "unsupported action",
+			body: `{"update":{"_index":"i","_id":"1"}}
+{"doc":{"message":"x"}}`,
+			want: []result{{action: "update", index: "i", errType: "illegal_argument_exception"}},
+		},
+		{
+			name: "missing index",
+			body: `{"index":{}}
+{"message":"x"}`,
+			want: []result{{action: "index", errType: "action_request_validation_exception"}},
+		},
+		{
+			name: "document errors do not stop the batch",
+			body: `{"index":{"_index":"i"}}
+not json
+{"index":{"_index":"i"}}
+{"level":"info"}
+{"index":{"_index":"i"}}
+{"message":"bad time","@timestamp":"yesterday"}
+{"index":{"_index":"i"}}
+{"message":"ok"}`,
+			want: []result{
+				{action: "index", index: "i", errType: "mapper_parsing_exception"},
+				{action: "index", index: "i", errType: "mapper_parsing_exception"},
+				{action: "index", index: "i", errType: "mapper_parsing_exception"},
+				{action: "index", index: "i", msg: "ok"},
+			},
+		},
+		{name: "empty body", body: "\n\n", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := parseBulk([]byte(tt.body), tt.defaultIndex)
+			if err != nil {
+				t.Fatalf("parseBulk() error = %v", err)
+			}
+
+			var got []result
+			for _, item := range items {
+				r := result{action: item.action, index: item.index, botCode: item.botCode}
+				if item.log != nil {
+					r.msg = item.log.Msg
+				}
+				if item.err != nil {
+					r.errType = item.err.Type
+				}
+				got = append(got, r)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseBulk() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBulkMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "action line is not json", body: "index\n{\"message\":\"x\"}"},
+		{name: "action line is not an object", body: "[1]\n{\"message\":\"x\"}"},
+		{name: "two actions in one line", body: `{"index":{},"create":{}}` + "\n" + `{"message":"x"}`},
+		{name: "empty action object", body: "{}\n{\"message\":\"x\"}"},
+		{name: "missing document", body: `{"index":{"_index":"i"}}`},
+		{name: "missing last document", body: `{"index":{"_index":"i"}}` + "\n" + `{"message":"x"}` + "\n" + `{"create":{"_index":"i"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseBulk([]byte(tt.body), ""); err == nil {
+				t.Fatal("parseBulk() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestParseDocument(t *testing.T) {
+	source := `{"@timestamp":"2025-01-15T12:00:00.123+03:00","message":"timeout","log":{"level":"WARN"},"service":{"name":"BOT_001"},"host":"web01","count":3}`
+
+	entry, botCode, bulkErr := parseDocument([]byte(source), "app-logs")
+	if bulkErr != nil {
+		t.Fatalf("parseDocument() error = %+v", bulkErr)
+	}
+	if botCode != "BOT_001" {
+		t.Errorf("botCode = %q, want %q", botCode, "BOT_001")
+	}
+	if entry.Msg != "timeout" || entry.Status != "Warning" {
+		t.Errorf("msg, status = %q, %q, want %q, %q", entry.Msg, entry.Status, "timeout", "Warning")
+	}
+	if want := time.Date(2025, time.January, 15, 9, 0, 0, 123e6, time.UTC); !entry.CreatedAt.Equal(want) {
+		t.Errorf("created_at = %v, want %v", entry.CreatedAt, want)
+	}
+
+	want := models.JSONB{
+		"log":      map[string]interface{}{"level": "WARN"},
+		"service":  map[string]interface{}{"name": "BOT_001"},
+		"host":     "web01",
+		"count":    json.Number("3"),
+		"es.index": "app-logs",
+	}
+	if !reflect.DeepEqual(entry.Attributes, want) {
+		t.Errorf("attributes = %#v, want %#v", entry.Attributes, want)
+	}
+}
+
+func TestParseDocumentWithoutTimestamp(t *testing.T) {
+	entry, _, bulkErr := parseDocument([]byte(`{"message":"hello","log.level":"nonsense"}`), "i")
+	if bulkErr != nil {
+		t.Fatalf("parseDocument() error = %+v", bulkErr)
+	}
+	if !entry.CreatedAt.IsZero() {
+		t.Errorf("created_at = %v, want zero", entry.CreatedAt)
+	}
+	if entry.Status != "Info" {
+		t.Errorf("status = %q, want %q", entry.Status, "Info")
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "rfc3339", value: "2025-01-15T12:00:00Z", want: time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC)},
+		{name: "rfc3339 with offset", value: "2025-01-15T12:00:00+03:00", want: time.Date(2025, time.January, 15, 9, 0, 0, 0, time.UTC)},
+		{name: "without zone", value: "2025-01-15T12:00:00.5", want: time.Date(2025, time.January, 15, 12, 0, 0, 5e8, time.UTC)},
+		{name: "date only", value: "2025-01-15", want: time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC)},
+		{name: "epoch millis", value: json.Number("1736942400000"), want: time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC)},
+		{name: "invalid string", value: "15.01.2025", wantErr: true},
+		{name: "fractional millis", value: json.Number("1736942400000.5"), wantErr: true},
+		{name: "boolean", value: true, wantErr: true},
+		{name: "null", value: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTimestamp(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseTimestamp() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTimestamp() error = %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseTimestamp() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
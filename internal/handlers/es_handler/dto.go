@@ -0,0 +1,44 @@
+package es_handler
+
+// BulkResponse повторяет формат ответа Elasticsearch _bulk
+type BulkResponse struct {
+	Took   int64                       `json:"took" example:"12"`
+	Errors bool                        `json:"errors" example:"false"`
+	Items  []map[string]BulkItemResult `json:"items"`
+}
+
+type BulkItemResult struct {
+	Index       string      `json:"_index" example:"filebeat-8.15.0"`
+	ID          *string     `json:"_id,omitempty" example:"12345"`
+	Version     int         `json:"_version,omitempty" example:"1"`
+	Result      string      `json:"result,omitempty" example:"created"`
+	Status      int         `json:"status" example:"201"`
+	Shards      *BulkShards `json:"_shards,omitempty"`
+	SeqNo       *int64      `json:"_seq_no,omitempty" example:"0"`
+	PrimaryTerm int         `json:"_primary_term,omitempty" example:"1"`
+	Error       *BulkError  `json:"error,omitempty"`
+}
+
+// BulkError — ошибка отдельной операции; Status — HTTP-статус элемента
+type BulkError struct {
+	Type   string `json:"type" example:"mapper_parsing_exception"`
+	Reason string `json:"reason" example:"field [message] is missing"`
+	Status int    `json:"-"`
+}
+
+type BulkShards struct {
+	Total      int `json:"total" example:"1"`
+	Successful int `json:"successful" example:"1"`
+	Failed     int `json:"failed" example:"0"`
+}
+
+// ErrorResponse — ошибка запроса целиком в формате Elasticsearch
+type ErrorResponse struct {
+	Error  ErrorCause `json:"error"`
+	Status int        `json:"status" example:"400"`
+}
+
+type ErrorCause struct {
+	Type   string `json:"type" example:"illegal_argument_exception"`
+	Reason string `json:"reason" example:"некорректная строка действия [1]"`
+}
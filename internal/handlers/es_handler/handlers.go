@@ -0,0 +1,181 @@
+package es_handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"logging_api/internal/models"
+	"logging_api/internal/utils/body"
+
+	"github.com/gin-gonic/gin"
+)
+
+// esVersion — версия Elasticsearch, которую сообщает эндпоинт информации о кластере.
+// Агенты проверяют её при подключении и выбирают формат запросов.
+const esVersion = "8.11.0"
+
+type LogService interface {
+	CreateLogs(entries []*models.Log) ([]models.WriteResult, error)
+	ResolveBotID(code string) *string
+}
+
+type ESHandler struct {
+	logService   LogService
+	maxBodyBytes int64
+}
+
+func NewESHandler(logService LogService, maxBodyBytes int64) *ESHandler {
+	return &ESHandler{
+		logService:   logService,
+		maxBodyBytes: maxBodyBytes,
+	}
+}
+
+// @Summary Информация о кластере (совместимость с Elasticsearch)
+// @Description Ответ в формате GET / Elasticsearch, нужен агентам (Filebeat, Fluent Bit) для проверки версии при подключении
+// @Tags elasticsearch
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /es [get]
+func (h *ESHandler) Info(c *gin.Context) {
+	c.Header("X-Elastic-Product", "Elasticsearch")
+	c.JSON(http.StatusOK, gin.H{
+		"name":         "logging_api",
+		"cluster_name": "logging_api",
+		"cluster_uuid": "logging_api",
+		"version": gin.H{
+			"number":                              esVersion,
+			"build_flavor":                        "default",
+			"lucene_version":                      "9.8.0",
+			"minimum_wire_compatibility_version":  "7.17.0",
+			"minimum_index_compatibility_version": "7.0.0",
+		},
+		"tagline": "You Know, for Search",
+	})
+}
+
+// @Summary Приём логов в формате Elasticsearch _bulk
+// @Description NDJSON тело _bulk (можно gzip). Поддерживаются действия index и create; message (или log) — текст лога,
+// @Description log.level — уровень, @timestamp — время события (created_at), остальные поля документа сохраняются в attributes.
+// @Description Ответ — по элементу на каждое действие, как в Elasticsearch; _id есть только у уже сохранённых логов
+// @Description (при асинхронной записи его нет), логи, отброшенные политикой хранения, возвращаются с result noop.
+// @Description Логи привязываются к боту токена; для админского токена бот определяется по полю service.name (код бота).
+// @Tags elasticsearch
+// @Accept application/x-ndjson
+// @Produce json
+// @Security BearerAuth
+// @Param index path string false "Индекс по умолчанию для действий без _index"
+// @Success 200 {object} BulkResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /es/_bulk [post]
+// @Router /es/{index}/_bulk [post]
+func (h *ESHandler) Bulk(c *gin.Context) {
+	started := time.Now()
+	c.Header("X-Elastic-Product", "Elasticsearch")
+
+	data, err := body.Read(c.Request, h.maxBodyBytes)
+	if err != nil {
+		switch {
+		case errors.Is(err, body.ErrTooLarge):
+			writeError(c, http.StatusRequestEntityTooLarge, "content_too_long_exception", err.Error())
+		default:
+			writeError(c, http.StatusBadRequest, "parse_exception", err.Error())
+		}
+		return
+	}
+
+	items, err := parseBulk(data, c.Param("index"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "illegal_argument_exception", err.Error())
+		return
+	}
+
+	resolveBot := h.botResolver(c)
+	var logs []*models.Log
+	for _, item := range items {
+		if item.err == nil {
+			item.log.BotID = resolveBot(item.botCode)
+			logs = append(logs, item.log)
+		}
+	}
+
+	results, err := h.logService.CreateLogs(logs)
+	if err != nil {
+		log.Printf("Ошибка сохранения логов _bulk: %v", err)
+		writeError(c, http.StatusInternalServerError, "exception", "ошибка сохранения логов")
+		return
+	}
+
+	response := BulkResponse{
+		Took:  time.Since(started).Milliseconds(),
+		Items: make([]map[string]BulkItemResult, 0, len(items)),
+	}
+	next := 0
+	for _, item := range items {
+		result := BulkItemResult{Index: item.index}
+		if item.err == nil {
+			item.err = writeItemResult(&result, item.log, results[next])
+			next++
+		}
+		if item.err != nil {
+			response.Errors = true
+			result.Status = item.err.Status
+			result.Error = item.err
+		}
+		response.Items = append(response.Items, map[string]BulkItemResult{item.action: result})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// botResolver возвращает функцию выбора бота: бот токена, а для админского токена — бот с кодом из service.name
+func (h *ESHandler) botResolver(c *gin.Context) func(code string) *string {
+	if botID := c.GetString("bot_id"); botID != "" {
+		return func(string) *string { return &botID }
+	}
+	if !c.GetBool("is_admin") {
+		return func(string) *string { return nil }
+	}
+	return h.logService.ResolveBotID
+}
+
+// writeItemResult заполняет элемент ответа по итогу записи лога. _id и _seq_no есть только у сохранённых логов:
+// у поставленных в очередь асинхронной записи id ещё нет. Для отклонённых логов возвращается ошибка элемента.
+func writeItemResult(result *BulkItemResult, entry *models.Log, write models.WriteResult) *BulkError {
+	switch write {
+	case models.WriteRejected:
+		return &BulkError{Type: "illegal_argument_exception", Reason: "[@timestamp] is outside the allowed time range", Status: 400}
+	case models.WriteDropped:
+		// Лог отброшен политикой хранения бота: агент не должен его повторять
+		result.Result = "noop"
+		result.Status = http.StatusOK
+		return nil
+	case models.WriteCreated:
+		id := strconv.FormatInt(entry.ID, 10)
+		seqNo := entry.ID
+		result.ID = &id
+		result.SeqNo = &seqNo
+	}
+
+	result.Version = 1
+	result.Result = "created"
+	result.Status = http.StatusCreated
+	result.Shards = &BulkShards{Total: 1, Successful: 1}
+	result.PrimaryTerm = 1
+	return nil
+}
+
+func writeError(c *gin.Context, status int, errorType, reason string) {
+	c.JSON(status, ErrorResponse{
+		Error:  ErrorCause{Type: errorType, Reason: reason},
+		Status: status,
+	})
+}
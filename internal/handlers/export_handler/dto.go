@@ -0,0 +1,29 @@
+package export_handler
+
+import "time"
+
+type ExportLogsQuery struct {
+	BotID   *string    `form:"bot_id" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TeamID  *string    `form:"team_id" binding:"omitempty,uuid" example:"9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"`
+	Status  []string   `form:"status" binding:"omitempty,dive,oneof=Debug Info Warning Error Critical" example:"Error"`
+	Version *string    `form:"version" binding:"omitempty,max=100" example:"1.4.2"`
+	From    *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-01-01T00:00:00Z"`
+	To      *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-02-01T00:00:00Z"`
+	Q       string     `form:"q" binding:"omitempty,max=500" example:"ошибка подключения"`
+	Sort    string     `form:"sort" binding:"omitempty,oneof=created_at received_at" example:"created_at"`
+	Format  string     `form:"format" binding:"omitempty,oneof=csv ndjson" example:"csv"`
+	Gzip    bool       `form:"gzip" example:"false"`
+}
+
+type ExportEffRunsQuery struct {
+	BotID   *string    `form:"bot_id" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OwnerID *string    `form:"owner_id" binding:"omitempty,uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	TeamID  *string    `form:"team_id" binding:"omitempty,uuid" example:"9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"`
+	Status  []string   `form:"status" binding:"omitempty,dive,oneof=success warning error" example:"error"`
+	Host    *string    `form:"host" binding:"omitempty,max=255" example:"server-01"`
+	Version *string    `form:"version" binding:"omitempty,max=100" example:"1.4.2"`
+	From    *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-01-01T00:00:00Z"`
+	To      *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-02-01T00:00:00Z"`
+	Format  string     `form:"format" binding:"omitempty,oneof=csv ndjson" example:"csv"`
+	Gzip    bool       `form:"gzip" example:"false"`
+}
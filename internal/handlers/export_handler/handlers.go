@@ -0,0 +1,286 @@
+package export_handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"logging_api/internal/models"
+	"logging_api/internal/utils/validator_error_handling"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	logsHeader    = []string{"id", "bot_id", "status", "msg", "attributes", "version", "created_at", "received_at"}
+	effRunsHeader = []string{"id", "bot_id", "owner_id", "period_from", "period_to", "status", "host", "extra", "version", "created_at"}
+)
+
+type LogService interface {
+	StreamLogs(filter *models.LogFilter, fn func(*models.Log) error) error
+}
+
+type EffRunService interface {
+	StreamEffRuns(filter *models.EffRunFilter, fn func(*models.EffRun) error) error
+}
+
+type ExportHandler struct {
+	logService    LogService
+	effRunService EffRunService
+}
+
+func NewExportHandler(logService LogService, effRunService EffRunService) *ExportHandler {
+	return &ExportHandler{
+		logService:    logService,
+		effRunService: effRunService,
+	}
+}
+
+// @Summary Выгрузка логов
+// @Description Потоково выгружает логи в CSV или NDJSON (по заголовку Accept или параметру format). Фильтры — как у GET /v1/logs.
+// @Description При Accept-Encoding: gzip или gzip=true ответ сжимается. Обычные токены выгружают только логи своего бота, токены команды — логи ботов команды.
+// @Tags exports
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Param bot_id query string false "ID бота (UUID, для админских токенов и токенов команды)"
+// @Param team_id query string false "ID текущей команды бота (UUID)"
+// @Param status query []string false "Уровни лога" collectionFormat(multi) Enums(Debug, Info, Warning, Error, Critical)
+// @Param version query string false "Версия бота на момент записи лога"
+// @Param from query string false "Начало периода (RFC3339, включительно)"
+// @Param to query string false "Конец периода (RFC3339, не включительно)"
+// @Param q query string false "Полнотекстовый запрос"
+// @Param sort query string false "Поле времени для сортировки и периода from/to (по умолчанию created_at)" Enums(created_at, received_at)
+// @Param format query string false "Формат (переопределяет Accept)" Enums(csv, ndjson)
+// @Param gzip query bool false "Сжать ответ gzip"
+// @Success 200 {string} string "Поток записей"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 406 {object} map[string]interface{}
+// @Router /v1/exports/logs [get]
+func (h *ExportHandler) ExportLogs(c *gin.Context) {
+	var query ExportLogsQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	filter := &models.LogFilter{
+		BotID:    query.BotID,
+		TeamID:   query.TeamID,
+		Statuses: query.Status,
+		Version:  query.Version,
+		From:     query.From,
+		To:       query.To,
+		Query:    query.Q,
+		SortBy:   query.Sort,
+	}
+	if !restrictAccess(c, &filter.BotID, &filter.TeamID) {
+		return
+	}
+
+	writer, ok := startExport(c, "logs", query.Format, query.Gzip, logsHeader)
+	if !ok {
+		return
+	}
+
+	err := h.logService.StreamLogs(filter, func(entry *models.Log) error {
+		return writer.Write(entry, func() []string {
+			return []string{
+				strconv.FormatInt(entry.ID, 10),
+				stringOrEmpty(entry.BotID),
+				entry.Status,
+				entry.Msg,
+				jsonbOrEmpty(entry.Attributes),
+				stringOrEmpty(entry.Version),
+				entry.CreatedAt.Format(time.RFC3339Nano),
+				entry.ReceivedAt.Format(time.RFC3339Nano),
+			}
+		})
+	})
+	finishExport(c, writer, err)
+}
+
+// @Summary Выгрузка запусков
+// @Description Потоково выгружает записи о запусках в CSV или NDJSON (по заголовку Accept или параметру format). Фильтры — как у GET /v1/eff-runs.
+// @Description При Accept-Encoding: gzip или gzip=true ответ сжимается. Обычные токены выгружают только запуски своего бота, токены команды — запуски ботов команды.
+// @Tags exports
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Param bot_id query string false "ID бота (UUID, для админских токенов и токенов команды)"
+// @Param team_id query string false "ID текущей команды бота (UUID)"
+// @Param owner_id query string false "ID владельца бота на момент запуска (UUID)"
+// @Param status query []string false "Статусы запуска" collectionFormat(multi) Enums(success, warning, error)
+// @Param host query string false "Хост"
+// @Param version query string false "Версия бота на момент запуска"
+// @Param from query string false "Начало периода по created_at (RFC3339, включительно)"
+// @Param to query string false "Конец периода по created_at (RFC3339, не включительно)"
+// @Param format query string false "Формат (переопределяет Accept)" Enums(csv, ndjson)
+// @Param gzip query bool false "Сжать ответ gzip"
+// @Success 200 {string} string "Поток записей"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 406 {object} map[string]interface{}
+// @Router /v1/exports/eff-runs [get]
+func (h *ExportHandler) ExportEffRuns(c *gin.Context) {
+	var query ExportEffRunsQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	filter := &models.EffRunFilter{
+		BotID:    query.BotID,
+		OwnerID:  query.OwnerID,
+		TeamID:   query.TeamID,
+		Statuses: query.Status,
+		Host:     query.Host,
+		Version:  query.Version,
+		From:     query.From,
+		To:       query.To,
+	}
+	if !restrictAccess(c, &filter.BotID, &filter.TeamID) {
+		return
+	}
+
+	writer, ok := startExport(c, "eff_runs", query.Format, query.Gzip, effRunsHeader)
+	if !ok {
+		return
+	}
+
+	err := h.effRunService.StreamEffRuns(filter, func(effRun *models.EffRun) error {
+		return writer.Write(effRun, func() []string {
+			return []string{
+				effRun.ID,
+				effRun.BotID,
+				stringOrEmpty(effRun.OwnerID),
+				timeOrEmpty(effRun.PeriodFrom),
+				timeOrEmpty(effRun.PeriodTo),
+				effRun.Status,
+				stringOrEmpty(effRun.Host),
+				jsonbOrEmpty(effRun.Extra),
+				stringOrEmpty(effRun.Version),
+				effRun.CreatedAt.Format(time.RFC3339Nano),
+			}
+		})
+	})
+	finishExport(c, writer, err)
+}
+
+func bindQuery(c *gin.Context, query interface{}) bool {
+	if err := c.ShouldBindQuery(query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return false
+	}
+	return true
+}
+
+// restrictAccess ограничивает выгрузку ботами команды для токена команды и ботом токена для обычных (не админских) токенов
+func restrictAccess(c *gin.Context, botIDFilter, teamIDFilter **string) bool {
+	if teamID := c.GetString("team_id"); teamID != "" {
+		if *teamIDFilter != nil && **teamIDFilter != teamID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "доступ к данным другой команды запрещён"})
+			return false
+		}
+		*teamIDFilter = &teamID
+		return true
+	}
+	if c.GetBool("is_admin") {
+		return true
+	}
+
+	botID := c.GetString("bot_id")
+	if botID == "" || (*botIDFilter != nil && **botIDFilter != botID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "доступ к данным другого бота запрещён"})
+		return false
+	}
+	*botIDFilter = &botID
+	return true
+}
+
+// startExport согласует формат и сжатие, пишет заголовки ответа и создаёт writer
+func startExport(c *gin.Context, name, format string, forceGzip bool, header []string) (*recordWriter, bool) {
+	format = negotiateFormat(format, c.GetHeader("Accept"))
+	if format == "" {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": "поддерживаются только text/csv и application/x-ndjson"})
+		return nil, false
+	}
+
+	contentType, ext := contentTypeNDJSON, "ndjson"
+	if format == formatCSV {
+		contentType, ext = contentTypeCSV+"; charset=utf-8", "csv"
+	}
+
+	useGzip := forceGzip || acceptsGzip(c.GetHeader("Accept-Encoding"))
+	filename := fmt.Sprintf("%s_%s.%s", name, time.Now().UTC().Format("20060102T150405Z"), ext)
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Vary", "Accept, Accept-Encoding")
+	if useGzip {
+		c.Header("Content-Encoding", "gzip")
+	}
+	c.Status(http.StatusOK)
+
+	writer, err := newRecordWriter(c.Writer, format, useGzip, header)
+	if err != nil {
+		log.Printf("Ошибка начала выгрузки %s: %v", name, err)
+		return nil, false
+	}
+	return writer, true
+}
+
+// finishExport завершает поток. Заголовки уже отправлены, поэтому при ошибке
+// в середине выгрузки соединение обрывается — иначе клиент принял бы
+// усечённый файл за полный.
+func finishExport(c *gin.Context, writer *recordWriter, err error) {
+	if err != nil {
+		log.Printf("Ошибка выгрузки: %v", err)
+		sentry.CaptureException(err)
+		if hijacker, ok := c.Writer.(http.Hijacker); ok {
+			if conn, _, hijackErr := hijacker.Hijack(); hijackErr == nil {
+				conn.Close()
+			}
+		}
+		return
+	}
+	if err := writer.Close(); err != nil {
+		log.Printf("Ошибка завершения выгрузки: %v", err)
+	}
+}
+
+func stringOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func jsonbOrEmpty(value models.JSONB) string {
+	if value == nil {
+		return ""
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func timeOrEmpty(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.Format(time.RFC3339Nano)
+}
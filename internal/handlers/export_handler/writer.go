@@ -0,0 +1,154 @@
+package export_handler
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+
+	contentTypeCSV    = "text/csv"
+	contentTypeNDJSON = "application/x-ndjson"
+
+	flushEvery = 1000
+)
+
+// negotiateFormat выбирает формат по параметру format или заголовку Accept.
+// Возвращает пустую строку, если клиент не принимает ни CSV, ни NDJSON.
+func negotiateFormat(format, accept string) string {
+	if format != "" {
+		return format
+	}
+	if accept == "" {
+		return formatNDJSON
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		switch mediaType {
+		case contentTypeCSV:
+			return formatCSV
+		case contentTypeNDJSON, "application/*", "*/*":
+			return formatNDJSON
+		}
+	}
+	return ""
+}
+
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		if strings.TrimSpace(strings.SplitN(part, ";", 2)[0]) == "gzip" {
+			return true
+		}
+	}
+	return false
+}
+
+// recordWriter пишет записи выгрузки в ответ построчно и периодически сбрасывает буферы клиенту
+type recordWriter struct {
+	format  string
+	flusher http.Flusher
+	gz      *gzip.Writer
+	buf     *bufio.Writer
+	csv     *csv.Writer
+	json    *json.Encoder
+	rows    int
+}
+
+func newRecordWriter(w http.ResponseWriter, format string, useGzip bool, header []string) (*recordWriter, error) {
+	rw := &recordWriter{format: format}
+	rw.flusher, _ = w.(http.Flusher)
+
+	var out io.Writer = w
+	if useGzip {
+		rw.gz = gzip.NewWriter(w)
+		out = rw.gz
+	}
+	rw.buf = bufio.NewWriterSize(out, 64*1024)
+
+	switch format {
+	case formatCSV:
+		// BOM нужен, чтобы Excel распознал UTF-8 и корректно показал кириллицу
+		if _, err := rw.buf.WriteString("\uFEFF"); err != nil {
+			return nil, err
+		}
+		rw.csv = csv.NewWriter(rw.buf)
+		if err := rw.csv.Write(header); err != nil {
+			return nil, err
+		}
+	default:
+		rw.json = json.NewEncoder(rw.buf)
+	}
+
+	return rw, nil
+}
+
+// escapeCell защищает ячейку CSV от выполнения как формулы в Excel и LibreOffice: значение, начинающееся
+// с =, +, -, @, табуляции или перевода каретки, предваряется апострофом
+func escapeCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// Write записывает одну запись: record — для NDJSON, row — для CSV
+func (rw *recordWriter) Write(record interface{}, row func() []string) error {
+	var err error
+	if rw.csv != nil {
+		cells := row()
+		for i, cell := range cells {
+			cells[i] = escapeCell(cell)
+		}
+		err = rw.csv.Write(cells)
+	} else {
+		err = rw.json.Encode(record)
+	}
+	if err != nil {
+		return err
+	}
+
+	rw.rows++
+	if rw.rows%flushEvery == 0 {
+		return rw.flush()
+	}
+	return nil
+}
+
+func (rw *recordWriter) flush() error {
+	if rw.csv != nil {
+		rw.csv.Flush()
+		if err := rw.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if err := rw.buf.Flush(); err != nil {
+		return err
+	}
+	if rw.gz != nil {
+		if err := rw.gz.Flush(); err != nil {
+			return err
+		}
+	}
+	if rw.flusher != nil {
+		rw.flusher.Flush()
+	}
+	return nil
+}
+
+func (rw *recordWriter) Close() error {
+	if err := rw.flush(); err != nil {
+		return err
+	}
+	if rw.gz != nil {
+		return rw.gz.Close()
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+package health_handler
+
+type FleetHealthQuery struct {
+	State           *string `form:"state" binding:"omitempty,oneof=healthy degraded failing silent unknown" example:"failing"`
+	TeamID          *string `form:"team_id" binding:"omitempty,uuid" example:"9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"`
+	IncludeInactive bool    `form:"include_inactive" example:"false"`
+}
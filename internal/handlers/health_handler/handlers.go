@@ -0,0 +1,91 @@
+package health_handler
+
+import (
+	"net/http"
+
+	"logging_api/internal/models"
+	"logging_api/internal/utils/validator_error_handling"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthService interface {
+	Fleet(state, teamID *string, includeInactive bool) *models.FleetHealth
+}
+
+type HealthHandler struct {
+	healthService HealthService
+}
+
+func NewHealthHandler(healthService HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// @Summary Состояние ботов
+// @Description Сводка состояния ботов по последним логам и запускам (требуется админский токен).
+// @Description failing — много логов Error/Critical за окно или несколько последних запусков с ошибкой;
+// @Description degraded — ошибки в логах или запуски с warning/error среди последних; silent — давно нет логов и запусков;
+// @Description unknown — логов и запусков не было. Пороги задаются в секции health конфигурации.
+// @Description Сначала идут боты, требующие внимания. counts — число ботов по состояниям (без учёта фильтра state).
+// @Tags bots
+// @Produce json
+// @Security BearerAuth
+// @Param state query string false "Только боты в этом состоянии" Enums(healthy, degraded, failing, silent, unknown)
+// @Param team_id query string false "Только боты команды (UUID)"
+// @Param include_inactive query bool false "Включать неактивных ботов (is_active = false)"
+// @Success 200 {object} models.FleetHealth
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /v1/bots/health [get]
+func (h *HealthHandler) GetFleetHealth(c *gin.Context) {
+	var query FleetHealthQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.healthService.Fleet(query.State, query.TeamID, query.IncludeInactive))
+}
+
+// @Summary Состояние ботов команды
+// @Description Сводка состояния ботов команды — то же, что /v1/bots/health с team_id.
+// @Description Доступна админскому токену и токену этой команды.
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param team_id path string true "ID команды (UUID)"
+// @Param state query string false "Только боты в этом состоянии" Enums(healthy, degraded, failing, silent, unknown)
+// @Param include_inactive query bool false "Включать неактивных ботов (is_active = false)"
+// @Success 200 {object} models.FleetHealth
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /v1/teams/{team_id}/health [get]
+func (h *HealthHandler) GetTeamHealth(c *gin.Context) {
+	teamID := c.Param("team_id")
+
+	if !c.GetBool("is_admin") && c.GetString("team_id") != teamID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "доступ к ботам другой команды запрещён"})
+		return
+	}
+
+	var query FleetHealthQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.healthService.Fleet(query.State, &teamID, query.IncludeInactive))
+}
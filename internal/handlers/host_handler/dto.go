@@ -0,0 +1,22 @@
+package host_handler
+
+import "time"
+
+type ListHostsQuery struct {
+	Q      string  `form:"q" binding:"omitempty,max=255" example:"server"`
+	Silent *bool   `form:"silent" example:"true"`
+	BotID  *string `form:"bot_id" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+type UpdateHostRequest struct {
+	Description *string `json:"description,omitempty" binding:"omitempty,max=1000" example:"Виртуальная машина платёжных ботов"`
+	OS          *string `json:"os,omitempty" binding:"omitempty,max=100" example:"Ubuntu 22.04"`
+	IP          *string `json:"ip,omitempty" binding:"omitempty,ip" example:"10.0.12.5"`
+	// Следить ли за молчанием хоста
+	Monitored *bool `json:"monitored,omitempty" example:"true"`
+}
+
+type HostStatsQuery struct {
+	From *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-01-01T00:00:00Z"`
+	To   *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-02-01T00:00:00Z"`
+}
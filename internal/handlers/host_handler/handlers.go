@@ -0,0 +1,201 @@
+package host_handler
+
+import (
+	"net/http"
+	"time"
+
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/validator_error_handling"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HostService interface {
+	GetHost(name string) (*models.Host, error)
+	ListHosts(filter *models.HostFilter) ([]*models.Host, error)
+	UpdateHost(name string, update models.HostUpdate) (*models.Host, error)
+	DeleteHost(name string) error
+	GetHostStats(name string, from, to *time.Time) (*models.HostStats, error)
+}
+
+type HostHandler struct {
+	hostService HostService
+}
+
+func NewHostHandler(hostService HostService) *HostHandler {
+	return &HostHandler{
+		hostService: hostService,
+	}
+}
+
+// @Summary Получить хосты
+// @Description Возвращает реестр хостов, собранный по полю host запусков (требуется админский токен): сначала замолчавшие, затем по имени
+// @Tags hosts
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Подстрока в имени, описании или IP (без учёта регистра)"
+// @Param silent query bool false "Только замолчавшие (true) или только присылающие запуски (false) хосты"
+// @Param bot_id query string false "Только хосты, с которых приходили запуски бота (UUID)"
+// @Success 200 {array} models.Host
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/hosts [get]
+func (h *HostHandler) ListHosts(c *gin.Context) {
+	var query ListHostsQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	hosts, err := h.hostService.ListHosts(&models.HostFilter{Query: query.Q, Silent: query.Silent, BotID: query.BotID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, hosts)
+}
+
+// @Summary Получить хост
+// @Description Возвращает хост с ботами, запуски которых с него приходили (требуется админский токен)
+// @Tags hosts
+// @Produce json
+// @Security BearerAuth
+// @Param host path string true "Имя хоста"
+// @Success 200 {object} models.Host
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/hosts/{host} [get]
+func (h *HostHandler) GetHost(c *gin.Context) {
+	host, err := h.hostService.GetHost(c.Param("host"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, host)
+}
+
+// @Summary Обновить хост
+// @Description Меняет описание, ОС и IP хоста и признак слежения за его молчанием (требуется админский токен).
+// @Description Если слежение выключено, отметка о молчании снимается.
+// @Tags hosts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param host path string true "Имя хоста"
+// @Param request body UpdateHostRequest true "Обновлённые данные"
+// @Success 200 {object} models.Host
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/hosts/{host} [put]
+func (h *HostHandler) UpdateHost(c *gin.Context) {
+	var request UpdateHostRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	update := models.HostUpdate{
+		Description: request.Description,
+		OS:          request.OS,
+		IP:          request.IP,
+		Monitored:   request.Monitored,
+	}
+	host, err := h.hostService.UpdateHost(c.Param("host"), update)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, host)
+}
+
+// @Summary Удалить хост
+// @Description Удаляет хост из реестра (требуется админский токен). При следующем запуске с этого хоста он появится снова.
+// @Tags hosts
+// @Produce json
+// @Security BearerAuth
+// @Param host path string true "Имя хоста"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/hosts/{host} [delete]
+func (h *HostHandler) DeleteHost(c *gin.Context) {
+	if err := h.hostService.DeleteHost(c.Param("host")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "хост удалён из реестра"})
+}
+
+// @Summary Показатели хоста
+// @Description Возвращает показатели запусков с хоста за период (требуется админский токен): число запусков по статусам,
+// @Description долю запусков с ошибкой, число разных ботов и наибольшее число ботов, работавших одновременно
+// @Description (по пересечению period_from–period_to запусков).
+// @Tags hosts
+// @Produce json
+// @Security BearerAuth
+// @Param host path string true "Имя хоста"
+// @Param from query string false "Начало периода по created_at (RFC3339, включительно)"
+// @Param to query string false "Конец периода по created_at (RFC3339, не включительно)"
+// @Success 200 {object} models.HostStats
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/hosts/{host}/stats [get]
+func (h *HostHandler) GetHostStats(c *gin.Context) {
+	var query HostStatsQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	stats, err := h.hostService.GetHostStats(c.Param("host"), query.From, query.To)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// writeError пишет ответ для ошибки сервиса хостов
+func writeError(c *gin.Context, err error) {
+	switch {
+	case customerrors.IsNotFound(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case customerrors.IsInvalidInput(err):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,7 +1,42 @@
 package log_handler
 
+import (
+	"logging_api/internal/models"
+	"time"
+)
+
 type CreateLogRequest struct {
 	Status string `json:"status" binding:"required,oneof=Debug Info Warning Error Critical" example:"Info" enums:"Debug,Info,Warning,Error,Critical"`
 	Msg    string `json:"msg" binding:"required,min=1" example:"Операция выполнена успешно"`
+	// Время события на стороне клиента (RFC3339); по умолчанию — время приёма
+	Timestamp *time.Time `json:"timestamp,omitempty" example:"2025-01-15T11:58:00Z"`
+	// Идентификатор события на стороне клиента: повтор с тем же event_id вернёт ранее созданный лог
+	EventID *string `json:"event_id,omitempty" binding:"omitempty,max=255" example:"7f3c1e0a-retry-safe-id"`
+}
+
+// maxBatchSize — максимальное число записей в пакетном запросе
+const maxBatchSize = 1000
+
+// headerDropped — заголовок ответа для лога, отброшенного политикой хранения бота
+const headerDropped = "X-Log-Dropped"
+
+type CreateLogBatchResponse struct {
+	Items    []*models.Log `json:"items"`
+	Created  int           `json:"created" example:"2"`
+	Replayed int           `json:"replayed" example:"1"`
+	Queued   int           `json:"queued" example:"0"`
+	Dropped  int           `json:"dropped" example:"0"`
 }
 
+type ListLogsQuery struct {
+	BotID   *string    `form:"bot_id" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TeamID  *string    `form:"team_id" binding:"omitempty,uuid" example:"9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"`
+	Status  []string   `form:"status" binding:"omitempty,dive,oneof=Debug Info Warning Error Critical" example:"Error"`
+	Version *string    `form:"version" binding:"omitempty,max=100" example:"1.4.2"`
+	From    *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-01-01T00:00:00Z"`
+	To      *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-02-01T00:00:00Z"`
+	Q       string     `form:"q" binding:"omitempty,max=500" example:"ошибка подключения"`
+	Sort    string     `form:"sort" binding:"omitempty,oneof=created_at received_at" example:"created_at"`
+	Limit   int        `form:"limit" binding:"omitempty,min=1,max=1000" example:"100"`
+	Cursor  string     `form:"cursor"`
+}
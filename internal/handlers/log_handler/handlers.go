@@ -1,16 +1,22 @@
 package log_handler
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/idempotency"
 	"logging_api/internal/utils/validator_error_handling"
 
 	"github.com/gin-gonic/gin"
 )
 
 type LogService interface {
-	CreateLog(botID *string, status, msg string) (*models.Log, error)
+	CreateLog(botID *string, status, msg string, timestamp *time.Time, key *models.IdempotencyKey) (*models.Log, models.WriteResult, error)
+	CreateLogBatch(entries []*models.Log, keys []*models.IdempotencyKey) ([]models.WriteResult, error)
+	ListLogs(filter *models.LogFilter, cursor string) (*models.LogPage, error)
 }
 
 type LogHandler struct {
@@ -24,15 +30,27 @@ func NewLogHandler(logService LogService) *LogHandler {
 }
 
 // @Summary Создать лог
-// @Description Создаёт новый лог от имени текущего бота (требуется авторизация)
+// @Description Создаёт новый лог от имени текущего бота (требуется авторизация).
+// @Description Необязательный timestamp задаёт время события (для логов, накопленных офлайн); время приёма сохраняется в received_at.
+// @Description Ключ идемпотентности (заголовок Idempotency-Key или поле event_id) защищает от дублей при повторах:
+// @Description повтор с тем же ключом возвращает ранее созданный лог со статусом 200 и заголовком Idempotent-Replayed: true.
+// @Description Повтор ключа с другим телом запроса отклоняется со статусом 422.
+// @Description При включённой асинхронной записи лог без ключа ставится в очередь: ответ 202, id ещё не назначен.
+// @Description Лог, отброшенный политикой хранения бота (минимальный уровень, семплирование), не сохраняется:
+// @Description ответ 202 с заголовком X-Log-Dropped: true.
 // @Tags logs
-// @Accept json
+// @Accept json,application/msgpack
 // @Produce json
 // @Security BearerAuth
+// @Param Idempotency-Key header string false "Ключ идемпотентности (до 255 символов)"
 // @Param request body CreateLogRequest true "Данные лога"
+// @Success 200 {object} models.Log "Повтор запроса: ранее созданный лог"
 // @Success 201 {object} models.Log
+// @Success 202 {object} models.Log "Лог поставлен в очередь записи или отброшен политикой хранения"
+// @Header 202 {string} X-Log-Dropped "true, если лог отброшен политикой хранения бота"
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{} "Ключ идемпотентности уже использован с другим телом запроса"
 // @Failure 500 {object} map[string]interface{}
 // @Router /v1/logs [post]
 func (h *LogHandler) CreateLog(c *gin.Context) {
@@ -47,29 +65,218 @@ func (h *LogHandler) CreateLog(c *gin.Context) {
 		return
 	}
 
-	botID, exists := c.Get("bot_id")
-	if !exists {
-		log, err := h.logService.CreateLog(nil, request.Status, request.Msg)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	key, err := idempotency.Key(c, request.EventID, request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var botIDPtr *string
+	if botIDStr := c.GetString("bot_id"); botIDStr != "" {
+		botIDPtr = &botIDStr
+	}
+
+	log, result, err := h.logService.CreateLog(botIDPtr, request.Status, request.Msg, request.Timestamp, key)
+	if err != nil {
+		if customerrors.IsUnprocessable(err) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch result {
+	case models.WriteReplayed:
+		idempotency.MarkReplayed(c)
+		c.JSON(http.StatusOK, log)
+	case models.WriteQueued:
+		c.JSON(http.StatusAccepted, log)
+	case models.WriteDropped:
+		c.Header(headerDropped, "true")
+		c.JSON(http.StatusAccepted, log)
+	default:
 		c.JSON(http.StatusCreated, log)
+	}
+}
+
+// @Summary Создать пачку логов
+// @Description Создаёт до 1000 логов одним запросом от имени текущего бота (требуется авторизация). Пачка сохраняется целиком или не сохраняется вовсе.
+// @Description Ключ идемпотентности записи — её event_id, иначе заголовок Idempotency-Key с номером записи (":0", ":1", ...).
+// @Description Записи с уже использованным ключом не создаются заново: в items возвращаются ранее созданные логи.
+// @Description Если ключ записи уже использован с другим телом, пачка отклоняется целиком со статусом 422.
+// @Description Статус 201, если создана хотя бы одна запись, иначе 200. При включённой асинхронной записи пачка без ключей
+// @Description ставится в очередь: ответ 202, id записей ещё не назначены. Записи, отброшенные политикой хранения бота,
+// @Description не сохраняются и учитываются в dropped; если отброшены все, ответ 202.
+// @Tags logs
+// @Accept json,application/x-ndjson,application/msgpack
+// @Produce json
+// @Security BearerAuth
+// @Param Idempotency-Key header string false "Ключ идемпотентности пачки (до 255 символов)"
+// @Param request body []CreateLogRequest true "Логи"
+// @Success 200 {object} CreateLogBatchResponse "Все записи — повторы"
+// @Success 201 {object} CreateLogBatchResponse
+// @Success 202 {object} CreateLogBatchResponse "Пачка поставлена в очередь записи"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{} "Ключ идемпотентности уже использован с другим телом запроса"
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/logs/batch [post]
+func (h *LogHandler) CreateLogBatch(c *gin.Context) {
+	var request []CreateLogRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
 		return
 	}
 
-	botIDStr := botID.(string)
+	if len(request) == 0 || len(request) > maxBatchSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "пачка должна содержать от 1 до 1000 записей"})
+		return
+	}
 
 	var botIDPtr *string
-	if botIDStr != "" {
+	if botIDStr := c.GetString("bot_id"); botIDStr != "" {
 		botIDPtr = &botIDStr
 	}
 
-	log, err := h.logService.CreateLog(botIDPtr, request.Status, request.Msg)
+	entries := make([]*models.Log, len(request))
+	keys := make([]*models.IdempotencyKey, len(request))
+	for i, item := range request {
+		key, err := idempotency.ItemKey(c, item.EventID, i, item)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("запись %d: %v", i, err)})
+			return
+		}
+		keys[i] = key
+
+		entries[i] = &models.Log{BotID: botIDPtr, Status: item.Status, Msg: item.Msg}
+		if item.Timestamp != nil {
+			entries[i].CreatedAt = *item.Timestamp
+		}
+	}
+
+	results, err := h.logService.CreateLogBatch(entries, keys)
 	if err != nil {
+		if customerrors.IsUnprocessable(err) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := CreateLogBatchResponse{Items: entries}
+	for _, result := range results {
+		switch result {
+		case models.WriteReplayed:
+			response.Replayed++
+		case models.WriteQueued:
+			response.Queued++
+		case models.WriteDropped:
+			response.Dropped++
+		default:
+			response.Created++
+		}
+	}
+
+	if response.Queued > 0 || response.Dropped == len(results) {
+		c.JSON(http.StatusAccepted, response)
+		return
+	}
+	if response.Created == 0 {
+		idempotency.MarkReplayed(c)
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// @Summary Получить логи
+// @Description Возвращает логи с фильтрами и постраничной выдачей (новые первыми). Обычные токены видят только логи своего бота, токены команды — логи ботов команды.
+// @Description Параметр q включает полнотекстовый поиск (русский и английский): слова через пробел — И, "фраза в кавычках", OR — ИЛИ, -слово — исключение.
+// @Description При поиске результаты упорядочены по релевантности и содержат rank и headline с подсветкой совпадений (<b>…</b>).
+// @Tags logs
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id query string false "ID бота (UUID, для админских токенов и токенов команды)"
+// @Param team_id query string false "ID текущей команды бота (UUID)"
+// @Param status query []string false "Уровни лога" collectionFormat(multi) Enums(Debug, Info, Warning, Error, Critical)
+// @Param version query string false "Версия бота на момент записи лога"
+// @Param from query string false "Начало периода (RFC3339, включительно)"
+// @Param to query string false "Конец периода (RFC3339, не включительно)"
+// @Param q query string false "Полнотекстовый запрос"
+// @Param sort query string false "Поле времени для сортировки и периода from/to (по умолчанию created_at)" Enums(created_at, received_at)
+// @Param limit query int false "Размер страницы (по умолчанию 100, максимум 1000)"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Success 200 {object} models.LogPage
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/logs [get]
+func (h *LogHandler) ListLogs(c *gin.Context) {
+	var query ListLogsQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	filter := &models.LogFilter{
+		BotID:    query.BotID,
+		TeamID:   query.TeamID,
+		Statuses: query.Status,
+		Version:  query.Version,
+		From:     query.From,
+		To:       query.To,
+		Query:    query.Q,
+		Limit:    query.Limit,
+		SortBy:   query.Sort,
+	}
+
+	// Обычные токены видят только логи своего бота, токены команды — логи ботов команды
+	if teamID := c.GetString("team_id"); teamID != "" {
+		if query.TeamID != nil && *query.TeamID != teamID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "доступ к логам другой команды запрещён"})
+			return
+		}
+		filter.TeamID = &teamID
+	} else if !c.GetBool("is_admin") {
+		botID := c.GetString("bot_id")
+		if botID == "" || (query.BotID != nil && *query.BotID != botID) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "доступ к логам другого бота запрещён"})
+			return
+		}
+		filter.BotID = &botID
+	}
+
+	page, err := h.logService.ListLogs(filter, query.Cursor)
+	if err != nil {
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, log)
+	c.JSON(http.StatusOK, page)
 }
@@ -0,0 +1,19 @@
+package log_policy_handler
+
+import "time"
+
+type SetLogPolicyRequest struct {
+	MinLevel    *string            `json:"min_level,omitempty" binding:"omitempty,oneof=Debug Info Warning Error Critical" example:"Info" enums:"Debug,Info,Warning,Error,Critical"`
+	SampleRates map[string]float64 `json:"sample_rates,omitempty" swaggertype:"object,number"`
+}
+
+type SetOverrideRequest struct {
+	Level           string `json:"level" binding:"required,oneof=Debug Info Warning Error Critical" example:"Debug" enums:"Debug,Info,Warning,Error,Critical"`
+	DurationMinutes int    `json:"duration_minutes" binding:"required,min=1,max=1440" example:"120"`
+}
+
+type LogDropsQuery struct {
+	BotID *string    `form:"bot_id" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	From  *time.Time `form:"from" time_format:"2006-01-02" example:"2025-01-01"`
+	To    *time.Time `form:"to" time_format:"2006-01-02" example:"2025-01-31"`
+}
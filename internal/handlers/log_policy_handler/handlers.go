@@ -0,0 +1,235 @@
+package log_policy_handler
+
+import (
+	"net/http"
+	"time"
+
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/validator_error_handling"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LogPolicyService interface {
+	GetPolicy(botID string) (*models.LogPolicy, error)
+	SetPolicy(botID string, minLevel *string, sampleRates map[string]float64) (*models.LogPolicy, error)
+	SetOverride(botID, level string, durationMinutes int) (*models.LogPolicy, error)
+	ClearOverride(botID string) (*models.LogPolicy, error)
+	DeletePolicy(botID string) error
+	ListDropCounts(botID *string, from, to *time.Time) ([]*models.LogDropCount, error)
+}
+
+type LogPolicyHandler struct {
+	logPolicyService LogPolicyService
+}
+
+func NewLogPolicyHandler(logPolicyService LogPolicyService) *LogPolicyHandler {
+	return &LogPolicyHandler{
+		logPolicyService: logPolicyService,
+	}
+}
+
+// @Summary Получить политику хранения логов бота
+// @Description Возвращает минимальный уровень, семплирование и временный уровень бота (требуется админский токен)
+// @Tags log-policies
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id path string true "ID бота (UUID)"
+// @Success 200 {object} models.LogPolicy
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bots/{bot_id}/log-policy [get]
+func (h *LogPolicyHandler) GetPolicy(c *gin.Context) {
+	policy, err := h.logPolicyService.GetPolicy(c.Param("bot_id"))
+	if err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// @Summary Задать политику хранения логов бота
+// @Description Задаёт минимальный сохраняемый уровень и долю сохраняемых логов по уровням (требуется админский токен).
+// @Description Например, {"min_level": "Info", "sample_rates": {"Info": 0.01}} — Debug не сохраняется, из Info сохраняется 1%.
+// @Description Уровень без значения в sample_rates сохраняется полностью. Временный уровень не меняется.
+// @Tags log-policies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id path string true "ID бота (UUID)"
+// @Param request body SetLogPolicyRequest true "Политика"
+// @Success 200 {object} models.LogPolicy
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bots/{bot_id}/log-policy [put]
+func (h *LogPolicyHandler) SetPolicy(c *gin.Context) {
+	var request SetLogPolicyRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	policy, err := h.logPolicyService.SetPolicy(c.Param("bot_id"), request.MinLevel, request.SampleRates)
+	if err != nil {
+		switch {
+		case customerrors.IsNotFound(err):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case customerrors.IsInvalidInput(err):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// @Summary Удалить политику хранения логов бота
+// @Description Удаляет политику: логи бота снова сохраняются полностью (требуется админский токен)
+// @Tags log-policies
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id path string true "ID бота (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bots/{bot_id}/log-policy [delete]
+func (h *LogPolicyHandler) DeletePolicy(c *gin.Context) {
+	if err := h.logPolicyService.DeletePolicy(c.Param("bot_id")); err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "политика хранения логов удалена"})
+}
+
+// @Summary Временно снизить уровень логов бота
+// @Description На duration_minutes минут (до суток) заменяет минимальный уровень бота на level; логи с этого уровня
+// @Description сохраняются без семплирования. Например, {"level": "Debug", "duration_minutes": 120} — сохранять всё
+// @Description следующие 2 часа на время разбора проблемы (требуется админский токен).
+// @Tags log-policies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id path string true "ID бота (UUID)"
+// @Param request body SetOverrideRequest true "Временный уровень"
+// @Success 200 {object} models.LogPolicy
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bots/{bot_id}/log-policy/override [put]
+func (h *LogPolicyHandler) SetOverride(c *gin.Context) {
+	var request SetOverrideRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	policy, err := h.logPolicyService.SetOverride(c.Param("bot_id"), request.Level, request.DurationMinutes)
+	if err != nil {
+		switch {
+		case customerrors.IsNotFound(err):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case customerrors.IsInvalidInput(err):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// @Summary Снять временный уровень логов бота
+// @Description Досрочно возвращает боту минимальный уровень и семплирование из политики (требуется админский токен)
+// @Tags log-policies
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id path string true "ID бота (UUID)"
+// @Success 200 {object} models.LogPolicy
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/bots/{bot_id}/log-policy/override [delete]
+func (h *LogPolicyHandler) ClearOverride(c *gin.Context) {
+	policy, err := h.logPolicyService.ClearOverride(c.Param("bot_id"))
+	if err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// @Summary Отброшенные логи
+// @Description Возвращает число логов, отброшенных политиками хранения, по ботам, уровням и причинам за период
+// @Description (level — ниже минимального уровня, sampled — не попал в выборку; требуется админский токен).
+// @Description Счётчики сохраняются периодически (log_policy.stats_flush_interval_sec), последние могут появиться с задержкой.
+// @Tags log-policies
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id query string false "ID бота (UUID)"
+// @Param from query string false "Первый день периода (YYYY-MM-DD, UTC)"
+// @Param to query string false "Последний день периода (YYYY-MM-DD, UTC)"
+// @Success 200 {array} models.LogDropCount
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/log-drops [get]
+func (h *LogPolicyHandler) GetDrops(c *gin.Context) {
+	var query LogDropsQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	counts, err := h.logPolicyService.ListDropCounts(query.BotID, query.From, query.To)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
@@ -0,0 +1,153 @@
+package loki_handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"logging_api/internal/models"
+	"logging_api/internal/utils/body"
+	"logging_api/internal/utils/loglevel"
+	"logging_api/pkg/loki"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	botCodeLabel = "bot_code"
+	levelLabel   = "level"
+)
+
+type LogService interface {
+	CreateLogs(entries []*models.Log) ([]models.WriteResult, error)
+	ResolveBotID(code string) *string
+}
+
+type LokiHandler struct {
+	logService   LogService
+	maxBodyBytes int64
+}
+
+func NewLokiHandler(logService LogService, maxBodyBytes int64) *LokiHandler {
+	return &LokiHandler{
+		logService:   logService,
+		maxBodyBytes: maxBodyBytes,
+	}
+}
+
+// @Summary Приём логов в формате Loki
+// @Description Совместим с POST /loki/api/v1/push: snappy-сжатый protobuf (application/x-protobuf) или JSON (application/json, можно gzip).
+// @Description Уровень берётся из метки level, метки потока и structured metadata сохраняются в attributes, время записи становится created_at.
+// @Description Записи со временем вне допустимого отклонения отклоняются ответом 400, остальные сохраняются.
+// @Description Логи привязываются к боту токена; для админского токена бот определяется по метке bot_code.
+// @Tags loki
+// @Accept application/x-protobuf
+// @Accept json
+// @Security BearerAuth
+// @Success 204 "Логи приняты"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 413 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /loki/api/v1/push [post]
+func (h *LokiHandler) Push(c *gin.Context) {
+	mediaType := body.MediaType(c.Request)
+	if mediaType != "application/x-protobuf" && mediaType != "application/json" {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "поддерживаются только application/x-protobuf и application/json"})
+		return
+	}
+
+	data, err := body.Read(c.Request, h.maxBodyBytes)
+	if err != nil {
+		switch {
+		case errors.Is(err, body.ErrTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		case errors.Is(err, body.ErrUnsupportedEncoding):
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	var request *loki.PushRequest
+	if mediaType == "application/json" {
+		request, err = loki.DecodePushJSON(data)
+	} else {
+		request, err = loki.DecodePushProto(data, h.maxBodyBytes)
+	}
+	if err != nil {
+		if errors.Is(err, loki.ErrTooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": body.ErrTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logs := toLogs(request, h.botResolver(c))
+
+	results, err := h.logService.CreateLogs(logs)
+	if err != nil {
+		log.Printf("Ошибка сохранения логов Loki: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "ошибка сохранения логов"})
+		return
+	}
+
+	// Как и Loki, остальные записи сохраняются, а об отклонённых сообщается ответом 400
+	var rejected int
+	for _, result := range results {
+		if result == models.WriteRejected {
+			rejected++
+		}
+	}
+	if rejected > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("отклонено записей со временем вне допустимого диапазона: %d из %d", rejected, len(logs))})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// botResolver возвращает функцию выбора бота: бот токена, а для админского токена — бот с кодом из метки bot_code
+func (h *LokiHandler) botResolver(c *gin.Context) func(code string) *string {
+	if botID := c.GetString("bot_id"); botID != "" {
+		return func(string) *string { return &botID }
+	}
+	if !c.GetBool("is_admin") {
+		return func(string) *string { return nil }
+	}
+	return h.logService.ResolveBotID
+}
+
+// toLogs преобразует потоки Loki в логи: метки потока и structured metadata записи становятся атрибутами,
+// уровень определяется по метке level (в метаданных записи или в метках потока), время записи становится created_at
+func toLogs(request *loki.PushRequest, resolveBot func(code string) *string) []*models.Log {
+	var logs []*models.Log
+	for _, stream := range request.Streams {
+		botID := resolveBot(stream.Labels[botCodeLabel])
+
+		for _, entry := range stream.Entries {
+			attributes := models.JSONB{}
+			for name, value := range stream.Labels {
+				attributes[name] = value
+			}
+			for name, value := range entry.StructuredMetadata {
+				attributes[name] = value
+			}
+
+			level, _ := attributes[levelLabel].(string)
+
+			logs = append(logs, &models.Log{
+				BotID:      botID,
+				Status:     loglevel.FromText(level, "Info"),
+				Msg:        entry.Line,
+				Attributes: attributes,
+				CreatedAt:  entry.Timestamp,
+			})
+		}
+	}
+	return logs
+}
@@ -0,0 +1,118 @@
+package otlp_handler
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"logging_api/internal/models"
+	"logging_api/pkg/otlp"
+)
+
+const serviceNameAttribute = "service.name"
+
+// toLogs преобразует записи OTLP в логи; время записи (time_unix_nano, иначе observed_time_unix_nano) становится
+// created_at. resolveBot возвращает бота для ресурса по service.name.
+// Записи без тела и имени события отклоняются и учитываются в rejected.
+func toLogs(request *otlp.ExportLogsRequest, resolveBot func(serviceName string) *string) (logs []*models.Log, rejected int64) {
+	for _, resourceLogs := range request.ResourceLogs {
+		resourceAttributes := otlp.AttributesMap(resourceLogs.Resource.Attributes)
+		serviceName, _ := resourceAttributes[serviceNameAttribute].(string)
+		botID := resolveBot(serviceName)
+
+		for _, scopeLogs := range resourceLogs.ScopeLogs {
+			scope := scopeMap(scopeLogs.Scope)
+
+			for _, record := range scopeLogs.LogRecords {
+				msg := bodyText(record.Body)
+				if msg == "" {
+					msg = record.EventName
+				}
+				if msg == "" {
+					rejected++
+					continue
+				}
+
+				attributes := models.JSONB(otlp.AttributesMap(record.Attributes))
+				if attributes == nil {
+					attributes = models.JSONB{}
+				}
+				if resourceAttributes != nil {
+					attributes["otel.resource"] = resourceAttributes
+				}
+				if scope != nil {
+					attributes["otel.scope"] = scope
+				}
+				if len(record.TraceID) > 0 {
+					attributes["otel.trace_id"] = hex.EncodeToString(record.TraceID)
+				}
+				if len(record.SpanID) > 0 {
+					attributes["otel.span_id"] = hex.EncodeToString(record.SpanID)
+				}
+				if record.SeverityText != "" {
+					attributes["otel.severity_text"] = record.SeverityText
+				}
+				if record.EventName != "" {
+					attributes["otel.event_name"] = record.EventName
+				}
+
+				entry := &models.Log{
+					BotID:      botID,
+					Status:     otlp.Level(record.SeverityNumber, record.SeverityText),
+					Msg:        msg,
+					Attributes: attributes,
+				}
+				if ts := recordTime(record); ts != nil {
+					entry.CreatedAt = *ts
+				}
+				logs = append(logs, entry)
+			}
+		}
+	}
+	return logs, rejected
+}
+
+func scopeMap(scope otlp.Scope) map[string]interface{} {
+	if scope.Name == "" && scope.Version == "" && len(scope.Attributes) == 0 {
+		return nil
+	}
+	result := map[string]interface{}{}
+	if scope.Name != "" {
+		result["name"] = scope.Name
+	}
+	if scope.Version != "" {
+		result["version"] = scope.Version
+	}
+	if attributes := otlp.AttributesMap(scope.Attributes); attributes != nil {
+		result["attributes"] = attributes
+	}
+	return result
+}
+
+// bodyText возвращает строковое тело как есть, а структурированное — в виде JSON
+func bodyText(body *otlp.AnyValue) string {
+	value := body.Interface()
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func recordTime(record otlp.LogRecord) *time.Time {
+	nanos := record.TimeUnixNano
+	if nanos == 0 {
+		nanos = record.ObservedTimeUnixNano
+	}
+	if nanos == 0 {
+		return nil
+	}
+	ts := time.Unix(0, int64(nanos)).UTC()
+	return &ts
+}
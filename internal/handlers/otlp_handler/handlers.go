@@ -0,0 +1,145 @@
+package otlp_handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"logging_api/internal/models"
+	"logging_api/internal/utils/body"
+	"logging_api/pkg/otlp"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeJSON     = "application/json"
+
+	// Коды google.rpc.Code для тела ответа с ошибкой
+	codeInvalidArgument = 3
+	codeInternal        = 13
+)
+
+type LogService interface {
+	CreateLogs(entries []*models.Log) ([]models.WriteResult, error)
+	ResolveBotID(code string) *string
+}
+
+type OTLPHandler struct {
+	logService   LogService
+	maxBodyBytes int64
+}
+
+func NewOTLPHandler(logService LogService, maxBodyBytes int64) *OTLPHandler {
+	return &OTLPHandler{
+		logService:   logService,
+		maxBodyBytes: maxBodyBytes,
+	}
+}
+
+// @Summary Приём логов OpenTelemetry (OTLP/HTTP)
+// @Description Принимает ExportLogsServiceRequest в protobuf (application/x-protobuf) или JSON (application/json), тело может быть сжато gzip.
+// @Description severity_number сопоставляется уровню лога, время записи становится created_at, атрибуты записи, ресурса и scope сохраняются в attributes.
+// @Description Записи без тела и со временем вне допустимого отклонения отклоняются (partialSuccess).
+// @Description Логи привязываются к боту токена; для админского токена бот определяется по атрибуту ресурса service.name (код бота).
+// @Tags otlp
+// @Accept application/x-protobuf
+// @Accept json
+// @Produce application/x-protobuf
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "ExportLogsServiceResponse (partialSuccess при отклонённых записях)"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 413 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/otlp/logs [post]
+func (h *OTLPHandler) ExportLogs(c *gin.Context) {
+	var isJSON bool
+	switch body.MediaType(c.Request) {
+	case contentTypeProtobuf, "application/protobuf":
+	case contentTypeJSON:
+		isJSON = true
+	default:
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "поддерживаются только application/x-protobuf и application/json"})
+		return
+	}
+
+	data, err := body.Read(c.Request, h.maxBodyBytes)
+	if err != nil {
+		switch {
+		case errors.Is(err, body.ErrTooLarge):
+			writeStatus(c, isJSON, http.StatusRequestEntityTooLarge, codeInvalidArgument, err.Error())
+		case errors.Is(err, body.ErrUnsupportedEncoding):
+			writeStatus(c, isJSON, http.StatusUnsupportedMediaType, codeInvalidArgument, err.Error())
+		default:
+			writeStatus(c, isJSON, http.StatusBadRequest, codeInvalidArgument, err.Error())
+		}
+		return
+	}
+
+	var request *otlp.ExportLogsRequest
+	if isJSON {
+		request, err = otlp.DecodeLogsJSON(data)
+	} else {
+		request, err = otlp.DecodeLogsProto(data)
+	}
+	if err != nil {
+		writeStatus(c, isJSON, http.StatusBadRequest, codeInvalidArgument, err.Error())
+		return
+	}
+
+	logs, noBody := toLogs(request, h.botResolver(c))
+
+	results, err := h.logService.CreateLogs(logs)
+	if err != nil {
+		log.Printf("Ошибка сохранения логов OTLP: %v", err)
+		writeStatus(c, isJSON, http.StatusInternalServerError, codeInternal, "ошибка сохранения логов")
+		return
+	}
+
+	var outOfRange int64
+	for _, result := range results {
+		if result == models.WriteRejected {
+			outOfRange++
+		}
+	}
+
+	var reasons []string
+	if noBody > 0 {
+		reasons = append(reasons, fmt.Sprintf("отклонено записей без тела: %d", noBody))
+	}
+	if outOfRange > 0 {
+		reasons = append(reasons, fmt.Sprintf("отклонено записей со временем вне допустимого диапазона: %d", outOfRange))
+	}
+	rejected := noBody + outOfRange
+	message := strings.Join(reasons, "; ")
+	if isJSON {
+		c.Data(http.StatusOK, contentTypeJSON, otlp.EncodeExportResponseJSON(rejected, message))
+		return
+	}
+	c.Data(http.StatusOK, contentTypeProtobuf, otlp.EncodeExportResponse(rejected, message))
+}
+
+// botResolver возвращает функцию выбора бота: бот токена, а для админского токена — бот с кодом из service.name
+func (h *OTLPHandler) botResolver(c *gin.Context) func(serviceName string) *string {
+	if botID := c.GetString("bot_id"); botID != "" {
+		return func(string) *string { return &botID }
+	}
+	if !c.GetBool("is_admin") {
+		return func(string) *string { return nil }
+	}
+	return h.logService.ResolveBotID
+}
+
+func writeStatus(c *gin.Context, isJSON bool, httpStatus int, code int32, message string) {
+	if isJSON {
+		c.Data(httpStatus, contentTypeJSON, otlp.EncodeStatusJSON(code, message))
+		return
+	}
+	c.Data(httpStatus, contentTypeProtobuf, otlp.EncodeStatus(code, message))
+}
@@ -1,11 +1,27 @@
 package owner_handler
 
+import "logging_api/internal/models"
+
 type CreateOwnerRequest struct {
-	FullName string `json:"full_name" binding:"required,min=2,max=255" example:"Иван Иванов"`
-	IsActive bool   `json:"is_active" example:"true"`
+	FullName string                     `json:"full_name" binding:"required,min=2,max=255" example:"Иван Иванов"`
+	IsActive bool                       `json:"is_active" example:"true"`
+	Contacts *models.OwnerContactsInput `json:"contacts,omitempty"`
 }
 
 type UpdateOwnerRequest struct {
 	FullName *string `json:"full_name,omitempty" binding:"omitempty,min=2,max=255" example:"Иван Петров"`
 	IsActive *bool   `json:"is_active,omitempty" example:"true"`
+	// Contacts — нет: контакты без изменений; есть: заменяют все контакты (не указанные удаляются)
+	Contacts *models.OwnerContactsInput `json:"contacts,omitempty"`
+}
+
+type ListOwnersQuery struct {
+	IsActive *bool   `form:"is_active" example:"true"`
+	Q        string  `form:"q" binding:"omitempty,max=255" example:"Иванов"`
+	TeamID   *string `form:"team_id" binding:"omitempty,uuid" example:"9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c"`
+	Archived string  `form:"archived" binding:"omitempty,oneof=exclude include only" example:"exclude"`
+	Sort     string  `form:"sort" binding:"omitempty,oneof=created_at full_name" example:"full_name"`
+	Order    string  `form:"order" binding:"omitempty,oneof=asc desc" example:"asc"`
+	Limit    int     `form:"limit" binding:"omitempty,min=1,max=1000" example:"100"`
+	Cursor   string  `form:"cursor"`
 }
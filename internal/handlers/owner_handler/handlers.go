@@ -11,11 +11,15 @@ import (
 )
 
 type OwnerService interface {
-	CreateOwner(fullName string, isActive bool) (*models.Owner, error)
+	CreateOwner(fullName string, isActive bool, contacts models.OwnerContacts) (*models.Owner, error)
 	GetOwnerByID(ownerID string) (*models.Owner, error)
-	GetAllOwners() ([]*models.Owner, error)
-	UpdateOwner(ownerID string, fullName *string, isActive *bool) (*models.Owner, error)
-	DeleteOwner(ownerID string) error
+	ListOwners(filter *models.OwnerFilter, cursor string) (*models.OwnerPage, error)
+	ListAllOwners(filter *models.OwnerFilter) ([]*models.Owner, error)
+	UpdateOwner(ownerID string, fullName *string, isActive *bool, contacts *models.OwnerContacts) (*models.Owner, error)
+	ArchiveOwner(ownerID, archivedBy string) (*models.Owner, error)
+	RestoreOwner(ownerID string) (*models.Owner, error)
+	GetOwnerPurgeReport(ownerID string) (*models.OwnerPurgeReport, error)
+	PurgeOwner(ownerID, purgedBy string) (*models.OwnerPurgeReport, error)
 }
 
 type OwnerHandler struct {
@@ -29,7 +33,8 @@ func NewOwnerHandler(ownerService OwnerService) *OwnerHandler {
 }
 
 // @Summary Создать владельца
-// @Description Создаёт нового владельца (требуется админский токен)
+// @Description Создаёт нового владельца (требуется админский токен). Для каждого канала из contacts.notify_channels
+// @Description должен быть указан соответствующий контакт.
 // @Tags owners
 // @Accept json
 // @Produce json
@@ -53,8 +58,17 @@ func (h *OwnerHandler) CreateOwner(c *gin.Context) {
 		return
 	}
 
-	owner, err := h.ownerService.CreateOwner(request.FullName, request.IsActive)
+	var contacts models.OwnerContacts
+	if request.Contacts != nil {
+		contacts = *request.Contacts.Contacts()
+	}
+
+	owner, err := h.ownerService.CreateOwner(request.FullName, request.IsActive, contacts)
 	if err != nil {
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -90,28 +104,75 @@ func (h *OwnerHandler) GetOwner(c *gin.Context) {
 	c.JSON(http.StatusOK, owner)
 }
 
-// @Summary Получить всех владельцев
-// @Description Возвращает список всех владельцев (требуется админский токен)
+// @Summary Получить владельцев
+// @Description Возвращает владельцев с фильтрами и постраничной выдачей (требуется админский токен).
+// @Description total — число владельцев, подходящих под фильтр. По умолчанию новые первыми; full_name сортируется по возрастанию.
+// @Description Без limit и cursor ответ — массив всех подходящих владельцев (прежний формат, для совместимости со старыми клиентами).
 // @Tags owners
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} models.Owner
+// @Param is_active query bool false "Активность"
+// @Param q query string false "Подстрока в имени (без учёта регистра)"
+// @Param team_id query string false "Только участники команды (UUID)"
+// @Param archived query string false "Владельцы в архиве: exclude — скрыть (по умолчанию), include — показать вместе с остальными, only — только они" Enums(exclude, include, only)
+// @Param sort query string false "Поле сортировки (по умолчанию created_at)" Enums(created_at, full_name)
+// @Param order query string false "Направление сортировки" Enums(asc, desc)
+// @Param limit query int false "Размер страницы (по умолчанию 100, максимум 1000)"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor (с теми же sort и order)"
+// @Success 200 {object} models.OwnerPage
+// @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /v1/owners [get]
-func (h *OwnerHandler) GetAllOwners(c *gin.Context) {
-	owners, err := h.ownerService.GetAllOwners()
+func (h *OwnerHandler) ListOwners(c *gin.Context) {
+	var query ListOwnersQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	filter := &models.OwnerFilter{
+		IsActive: query.IsActive,
+		Query:    query.Q,
+		TeamID:   query.TeamID,
+		Archived: query.Archived,
+		SortBy:   query.Sort,
+		Order:    query.Order,
+		Limit:    query.Limit,
+	}
+
+	// Без параметров постраничной выдачи — прежний формат ответа: массив всех подходящих записей
+	if query.Limit == 0 && query.Cursor == "" {
+		items, err := h.ownerService.ListAllOwners(filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, items)
+		return
+	}
+
+	page, err := h.ownerService.ListOwners(filter, query.Cursor)
 	if err != nil {
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, owners)
+	c.JSON(http.StatusOK, page)
 }
 
 // @Summary Обновить владельца
-// @Description Обновляет данные владельца (требуется админский токен)
+// @Description Обновляет данные владельца (требуется админский токен). Переданный contacts заменяет все контакты.
 // @Tags owners
 // @Accept json
 // @Produce json
@@ -138,12 +199,16 @@ func (h *OwnerHandler) UpdateOwner(c *gin.Context) {
 		return
 	}
 
-	owner, err := h.ownerService.UpdateOwner(ownerID, request.FullName, request.IsActive)
+	owner, err := h.ownerService.UpdateOwner(ownerID, request.FullName, request.IsActive, request.Contacts.Contacts())
 	if err != nil {
 		if customerrors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -151,25 +216,121 @@ func (h *OwnerHandler) UpdateOwner(c *gin.Context) {
 	c.JSON(http.StatusOK, owner)
 }
 
-// @Summary Удалить владельца
-// @Description Полностью удаляет владельца из базы данных (требуется админский токен)
+// @Summary Перенести владельца в архив
+// @Description Переносит владельца в архив (требуется админский токен): владелец скрывается из списков, его боты
+// @Description сохраняют привязку к нему. Повторный вызов ничего не меняет.
 // @Tags owners
 // @Produce json
 // @Security BearerAuth
 // @Param owner_id path string true "ID владельца (UUID)"
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} models.Owner
 // @Failure 401 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /v1/owners/{owner_id} [delete]
-func (h *OwnerHandler) DeleteOwner(c *gin.Context) {
+func (h *OwnerHandler) ArchiveOwner(c *gin.Context) {
+	ownerID := c.Param("owner_id")
+
+	owner, err := h.ownerService.ArchiveOwner(ownerID, c.GetString("token_id"))
+	if err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, owner)
+}
+
+// @Summary Восстановить владельца из архива
+// @Description Возвращает владельца из архива (требуется админский токен). Для владельца не в архиве ничего не меняет.
+// @Tags owners
+// @Produce json
+// @Security BearerAuth
+// @Param owner_id path string true "ID владельца (UUID)"
+// @Success 200 {object} models.Owner
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/owners/{owner_id}/restore [post]
+func (h *OwnerHandler) RestoreOwner(c *gin.Context) {
 	ownerID := c.Param("owner_id")
 
-	err := h.ownerService.DeleteOwner(ownerID)
+	owner, err := h.ownerService.RestoreOwner(ownerID)
 	if err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, owner)
+}
+
+// @Summary Что изменит окончательное удаление владельца
+// @Description Считает ботов, которые потеряют привязку к владельцу. Ничего не удаляет (требуется админский токен).
+// @Tags owners
+// @Produce json
+// @Security BearerAuth
+// @Param owner_id path string true "ID владельца (UUID)"
+// @Success 200 {object} models.OwnerPurgeReport
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/owners/{owner_id}/purge [get]
+func (h *OwnerHandler) GetPurgeReport(c *gin.Context) {
+	ownerID := c.Param("owner_id")
+
+	report, err := h.ownerService.GetOwnerPurgeReport(ownerID)
+	if err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Окончательно удалить владельца
+// @Description Удаляет владельца из базы данных; его боты остаются без владельца (это записывается в историю передачи). Удалить можно только владельца в архиве
+// @Description (требуется админский токен). Возвращает отчёт о фактически изменённом.
+// @Tags owners
+// @Produce json
+// @Security BearerAuth
+// @Param owner_id path string true "ID владельца (UUID)"
+// @Success 200 {object} models.OwnerPurgeReport
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/owners/{owner_id}/purge [delete]
+func (h *OwnerHandler) PurgeOwner(c *gin.Context) {
+	ownerID := c.Param("owner_id")
+
+	report, err := h.ownerService.PurgeOwner(ownerID, c.GetString("token_id"))
+	if err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if customerrors.IsConflict(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "владелец удалён"})
+	c.JSON(http.StatusOK, report)
 }
@@ -0,0 +1,9 @@
+package partition_handler
+
+import "logging_api/internal/models"
+
+type PartitionsResponse struct {
+	Partitions    []*models.LogPartition `json:"partitions"`
+	TotalBytes    int64                  `json:"total_bytes" example:"1073741824"`
+	RetentionDays map[string]int         `json:"retention_days"`
+}
@@ -0,0 +1,53 @@
+package partition_handler
+
+import (
+	"net/http"
+
+	"logging_api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PartitionService interface {
+	GetPartitions() ([]*models.LogPartition, error)
+	RetentionDays() map[string]int
+}
+
+type PartitionHandler struct {
+	partitionService PartitionService
+}
+
+func NewPartitionHandler(partitionService PartitionService) *PartitionHandler {
+	return &PartitionHandler{
+		partitionService: partitionService,
+	}
+}
+
+// @Summary Секции таблицы логов
+// @Description Возвращает месячные секции таблицы logs с размерами и текущую политику хранения по уровням (требуется админский токен)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} PartitionsResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/partitions [get]
+func (h *PartitionHandler) GetPartitions(c *gin.Context) {
+	partitions, err := h.partitionService.GetPartitions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var totalBytes int64
+	for _, partition := range partitions {
+		totalBytes += partition.TotalBytes
+	}
+
+	c.JSON(http.StatusOK, PartitionsResponse{
+		Partitions:    partitions,
+		TotalBytes:    totalBytes,
+		RetentionDays: h.partitionService.RetentionDays(),
+	})
+}
@@ -0,0 +1,9 @@
+package profile_handler
+
+import "logging_api/internal/models"
+
+type UpdateProfileRequest struct {
+	FullName *string `json:"full_name,omitempty" binding:"omitempty,min=2,max=255" example:"Иван Петров"`
+	// Contacts — нет: контакты без изменений; есть: заменяют все контакты (не указанные удаляются)
+	Contacts *models.OwnerContactsInput `json:"contacts,omitempty"`
+}
@@ -0,0 +1,95 @@
+package profile_handler
+
+import (
+	"net/http"
+
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/validator_error_handling"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileService interface {
+	GetOwnerByID(ownerID string) (*models.Owner, error)
+	UpdateProfile(ownerID string, fullName *string, contacts *models.OwnerContacts) (*models.Owner, error)
+}
+
+// ProfileHandler даёт владельцу доступ к своему профилю по токену владельца
+type ProfileHandler struct {
+	profileService ProfileService
+}
+
+func NewProfileHandler(profileService ProfileService) *ProfileHandler {
+	return &ProfileHandler{
+		profileService: profileService,
+	}
+}
+
+// @Summary Получить свой профиль
+// @Description Возвращает профиль владельца токена (требуется токен владельца)
+// @Tags profile
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.Owner
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/profile [get]
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	owner, err := h.profileService.GetOwnerByID(c.GetString("profile_owner_id"))
+	if err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, owner)
+}
+
+// @Summary Обновить свой профиль
+// @Description Обновляет имя и контакты владельца токена (требуется токен владельца). Переданный contacts заменяет
+// @Description все контакты; для каждого канала из notify_channels должен быть указан соответствующий контакт.
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateProfileRequest true "Обновлённые данные"
+// @Success 200 {object} models.Owner
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/profile [put]
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	var request UpdateProfileRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	owner, err := h.profileService.UpdateProfile(c.GetString("profile_owner_id"), request.FullName, request.Contacts.Contacts())
+	if err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, owner)
+}
@@ -0,0 +1,28 @@
+package redaction_handler
+
+import "time"
+
+type CreateRedactionRuleRequest struct {
+	BotID       *string `json:"bot_id,omitempty" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name        string  `json:"name" binding:"required,min=1,max=100" example:"contract_number"`
+	Pattern     string  `json:"pattern" binding:"required,max=1000" example:"Договор № (?P<pii>\\d{6,})"`
+	Replacement *string `json:"replacement,omitempty" binding:"omitempty,max=255" example:"[договор]"`
+	IsActive    *bool   `json:"is_active,omitempty" example:"true"`
+}
+
+type UpdateRedactionRuleRequest struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=100" example:"contract_number"`
+	Pattern     *string `json:"pattern,omitempty" binding:"omitempty,max=1000" example:"Договор № (?P<pii>\\d{6,})"`
+	Replacement *string `json:"replacement,omitempty" binding:"omitempty,max=255" example:"[договор]"`
+	IsActive    *bool   `json:"is_active,omitempty" example:"false"`
+}
+
+type ListRedactionRulesQuery struct {
+	BotID *string `form:"bot_id" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+type RedactionStatsQuery struct {
+	BotID *string    `form:"bot_id" binding:"omitempty,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	From  *time.Time `form:"from" time_format:"2006-01-02" example:"2025-01-01"`
+	To    *time.Time `form:"to" time_format:"2006-01-02" example:"2025-01-31"`
+}
@@ -0,0 +1,244 @@
+package redaction_handler
+
+import (
+	"net/http"
+	"time"
+
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/validator_error_handling"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RedactionService interface {
+	CreateRule(botID *string, name, pattern string, replacement *string, isActive bool) (*models.RedactionRule, error)
+	GetRuleByID(ruleID string) (*models.RedactionRule, error)
+	ListRules(botID *string) ([]*models.RedactionRule, error)
+	UpdateRule(ruleID string, name, pattern, replacement *string, isActive *bool) (*models.RedactionRule, error)
+	DeleteRule(ruleID string) error
+	ListCounts(botID *string, from, to *time.Time) ([]*models.RedactionCount, error)
+}
+
+type RedactionHandler struct {
+	redactionService RedactionService
+}
+
+func NewRedactionHandler(redactionService RedactionService) *RedactionHandler {
+	return &RedactionHandler{
+		redactionService: redactionService,
+	}
+}
+
+// @Summary Создать правило маскирования
+// @Description Создаёт правило маскирования сообщений логов (требуется админский токен).
+// @Description Без bot_id правило применяется ко всем логам. Выражение в синтаксисе RE2; если в нём есть группа (?P<pii>...),
+// @Description заменяется только она. Без replacement совпадение заменяется на [REDACTED:<name>].
+// @Tags redaction
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateRedactionRuleRequest true "Данные правила"
+// @Success 201 {object} models.RedactionRule
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/redaction-rules [post]
+func (h *RedactionHandler) CreateRule(c *gin.Context) {
+	var request CreateRedactionRuleRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	isActive := true
+	if request.IsActive != nil {
+		isActive = *request.IsActive
+	}
+
+	rule, err := h.redactionService.CreateRule(request.BotID, request.Name, request.Pattern, request.Replacement, isActive)
+	if err != nil {
+		if customerrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// @Summary Получить правила маскирования
+// @Description Возвращает правила маскирования (требуется админский токен). С bot_id — правила бота и глобальные правила.
+// @Tags redaction
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id query string false "ID бота (UUID)"
+// @Success 200 {array} models.RedactionRule
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/redaction-rules [get]
+func (h *RedactionHandler) ListRules(c *gin.Context) {
+	var query ListRedactionRulesQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	rules, err := h.redactionService.ListRules(query.BotID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// @Summary Получить правило маскирования
+// @Description Возвращает правило маскирования по ID (требуется админский токен)
+// @Tags redaction
+// @Produce json
+// @Security BearerAuth
+// @Param rule_id path string true "ID правила (UUID)"
+// @Success 200 {object} models.RedactionRule
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/redaction-rules/{rule_id} [get]
+func (h *RedactionHandler) GetRule(c *gin.Context) {
+	ruleID := c.Param("rule_id")
+
+	rule, err := h.redactionService.GetRuleByID(ruleID)
+	if err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary Обновить правило маскирования
+// @Description Обновляет правило маскирования (требуется админский токен). Изменения применяются к новым логам.
+// @Tags redaction
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param rule_id path string true "ID правила (UUID)"
+// @Param request body UpdateRedactionRuleRequest true "Обновлённые данные"
+// @Success 200 {object} models.RedactionRule
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/redaction-rules/{rule_id} [put]
+func (h *RedactionHandler) UpdateRule(c *gin.Context) {
+	ruleID := c.Param("rule_id")
+
+	var request UpdateRedactionRuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	rule, err := h.redactionService.UpdateRule(ruleID, request.Name, request.Pattern, request.Replacement, request.IsActive)
+	if err != nil {
+		switch {
+		case customerrors.IsNotFound(err):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case customerrors.IsInvalidInput(err):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary Удалить правило маскирования
+// @Description Удаляет правило маскирования (требуется админский токен)
+// @Tags redaction
+// @Produce json
+// @Security BearerAuth
+// @Param rule_id path string true "ID правила (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/redaction-rules/{rule_id} [delete]
+func (h *RedactionHandler) DeleteRule(c *gin.Context) {
+	ruleID := c.Param("rule_id")
+
+	if err := h.redactionService.DeleteRule(ruleID); err != nil {
+		if customerrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "правило маскирования удалено"})
+}
+
+// @Summary Статистика маскирования
+// @Description Возвращает число замаскированных фрагментов по ботам и правилам за период (требуется админский токен).
+// @Description Счётчики сохраняются периодически (redaction.stats_flush_interval_sec), последние замены могут появиться с задержкой.
+// @Tags redaction
+// @Produce json
+// @Security BearerAuth
+// @Param bot_id query string false "ID бота (UUID)"
+// @Param from query string false "Первый день периода (YYYY-MM-DD, UTC)"
+// @Param to query string false "Последний день периода (YYYY-MM-DD, UTC)"
+// @Success 200 {array} models.RedactionCount
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/redaction-rules/stats [get]
+func (h *RedactionHandler) GetStats(c *gin.Context) {
+	var query RedactionStatsQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	counts, err := h.redactionService.ListCounts(query.BotID, query.From, query.To)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
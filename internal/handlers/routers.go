@@ -3,11 +3,27 @@ package handlers
 import (
 	"net/http"
 
+	"logging_api/internal/handlers/archive_handler"
 	"logging_api/internal/handlers/auth_handler"
 	"logging_api/internal/handlers/bot_handler"
+	"logging_api/internal/handlers/bot_reference_handler"
+	"logging_api/internal/handlers/catalog_handler"
+	"logging_api/internal/handlers/deployment_handler"
 	"logging_api/internal/handlers/eff_run_handler"
+	"logging_api/internal/handlers/es_handler"
+	"logging_api/internal/handlers/export_handler"
+	"logging_api/internal/handlers/health_handler"
+	"logging_api/internal/handlers/host_handler"
 	"logging_api/internal/handlers/log_handler"
+	"logging_api/internal/handlers/log_policy_handler"
+	"logging_api/internal/handlers/loki_handler"
+	"logging_api/internal/handlers/otlp_handler"
 	"logging_api/internal/handlers/owner_handler"
+	"logging_api/internal/handlers/partition_handler"
+	"logging_api/internal/handlers/profile_handler"
+	"logging_api/internal/handlers/redaction_handler"
+	"logging_api/internal/handlers/team_handler"
+	"logging_api/internal/handlers/ws_handler"
 	"logging_api/internal/middleware"
 
 	sentrygin "github.com/getsentry/sentry-go/gin"
@@ -20,11 +36,31 @@ func SetupRoutes(
 	authHandler *auth_handler.AuthHandler,
 	botHandler *bot_handler.BotHandler,
 	ownerHandler *owner_handler.OwnerHandler,
+	profileHandler *profile_handler.ProfileHandler,
 	logHandler *log_handler.LogHandler,
 	effRunHandler *eff_run_handler.EffRunHandler,
+	wsHandler *ws_handler.WSHandler,
+	partitionHandler *partition_handler.PartitionHandler,
+	archiveHandler *archive_handler.ArchiveHandler,
+	exportHandler *export_handler.ExportHandler,
+	otlpHandler *otlp_handler.OTLPHandler,
+	lokiHandler *loki_handler.LokiHandler,
+	esHandler *es_handler.ESHandler,
+	redactionHandler *redaction_handler.RedactionHandler,
+	logPolicyHandler *log_policy_handler.LogPolicyHandler,
+	healthHandler *health_handler.HealthHandler,
+	catalogHandler *catalog_handler.CatalogHandler,
+	teamHandler *team_handler.TeamHandler,
+	deploymentHandler *deployment_handler.DeploymentHandler,
+	hostHandler *host_handler.HostHandler,
+	botReferenceHandler *bot_reference_handler.BotReferenceHandler,
 	authMiddleware *middleware.AuthMiddleware,
+	bodyMiddleware *middleware.BodyMiddleware,
 ) *gin.Engine {
-	router := gin.Default()
+	router := gin.New()
+
+	// Токен WebSocket убирается из URL до того, как его увидят логгер и Sentry
+	router.Use(middleware.WebSocketToken(), gin.Logger(), gin.Recovery())
 
 	// Sentry middleware для отслеживания ошибок и запросов
 	router.Use(sentrygin.New(sentrygin.Options{
@@ -44,6 +80,20 @@ func SetupRoutes(
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 
+	// Совместимость с агентами Loki (Promtail, Grafana Alloy): путь как у Loki
+	router.POST("/loki/api/v1/push", authMiddleware.AuthRequired(), lokiHandler.Push)
+
+	// Совместимость с выходом Elasticsearch у Filebeat и Fluent Bit: адрес кластера — /es
+	es := router.Group("/es")
+	es.Use(authMiddleware.AuthRequired())
+	{
+		es.GET("", esHandler.Info)
+		es.GET("/", esHandler.Info)
+		es.HEAD("/", esHandler.Info)
+		es.POST("/_bulk", esHandler.Bulk)
+		es.POST("/:index/_bulk", esHandler.Bulk)
+	}
+
 	api := router.Group("/v1")
 	{
 		auth := api.Group("/auth")
@@ -65,32 +115,151 @@ func SetupRoutes(
 		owners.Use(authMiddleware.AdminRequired())
 		{
 			owners.POST("", ownerHandler.CreateOwner)
-			owners.GET("", ownerHandler.GetAllOwners)
+			owners.GET("", ownerHandler.ListOwners)
 			owners.GET("/:owner_id", ownerHandler.GetOwner)
 			owners.PUT("/:owner_id", ownerHandler.UpdateOwner)
-			owners.DELETE("/:owner_id", ownerHandler.DeleteOwner)
+			owners.DELETE("/:owner_id", ownerHandler.ArchiveOwner)
+			owners.POST("/:owner_id/restore", ownerHandler.RestoreOwner)
+			owners.GET("/:owner_id/purge", ownerHandler.GetPurgeReport)
+			owners.DELETE("/:owner_id/purge", ownerHandler.PurgeOwner)
+		}
+
+		profile := api.Group("/profile")
+		profile.Use(authMiddleware.OwnerRequired())
+		{
+			profile.GET("", profileHandler.GetProfile)
+			profile.PUT("", profileHandler.UpdateProfile)
 		}
 
 		bots := api.Group("/bots")
 		bots.Use(authMiddleware.AdminRequired())
 		{
 			bots.POST("", botHandler.CreateBot)
-			bots.GET("", botHandler.GetAllBots)
+			bots.GET("", botHandler.ListBots)
+			bots.GET("/health", healthHandler.GetFleetHealth)
+			bots.GET("/export", catalogHandler.ExportCatalog)
+			bots.POST("/import", catalogHandler.ImportCatalog)
 			bots.GET("/:bot_id", botHandler.GetBot)
 			bots.PUT("/:bot_id", botHandler.UpdateBot)
-			bots.DELETE("/:bot_id", botHandler.DeleteBot)
+			bots.DELETE("/:bot_id", botHandler.ArchiveBot)
+			bots.PUT("/:bot_id/owner", botHandler.TransferBot)
+			bots.DELETE("/:bot_id/owner", botHandler.UnassignOwner)
+			bots.PUT("/:bot_id/team", botHandler.SetTeam)
+			bots.DELETE("/:bot_id/team", botHandler.RemoveTeam)
+			bots.GET("/:bot_id/owners-history", botHandler.GetOwnerHistory)
+			bots.POST("/:bot_id/restore", botHandler.RestoreBot)
+			bots.GET("/:bot_id/purge", botHandler.GetPurgeReport)
+			bots.DELETE("/:bot_id/purge", botHandler.PurgeBot)
+			bots.GET("/:bot_id/log-policy", logPolicyHandler.GetPolicy)
+			bots.PUT("/:bot_id/log-policy", logPolicyHandler.SetPolicy)
+			bots.DELETE("/:bot_id/log-policy", logPolicyHandler.DeletePolicy)
+			bots.PUT("/:bot_id/log-policy/override", logPolicyHandler.SetOverride)
+			bots.DELETE("/:bot_id/log-policy/override", logPolicyHandler.ClearOverride)
+		}
+
+		botTypes := api.Group("/bot-types")
+		botTypes.Use(authMiddleware.AdminRequired())
+		{
+			botTypes.GET("", botReferenceHandler.ListBotTypes)
+			botTypes.POST("", botReferenceHandler.CreateBotType)
+			botTypes.PUT("/:code", botReferenceHandler.UpdateBotType)
+			botTypes.DELETE("/:code", botReferenceHandler.DeleteBotType)
+		}
+
+		botLanguages := api.Group("/bot-languages")
+		botLanguages.Use(authMiddleware.AdminRequired())
+		{
+			botLanguages.GET("", botReferenceHandler.ListBotLanguages)
+			botLanguages.POST("", botReferenceHandler.CreateBotLanguage)
+			botLanguages.PUT("/:code", botReferenceHandler.UpdateBotLanguage)
+			botLanguages.DELETE("/:code", botReferenceHandler.DeleteBotLanguage)
+		}
+
+		teams := api.Group("/teams")
+		{
+			// Состояние ботов команды доступно и токену самой команды
+			teams.GET("/:team_id/health", authMiddleware.AuthRequired(), healthHandler.GetTeamHealth)
+
+			teamsAdmin := teams.Group("")
+			teamsAdmin.Use(authMiddleware.AdminRequired())
+			teamsAdmin.POST("", teamHandler.CreateTeam)
+			teamsAdmin.GET("", teamHandler.ListTeams)
+			teamsAdmin.GET("/:team_id", teamHandler.GetTeam)
+			teamsAdmin.PUT("/:team_id", teamHandler.UpdateTeam)
+			teamsAdmin.DELETE("/:team_id", teamHandler.DeleteTeam)
+			teamsAdmin.PUT("/:team_id/notifications", teamHandler.SetNotifications)
+			teamsAdmin.PUT("/:team_id/members/:owner_id", teamHandler.SetMember)
+			teamsAdmin.DELETE("/:team_id/members/:owner_id", teamHandler.RemoveMember)
+		}
+
+		redactionRules := api.Group("/redaction-rules")
+		redactionRules.Use(authMiddleware.AdminRequired())
+		{
+			redactionRules.POST("", redactionHandler.CreateRule)
+			redactionRules.GET("", redactionHandler.ListRules)
+			redactionRules.GET("/stats", redactionHandler.GetStats)
+			redactionRules.GET("/:rule_id", redactionHandler.GetRule)
+			redactionRules.PUT("/:rule_id", redactionHandler.UpdateRule)
+			redactionRules.DELETE("/:rule_id", redactionHandler.DeleteRule)
 		}
 
 		logs := api.Group("/logs")
 		logs.Use(authMiddleware.AuthRequired())
 		{
-			logs.POST("", logHandler.CreateLog)
+			logs.POST("", bodyMiddleware.Decode(), logHandler.CreateLog)
+			logs.POST("/batch", bodyMiddleware.Decode(), logHandler.CreateLogBatch)
+			logs.GET("", logHandler.ListLogs)
 		}
 
 		effRuns := api.Group("/eff-runs")
 		effRuns.Use(authMiddleware.AuthRequired())
 		{
-			effRuns.POST("", effRunHandler.CreateEffRun)
+			effRuns.POST("", bodyMiddleware.Decode(), effRunHandler.CreateEffRun)
+			effRuns.POST("/batch", bodyMiddleware.Decode(), effRunHandler.CreateEffRunBatch)
+			effRuns.GET("", effRunHandler.ListEffRuns)
+		}
+
+		deployments := api.Group("/deployments")
+		deployments.Use(authMiddleware.AuthRequired())
+		{
+			deployments.POST("", deploymentHandler.CreateDeployment)
+			deployments.GET("", deploymentHandler.ListDeployments)
+			deployments.GET("/versions", deploymentHandler.CompareVersions)
+		}
+
+		hosts := api.Group("/hosts")
+		hosts.Use(authMiddleware.AdminRequired())
+		{
+			hosts.GET("", hostHandler.ListHosts)
+			hosts.GET("/:host", hostHandler.GetHost)
+			hosts.PUT("/:host", hostHandler.UpdateHost)
+			hosts.DELETE("/:host", hostHandler.DeleteHost)
+			hosts.GET("/:host/stats", hostHandler.GetHostStats)
+		}
+
+		exports := api.Group("/exports")
+		exports.Use(authMiddleware.AuthRequired())
+		{
+			exports.GET("/logs", exportHandler.ExportLogs)
+			exports.GET("/eff-runs", exportHandler.ExportEffRuns)
+		}
+
+		otlp := api.Group("/otlp")
+		otlp.Use(authMiddleware.AuthRequired())
+		{
+			otlp.POST("/logs", otlpHandler.ExportLogs)
+		}
+
+		api.GET("/ws", authMiddleware.AuthRequired(), wsHandler.Subscribe)
+
+		admin := api.Group("/admin")
+		admin.Use(authMiddleware.AdminRequired())
+		{
+			admin.GET("/partitions", partitionHandler.GetPartitions)
+			admin.GET("/log-drops", logPolicyHandler.GetDrops)
+			admin.GET("/archives/manifests", archiveHandler.GetManifests)
+			admin.POST("/archives/import", archiveHandler.ImportArchive)
+			admin.DELETE("/archives/imports/:table", archiveHandler.DropImport)
 		}
 	}
 
@@ -0,0 +1,27 @@
+package team_handler
+
+type TeamNotificationsRequest struct {
+	WebhookURL *string  `json:"webhook_url,omitempty" binding:"omitempty,url,max=2048" example:"https://hooks.example.com/payments"`
+	States     []string `json:"states" binding:"required,dive,oneof=healthy degraded failing silent unknown" example:"failing,silent"`
+}
+
+type CreateTeamRequest struct {
+	Name        string  `json:"name" binding:"required,min=2,max=255" example:"Платежи"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=1000" example:"Боты платёжного контура"`
+	// Notifications — нет: без webhook, уведомления о переходе в failing и silent
+	Notifications *TeamNotificationsRequest `json:"notifications,omitempty"`
+}
+
+type UpdateTeamRequest struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=2,max=255" example:"Платежи и биллинг"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=1000" example:"Боты платёжного контура и биллинга"`
+}
+
+type SetMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=lead member" example:"member"`
+}
+
+type ListTeamsQuery struct {
+	Q       string  `form:"q" binding:"omitempty,max=255" example:"плат"`
+	OwnerID *string `form:"owner_id" binding:"omitempty,uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+}
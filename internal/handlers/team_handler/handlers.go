@@ -0,0 +1,302 @@
+package team_handler
+
+import (
+	"net/http"
+
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/validator_error_handling"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TeamService interface {
+	CreateTeam(name string, description *string, notifications models.TeamNotifications) (*models.Team, error)
+	GetTeamByID(teamID string) (*models.Team, error)
+	ListTeams(filter *models.TeamFilter) ([]*models.Team, error)
+	UpdateTeam(teamID string, name, description *string) (*models.Team, error)
+	SetNotifications(teamID string, notifications models.TeamNotifications) (*models.Team, error)
+	DeleteTeam(teamID string) error
+	SetMember(teamID, ownerID, role string) (*models.TeamMember, error)
+	RemoveMember(teamID, ownerID string) error
+}
+
+type TeamHandler struct {
+	teamService TeamService
+}
+
+func NewTeamHandler(teamService TeamService) *TeamHandler {
+	return &TeamHandler{
+		teamService: teamService,
+	}
+}
+
+// @Summary Создать команду
+// @Description Создаёт команду владельцев (требуется админский токен). Имя команды уникально.
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateTeamRequest true "Данные команды"
+// @Success 201 {object} models.Team
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/teams [post]
+func (h *TeamHandler) CreateTeam(c *gin.Context) {
+	var request CreateTeamRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	var notifications models.TeamNotifications
+	if request.Notifications != nil {
+		notifications = models.TeamNotifications{
+			WebhookURL: request.Notifications.WebhookURL,
+			States:     request.Notifications.States,
+		}
+	}
+
+	team, err := h.teamService.CreateTeam(request.Name, request.Description, notifications)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, team)
+}
+
+// @Summary Получить команду
+// @Description Возвращает команду с участниками (требуется админский токен)
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param team_id path string true "ID команды (UUID)"
+// @Success 200 {object} models.Team
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/teams/{team_id} [get]
+func (h *TeamHandler) GetTeam(c *gin.Context) {
+	team, err := h.teamService.GetTeamByID(c.Param("team_id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+// @Summary Получить команды
+// @Description Возвращает команды по имени с числом участников и ботов (требуется админский токен)
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Подстрока в имени (без учёта регистра)"
+// @Param owner_id query string false "Только команды, в которых состоит владелец (UUID)"
+// @Success 200 {array} models.Team
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/teams [get]
+func (h *TeamHandler) ListTeams(c *gin.Context) {
+	var query ListTeamsQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	teams, err := h.teamService.ListTeams(&models.TeamFilter{Query: query.Q, OwnerID: query.OwnerID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, teams)
+}
+
+// @Summary Обновить команду
+// @Description Меняет имя и описание команды (требуется админский токен)
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param team_id path string true "ID команды (UUID)"
+// @Param request body UpdateTeamRequest true "Обновлённые данные"
+// @Success 200 {object} models.Team
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/teams/{team_id} [put]
+func (h *TeamHandler) UpdateTeam(c *gin.Context) {
+	var request UpdateTeamRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	team, err := h.teamService.UpdateTeam(c.Param("team_id"), request.Name, request.Description)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+// @Summary Настроить уведомления команды
+// @Description Заменяет настройки уведомлений команды (требуется админский токен). После каждого расчёта состояния ботов
+// @Description на webhook_url отправляется POST с переходами ботов команды в состояния из states (одно уведомление на команду).
+// @Description Без webhook_url уведомления не отправляются.
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param team_id path string true "ID команды (UUID)"
+// @Param request body TeamNotificationsRequest true "Настройки уведомлений"
+// @Success 200 {object} models.Team
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/teams/{team_id}/notifications [put]
+func (h *TeamHandler) SetNotifications(c *gin.Context) {
+	var request TeamNotificationsRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	notifications := models.TeamNotifications{WebhookURL: request.WebhookURL, States: request.States}
+	team, err := h.teamService.SetNotifications(c.Param("team_id"), notifications)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+// @Summary Удалить команду
+// @Description Удаляет команду (требуется админский токен): её боты остаются без команды, токены команды удаляются
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param team_id path string true "ID команды (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/teams/{team_id} [delete]
+func (h *TeamHandler) DeleteTeam(c *gin.Context) {
+	if err := h.teamService.DeleteTeam(c.Param("team_id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "команда удалена"})
+}
+
+// @Summary Добавить владельца в команду
+// @Description Добавляет владельца в команду или меняет его роль (требуется админский токен). Владельца в архиве добавить нельзя.
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param team_id path string true "ID команды (UUID)"
+// @Param owner_id path string true "ID владельца (UUID)"
+// @Param request body SetMemberRequest true "Роль в команде"
+// @Success 200 {object} models.TeamMember
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/teams/{team_id}/members/{owner_id} [put]
+func (h *TeamHandler) SetMember(c *gin.Context) {
+	var request SetMemberRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if validationErrs := validator_error_handling.ValidateError(err); validationErrs != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrs.Errors()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный формат данных"})
+		return
+	}
+
+	member, err := h.teamService.SetMember(c.Param("team_id"), c.Param("owner_id"), request.Role)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// @Summary Исключить владельца из команды
+// @Description Исключает владельца из команды (требуется админский токен)
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param team_id path string true "ID команды (UUID)"
+// @Param owner_id path string true "ID владельца (UUID)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/teams/{team_id}/members/{owner_id} [delete]
+func (h *TeamHandler) RemoveMember(c *gin.Context) {
+	if err := h.teamService.RemoveMember(c.Param("team_id"), c.Param("owner_id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "владелец исключён из команды"})
+}
+
+// writeError пишет ответ для ошибки сервиса команд
+func writeError(c *gin.Context, err error) {
+	switch {
+	case customerrors.IsNotFound(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case customerrors.IsInvalidInput(err):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case customerrors.IsConflict(err):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
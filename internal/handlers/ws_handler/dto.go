@@ -0,0 +1,15 @@
+package ws_handler
+
+type SubscriptionFilter struct {
+	BotIDs   []string `json:"bot_ids,omitempty"`
+	Statuses []string `json:"statuses,omitempty"`
+}
+
+// ClientMessage — входящее сообщение протокола подписок.
+// Поддерживаемые типы: subscribe, unsubscribe, ping.
+type ClientMessage struct {
+	Type    string             `json:"type"`
+	ID      string             `json:"id,omitempty"`
+	Channel string             `json:"channel,omitempty"`
+	Filter  SubscriptionFilter `json:"filter"`
+}
@@ -0,0 +1,239 @@
+package ws_handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"logging_api/configs"
+	"logging_api/internal/middleware"
+	streamservice "logging_api/internal/service/stream_service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+var channelStatuses = map[string]map[string]struct{}{
+	streamservice.ChannelLogs: {
+		"Debug": {}, "Info": {}, "Warning": {}, "Error": {}, "Critical": {},
+	},
+	streamservice.ChannelEffRuns: {
+		"success": {}, "warning": {}, "error": {},
+	},
+}
+
+// subscriberAccess — события, доступные токену подписчика
+type subscriberAccess struct {
+	botID   string
+	isAdmin bool
+	// teamID — команда токена; teamBotIDs — её боты на момент подключения
+	teamID     string
+	teamBotIDs []string
+}
+
+type StreamHub interface {
+	NewClient(sendBuffer, maxSubscriptions int) *streamservice.Client
+}
+
+type WSHandler struct {
+	hub      StreamHub
+	config   configs.WebSocketConfig
+	upgrader websocket.Upgrader
+}
+
+func NewWSHandler(hub StreamHub, config configs.WebSocketConfig) *WSHandler {
+	h := &WSHandler{
+		hub:    hub,
+		config: config,
+	}
+	h.upgrader = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     h.checkOrigin,
+		Subprotocols:    []string{middleware.BearerSubprotocol},
+	}
+	return h
+}
+
+// checkOrigin пропускает любой Origin, если список разрешённых не задан:
+// авторизация идёт по bearer токену, а не по cookie
+func (h *WSHandler) checkOrigin(r *http.Request) bool {
+	if len(h.config.AllowedOrigins) == 0 {
+		return true
+	}
+	origin := r.Header.Get("Origin")
+	for _, allowed := range h.config.AllowedOrigins {
+		if origin == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// @Summary Подписка на события (WebSocket)
+// @Description Открывает WebSocket соединение для подписки на события логов (channel=logs) и запусков (channel=eff_runs).
+// @Description Входящие сообщения: {"type":"subscribe","id":"s1","channel":"logs","filter":{"bot_ids":[],"statuses":["Error"]}}, {"type":"unsubscribe","id":"s1"}, {"type":"ping"}.
+// @Description Исходящие сообщения: subscribed, unsubscribed, pong, error, event. Клиенты, не успевающие читать события, отключаются с кодом 1013.
+// @Description Токен передаётся в заголовке Authorization, а из браузера — подпротоколом: new WebSocket(url, ["bearer", token]).
+// @Description Query параметр access_token тоже принимается, но не рекомендуется. Обычные токены видят только события своего бота,
+// @Description токены команды — события ботов, состоявших в команде на момент подключения.
+// @Tags stream
+// @Security BearerAuth
+// @Param Sec-WebSocket-Protocol header string false "bearer, <токен> (если нельзя передать заголовок Authorization)"
+// @Param access_token query string false "Токен (устаревший способ, лучше подпротокол bearer)"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 401 {object} map[string]interface{}
+// @Router /v1/ws [get]
+func (h *WSHandler) Subscribe(c *gin.Context) {
+	access := subscriberAccess{
+		botID:   c.GetString("bot_id"),
+		isAdmin: c.GetBool("is_admin"),
+		teamID:  c.GetString("team_id"),
+	}
+	if teamBotIDs, ok := c.Get("team_bot_ids"); ok {
+		access.teamBotIDs, _ = teamBotIDs.([]string)
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrader уже записал ответ с ошибкой
+		return
+	}
+
+	client := h.hub.NewClient(h.config.SendBufferSize, h.config.MaxSubscriptions)
+	go h.writePump(conn, client)
+	h.readPump(conn, client, access)
+}
+
+func (h *WSHandler) readPump(conn *websocket.Conn, client *streamservice.Client, access subscriberAccess) {
+	defer client.Close()
+
+	pongTimeout := time.Duration(h.config.PongTimeoutSec) * time.Second
+	conn.SetReadLimit(h.config.MaxMessageBytes)
+	_ = conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(pongTimeout))
+
+		var msg ClientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			client.Enqueue(streamservice.Message{Type: "error", Error: "неверный формат сообщения"})
+			continue
+		}
+
+		switch msg.Type {
+		case "subscribe":
+			h.handleSubscribe(client, msg, access)
+		case "unsubscribe":
+			if !client.Unsubscribe(msg.ID) {
+				client.Enqueue(streamservice.Message{Type: "error", ID: msg.ID, Error: "подписка не найдена"})
+				continue
+			}
+			client.Enqueue(streamservice.Message{Type: "unsubscribed", ID: msg.ID})
+		case "ping":
+			client.Enqueue(streamservice.Message{Type: "pong", ID: msg.ID})
+		default:
+			client.Enqueue(streamservice.Message{Type: "error", ID: msg.ID, Error: "неизвестный тип сообщения"})
+		}
+	}
+}
+
+func (h *WSHandler) handleSubscribe(client *streamservice.Client, msg ClientMessage, access subscriberAccess) {
+	if msg.ID == "" {
+		client.Enqueue(streamservice.Message{Type: "error", Error: "id подписки обязателен"})
+		return
+	}
+
+	allowedStatuses, ok := channelStatuses[msg.Channel]
+	if !ok {
+		client.Enqueue(streamservice.Message{Type: "error", ID: msg.ID, Error: "неизвестный канал"})
+		return
+	}
+	for _, status := range msg.Filter.Statuses {
+		if _, ok := allowedStatuses[status]; !ok {
+			client.Enqueue(streamservice.Message{Type: "error", ID: msg.ID, Error: "недопустимый статус: " + status})
+			return
+		}
+	}
+
+	filter := streamservice.Filter{
+		BotIDs:   msg.Filter.BotIDs,
+		Statuses: msg.Filter.Statuses,
+	}
+
+	// Обычные токены видят только события своего бота, токены команды — события ботов команды
+	if access.teamID != "" {
+		for _, id := range msg.Filter.BotIDs {
+			if !slices.Contains(access.teamBotIDs, id) {
+				client.Enqueue(streamservice.Message{Type: "error", ID: msg.ID, Error: "доступ к событиям бота другой команды запрещён"})
+				return
+			}
+		}
+		if len(access.teamBotIDs) == 0 {
+			client.Enqueue(streamservice.Message{Type: "error", ID: msg.ID, Error: "в команде нет ботов"})
+			return
+		}
+		if len(filter.BotIDs) == 0 {
+			filter.BotIDs = access.teamBotIDs
+		}
+	} else if !access.isAdmin {
+		for _, id := range msg.Filter.BotIDs {
+			if id != access.botID {
+				client.Enqueue(streamservice.Message{Type: "error", ID: msg.ID, Error: "доступ к событиям другого бота запрещён"})
+				return
+			}
+		}
+		filter.BotIDs = []string{access.botID}
+	}
+
+	if err := client.Subscribe(msg.ID, msg.Channel, filter); err != nil {
+		client.Enqueue(streamservice.Message{Type: "error", ID: msg.ID, Error: err.Error()})
+		return
+	}
+
+	client.Enqueue(streamservice.Message{Type: "subscribed", ID: msg.ID, Channel: msg.Channel})
+}
+
+func (h *WSHandler) writePump(conn *websocket.Conn, client *streamservice.Client) {
+	writeTimeout := time.Duration(h.config.WriteTimeoutSec) * time.Second
+	pingInterval := time.Duration(h.config.PongTimeoutSec) * time.Second * 9 / 10
+	ticker := time.NewTicker(pingInterval)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+
+	for {
+		select {
+		case data := <-client.Send():
+			_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				client.Close()
+				return
+			}
+		case <-ticker.C:
+			_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				client.Close()
+				return
+			}
+		case <-client.Done():
+			code, reason := websocket.CloseNormalClosure, ""
+			if client.Dropped() {
+				code, reason = websocket.CloseTryAgainLater, "клиент не успевает читать события"
+				log.Println("WebSocket клиент отключён: переполнен буфер исходящих сообщений")
+			}
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(writeTimeout))
+			return
+		}
+	}
+}
@@ -54,21 +54,56 @@ func (m *AuthMiddleware) validateAndSetToken(c *gin.Context) (*authservice.Token
 		return nil, false
 	}
 
+	if tokenInfo.BotArchived {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "бот токена перенесён в архив"})
+		c.Abort()
+		return nil, false
+	}
+
+	if tokenInfo.ProfileOwnerArchived {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "владелец токена перенесён в архив"})
+		c.Abort()
+		return nil, false
+	}
+
 	c.Set("token_id", tokenInfo.TokenID)
 	// Устанавливаем bot_id только если он не пустой (для админских токенов bot_id может быть пустым)
 	if tokenInfo.BotID != "" {
 		c.Set("bot_id", tokenInfo.BotID)
 	}
 	c.Set("is_admin", tokenInfo.IsAdmin)
+	if tokenInfo.TeamID != "" {
+		c.Set("team_id", tokenInfo.TeamID)
+		c.Set("team_bot_ids", tokenInfo.TeamBotIDs)
+	}
+	if tokenInfo.ProfileOwnerID != "" {
+		c.Set("profile_owner_id", tokenInfo.ProfileOwnerID)
+	}
 
 	return tokenInfo, true
 }
 
 func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if _, ok := m.validateAndSetToken(c); !ok {
+		tokenInfo, ok := m.validateAndSetToken(c)
+		if !ok {
+			return
+		}
+
+		// Токен владельца даёт доступ только к профилю владельца
+		if tokenInfo.ProfileOwnerID != "" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "токен владельца даёт доступ только к профилю"})
+			c.Abort()
+			return
+		}
+
+		// Токен команды даёт доступ только на чтение
+		if tokenInfo.TeamID != "" && c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.JSON(http.StatusForbidden, gin.H{"error": "токен команды даёт доступ только на чтение"})
+			c.Abort()
 			return
 		}
+
 		c.Next()
 	}
 }
@@ -91,7 +126,26 @@ func (m *AuthMiddleware) AdminRequired() gin.HandlerFunc {
 	}
 }
 
+// OwnerRequired пропускает только токены владельцев
+func (m *AuthMiddleware) OwnerRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenInfo, ok := m.validateAndSetToken(c)
+		if !ok {
+			return
+		}
+
+		if tokenInfo.ProfileOwnerID == "" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "требуется токен владельца"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 func extractToken(c *gin.Context) string {
+	// Токен WebSocket-клиентов из браузера переносит в заголовок WebSocketToken
 	bearerToken := c.GetHeader("Authorization")
 	if bearerToken == "" {
 		return ""
@@ -101,6 +155,14 @@ func extractToken(c *gin.Context) string {
 	if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
 		return parts[1]
 	}
+	// Выход Elasticsearch у Fluent Bit умеет только Basic-авторизацию:
+	// токен передаётся паролем, имя пользователя не проверяется
+	if len(parts) == 2 && strings.EqualFold(parts[0], "Basic") {
+		if _, password, ok := c.Request.BasicAuth(); ok {
+			return password
+		}
+		return ""
+	}
 
 	return bearerToken
 }
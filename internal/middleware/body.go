@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"logging_api/internal/utils/body"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BodyMiddleware struct {
+	maxBodyBytes int64
+}
+
+func NewBodyMiddleware(maxBodyBytes int64) *BodyMiddleware {
+	return &BodyMiddleware{
+		maxBodyBytes: maxBodyBytes,
+	}
+}
+
+// Decode распаковывает тело запроса (Content-Encoding: gzip, zstd) и перекодирует
+// application/x-ndjson и application/msgpack в JSON, чтобы обработчики с ShouldBindJSON
+// принимали эти форматы без изменений. Размер тела после распаковки ограничен.
+func (m *BodyMiddleware) Decode() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		data, err := body.Read(c.Request, m.maxBodyBytes)
+		if err != nil {
+			switch {
+			case errors.Is(err, body.ErrTooLarge):
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			case errors.Is(err, body.ErrUnsupportedEncoding):
+				c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			}
+			c.Abort()
+			return
+		}
+
+		switch mediaType := body.MediaType(c.Request); {
+		case body.IsNDJSON(mediaType):
+			data, err = body.NDJSONToJSON(data)
+		case body.IsMsgpack(mediaType):
+			data, err = body.MsgpackToJSON(data)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(data))
+		c.Request.ContentLength = int64(len(data))
+		c.Request.Header.Set("Content-Length", strconv.Itoa(len(data)))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Del("Content-Encoding")
+
+		c.Next()
+	}
+}
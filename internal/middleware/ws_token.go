@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// accessTokenParam — query параметр с токеном (устаревший способ для WebSocket из браузера)
+	accessTokenParam = "access_token"
+	// BearerSubprotocol — подпротокол WebSocket, следом за которым клиент передаёт токен:
+	// new WebSocket(url, ["bearer", token])
+	BearerSubprotocol = "bearer"
+)
+
+// WebSocketToken переносит токен WebSocket-клиента, который не может задать заголовок Authorization, в этот заголовок.
+// Токен берётся из подпротокола после "bearer" (Sec-WebSocket-Protocol) или из query параметра access_token.
+// Оба места очищаются от токена, чтобы он не попал в журнал запросов и Sentry (заголовок Authorization они
+// не сохраняют), поэтому middleware должен стоять раньше логгера и sentrygin.
+func WebSocketToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request := c.Request
+
+		var token string
+		query := request.URL.Query()
+		if query.Has(accessTokenParam) {
+			token = query.Get(accessTokenParam)
+			query.Del(accessTokenParam)
+			request.URL.RawQuery = query.Encode()
+			request.RequestURI = request.URL.RequestURI()
+		}
+
+		protocols := websocket.Subprotocols(request)
+		if len(protocols) >= 2 && protocols[0] == BearerSubprotocol {
+			token = protocols[1]
+			// Клиент ждёт в ответе выбранный подпротокол "bearer", остальные оставляются для обработчика
+			request.Header.Set("Sec-WebSocket-Protocol", strings.Join(append([]string{BearerSubprotocol}, protocols[2:]...), ", "))
+		}
+
+		if token != "" && request.Header.Get("Authorization") == "" && strings.EqualFold(request.Header.Get("Upgrade"), "websocket") {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		c.Next()
+	}
+}
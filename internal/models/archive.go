@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// ArchiveManifest описывает архивные файлы одного бота за один день (UTC)
+type ArchiveManifest struct {
+	BotID string        `json:"bot_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Day   string        `json:"day" example:"2025-01-15"`
+	Files []ArchiveFile `json:"files"`
+}
+
+// ArchiveFile — gzip-сжатый NDJSON файл с логами одного уровня
+type ArchiveFile struct {
+	Level          string    `json:"level" example:"Info"`
+	Key            string    `json:"key" example:"logs/2025/01/15/550e8400-e29b-41d4-a716-446655440000/Info.ndjson.gz"`
+	Rows           int64     `json:"rows" example:"15230"`
+	Bytes          int64     `json:"bytes" example:"482113"`
+	SHA256         string    `json:"sha256" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	MinID          int64     `json:"min_id" example:"100"`
+	MaxID          int64     `json:"max_id" example:"15329"`
+	FirstCreatedAt time.Time `json:"first_created_at" example:"2025-01-15T00:00:01Z"`
+	LastCreatedAt  time.Time `json:"last_created_at" example:"2025-01-15T23:59:58Z"`
+	ExportedAt     time.Time `json:"exported_at" example:"2025-04-16T03:00:00Z"`
+}
+
+// ArchiveImport — результат загрузки архива во временную таблицу
+type ArchiveImport struct {
+	Table string `json:"table" example:"archive_import_1736899200"`
+	Files int    `json:"files" example:"12"`
+	Rows  int64  `json:"rows" example:"182400"`
+}
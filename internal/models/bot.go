@@ -7,12 +7,78 @@ type Bot struct {
 	ID          string    `json:"id" db:"id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
 	Code        string    `json:"code" db:"code" binding:"required" example:"BOT_001"`
 	Name        string    `json:"name" db:"name" binding:"required" example:"Telegram Bot"`
-	BotType     string    `json:"bot_type" db:"bot_type" binding:"required,oneof=AI Backend Frontend Robot" example:"Backend"`
-	Language    string    `json:"language" db:"language" binding:"required,oneof=Python Go N8N PIX JS C Other" example:"Python"`
+	BotType     string    `json:"bot_type" db:"bot_type" binding:"required,max=50" example:"Backend"`
+	Language    string    `json:"language" db:"language" binding:"required,max=50" example:"Python"`
 	Description *string   `json:"description,omitempty" db:"description" example:"Бот для обработки сообщений"`
 	Tags        []string  `json:"tags,omitempty" db:"tags" example:"telegram,automation"`
 	OwnerID     *string   `json:"owner_id,omitempty" db:"owner_id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string"`
+	TeamID      *string   `json:"team_id,omitempty" db:"team_id" example:"9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c" swaggertype:"string"`
 	IsActive    bool      `json:"is_active" db:"is_active" example:"true"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at" example:"2023-01-15T12:00:00Z"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at" example:"2023-01-15T12:00:00Z"`
+	// ArchivedAt — время переноса в архив; ArchivedBy — ID админского токена, которым бот перенесён
+	ArchivedAt *time.Time `json:"archived_at,omitempty" db:"archived_at" example:"2023-02-01T12:00:00Z"`
+	ArchivedBy *string    `json:"archived_by,omitempty" db:"archived_by" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string"`
+	// Health — состояние по последним логам и запускам; не хранится в таблице bots
+	Health *BotHealth `json:"health,omitempty" db:"-"`
+}
+
+// BotFilter — параметры выборки ботов
+type BotFilter struct {
+	BotType  *string
+	Language *string
+	OwnerID  *string
+	TeamID   *string
+	IsActive *bool
+	Tags     []string
+	// TagsAll — нужны все теги из Tags (иначе хотя бы один)
+	TagsAll bool
+	// Query — подстрока в code, name или description (без учёта регистра)
+	Query string
+	// Archived — как учитывать ботов в архиве (ArchivedExclude, ArchivedInclude, ArchivedOnly)
+	Archived string
+	Limit    int
+
+	// Поле сортировки (created_at, updated_at, code, name) и направление (asc, desc)
+	SortBy string
+	Order  string
+
+	// Ключ последней записи предыдущей страницы: значение поля сортировки и ID
+	AfterTime  *time.Time
+	AfterValue *string
+	AfterID    *string
+}
+
+type BotPage struct {
+	Items      []*Bot  `json:"items"`
+	Total      int     `json:"total" example:"42"`
+	NextCursor *string `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyNS0wMS0xNVQxMjowMDowMFoiLCJpZCI6IjEyMzQ1In0"`
+}
+
+// Учёт записей в архиве при выборке
+const (
+	ArchivedExclude = "exclude"
+	ArchivedInclude = "include"
+	ArchivedOnly    = "only"
+)
+
+// BotPurgeReport — что удаляется вместе с ботом при окончательном удалении
+type BotPurgeReport struct {
+	BotID string `json:"bot_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Code  string `json:"code" example:"BOT_001"`
+	// Удаляемые записи связанных таблиц
+	Tokens          int64 `json:"tokens" example:"2"`
+	EffRuns         int64 `json:"eff_runs" example:"1450"`
+	RedactionRules  int64 `json:"redaction_rules" example:"1"`
+	RedactionCounts int64 `json:"redaction_counts" example:"30"`
+	LogPolicies     int64 `json:"log_policies" example:"1"`
+	LogDropCounts   int64 `json:"log_drop_counts" example:"90"`
+	OwnerHistory    int64 `json:"owner_history" example:"2"`
+	Deployments     int64 `json:"deployments" example:"14"`
+	HostBots        int64 `json:"host_bots" example:"2"`
+	HealthStates    int64 `json:"health_states" example:"1"`
+	// LogsDetached — логи бота остаются, но теряют привязку к нему (bot_id станет NULL)
+	LogsDetached int64 `json:"logs_detached" example:"250000"`
+	// Purged — удаление выполнено (false — предварительный отчёт)
+	Purged bool `json:"purged" example:"false"`
 }
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// BotOwnerChange — запись истории передачи бота между владельцами
+type BotOwnerChange struct {
+	ID    string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	BotID string `json:"bot_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	// FromOwnerID — прежний владелец (нет — у бота не было владельца); ToOwnerID — новый (нет — владелец снят)
+	FromOwnerID *string   `json:"from_owner_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string"`
+	ToOwnerID   *string   `json:"to_owner_id,omitempty" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7" swaggertype:"string"`
+	ChangedAt   time.Time `json:"changed_at" example:"2023-03-01T09:00:00Z"`
+	// ChangedBy — ID админского токена, которым выполнена передача
+	ChangedBy *string `json:"changed_by,omitempty" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string"`
+	Reason    *string `json:"reason,omitempty" example:"Переход в команду платежей"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Справочники ботов
+const (
+	BotReferenceTypes     = "bot_types"
+	BotReferenceLanguages = "bot_languages"
+)
+
+// BotReference — значение справочника типов (bot_types) или языков (bot_languages) ботов
+type BotReference struct {
+	Code        string  `json:"code" example:"UiPath"`
+	Description *string `json:"description,omitempty" example:"Роботы на платформе UiPath"`
+	// BotCount — число ботов (включая ботов в архиве) с этим значением
+	BotCount  int       `json:"bot_count" example:"12"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-15T12:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2023-01-15T12:00:00Z"`
+}
@@ -0,0 +1,100 @@
+package models
+
+// Catalog — декларативное описание владельцев и ботов (для хранения в git). Владельцы ключуются по full_name,
+// боты — по code; бот ссылается на владельца по его full_name, на команду — по её имени.
+type Catalog struct {
+	Owners []*CatalogOwner `json:"owners" yaml:"owners" binding:"dive"`
+	Bots   []*CatalogBot   `json:"bots" yaml:"bots" binding:"dive"`
+}
+
+type CatalogOwner struct {
+	FullName string `json:"full_name" yaml:"full_name" binding:"required,min=2,max=255" example:"Иван Иванов"`
+	// IsActive — по умолчанию true
+	IsActive *bool `json:"is_active,omitempty" yaml:"is_active,omitempty" example:"true"`
+}
+
+type CatalogBot struct {
+	Code        string   `json:"code" yaml:"code" binding:"required,min=2,max=50" example:"BOT_001"`
+	Name        string   `json:"name" yaml:"name" binding:"required,min=2,max=255" example:"Telegram Bot"`
+	BotType     string   `json:"bot_type" yaml:"bot_type" binding:"required,max=50" example:"Backend"`
+	Language    string   `json:"language" yaml:"language" binding:"required,max=50" example:"Python"`
+	Description *string  `json:"description,omitempty" yaml:"description,omitempty" example:"Бот для обработки сообщений"`
+	Tags        []string `json:"tags,omitempty" yaml:"tags,omitempty" binding:"omitempty,dive,min=1,max=100" example:"telegram,automation"`
+	// Owner — full_name владельца из раздела owners; нет — бот без владельца
+	Owner *string `json:"owner,omitempty" yaml:"owner,omitempty" example:"Иван Иванов"`
+	// Team — имя существующей команды; нет — бот вне команд
+	Team *string `json:"team,omitempty" yaml:"team,omitempty" example:"Платежи"`
+	// IsActive — по умолчанию true
+	IsActive *bool `json:"is_active,omitempty" yaml:"is_active,omitempty" example:"true"`
+	// LogPolicy — минимальный уровень и семплирование; нет — логи сохраняются полностью
+	LogPolicy *CatalogLogPolicy `json:"log_policy,omitempty" yaml:"log_policy,omitempty"`
+}
+
+// CatalogLogPolicy — постоянная часть политики хранения логов (временный уровень в каталог не входит)
+type CatalogLogPolicy struct {
+	MinLevel    *string            `json:"min_level,omitempty" yaml:"min_level,omitempty" binding:"omitempty,oneof=Debug Info Warning Error Critical" example:"Info"`
+	SampleRates map[string]float64 `json:"sample_rates,omitempty" yaml:"sample_rates,omitempty" swaggertype:"object,number"`
+}
+
+// CatalogState — текущие владельцы и боты (не в архиве) с политиками хранения по ID бота, команды
+// и коды справочников типов и языков ботов
+type CatalogState struct {
+	Owners       []*Owner
+	Bots         []*Bot
+	Policies     map[string]*LogPolicy
+	Teams        []*Team
+	BotTypes     []string
+	BotLanguages []string
+}
+
+// Виды и действия изменений импорта каталога
+const (
+	CatalogKindOwner = "owner"
+	CatalogKindBot   = "bot"
+
+	CatalogActionCreate  = "create"
+	CatalogActionUpdate  = "update"
+	CatalogActionArchive = "archive"
+)
+
+// CatalogChange — одно изменение импорта каталога
+type CatalogChange struct {
+	Kind   string `json:"kind" example:"bot" enums:"owner,bot"`
+	Action string `json:"action" example:"update" enums:"create,update,archive"`
+	// Key — full_name владельца или code бота
+	Key string `json:"key" example:"BOT_001"`
+	// Fields — изменённые поля (для update)
+	Fields []string `json:"fields,omitempty" example:"owner,tags"`
+}
+
+type CatalogImportResult struct {
+	DryRun  bool             `json:"dry_run" example:"true"`
+	Changes []*CatalogChange `json:"changes"`
+	// Unchanged — число ботов из файла, которые уже совпадают с каталогом
+	Unchanged int `json:"unchanged" example:"180"`
+}
+
+// CatalogPlan — изменения, которые импорт вносит в базу
+type CatalogPlan struct {
+	// OwnerIDs — ID существующих владельцев по full_name; созданные владельцы добавляются при применении
+	OwnerIDs     map[string]string
+	CreateOwners []*CatalogOwner
+	// UpdateOwners — существующие владельцы с новым значением IsActive
+	UpdateOwners []*Owner
+	Bots         []*CatalogBotOp
+	// ArchiveBots — ID ботов, которых нет в файле (при prune)
+	ArchiveBots []string
+}
+
+// CatalogBotOp — создание (BotID пуст) или обновление бота из каталога
+type CatalogBotOp struct {
+	BotID string
+	Bot   *CatalogBot
+	// OwnerChanged — владелец меняется (передача записывается в историю); FromOwnerID — прежний владелец
+	OwnerChanged bool
+	FromOwnerID  *string
+	// TeamID — команда бота из каталога
+	TeamID *string
+	// PolicyChanged — нужно сохранить LogPolicy бота
+	PolicyChanged bool
+}
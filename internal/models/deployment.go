@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Deployment — выкладка версии бота
+type Deployment struct {
+	ID         string    `json:"id" db:"id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
+	BotID      string    `json:"bot_id" db:"bot_id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
+	Version    string    `json:"version" db:"version" example:"1.4.2"`
+	GitSHA     *string   `json:"git_sha,omitempty" db:"git_sha" example:"9fceb02d0ae598e95dc970b74767f19372d61af8"`
+	Host       *string   `json:"host,omitempty" db:"host" example:"server-01"`
+	Changelog  *string   `json:"changelog,omitempty" db:"changelog" example:"Исправлена обработка таймаутов"`
+	DeployedAt time.Time `json:"deployed_at" db:"deployed_at" example:"2023-01-15T12:00:00Z"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at" example:"2023-01-15T12:00:05Z"`
+}
+
+// DeploymentFilter — параметры выборки выкладок (период по deployed_at)
+type DeploymentFilter struct {
+	BotID *string
+	// TeamID — текущая команда бота
+	TeamID *string
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+
+	// Ключ последней записи предыдущей страницы
+	AfterDeployedAt *time.Time
+	AfterID         *string
+}
+
+type DeploymentPage struct {
+	Items      []*Deployment `json:"items"`
+	NextCursor *string       `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyNS0wMS0xNVQxMjowMDowMFoiLCJpZCI6IjEyMzQ1In0"`
+}
+
+// VersionStats — показатели логов и запусков бота, записанных при одной версии
+type VersionStats struct {
+	Version string `json:"version" example:"1.4.2"`
+	// FirstDeployedAt и LastDeployedAt — первая и последняя выкладка версии
+	FirstDeployedAt time.Time `json:"first_deployed_at" example:"2023-01-15T12:00:00Z"`
+	LastDeployedAt  time.Time `json:"last_deployed_at" example:"2023-01-16T12:00:00Z"`
+	Deployments     int       `json:"deployments" example:"2"`
+
+	Runs        int64 `json:"runs" example:"120"`
+	RunsSuccess int64 `json:"runs_success" example:"110"`
+	RunsWarning int64 `json:"runs_warning" example:"6"`
+	RunsError   int64 `json:"runs_error" example:"4"`
+	// SuccessRate — доля успешных запусков; нет — запусков не было
+	SuccessRate *float64 `json:"success_rate,omitempty" example:"0.9167"`
+
+	Logs      int64 `json:"logs" example:"5400"`
+	ErrorLogs int64 `json:"error_logs" example:"27"`
+	// ErrorRate — доля логов Error и Critical; нет — логов не было
+	ErrorRate *float64 `json:"error_rate,omitempty" example:"0.005"`
+}
+
+// VersionComparison — сравнение последних версий бота, новые первыми
+type VersionComparison struct {
+	BotID    string          `json:"bot_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Versions []*VersionStats `json:"versions"`
+}
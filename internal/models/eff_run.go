@@ -11,5 +11,35 @@ type EffRun struct {
 	Status     string     `json:"status" db:"status" binding:"oneof=success warning error" example:"success" enums:"success,warning,error"`
 	Host       *string    `json:"host,omitempty" db:"host" example:"server-01"`
 	Extra      JSONB      `json:"extra,omitempty" db:"extra" swaggertype:"object"`
+	Version    *string    `json:"version,omitempty" db:"version" example:"1.4.2"`
 	CreatedAt  time.Time  `json:"created_at" db:"created_at" example:"2023-01-15T12:00:00Z"`
+	// OwnerID — владелец бота на момент запуска (по истории передачи бота); не хранится в таблице eff_runs
+	OwnerID *string `json:"owner_id,omitempty" db:"-" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string"`
+}
+
+// EffRunFilter — параметры выборки запусков (период по created_at)
+type EffRunFilter struct {
+	BotID *string
+	// OwnerID — владелец бота на момент запуска
+	OwnerID *string
+	// TeamID — текущая команда бота
+	TeamID   *string
+	Statuses []string
+	Host     *string
+	// Version — версия бота на момент записи запуска
+	Version *string
+	From    *time.Time
+	To      *time.Time
+	Limit   int
+
+	// Ключ последней записи предыдущей страницы
+	AfterCreatedAt *time.Time
+	AfterID        *string
+}
+
+type EffRunPage struct {
+	Items []*EffRun `json:"items"`
+	// Deployments — выкладки тех же ботов за период страницы (только с include_deployments)
+	Deployments []*Deployment `json:"deployments,omitempty"`
+	NextCursor  *string       `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyNS0wMS0xNVQxMjowMDowMFoiLCJpZCI6IjEyMzQ1In0"`
 }
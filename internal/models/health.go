@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// Состояния бота
+const (
+	HealthHealthy  = "healthy"
+	HealthDegraded = "degraded"
+	HealthFailing  = "failing"
+	HealthSilent   = "silent"
+	HealthUnknown  = "unknown"
+)
+
+// BotHealth — состояние бота по последним логам и запускам
+type BotHealth struct {
+	State string `json:"state" example:"degraded" enums:"healthy,degraded,failing,silent,unknown"`
+	// Reasons — почему состояние не healthy
+	Reasons      []string   `json:"reasons,omitempty" example:"12 логов Error/Critical за 60 мин"`
+	LastSignalAt *time.Time `json:"last_signal_at,omitempty" example:"2023-01-15T12:00:00Z"`
+	LastLogAt    *time.Time `json:"last_log_at,omitempty" example:"2023-01-15T12:00:00Z"`
+	LastRunAt    *time.Time `json:"last_run_at,omitempty" example:"2023-01-15T11:00:00Z"`
+	// LastRunStatus — статус последнего запуска
+	LastRunStatus *string `json:"last_run_status,omitempty" example:"success" enums:"success,warning,error"`
+	// ErrorLogs — число логов Error и Critical за окно health.window_minutes
+	ErrorLogs int64 `json:"error_logs" example:"12"`
+	// FailedRuns — число запусков со статусом error среди последних health.recent_runs
+	FailedRuns int       `json:"failed_runs" example:"0"`
+	CheckedAt  time.Time `json:"checked_at" example:"2023-01-15T12:01:00Z"`
+}
+
+// BotSignals — исходные данные для расчёта состояния бота
+type BotSignals struct {
+	BotID     string
+	Code      string
+	Name      string
+	IsActive  bool
+	TeamID    *string
+	LastLogAt *time.Time
+	ErrorLogs int64
+	LastRunAt *time.Time
+	// RecentRuns — статусы последних запусков, новые первыми
+	RecentRuns []string
+}
+
+// BotHealthItem — бот в сводке состояния
+type BotHealthItem struct {
+	BotID    string     `json:"bot_id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
+	Code     string     `json:"code" example:"BOT_001"`
+	Name     string     `json:"name" example:"Telegram Bot"`
+	IsActive bool       `json:"is_active" example:"true"`
+	TeamID   *string    `json:"team_id,omitempty" example:"9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c" swaggertype:"string"`
+	Health   *BotHealth `json:"health"`
+}
+
+// FleetHealth — сводка состояния ботов
+type FleetHealth struct {
+	// Counts — число ботов по состояниям
+	Counts    map[string]int   `json:"counts" swaggertype:"object,integer"`
+	Bots      []*BotHealthItem `json:"bots"`
+	CheckedAt *time.Time       `json:"checked_at,omitempty" example:"2023-01-15T12:01:00Z"`
+}
+
+// HealthChange — переход бота в другое состояние между двумя расчётами
+type HealthChange struct {
+	BotID  string     `json:"bot_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Code   string     `json:"code" example:"BOT_001"`
+	Name   string     `json:"name" example:"Telegram Bot"`
+	TeamID *string    `json:"-"`
+	From   string     `json:"from" example:"healthy"`
+	To     string     `json:"to" example:"failing"`
+	Health *BotHealth `json:"health"`
+}
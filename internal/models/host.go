@@ -0,0 +1,98 @@
+package models
+
+import "time"
+
+// Состояния хоста в уведомлениях
+const (
+	HostSilent    = "silent"
+	HostRecovered = "recovered"
+)
+
+// Host — хост из реестра, собранного по eff_runs.host
+type Host struct {
+	Name        string  `json:"name" example:"server-01"`
+	Description *string `json:"description,omitempty" example:"Виртуальная машина платёжных ботов"`
+	OS          *string `json:"os,omitempty" example:"Ubuntu 22.04"`
+	IP          *string `json:"ip,omitempty" example:"10.0.12.5"`
+	// Monitored — следить ли за молчанием хоста
+	Monitored   bool      `json:"monitored" example:"true"`
+	FirstSeenAt time.Time `json:"first_seen_at" example:"2023-01-15T12:00:00Z"`
+	LastSeenAt  time.Time `json:"last_seen_at" example:"2023-01-16T12:00:00Z"`
+	// RunsSeen — число запусков с хоста с момента его появления в реестре
+	RunsSeen int64 `json:"runs_seen" example:"1450"`
+	// BotCount — число ботов, запуски которых приходили с хоста
+	BotCount int `json:"bot_count" example:"3"`
+	// SilentSince — с какого момента хост считается замолчавшим
+	SilentSince *time.Time `json:"silent_since,omitempty" example:"2023-01-16T13:00:00Z"`
+	CreatedAt   time.Time  `json:"created_at" example:"2023-01-15T12:00:00Z"`
+	UpdatedAt   time.Time  `json:"updated_at" example:"2023-01-15T12:00:00Z"`
+	// Bots — боты хоста; заполняется только при получении одного хоста
+	Bots []*HostBot `json:"bots,omitempty"`
+}
+
+// HostBot — бот, запуски которого приходили с хоста
+type HostBot struct {
+	BotID       string    `json:"bot_id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
+	Code        string    `json:"code" example:"BOT_001"`
+	Name        string    `json:"name" example:"Telegram Bot"`
+	FirstSeenAt time.Time `json:"first_seen_at" example:"2023-01-15T12:00:00Z"`
+	LastSeenAt  time.Time `json:"last_seen_at" example:"2023-01-16T12:00:00Z"`
+	RunsSeen    int64     `json:"runs_seen" example:"480"`
+}
+
+// HostFilter — параметры выборки хостов
+type HostFilter struct {
+	// Query — подстрока в имени, описании или IP (без учёта регистра)
+	Query string
+	// Silent — только замолчавшие (true) или только присылающие запуски (false) хосты
+	Silent *bool
+	// BotID — только хосты, с которых приходили запуски бота
+	BotID *string
+}
+
+// HostUpdate — изменяемые вручную поля хоста (nil — без изменений)
+type HostUpdate struct {
+	Description *string
+	OS          *string
+	IP          *string
+	Monitored   *bool
+}
+
+// HostSighting — запуски бота с хоста, накопленные между сохранениями реестра
+type HostSighting struct {
+	Host        string
+	BotID       string
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+	Runs        int64
+}
+
+// HostStats — показатели запусков с хоста за период
+type HostStats struct {
+	Host        string     `json:"host" example:"server-01"`
+	From        *time.Time `json:"from,omitempty" example:"2025-01-01T00:00:00Z"`
+	To          *time.Time `json:"to,omitempty" example:"2025-02-01T00:00:00Z"`
+	Runs        int64      `json:"runs" example:"1450"`
+	RunsSuccess int64      `json:"runs_success" example:"1400"`
+	RunsWarning int64      `json:"runs_warning" example:"30"`
+	RunsError   int64      `json:"runs_error" example:"20"`
+	// FailureRate — доля запусков со статусом error; нет — запусков не было
+	FailureRate *float64 `json:"failure_rate,omitempty" example:"0.0138"`
+	// Bots — число разных ботов, запуски которых приходили с хоста за период
+	Bots int `json:"bots" example:"3"`
+	// PeakConcurrentBots — наибольшее число ботов, работавших на хосте одновременно
+	// (по пересечению period_from–period_to запусков)
+	PeakConcurrentBots int `json:"peak_concurrent_bots" example:"2"`
+}
+
+// HostNotification — тело уведомления о замолчавшем или снова присылающем запуски хосте
+type HostNotification struct {
+	Host  string `json:"host" example:"server-01"`
+	State string `json:"state" example:"silent" enums:"silent,recovered"`
+	// LastSeenAt — время последнего запуска с хоста
+	LastSeenAt time.Time `json:"last_seen_at" example:"2023-01-16T12:00:00Z"`
+	// ExpectedIntervalSec — обычный интервал между запусками с хоста
+	ExpectedIntervalSec float64 `json:"expected_interval_sec" example:"600"`
+	// Bots — коды ботов, запуски которых приходили с хоста
+	Bots []string `json:"bots" example:"BOT_001,BOT_002"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrIdempotencyKeyReused — ключ уже использован запросом с другим телом
+var ErrIdempotencyKeyReused = errors.New("idempotency key is already used with a different request")
+
+// IdempotencyKey — ключ идемпотентности запроса на создание.
+// Ключи, созданные раньше NotBefore, считаются истёкшими и могут быть использованы заново.
+// RequestHash — хеш тела запроса: повтор ключа с другим телом отклоняется.
+type IdempotencyKey struct {
+	Namespace   string
+	Key         string
+	RequestHash string
+	NotBefore   time.Time
+}
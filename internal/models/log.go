@@ -3,9 +3,63 @@ package models
 import "time"
 
 type Log struct {
-	ID        int64     `json:"id" db:"id" example:"12345"`
-	BotID     *string   `json:"bot_id,omitempty" db:"bot_id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
-	Status    string    `json:"status" db:"status" binding:"oneof=Debug Info Warning Error Critical" example:"Info" enums:"Debug,Info,Warning,Error,Critical"`
-	Msg       string    `json:"msg" db:"msg" binding:"required" example:"Операция выполнена успешно"`
-	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2023-01-15T12:00:00Z"`
+	ID         int64     `json:"id" db:"id" example:"12345"`
+	BotID      *string   `json:"bot_id,omitempty" db:"bot_id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
+	Status     string    `json:"status" db:"status" binding:"oneof=Debug Info Warning Error Critical" example:"Info" enums:"Debug,Info,Warning,Error,Critical"`
+	Msg        string    `json:"msg" db:"msg" binding:"required" example:"Операция выполнена успешно"`
+	Attributes JSONB     `json:"attributes,omitempty" db:"attributes" swaggertype:"object"`
+	Version    *string   `json:"version,omitempty" db:"version" example:"1.4.2"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at" example:"2023-01-15T12:00:00Z"`
+	ReceivedAt time.Time `json:"received_at" db:"received_at" example:"2023-01-15T12:00:05Z"`
 }
+
+// LogFilter — параметры выборки логов
+type LogFilter struct {
+	BotID *string
+	// TeamID — текущая команда бота
+	TeamID   *string
+	Statuses []string
+	// Version — версия бота на момент записи лога
+	Version *string
+	From    *time.Time
+	To      *time.Time
+	Query   string
+	Limit   int
+
+	// Поле времени для сортировки и периода From/To: created_at (по умолчанию) или received_at
+	SortBy string
+
+	// Ключ последней записи предыдущей страницы (сортировка по времени)
+	AfterTime *time.Time
+	AfterID   *int64
+	// Смещение (сортировка по релевантности при полнотекстовом поиске)
+	Offset int
+}
+
+// LogHit — лог в результатах выборки; Rank и Headline заполняются только при полнотекстовом поиске
+type LogHit struct {
+	Log
+	Rank     *float64 `json:"rank,omitempty" example:"0.35"`
+	Headline *string  `json:"headline,omitempty" example:"Ошибка <b>подключения</b> к базе"`
+}
+
+type LogPage struct {
+	Items      []*LogHit `json:"items"`
+	NextCursor *string   `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyNS0wMS0xNVQxMjowMDowMFoiLCJpZCI6IjEyMzQ1In0"`
+}
+
+// WriteResult — итог записи лога
+type WriteResult int
+
+const (
+	// WriteCreated — лог сохранён
+	WriteCreated WriteResult = iota
+	// WriteReplayed — повтор по ключу идемпотентности, возвращён ранее созданный лог
+	WriteReplayed
+	// WriteQueued — лог поставлен в очередь асинхронной записи и ещё не сохранён
+	WriteQueued
+	// WriteDropped — лог отброшен политикой хранения бота (уровень ниже минимального или семплирование)
+	WriteDropped
+	// WriteRejected — время события не укладывается в допустимое отклонение от времени сервера
+	WriteRejected
+)
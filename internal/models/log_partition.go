@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// LogPartition описывает месячную секцию таблицы logs
+type LogPartition struct {
+	Name       string     `json:"name" example:"logs_p202501"`
+	From       *time.Time `json:"from,omitempty" example:"2025-01-01T00:00:00Z"`
+	To         *time.Time `json:"to,omitempty" example:"2025-02-01T00:00:00Z"`
+	IsDefault  bool       `json:"is_default" example:"false"`
+	RowsApprox int64      `json:"rows_approx" example:"1250000"`
+	TotalBytes int64      `json:"total_bytes" example:"268435456"`
+	TotalSize  string     `json:"total_size" example:"256 MB"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// LogPolicy — политика хранения логов бота
+type LogPolicy struct {
+	BotID string `json:"bot_id" db:"bot_id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
+	// MinLevel — минимальный сохраняемый уровень; nil — все уровни
+	MinLevel *string `json:"min_level,omitempty" db:"min_level" example:"Info" enums:"Debug,Info,Warning,Error,Critical"`
+	// SampleRates — доля сохраняемых логов по уровням (0..1); уровень без значения сохраняется полностью
+	SampleRates map[string]float64 `json:"sample_rates" db:"sample_rates" swaggertype:"object,number"`
+	// OverrideLevel — временный минимальный уровень до OverrideUntil, без семплирования
+	OverrideLevel *string    `json:"override_level,omitempty" db:"override_level" example:"Debug" enums:"Debug,Info,Warning,Error,Critical"`
+	OverrideUntil *time.Time `json:"override_until,omitempty" db:"override_until" example:"2023-01-15T14:00:00Z"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at" example:"2023-01-15T12:00:00Z"`
+}
+
+// Причины отбрасывания лога политикой хранения
+const (
+	DropReasonLevel   = "level"
+	DropReasonSampled = "sampled"
+)
+
+// LogDropCount — число отброшенных логов бота за период по уровню и причине
+type LogDropCount struct {
+	BotID   string  `json:"bot_id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
+	BotCode *string `json:"bot_code,omitempty" example:"PROJ001"`
+	Status  string  `json:"status" example:"Debug"`
+	Reason  string  `json:"reason" example:"level" enums:"level,sampled"`
+	Count   int64   `json:"count" example:"15000"`
+}
+
+// LogDropCountDelta — прирост числа отброшенных логов по боту, дню, уровню и причине
+type LogDropCountDelta struct {
+	BotID  string
+	Day    time.Time
+	Status string
+	Reason string
+	Count  int64
+}
@@ -4,8 +4,93 @@ import "time"
 
 // Owner представляет владельца ботов в системе
 type Owner struct {
-	ID        string    `json:"id" db:"id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
-	FullName  string    `json:"full_name" db:"full_name" binding:"required" example:"Иван Иванов"`
-	IsActive  bool      `json:"is_active" db:"is_active" example:"true"`
-	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2023-01-15T12:00:00Z"`
+	ID        string        `json:"id" db:"id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
+	FullName  string        `json:"full_name" db:"full_name" binding:"required" example:"Иван Иванов"`
+	IsActive  bool          `json:"is_active" db:"is_active" example:"true"`
+	Contacts  OwnerContacts `json:"contacts"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at" example:"2023-01-15T12:00:00Z"`
+	// ArchivedAt — время переноса в архив; ArchivedBy — ID админского токена, которым владелец перенесён
+	ArchivedAt *time.Time `json:"archived_at,omitempty" db:"archived_at" example:"2023-02-01T12:00:00Z"`
+	ArchivedBy *string    `json:"archived_by,omitempty" db:"archived_by" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string"`
+}
+
+// Каналы уведомлений владельца
+const (
+	ChannelEmail    = "email"
+	ChannelTelegram = "telegram"
+	ChannelPhone    = "phone"
+)
+
+// OwnerContacts — контакты владельца и предпочитаемые каналы уведомлений
+type OwnerContacts struct {
+	Email    *string `json:"email,omitempty" db:"email" example:"ivanov@example.com"`
+	Telegram *string `json:"telegram,omitempty" db:"telegram" example:"@ivanov"`
+	Phone    *string `json:"phone,omitempty" db:"phone" example:"+79991234567"`
+	Timezone *string `json:"timezone,omitempty" db:"timezone" example:"Europe/Moscow"`
+	// NotifyChannels — каналы в порядке предпочтения; для каждого должен быть указан контакт
+	NotifyChannels []string `json:"notify_channels" db:"notify_channels" example:"telegram,email"`
+}
+
+// OwnerContactsInput — контакты владельца в запросах на создание и изменение владельца и профиля
+type OwnerContactsInput struct {
+	Email          *string  `json:"email,omitempty" binding:"omitempty,email,max=255" example:"ivanov@example.com"`
+	Telegram       *string  `json:"telegram,omitempty" binding:"omitempty,max=33,telegram" example:"@ivanov"`
+	Phone          *string  `json:"phone,omitempty" binding:"omitempty,e164" example:"+79991234567"`
+	Timezone       *string  `json:"timezone,omitempty" binding:"omitempty,timezone,max=64" example:"Europe/Moscow"`
+	NotifyChannels []string `json:"notify_channels,omitempty" binding:"omitempty,dive,oneof=email telegram phone" example:"telegram,email"`
+}
+
+// Contacts переводит контакты из запроса в модель; nil — контакты не переданы
+func (c *OwnerContactsInput) Contacts() *OwnerContacts {
+	if c == nil {
+		return nil
+	}
+	return &OwnerContacts{
+		Email:          c.Email,
+		Telegram:       c.Telegram,
+		Phone:          c.Phone,
+		Timezone:       c.Timezone,
+		NotifyChannels: c.NotifyChannels,
+	}
+}
+
+// OwnerFilter — параметры выборки владельцев
+type OwnerFilter struct {
+	IsActive *bool
+	// Query — подстрока в full_name (без учёта регистра)
+	Query string
+	// TeamID — только участники команды
+	TeamID *string
+	// Archived — как учитывать владельцев в архиве (ArchivedExclude, ArchivedInclude, ArchivedOnly)
+	Archived string
+	Limit    int
+
+	// Поле сортировки (created_at, full_name) и направление (asc, desc)
+	SortBy string
+	Order  string
+
+	// Ключ последней записи предыдущей страницы: значение поля сортировки и ID
+	AfterTime  *time.Time
+	AfterValue *string
+	AfterID    *string
+}
+
+type OwnerPage struct {
+	Items      []*Owner `json:"items"`
+	Total      int      `json:"total" example:"12"`
+	NextCursor *string  `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyNS0wMS0xNVQxMjowMDowMFoiLCJpZCI6IjEyMzQ1In0"`
+}
+
+// OwnerPurgeReport — последствия окончательного удаления владельца
+type OwnerPurgeReport struct {
+	OwnerID  string `json:"owner_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	FullName string `json:"full_name" example:"Иван Иванов"`
+	// BotsDetached — боты владельца остаются, но теряют привязку к нему (owner_id станет NULL)
+	BotsDetached int64 `json:"bots_detached" example:"3"`
+	// TeamMemberships — членство владельца в командах, которое удаляется вместе с ним
+	TeamMemberships int64 `json:"team_memberships" example:"1"`
+	// Tokens — токены владельца, которые удаляются вместе с ним
+	Tokens int64 `json:"tokens" example:"1"`
+	// Purged — удаление выполнено (false — предварительный отчёт)
+	Purged bool `json:"purged" example:"false"`
 }
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// RedactionRule — пользовательское правило маскирования сообщений логов
+type RedactionRule struct {
+	ID          string    `json:"id" db:"id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
+	BotID       *string   `json:"bot_id,omitempty" db:"bot_id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
+	Name        string    `json:"name" db:"name" example:"contract_number"`
+	Pattern     string    `json:"pattern" db:"pattern" example:"Договор № (?P<pii>\\d{6,})"`
+	Replacement *string   `json:"replacement,omitempty" db:"replacement" example:"[договор]"`
+	IsActive    bool      `json:"is_active" db:"is_active" example:"true"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at" example:"2023-01-15T12:00:00Z"`
+}
+
+// RedactionCount — число замаскированных фрагментов бота по правилу за период
+type RedactionCount struct {
+	BotID   *string `json:"bot_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
+	BotCode *string `json:"bot_code,omitempty" example:"PROJ001"`
+	Rule    string  `json:"rule" example:"phone"`
+	Count   int64   `json:"count" example:"42"`
+}
+
+// RedactionCountDelta — прирост числа замен по боту, правилу и дню
+type RedactionCountDelta struct {
+	BotID *string
+	Rule  string
+	Day   time.Time
+	Count int64
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Team — команда владельцев, совместно отвечающая за ботов
+type Team struct {
+	ID            string            `json:"id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
+	Name          string            `json:"name" example:"Платежи"`
+	Description   *string           `json:"description,omitempty" example:"Боты платёжного контура"`
+	Notifications TeamNotifications `json:"notifications"`
+	// MemberCount и BotCount — число участников и ботов (не в архиве) команды
+	MemberCount int       `json:"member_count" example:"4"`
+	BotCount    int       `json:"bot_count" example:"12"`
+	CreatedAt   time.Time `json:"created_at" example:"2023-01-15T12:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" example:"2023-01-15T12:00:00Z"`
+	// Members — участники команды; заполняется только при получении одной команды
+	Members []*TeamMember `json:"members,omitempty"`
+}
+
+// TeamNotifications — настройки уведомлений команды о смене состояния её ботов
+type TeamNotifications struct {
+	// WebhookURL — адрес для POST-уведомлений; нет — уведомления не отправляются
+	WebhookURL *string `json:"webhook_url,omitempty" example:"https://hooks.example.com/payments"`
+	// States — состояния, переход бота в которые вызывает уведомление
+	States []string `json:"states" example:"failing,silent"`
+}
+
+// TeamMember — владелец в команде
+type TeamMember struct {
+	OwnerID  string    `json:"owner_id" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7" swaggertype:"string" format:"uuid"`
+	FullName string    `json:"full_name" example:"Иван Иванов"`
+	Role     string    `json:"role" example:"member" enums:"lead,member"`
+	AddedAt  time.Time `json:"added_at" example:"2023-01-15T12:00:00Z"`
+}
+
+// TeamFilter — параметры выборки команд
+type TeamFilter struct {
+	// Query — подстрока в name (без учёта регистра)
+	Query string
+	// OwnerID — только команды, в которых состоит владелец
+	OwnerID *string
+}
+
+// TeamNotification — тело уведомления, отправляемого на webhook команды
+type TeamNotification struct {
+	TeamID   string          `json:"team_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TeamName string          `json:"team_name" example:"Платежи"`
+	Changes  []*HealthChange `json:"changes"`
+}
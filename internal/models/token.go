@@ -6,6 +6,8 @@ import "time"
 type Token struct {
 	ID        string    `json:"id" db:"id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
 	BotID     *string   `json:"bot_id,omitempty" db:"bot_id" example:"550e8400-e29b-41d4-a716-446655440000" swaggertype:"string" format:"uuid"`
+	TeamID    *string   `json:"team_id,omitempty" db:"team_id" example:"9b2e7c1a-1f4d-4c8e-9a6b-2d3f4e5a6b7c" swaggertype:"string" format:"uuid"`
+	OwnerID   *string   `json:"owner_id,omitempty" db:"owner_id" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7" swaggertype:"string" format:"uuid"`
 	Name      string    `json:"name" db:"name" binding:"required,min=3,max=100" example:"Production Server"`
 	IsActive  bool      `json:"is_active" db:"is_active" example:"true"`
 	IsAdmin   bool      `json:"is_admin" db:"is_admin" example:"false"`
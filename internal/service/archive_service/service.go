@@ -0,0 +1,406 @@
+package archiveservice
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+	"logging_api/pkg/archive"
+)
+
+const (
+	manifestName  = "manifest.json"
+	noBotKey      = "no_bot"
+	importBatch   = 5000
+	importPrefix  = "archive_import_"
+	maxImportDays = 366
+)
+
+var importTableRe = regexp.MustCompile(`^archive_import_\d+$`)
+
+type ArchiveRepoInterface interface {
+	GetDaysBefore(status string, before time.Time) ([]time.Time, error)
+	StreamLogs(status string, from, to time.Time, fn func(*models.Log) error) error
+	DeleteLogs(status string, from, to time.Time) (int64, error)
+	CreateImportTable(table string) error
+	ImportLogs(table string, logs []*models.Log) error
+	DropImportTable(table string) error
+}
+
+type ArchiveService struct {
+	archiveRepo ArchiveRepoInterface
+	storage     archive.Storage
+}
+
+func NewArchiveService(archiveRepo ArchiveRepoInterface, storage archive.Storage) *ArchiveService {
+	return &ArchiveService{
+		archiveRepo: archiveRepo,
+		storage:     storage,
+	}
+}
+
+// ArchiveBefore выгружает в архив и удаляет логи уровня level за полные дни (UTC) до before.
+// День удаляется из базы только после успешной выгрузки всех его файлов.
+func (s *ArchiveService) ArchiveBefore(level string, before time.Time) (int64, error) {
+	before = before.UTC().Truncate(24 * time.Hour)
+
+	days, err := s.archiveRepo.GetDaysBefore(level, before)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения дней для архивации: %w", err)
+	}
+
+	var total int64
+	for _, day := range days {
+		if err := s.archiveDay(level, day); err != nil {
+			return total, fmt.Errorf("ошибка архивации логов %s за %s: %w", level, day.Format("2006-01-02"), err)
+		}
+
+		deleted, err := s.archiveRepo.DeleteLogs(level, day, day.AddDate(0, 0, 1))
+		if err != nil {
+			return total, fmt.Errorf("ошибка удаления архивированных логов: %w", err)
+		}
+		total += deleted
+	}
+
+	return total, nil
+}
+
+// archiveFile накапливает логи одного бота во временный gzip файл
+type archiveFile struct {
+	tmp    *os.File
+	hash   hash.Hash
+	gz     *gzip.Writer
+	enc    *json.Encoder
+	botKey string
+	entry  models.ArchiveFile
+}
+
+func (s *ArchiveService) archiveDay(level string, day time.Time) error {
+	var current *archiveFile
+	defer func() {
+		if current != nil {
+			current.discard()
+		}
+	}()
+
+	err := s.archiveRepo.StreamLogs(level, day, day.AddDate(0, 0, 1), func(entry *models.Log) error {
+		botKey := noBotKey
+		if entry.BotID != nil {
+			botKey = *entry.BotID
+		}
+
+		if current != nil && current.botKey != botKey {
+			if err := s.flush(current, day); err != nil {
+				return err
+			}
+			current = nil
+		}
+
+		if current == nil {
+			file, err := newArchiveFile(botKey, level, dayPrefix(day))
+			if err != nil {
+				return err
+			}
+			current = file
+		}
+
+		return current.write(entry)
+	})
+	if err != nil {
+		return err
+	}
+
+	if current != nil {
+		if err := s.flush(current, day); err != nil {
+			return err
+		}
+		current = nil
+	}
+
+	return nil
+}
+
+func newArchiveFile(botKey, level, prefix string) (*archiveFile, error) {
+	tmp, err := os.CreateTemp("", "logs-archive-*.ndjson.gz")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания временного файла: %w", err)
+	}
+
+	h := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(tmp, h))
+
+	return &archiveFile{
+		tmp:    tmp,
+		hash:   h,
+		gz:     gz,
+		enc:    json.NewEncoder(gz),
+		botKey: botKey,
+		entry: models.ArchiveFile{
+			Level: level,
+			Key:   path.Join(prefix, botKey, level+".ndjson.gz"),
+		},
+	}, nil
+}
+
+func (f *archiveFile) write(entry *models.Log) error {
+	if err := f.enc.Encode(entry); err != nil {
+		return fmt.Errorf("ошибка записи в архив: %w", err)
+	}
+
+	if f.entry.Rows == 0 {
+		f.entry.MinID = entry.ID
+		f.entry.FirstCreatedAt = entry.CreatedAt
+	}
+	f.entry.Rows++
+	if entry.ID < f.entry.MinID {
+		f.entry.MinID = entry.ID
+	}
+	if entry.ID > f.entry.MaxID {
+		f.entry.MaxID = entry.ID
+	}
+	if entry.CreatedAt.Before(f.entry.FirstCreatedAt) {
+		f.entry.FirstCreatedAt = entry.CreatedAt
+	}
+	if entry.CreatedAt.After(f.entry.LastCreatedAt) {
+		f.entry.LastCreatedAt = entry.CreatedAt
+	}
+	return nil
+}
+
+func (f *archiveFile) discard() {
+	f.tmp.Close()
+	os.Remove(f.tmp.Name())
+}
+
+// flush загружает файл в хранилище и обновляет манифест бота за день
+func (s *ArchiveService) flush(f *archiveFile, day time.Time) error {
+	defer f.discard()
+
+	if err := f.gz.Close(); err != nil {
+		return fmt.Errorf("ошибка сжатия архива: %w", err)
+	}
+
+	size, err := f.tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("ошибка чтения временного файла: %w", err)
+	}
+	if _, err := f.tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("ошибка чтения временного файла: %w", err)
+	}
+
+	f.entry.Bytes = size
+	f.entry.SHA256 = hex.EncodeToString(f.hash.Sum(nil))
+	f.entry.ExportedAt = time.Now().UTC()
+
+	manifestKey := path.Join(dayPrefix(day), f.botKey, manifestName)
+	manifest, err := s.readManifest(manifestKey)
+	if err != nil {
+		if !errors.Is(err, archive.ErrNotFound) {
+			return err
+		}
+		manifest = &models.ArchiveManifest{
+			BotID: f.botKey,
+			Day:   day.Format("2006-01-02"),
+		}
+	}
+
+	// Логи с клиентским временем могут прийти за уже архивированный день:
+	// тогда пишем отдельный файл, чтобы не затереть выгруженный ранее
+	for _, existing := range manifest.Files {
+		if existing.Key == f.entry.Key {
+			f.entry.Key = path.Join(dayPrefix(day), f.botKey, fmt.Sprintf("%s-%d.ndjson.gz", f.entry.Level, f.entry.ExportedAt.UnixNano()))
+			break
+		}
+	}
+
+	if err := s.storage.Put(f.entry.Key, f.tmp, size); err != nil {
+		return fmt.Errorf("ошибка загрузки архива: %w", err)
+	}
+
+	manifest.Files = append(manifest.Files, f.entry)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации манифеста: %w", err)
+	}
+
+	if err := s.storage.Put(manifestKey, strings.NewReader(string(data)), int64(len(data))); err != nil {
+		return fmt.Errorf("ошибка загрузки манифеста: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ArchiveService) readManifest(key string) (*models.ArchiveManifest, error) {
+	reader, err := s.storage.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var manifest models.ArchiveManifest
+	if err := json.NewDecoder(reader).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("ошибка чтения манифеста %s: %w", key, err)
+	}
+	return &manifest, nil
+}
+
+// GetManifests возвращает манифесты за дни [from, to] (включительно), опционально для одного бота
+func (s *ArchiveService) GetManifests(from, to time.Time, botID *string) ([]*models.ArchiveManifest, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: дата окончания раньше даты начала", customerrors.ErrInvalidInput)
+	}
+	if to.Sub(from) > maxImportDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: диапазон не может превышать %d дней", customerrors.ErrInvalidInput, maxImportDays)
+	}
+
+	var manifests []*models.ArchiveManifest
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		prefix := dayPrefix(day) + "/"
+		if botID != nil {
+			prefix += *botID + "/"
+		}
+
+		keys, err := s.storage.List(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения архива: %w", err)
+		}
+
+		for _, key := range keys {
+			if path.Base(key) != manifestName {
+				continue
+			}
+			manifest, err := s.readManifest(key)
+			if err != nil {
+				return nil, err
+			}
+			manifests = append(manifests, manifest)
+		}
+	}
+
+	return manifests, nil
+}
+
+// ImportRange загружает архивные логи за дни [from, to] во временную таблицу для расследования
+func (s *ArchiveService) ImportRange(from, to time.Time, botID *string, levels []string) (*models.ArchiveImport, error) {
+	manifests, err := s.GetManifests(from, to, botID)
+	if err != nil {
+		return nil, err
+	}
+
+	levelSet := make(map[string]struct{}, len(levels))
+	for _, level := range levels {
+		levelSet[level] = struct{}{}
+	}
+
+	result := &models.ArchiveImport{
+		Table: fmt.Sprintf("%s%d", importPrefix, time.Now().UnixNano()),
+	}
+
+	if err := s.archiveRepo.CreateImportTable(result.Table); err != nil {
+		return nil, fmt.Errorf("ошибка создания временной таблицы: %w", err)
+	}
+
+	for _, manifest := range manifests {
+		for _, file := range manifest.Files {
+			if _, ok := levelSet[file.Level]; len(levelSet) > 0 && !ok {
+				continue
+			}
+
+			rows, err := s.importFile(result.Table, file.Key)
+			if err != nil {
+				if dropErr := s.archiveRepo.DropImportTable(result.Table); dropErr != nil {
+					log.Printf("Не удалось удалить таблицу импорта %s: %v", result.Table, dropErr)
+				}
+				return nil, fmt.Errorf("ошибка загрузки %s: %w", file.Key, err)
+			}
+			result.Files++
+			result.Rows += rows
+		}
+	}
+
+	log.Printf("Архив за %s — %s загружен в %s: %d файлов, %d строк",
+		from.Format("2006-01-02"), to.Format("2006-01-02"), result.Table, result.Files, result.Rows)
+
+	return result, nil
+}
+
+func (s *ArchiveService) importFile(table, key string) (int64, error) {
+	reader, err := s.storage.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	gz, err := gzip.NewReader(reader)
+	if err != nil {
+		return 0, err
+	}
+	defer gz.Close()
+
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var total int64
+	batch := make([]*models.Log, 0, importBatch)
+	for scanner.Scan() {
+		var entry models.Log
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return total, fmt.Errorf("повреждённая строка архива: %w", err)
+		}
+		batch = append(batch, &entry)
+
+		if len(batch) == importBatch {
+			if err := s.archiveRepo.ImportLogs(table, batch); err != nil {
+				return total, err
+			}
+			total += int64(len(batch))
+			batch = batch[:0]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return total, err
+	}
+
+	if len(batch) > 0 {
+		if err := s.archiveRepo.ImportLogs(table, batch); err != nil {
+			return total, err
+		}
+		total += int64(len(batch))
+	}
+
+	return total, nil
+}
+
+func (s *ArchiveService) DropImport(table string) error {
+	if !importTableRe.MatchString(table) {
+		return fmt.Errorf("%w: таблица %s не является таблицей импорта архива", customerrors.ErrNotFound, table)
+	}
+
+	if err := s.archiveRepo.DropImportTable(table); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: таблица импорта не найдена", customerrors.ErrNotFound)
+		}
+		return fmt.Errorf("ошибка удаления таблицы импорта: %w", err)
+	}
+	return nil
+}
+
+func dayPrefix(day time.Time) string {
+	return path.Join("logs", day.Format("2006/01/02"))
+}
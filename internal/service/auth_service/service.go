@@ -13,12 +13,21 @@ type TokenInfo struct {
 	OwnerID  string
 	IsAdmin  bool
 	IsActive bool
+	// BotArchived — бот токена перенесён в архив
+	BotArchived bool
+	// ProfileOwnerID — владелец токена владельца; ProfileOwnerArchived — он перенесён в архив
+	ProfileOwnerID       string
+	ProfileOwnerArchived bool
+	// TeamID — команда токена; TeamBotIDs — её боты не в архиве на момент проверки токена
+	TeamID     string
+	TeamBotIDs []string
 }
 
 type AuthRepoInterface interface {
-	CreateToken(botID *string, name string, isAdmin bool) (*models.Token, error)
+	CreateToken(botID, teamID, ownerID *string, name string, isAdmin bool) (*models.Token, error)
 	GetTokenByID(tokenID string) (*models.Token, error)
-	GetTokenWithOwner(tokenID string) (token *models.Token, ownerID string, err error)
+	GetTokenWithOwner(tokenID string) (token *models.Token, ownerID string, botArchived, ownerArchived bool, err error)
+	GetTeamBotIDs(teamID string) ([]string, error)
 	UpdateToken(tokenID, newName string) (*models.Token, error)
 	DeactivateToken(tokenID string) error
 	DeleteToken(tokenID string) error
@@ -28,35 +37,77 @@ type BotsRepoInterface interface {
 	GetBotByID(botID string) (*models.Bot, error)
 }
 
+type TeamsRepoInterface interface {
+	GetTeamByID(teamID string) (*models.Team, error)
+}
+
+type OwnersRepoInterface interface {
+	GetOwnerByID(ownerID string) (*models.Owner, error)
+}
+
 type AuthService struct {
-	authRepo AuthRepoInterface
-	botsRepo BotsRepoInterface
+	authRepo   AuthRepoInterface
+	botsRepo   BotsRepoInterface
+	teamsRepo  TeamsRepoInterface
+	ownersRepo OwnersRepoInterface
 }
 
-func NewAuthService(authRepo AuthRepoInterface, botsRepo BotsRepoInterface) *AuthService {
+func NewAuthService(authRepo AuthRepoInterface, botsRepo BotsRepoInterface, teamsRepo TeamsRepoInterface, ownersRepo OwnersRepoInterface) *AuthService {
 	return &AuthService{
-		authRepo: authRepo,
-		botsRepo: botsRepo,
+		authRepo:   authRepo,
+		botsRepo:   botsRepo,
+		teamsRepo:  teamsRepo,
+		ownersRepo: ownersRepo,
 	}
 }
 
-func (s *AuthService) CreateToken(botID *string, tokenName string, isAdmin bool) (*models.Token, error) {
+// CreateToken создаёт токен. Токен с teamID — токен команды: он не админский, не привязан к боту
+// и даёт доступ на чтение данных всех ботов команды. Токен с ownerID — токен владельца: он даёт
+// доступ только к профилю владельца.
+func (s *AuthService) CreateToken(botID, teamID, ownerID *string, tokenName string, isAdmin bool) (*models.Token, error) {
 
-	if !isAdmin {
+	if ownerID != nil {
+		if isAdmin || botID != nil || teamID != nil {
+			return nil, fmt.Errorf("%w: токен владельца не может быть админским или привязанным к боту или команде", customerrors.ErrInvalidInput)
+		}
+		owner, err := s.ownersRepo.GetOwnerByID(*ownerID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, fmt.Errorf("%w: владелец с id %s не найден", customerrors.ErrNotFound, *ownerID)
+			}
+			return nil, fmt.Errorf("ошибка проверки владельца: %w", err)
+		}
+		if owner.ArchivedAt != nil {
+			return nil, fmt.Errorf("%w: владелец с id %s в архиве", customerrors.ErrConflict, *ownerID)
+		}
+	} else if teamID != nil {
+		if isAdmin || botID != nil {
+			return nil, fmt.Errorf("%w: токен команды не может быть админским или привязанным к боту", customerrors.ErrInvalidInput)
+		}
+		if _, err := s.teamsRepo.GetTeamByID(*teamID); err != nil {
+			if err == sql.ErrNoRows {
+				return nil, fmt.Errorf("%w: команда с id %s не найдена", customerrors.ErrNotFound, *teamID)
+			}
+			return nil, fmt.Errorf("ошибка проверки команды: %w", err)
+		}
+	} else if !isAdmin {
 		if botID == nil || *botID == "" {
 			return nil, fmt.Errorf("%w: bot_id обязателен для обычных токенов", customerrors.ErrNotFound)
 		}
 		// Проверяем существование бота
-		_, err := s.botsRepo.GetBotByID(*botID)
+		bot, err := s.botsRepo.GetBotByID(*botID)
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return nil, fmt.Errorf("%w: бот с id %s не найден", customerrors.ErrNotFound, *botID)
 			}
 			return nil, fmt.Errorf("ошибка проверки бота: %w", err)
 		}
+		if bot.ArchivedAt != nil {
+			return nil, fmt.Errorf("%w: бот с id %s в архиве", customerrors.ErrConflict, *botID)
+		}
 	}
 
-	token, err := s.authRepo.CreateToken(botID, tokenName, isAdmin)
+	token, err := s.authRepo.CreateToken(botID, teamID, ownerID, tokenName, isAdmin)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка создания токена: %w", err)
 	}
@@ -95,7 +146,7 @@ func (s *AuthService) DeleteToken(tokenID string) error {
 }
 
 func (s *AuthService) ValidateToken(tokenID string) (*TokenInfo, error) {
-	token, ownerID, err := s.authRepo.GetTokenWithOwner(tokenID)
+	token, ownerID, botArchived, ownerArchived, err := s.authRepo.GetTokenWithOwner(tokenID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("%w: токен не найден", customerrors.ErrNotFound)
@@ -108,13 +159,29 @@ func (s *AuthService) ValidateToken(tokenID string) (*TokenInfo, error) {
 		botID = *token.BotID
 	}
 
-	return &TokenInfo{
-		TokenID:  token.ID,
-		BotID:    botID,
-		OwnerID:  ownerID,
-		IsAdmin:  token.IsAdmin,
-		IsActive: token.IsActive,
-	}, nil
+	info := &TokenInfo{
+		TokenID:     token.ID,
+		BotID:       botID,
+		OwnerID:     ownerID,
+		IsAdmin:     token.IsAdmin,
+		IsActive:    token.IsActive,
+		BotArchived: botArchived,
+	}
+
+	if token.OwnerID != nil {
+		info.ProfileOwnerID = *token.OwnerID
+		info.ProfileOwnerArchived = ownerArchived
+	}
+
+	if token.TeamID != nil {
+		info.TeamID = *token.TeamID
+		info.TeamBotIDs, err = s.authRepo.GetTeamBotIDs(*token.TeamID)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка получения ботов команды: %w", err)
+		}
+	}
+
+	return info, nil
 }
 
 func (s *AuthService) GetMe(tokenID string) (*models.Token, error) {
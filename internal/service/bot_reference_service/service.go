@@ -0,0 +1,126 @@
+package botreferenceservice
+
+import (
+	"database/sql"
+	"fmt"
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+	"strings"
+)
+
+type BotReferenceRepoInterface interface {
+	ListReferences(kind string) ([]*models.BotReference, error)
+	GetReference(kind, code string) (*models.BotReference, error)
+	CreateReference(kind, code string, description *string) error
+	UpdateReference(kind, code string, newCode, description *string) error
+	DeleteReference(kind, code string) error
+}
+
+// referenceNames — названия значений справочников в сообщениях об ошибках
+var referenceNames = map[string]string{
+	models.BotReferenceTypes:     "тип бота",
+	models.BotReferenceLanguages: "язык бота",
+}
+
+// BotReferenceService ведёт справочники типов и языков ботов
+type BotReferenceService struct {
+	repo BotReferenceRepoInterface
+}
+
+func NewBotReferenceService(repo BotReferenceRepoInterface) *BotReferenceService {
+	return &BotReferenceService{repo: repo}
+}
+
+func (s *BotReferenceService) ListReferences(kind string) ([]*models.BotReference, error) {
+	references, err := s.repo.ListReferences(kind)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения справочника: %w", err)
+	}
+	if references == nil {
+		references = []*models.BotReference{}
+	}
+	return references, nil
+}
+
+func (s *BotReferenceService) GetReference(kind, code string) (*models.BotReference, error) {
+	reference, err := s.repo.GetReference(kind, code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s %q не найден", customerrors.ErrNotFound, referenceNames[kind], code)
+		}
+		return nil, fmt.Errorf("ошибка получения справочника: %w", err)
+	}
+	return reference, nil
+}
+
+// CreateReference добавляет значение в справочник; код уникален с точностью до регистра
+func (s *BotReferenceService) CreateReference(kind, code string, description *string) (*models.BotReference, error) {
+	if err := s.checkCode(kind, code, ""); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateReference(kind, code, description); err != nil {
+		return nil, fmt.Errorf("ошибка добавления в справочник: %w", err)
+	}
+
+	return s.GetReference(kind, code)
+}
+
+// UpdateReference меняет код и описание значения (nil — без изменений). Новый код переносится на всех ботов.
+func (s *BotReferenceService) UpdateReference(kind, code string, newCode, description *string) (*models.BotReference, error) {
+	if newCode != nil && *newCode != code {
+		if err := s.checkCode(kind, *newCode, code); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateReference(kind, code, newCode, description); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s %q не найден", customerrors.ErrNotFound, referenceNames[kind], code)
+		}
+		return nil, fmt.Errorf("ошибка обновления справочника: %w", err)
+	}
+
+	if newCode != nil {
+		code = *newCode
+	}
+	return s.GetReference(kind, code)
+}
+
+// DeleteReference удаляет значение из справочника; значение, которое используют боты (в том числе в архиве), удалить нельзя
+func (s *BotReferenceService) DeleteReference(kind, code string) error {
+	reference, err := s.GetReference(kind, code)
+	if err != nil {
+		return err
+	}
+	if reference.BotCount > 0 {
+		return fmt.Errorf("%w: %s %q используется ботами (%d), сначала смените его у них", customerrors.ErrConflict, referenceNames[kind], code, reference.BotCount)
+	}
+
+	if err := s.repo.DeleteReference(kind, code); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: %s %q не найден", customerrors.ErrNotFound, referenceNames[kind], code)
+		}
+		return fmt.Errorf("ошибка удаления из справочника: %w", err)
+	}
+	return nil
+}
+
+// checkCode проверяет код нового значения: без пробелов по краям и не совпадает с другими значениями
+// справочника (кроме exceptCode) без учёта регистра
+func (s *BotReferenceService) checkCode(kind, code, exceptCode string) error {
+	if strings.TrimSpace(code) != code {
+		return fmt.Errorf("%w: код не должен начинаться или заканчиваться пробелами", customerrors.ErrInvalidInput)
+	}
+
+	references, err := s.repo.ListReferences(kind)
+	if err != nil {
+		return fmt.Errorf("ошибка проверки справочника: %w", err)
+	}
+	for _, reference := range references {
+		if reference.Code != exceptCode && strings.EqualFold(reference.Code, code) {
+			return fmt.Errorf("%w: %s %q уже есть в справочнике", customerrors.ErrConflict, referenceNames[kind], reference.Code)
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"logging_api/internal/models"
 	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/pagination"
 )
 
 type BotRepoInterface interface {
@@ -12,26 +13,69 @@ type BotRepoInterface interface {
 	GetBotByID(botID string) (*models.Bot, error)
 	GetBotByCode(code string) (*models.Bot, error)
 	GetBotsByOwner(ownerID string) ([]*models.Bot, error)
-	GetAllBots() ([]*models.Bot, error)
-	UpdateBot(bot *models.Bot) (*models.Bot, error)
-	DeleteBot(botID string) error
+	ListBots(filter *models.BotFilter) ([]*models.Bot, int, error)
+	UpdateBot(bot *models.Bot, changedBy *string) (*models.Bot, error)
+	TransferBot(botID string, toOwnerID, changedBy, reason *string) (bool, error)
+	SetBotTeam(botID string, teamID *string) error
+	ListOwnerHistory(botID string) ([]*models.BotOwnerChange, error)
+	ArchiveBot(botID string, archivedBy *string) error
+	RestoreBot(botID string) error
+	GetBotPurgeReport(botID string) (*models.BotPurgeReport, error)
+	PurgeBot(botID string) (*models.BotPurgeReport, error)
+}
+
+// OwnerRepoInterface нужен для проверки владельца при передаче бота
+type OwnerRepoInterface interface {
+	GetOwnerByID(ownerID string) (*models.Owner, error)
+}
+
+// TeamRepoInterface нужен для проверки команды при включении в неё бота
+type TeamRepoInterface interface {
+	GetTeamByID(teamID string) (*models.Team, error)
+}
+
+// BotReferenceRepoInterface нужен для проверки типа и языка бота по справочникам
+type BotReferenceRepoInterface interface {
+	GetReference(kind, code string) (*models.BotReference, error)
+}
+
+// HealthProvider возвращает последнее рассчитанное состояние бота
+type HealthProvider interface {
+	Health(botID string) *models.BotHealth
 }
 
 type BotService struct {
-	botRepo BotRepoInterface
+	botRepo   BotRepoInterface
+	ownerRepo OwnerRepoInterface
+	teamRepo  TeamRepoInterface
+	refRepo   BotReferenceRepoInterface
+	health    HealthProvider
 }
 
-func NewBotService(botRepo BotRepoInterface) *BotService {
+// NewBotService создаёт сервис ботов. health может быть nil — тогда боты возвращаются без состояния.
+func NewBotService(botRepo BotRepoInterface, ownerRepo OwnerRepoInterface, teamRepo TeamRepoInterface, refRepo BotReferenceRepoInterface, health HealthProvider) *BotService {
 	return &BotService{
-		botRepo: botRepo,
+		botRepo:   botRepo,
+		ownerRepo: ownerRepo,
+		teamRepo:  teamRepo,
+		refRepo:   refRepo,
+		health:    health,
 	}
 }
 
 func (s *BotService) CreateBot(bot *models.Bot) (*models.Bot, error) {
+	if err := s.checkReferences(bot); err != nil {
+		return nil, err
+	}
+	if err := s.checkTeam(bot.TeamID); err != nil {
+		return nil, err
+	}
+
 	createdBot, err := s.botRepo.CreateBot(bot)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка создания бота: %w", err)
 	}
+	s.withHealth(createdBot)
 	return createdBot, nil
 }
 
@@ -43,6 +87,7 @@ func (s *BotService) GetBotByID(botID string) (*models.Bot, error) {
 		}
 		return nil, fmt.Errorf("ошибка получения бота: %w", err)
 	}
+	s.withHealth(bot)
 	return bot, nil
 }
 
@@ -54,33 +99,301 @@ func (s *BotService) GetBotByCode(code string) (*models.Bot, error) {
 		}
 		return nil, fmt.Errorf("ошибка получения бота: %w", err)
 	}
+	s.withHealth(bot)
 	return bot, nil
 }
 
-func (s *BotService) GetAllBots() ([]*models.Bot, error) {
-	bots, err := s.botRepo.GetAllBots()
+// ListBots возвращает страницу ботов по фильтру; cursor — курсор из предыдущей страницы.
+// По умолчанию сортировка по created_at; поля времени сортируются по убыванию, code и name — по возрастанию.
+func (s *BotService) ListBots(filter *models.BotFilter, cursor string) (*models.BotPage, error) {
+	filter.Limit = pagination.Limit(filter.Limit)
+	byTime := defaultBotSort(filter)
+
+	c, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customerrors.ErrInvalidInput, err)
+	}
+	if c != nil {
+		if c.ID == "" || (byTime && c.Time == nil) || (!byTime && c.Value == "") {
+			return nil, fmt.Errorf("%w: %v", customerrors.ErrInvalidInput, pagination.ErrInvalidCursor)
+		}
+		filter.AfterID = &c.ID
+		if byTime {
+			filter.AfterTime = c.Time
+		} else {
+			filter.AfterValue = &c.Value
+		}
+	}
+
+	bots, total, err := s.botRepo.ListBots(filter)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения списка ботов: %w", err)
 	}
+
+	page := &models.BotPage{Items: bots, Total: total}
+	if page.Items == nil {
+		page.Items = []*models.Bot{}
+	}
+	s.withHealth(page.Items...)
+
+	if len(bots) > filter.Limit {
+		page.Items = bots[:filter.Limit]
+		last := page.Items[len(page.Items)-1]
+		next := &pagination.Cursor{ID: last.ID}
+		switch filter.SortBy {
+		case "updated_at":
+			next.Time = &last.UpdatedAt
+		case "code":
+			next.Value = last.Code
+		case "name":
+			next.Value = last.Name
+		default:
+			next.Time = &last.CreatedAt
+		}
+		encoded := next.Encode()
+		page.NextCursor = &encoded
+	}
+
+	return page, nil
+}
+
+// ListAllBots возвращает всех ботов по фильтру без постраничной выдачи, с той же сортировкой, что ListBots
+// (прежний формат GET /v1/bots без limit и cursor)
+func (s *BotService) ListAllBots(filter *models.BotFilter) ([]*models.Bot, error) {
+	filter.Limit = 0
+	defaultBotSort(filter)
+
+	bots, _, err := s.botRepo.ListBots(filter)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения списка ботов: %w", err)
+	}
+	if bots == nil {
+		bots = []*models.Bot{}
+	}
+	s.withHealth(bots...)
 	return bots, nil
 }
 
-func (s *BotService) UpdateBot(bot *models.Bot) (*models.Bot, error) {
-	updatedBot, err := s.botRepo.UpdateBot(bot)
+// defaultBotSort задаёт сортировку по умолчанию и возвращает, сортируются ли боты по времени
+func defaultBotSort(filter *models.BotFilter) bool {
+	if filter.SortBy == "" {
+		filter.SortBy = "created_at"
+	}
+	byTime := filter.SortBy == "created_at" || filter.SortBy == "updated_at"
+	if filter.Order == "" {
+		filter.Order = "asc"
+		if byTime {
+			filter.Order = "desc"
+		}
+	}
+	return byTime
+}
+
+// UpdateBot обновляет данные бота. Если у бота другой владелец, бот передаётся ему с записью в историю
+// от имени changedBy (ID админского токена); передача и изменение данных применяются вместе или не применяются вовсе.
+// Владелец проверяется только при смене: бота владельца в архиве можно изменять, не передавая его.
+func (s *BotService) UpdateBot(bot *models.Bot, changedBy string) (*models.Bot, error) {
+	if err := s.checkReferences(bot); err != nil {
+		return nil, err
+	}
+	if err := s.checkTeam(bot.TeamID); err != nil {
+		return nil, err
+	}
+
+	current, err := s.botRepo.GetBotByID(bot.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: бот не найден", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка получения бота: %w", err)
+	}
+	if !sameOwner(current.OwnerID, bot.OwnerID) {
+		if err := s.checkOwner(bot.OwnerID); err != nil {
+			return nil, err
+		}
+	}
+
+	var by *string
+	if changedBy != "" {
+		by = &changedBy
+	}
+	updatedBot, err := s.botRepo.UpdateBot(bot, by)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("%w: бот не найден", customerrors.ErrNotFound)
 		}
 		return nil, fmt.Errorf("ошибка обновления бота: %w", err)
 	}
+	s.withHealth(updatedBot)
 	return updatedBot, nil
 }
 
-func (s *BotService) DeleteBot(botID string) error {
-	err := s.botRepo.DeleteBot(botID)
+// TransferBot передаёт бота владельцу ownerID (nil — снять владельца) и записывает передачу в историю.
+// Передать бота можно только существующему владельцу не в архиве; передача текущему владельцу ничего не меняет.
+func (s *BotService) TransferBot(botID string, ownerID *string, changedBy string, reason *string) (*models.Bot, error) {
+	if err := s.checkOwner(ownerID); err != nil {
+		return nil, err
+	}
+
+	var by *string
+	if changedBy != "" {
+		by = &changedBy
+	}
+	if _, err := s.botRepo.TransferBot(botID, ownerID, by, reason); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: бот не найден", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка передачи бота: %w", err)
+	}
+	return s.GetBotByID(botID)
+}
+
+// checkOwner проверяет, что бота можно передать владельцу ownerID: он существует и не в архиве (nil — без проверки)
+func (s *BotService) checkOwner(ownerID *string) error {
+	if ownerID == nil {
+		return nil
+	}
+
+	owner, err := s.ownerRepo.GetOwnerByID(*ownerID)
 	if err != nil {
-		return fmt.Errorf("ошибка удаления бота: %w", err)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: владелец с id %s не найден", customerrors.ErrInvalidInput, *ownerID)
+		}
+		return fmt.Errorf("ошибка проверки владельца: %w", err)
+	}
+	if owner.ArchivedAt != nil {
+		return fmt.Errorf("%w: владелец с id %s в архиве", customerrors.ErrConflict, *ownerID)
 	}
 	return nil
 }
 
+// sameOwner сообщает, совпадают ли владельцы (nil — без владельца)
+func sameOwner(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// SetBotTeam включает бота в команду teamID (nil — исключает из команды)
+func (s *BotService) SetBotTeam(botID string, teamID *string) (*models.Bot, error) {
+	if err := s.checkTeam(teamID); err != nil {
+		return nil, err
+	}
+
+	if err := s.botRepo.SetBotTeam(botID, teamID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: бот не найден", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка изменения команды бота: %w", err)
+	}
+	return s.GetBotByID(botID)
+}
+
+// checkReferences проверяет, что тип и язык бота есть в справочниках
+func (s *BotService) checkReferences(bot *models.Bot) error {
+	if err := s.checkReference(models.BotReferenceTypes, bot.BotType, "тип бота"); err != nil {
+		return err
+	}
+	return s.checkReference(models.BotReferenceLanguages, bot.Language, "язык бота")
+}
+
+func (s *BotService) checkReference(kind, code, name string) error {
+	if _, err := s.refRepo.GetReference(kind, code); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: %s %q не найден в справочнике", customerrors.ErrInvalidInput, name, code)
+		}
+		return fmt.Errorf("ошибка проверки справочника: %w", err)
+	}
+	return nil
+}
+
+// checkTeam проверяет, что команда teamID существует (nil — без команды)
+func (s *BotService) checkTeam(teamID *string) error {
+	if teamID == nil {
+		return nil
+	}
+	if _, err := s.teamRepo.GetTeamByID(*teamID); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: команда с id %s не найдена", customerrors.ErrInvalidInput, *teamID)
+		}
+		return fmt.Errorf("ошибка проверки команды: %w", err)
+	}
+	return nil
+}
+
+// GetOwnerHistory возвращает историю передачи бота, новые записи первыми
+func (s *BotService) GetOwnerHistory(botID string) ([]*models.BotOwnerChange, error) {
+	if _, err := s.botRepo.GetBotByID(botID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: бот не найден", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка получения бота: %w", err)
+	}
+
+	changes, err := s.botRepo.ListOwnerHistory(botID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения истории владельцев: %w", err)
+	}
+	if changes == nil {
+		changes = []*models.BotOwnerChange{}
+	}
+	return changes, nil
+}
+
+// ArchiveBot переносит бота в архив; archivedBy — ID админского токена. Повторный перенос возвращает бота без изменений.
+func (s *BotService) ArchiveBot(botID, archivedBy string) (*models.Bot, error) {
+	var by *string
+	if archivedBy != "" {
+		by = &archivedBy
+	}
+	if err := s.botRepo.ArchiveBot(botID, by); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("ошибка переноса бота в архив: %w", err)
+	}
+	return s.GetBotByID(botID)
+}
+
+// RestoreBot возвращает бота из архива. Для бота не в архиве возвращает его без изменений.
+func (s *BotService) RestoreBot(botID string) (*models.Bot, error) {
+	if err := s.botRepo.RestoreBot(botID); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("ошибка восстановления бота: %w", err)
+	}
+	return s.GetBotByID(botID)
+}
+
+// GetBotPurgeReport возвращает отчёт о том, что удалит окончательное удаление бота
+func (s *BotService) GetBotPurgeReport(botID string) (*models.BotPurgeReport, error) {
+	report, err := s.botRepo.GetBotPurgeReport(botID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: бот не найден", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка подсчёта связанных записей бота: %w", err)
+	}
+	return report, nil
+}
+
+// PurgeBot окончательно удаляет бота; удалить можно только бота в архиве
+func (s *BotService) PurgeBot(botID string) (*models.BotPurgeReport, error) {
+	report, err := s.botRepo.PurgeBot(botID)
+	if err == sql.ErrNoRows {
+		if _, err := s.GetBotByID(botID); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: окончательно удалить можно только бота в архиве", customerrors.ErrConflict)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка удаления бота: %w", err)
+	}
+	return report, nil
+}
+
+// withHealth дополняет ботов их состоянием
+func (s *BotService) withHealth(bots ...*models.Bot) {
+	if s.health == nil {
+		return
+	}
+	for _, bot := range bots {
+		bot.Health = s.health.Health(bot.ID)
+	}
+}
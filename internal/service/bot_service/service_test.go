@@ -0,0 +1,101 @@
+package botservice
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+)
+
+// stubBotRepo хранит ботов в памяти; методы, не нужные тестам, не реализованы
+type stubBotRepo struct {
+	BotRepoInterface
+	bots map[string]*models.Bot
+}
+
+func (r *stubBotRepo) GetBotByID(botID string) (*models.Bot, error) {
+	bot, ok := r.bots[botID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *bot
+	return &copied, nil
+}
+
+func (r *stubBotRepo) UpdateBot(bot *models.Bot, changedBy *string) (*models.Bot, error) {
+	if _, ok := r.bots[bot.ID]; !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *bot
+	r.bots[bot.ID] = &copied
+	return bot, nil
+}
+
+type stubOwnerRepo map[string]*models.Owner
+
+func (r stubOwnerRepo) GetOwnerByID(ownerID string) (*models.Owner, error) {
+	owner, ok := r[ownerID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return owner, nil
+}
+
+type stubRefRepo struct{}
+
+func (stubRefRepo) GetReference(kind, code string) (*models.BotReference, error) {
+	return &models.BotReference{}, nil
+}
+
+func TestUpdateBotOwnerCheck(t *testing.T) {
+	archivedAt := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	archived, active, missing := "owner-archived", "owner-active", "owner-missing"
+
+	tests := []struct {
+		name    string
+		current *string
+		ownerID *string
+		check   func(error) bool
+	}{
+		{name: "rename bot of archived owner", current: &archived, ownerID: &archived},
+		{name: "transfer from archived owner", current: &archived, ownerID: &active},
+		{name: "transfer to archived owner", current: nil, ownerID: &archived, check: customerrors.IsConflict},
+		{name: "transfer to missing owner", current: &active, ownerID: &missing, check: customerrors.IsInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubBotRepo{bots: map[string]*models.Bot{
+				"bot": {ID: "bot", Name: "old", BotType: "bot", Language: "go", OwnerID: tt.current},
+			}}
+			owners := stubOwnerRepo{
+				archived: {ID: archived, ArchivedAt: &archivedAt},
+				active:   {ID: active},
+			}
+			s := NewBotService(repo, owners, nil, stubRefRepo{}, nil)
+
+			bot, _ := repo.GetBotByID("bot")
+			bot.Name = "new"
+			bot.OwnerID = tt.ownerID
+
+			_, err := s.UpdateBot(bot, "")
+			if tt.check == nil {
+				if err != nil {
+					t.Fatalf("UpdateBot() error = %v", err)
+				}
+				if got := repo.bots["bot"].Name; got != "new" {
+					t.Errorf("name = %q, want %q", got, "new")
+				}
+				return
+			}
+			if !tt.check(err) {
+				t.Fatalf("UpdateBot() error = %v", err)
+			}
+			if got := repo.bots["bot"].Name; got != "old" {
+				t.Errorf("name = %q, want %q", got, "old")
+			}
+		})
+	}
+}
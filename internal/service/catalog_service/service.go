@@ -0,0 +1,383 @@
+package catalogservice
+
+import (
+	"fmt"
+	"log"
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/loglevel"
+	"sort"
+)
+
+type CatalogRepoInterface interface {
+	GetCatalogState() (*models.CatalogState, error)
+	ImportCatalog(build func(state *models.CatalogState) (*models.CatalogPlan, error), changedBy *string) error
+}
+
+// PolicyReloader перечитывает политики хранения логов после их изменения импортом
+type PolicyReloader interface {
+	ReloadPolicies() error
+}
+
+type CatalogService struct {
+	repo     CatalogRepoInterface
+	policies PolicyReloader
+}
+
+func NewCatalogService(repo CatalogRepoInterface, policies PolicyReloader) *CatalogService {
+	return &CatalogService{
+		repo:     repo,
+		policies: policies,
+	}
+}
+
+// Export возвращает каталог владельцев и ботов не в архиве: владельцы по full_name, боты по code
+func (s *CatalogService) Export() (*models.Catalog, error) {
+	state, err := s.repo.GetCatalogState()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения каталога: %w", err)
+	}
+
+	teamNames := make(map[string]string, len(state.Teams))
+	for _, team := range state.Teams {
+		teamNames[team.ID] = team.Name
+	}
+
+	ownerNames := make(map[string]string, len(state.Owners))
+	catalog := &models.Catalog{
+		Owners: make([]*models.CatalogOwner, 0, len(state.Owners)),
+		Bots:   make([]*models.CatalogBot, 0, len(state.Bots)),
+	}
+	for _, owner := range state.Owners {
+		ownerNames[owner.ID] = owner.FullName
+		isActive := owner.IsActive
+		catalog.Owners = append(catalog.Owners, &models.CatalogOwner{FullName: owner.FullName, IsActive: &isActive})
+	}
+
+	for _, bot := range state.Bots {
+		isActive := bot.IsActive
+		entry := &models.CatalogBot{
+			Code:        bot.Code,
+			Name:        bot.Name,
+			BotType:     bot.BotType,
+			Language:    bot.Language,
+			Description: bot.Description,
+			Tags:        bot.Tags,
+			IsActive:    &isActive,
+		}
+		if bot.OwnerID != nil {
+			if name, ok := ownerNames[*bot.OwnerID]; ok {
+				entry.Owner = &name
+			}
+		}
+		if bot.TeamID != nil {
+			if name, ok := teamNames[*bot.TeamID]; ok {
+				entry.Team = &name
+			}
+		}
+		if policy, ok := state.Policies[bot.ID]; ok && !emptyPolicy(policy.MinLevel, policy.SampleRates) {
+			entry.LogPolicy = &models.CatalogLogPolicy{MinLevel: policy.MinLevel, SampleRates: policy.SampleRates}
+		}
+		catalog.Bots = append(catalog.Bots, entry)
+	}
+
+	return catalog, nil
+}
+
+// Import приводит владельцев и ботов к каталогу: владельцы сопоставляются по full_name, боты — по code,
+// команды, типы и языки ботов должны уже существовать.
+// При prune боты не из каталога переносятся в архив. При dryRun изменения только возвращаются;
+// иначе они применяются одной транзакцией от имени changedBy (ID админского токена).
+func (s *CatalogService) Import(catalog *models.Catalog, dryRun, prune bool, changedBy string) (*models.CatalogImportResult, error) {
+	if err := validate(catalog); err != nil {
+		return nil, err
+	}
+	if prune && len(catalog.Bots) == 0 {
+		return nil, fmt.Errorf("%w: в каталоге нет ботов, prune перенёс бы в архив всех ботов", customerrors.ErrInvalidInput)
+	}
+
+	var by *string
+	if changedBy != "" {
+		by = &changedBy
+	}
+
+	// План строится по состоянию, прочитанному в транзакции импорта: каталог не может измениться между сравнением
+	// и применением
+	var plan *models.CatalogPlan
+	var result *models.CatalogImportResult
+	var planErr error
+	err := s.repo.ImportCatalog(func(state *models.CatalogState) (*models.CatalogPlan, error) {
+		plan, result, planErr = buildPlan(state, catalog, prune)
+		if planErr != nil {
+			return nil, planErr
+		}
+		result.DryRun = dryRun
+		if dryRun || len(result.Changes) == 0 {
+			return nil, nil
+		}
+		return plan, nil
+	}, by)
+	if planErr != nil {
+		return nil, planErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка импорта каталога: %w", err)
+	}
+	if dryRun || len(result.Changes) == 0 {
+		return result, nil
+	}
+
+	for _, op := range plan.Bots {
+		if op.PolicyChanged {
+			if err := s.policies.ReloadPolicies(); err != nil {
+				log.Printf("Ошибка загрузки политик хранения логов: %v", err)
+			}
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// validate проверяет каталог целиком: уникальность ключей, ссылки на владельцев и политики хранения
+func validate(catalog *models.Catalog) error {
+	owners := make(map[string]bool, len(catalog.Owners))
+	for _, owner := range catalog.Owners {
+		if owners[owner.FullName] {
+			return fmt.Errorf("%w: владелец %q указан несколько раз", customerrors.ErrInvalidInput, owner.FullName)
+		}
+		owners[owner.FullName] = true
+	}
+
+	codes := make(map[string]bool, len(catalog.Bots))
+	for _, bot := range catalog.Bots {
+		if codes[bot.Code] {
+			return fmt.Errorf("%w: бот %q указан несколько раз", customerrors.ErrInvalidInput, bot.Code)
+		}
+		codes[bot.Code] = true
+
+		if bot.Owner != nil && !owners[*bot.Owner] {
+			return fmt.Errorf("%w: владелец %q бота %q не описан в разделе owners", customerrors.ErrInvalidInput, *bot.Owner, bot.Code)
+		}
+		if bot.LogPolicy != nil {
+			for level, rate := range bot.LogPolicy.SampleRates {
+				if loglevel.Rank(level) < 0 {
+					return fmt.Errorf("%w: неизвестный уровень в sample_rates бота %q: %s", customerrors.ErrInvalidInput, bot.Code, level)
+				}
+				if rate < 0 || rate > 1 {
+					return fmt.Errorf("%w: доля для уровня %s бота %q должна быть от 0 до 1", customerrors.ErrInvalidInput, level, bot.Code)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// buildPlan сравнивает каталог с текущим состоянием и возвращает план изменений и их описание
+func buildPlan(state *models.CatalogState, catalog *models.Catalog, prune bool) (*models.CatalogPlan, *models.CatalogImportResult, error) {
+	plan := &models.CatalogPlan{OwnerIDs: make(map[string]string)}
+	result := &models.CatalogImportResult{Changes: []*models.CatalogChange{}}
+
+	existingOwners := make(map[string]*models.Owner, len(state.Owners))
+	duplicateOwners := make(map[string]bool)
+	for _, owner := range state.Owners {
+		if _, ok := existingOwners[owner.FullName]; ok {
+			duplicateOwners[owner.FullName] = true
+		}
+		existingOwners[owner.FullName] = owner
+	}
+
+	for _, owner := range catalog.Owners {
+		if duplicateOwners[owner.FullName] {
+			return nil, nil, fmt.Errorf("%w: несколько владельцев с именем %q, переименуйте или перенесите лишних в архив", customerrors.ErrInvalidInput, owner.FullName)
+		}
+		isActive := owner.IsActive == nil || *owner.IsActive
+
+		existing, ok := existingOwners[owner.FullName]
+		if !ok {
+			plan.CreateOwners = append(plan.CreateOwners, owner)
+			result.Changes = append(result.Changes, &models.CatalogChange{Kind: models.CatalogKindOwner, Action: models.CatalogActionCreate, Key: owner.FullName})
+			continue
+		}
+		plan.OwnerIDs[owner.FullName] = existing.ID
+		if existing.IsActive != isActive {
+			plan.UpdateOwners = append(plan.UpdateOwners, &models.Owner{ID: existing.ID, FullName: existing.FullName, IsActive: isActive})
+			result.Changes = append(result.Changes, &models.CatalogChange{
+				Kind: models.CatalogKindOwner, Action: models.CatalogActionUpdate, Key: owner.FullName, Fields: []string{"is_active"},
+			})
+		}
+	}
+
+	teamIDs := make(map[string]string, len(state.Teams))
+	for _, team := range state.Teams {
+		teamIDs[team.Name] = team.ID
+	}
+
+	botTypes := make(map[string]bool, len(state.BotTypes))
+	for _, code := range state.BotTypes {
+		botTypes[code] = true
+	}
+	botLanguages := make(map[string]bool, len(state.BotLanguages))
+	for _, code := range state.BotLanguages {
+		botLanguages[code] = true
+	}
+
+	existingBots := make(map[string][]*models.Bot, len(state.Bots))
+	for _, bot := range state.Bots {
+		existingBots[bot.Code] = append(existingBots[bot.Code], bot)
+	}
+
+	for _, entry := range catalog.Bots {
+		matches := existingBots[entry.Code]
+		if len(matches) > 1 {
+			return nil, nil, fmt.Errorf("%w: несколько ботов с кодом %q, перенесите лишних в архив", customerrors.ErrInvalidInput, entry.Code)
+		}
+
+		if !botTypes[entry.BotType] {
+			return nil, nil, fmt.Errorf("%w: тип %q бота %q не найден в справочнике", customerrors.ErrInvalidInput, entry.BotType, entry.Code)
+		}
+		if !botLanguages[entry.Language] {
+			return nil, nil, fmt.Errorf("%w: язык %q бота %q не найден в справочнике", customerrors.ErrInvalidInput, entry.Language, entry.Code)
+		}
+
+		var wantTeam *string
+		if entry.Team != nil {
+			id, ok := teamIDs[*entry.Team]
+			if !ok {
+				return nil, nil, fmt.Errorf("%w: команда %q бота %q не найдена", customerrors.ErrInvalidInput, *entry.Team, entry.Code)
+			}
+			wantTeam = &id
+		}
+
+		if len(matches) == 0 {
+			plan.Bots = append(plan.Bots, &models.CatalogBotOp{Bot: entry, TeamID: wantTeam, PolicyChanged: entry.LogPolicy != nil})
+			result.Changes = append(result.Changes, &models.CatalogChange{Kind: models.CatalogKindBot, Action: models.CatalogActionCreate, Key: entry.Code})
+			continue
+		}
+
+		bot := matches[0]
+		op := &models.CatalogBotOp{BotID: bot.ID, Bot: entry, TeamID: wantTeam}
+		fields := botChanges(bot, entry)
+		if !sameString(bot.TeamID, wantTeam) {
+			fields = append(fields, "team")
+		}
+
+		// wantOwner пуст и для нового владельца — он будет создан, значит владелец бота меняется
+		var wantOwner *string
+		if entry.Owner != nil {
+			if id, ok := plan.OwnerIDs[*entry.Owner]; ok {
+				wantOwner = &id
+			}
+		}
+		if (entry.Owner != nil && wantOwner == nil) || !sameString(bot.OwnerID, wantOwner) {
+			op.OwnerChanged = true
+			op.FromOwnerID = bot.OwnerID
+			fields = append(fields, "owner")
+		}
+
+		var minLevel *string
+		var rates map[string]float64
+		if policy, ok := state.Policies[bot.ID]; ok {
+			minLevel, rates = policy.MinLevel, policy.SampleRates
+		}
+		var wantLevel *string
+		var wantRates map[string]float64
+		if entry.LogPolicy != nil {
+			wantLevel, wantRates = entry.LogPolicy.MinLevel, entry.LogPolicy.SampleRates
+		}
+		if !sameString(minLevel, wantLevel) || !sameRates(rates, wantRates) {
+			op.PolicyChanged = true
+			fields = append(fields, "log_policy")
+		}
+
+		if len(fields) == 0 {
+			result.Unchanged++
+			continue
+		}
+		plan.Bots = append(plan.Bots, op)
+		result.Changes = append(result.Changes, &models.CatalogChange{
+			Kind: models.CatalogKindBot, Action: models.CatalogActionUpdate, Key: entry.Code, Fields: fields,
+		})
+	}
+
+	if prune {
+		inCatalog := make(map[string]bool, len(catalog.Bots))
+		for _, entry := range catalog.Bots {
+			inCatalog[entry.Code] = true
+		}
+		for _, bot := range state.Bots {
+			if inCatalog[bot.Code] {
+				continue
+			}
+			plan.ArchiveBots = append(plan.ArchiveBots, bot.ID)
+			result.Changes = append(result.Changes, &models.CatalogChange{Kind: models.CatalogKindBot, Action: models.CatalogActionArchive, Key: bot.Code})
+		}
+	}
+
+	return plan, result, nil
+}
+
+// botChanges возвращает поля бота (кроме владельца, команды и политики), отличающиеся от каталога
+func botChanges(bot *models.Bot, entry *models.CatalogBot) []string {
+	var fields []string
+	if bot.Name != entry.Name {
+		fields = append(fields, "name")
+	}
+	if bot.BotType != entry.BotType {
+		fields = append(fields, "bot_type")
+	}
+	if bot.Language != entry.Language {
+		fields = append(fields, "language")
+	}
+	if !sameString(bot.Description, entry.Description) {
+		fields = append(fields, "description")
+	}
+	if !sameTags(bot.Tags, entry.Tags) {
+		fields = append(fields, "tags")
+	}
+	if bot.IsActive != (entry.IsActive == nil || *entry.IsActive) {
+		fields = append(fields, "is_active")
+	}
+	return fields
+}
+
+func emptyPolicy(minLevel *string, rates map[string]float64) bool {
+	return minLevel == nil && len(rates) == 0
+}
+
+func sameString(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// sameTags сравнивает теги без учёта порядка
+func sameTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameRates(a, b map[string]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for level, rate := range a {
+		if other, ok := b[level]; !ok || other != rate {
+			return false
+		}
+	}
+	return true
+}
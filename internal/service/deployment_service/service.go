@@ -0,0 +1,247 @@
+package deploymentservice
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"logging_api/configs"
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/pagination"
+
+	"github.com/getsentry/sentry-go"
+)
+
+type DeploymentRepoInterface interface {
+	CreateDeployment(botID, version string, gitSHA, host, changelog *string, deployedAt *time.Time) (*models.Deployment, error)
+	ListDeployments(filter *models.DeploymentFilter) ([]*models.Deployment, error)
+	ListVersionHistory(since time.Time) ([]*models.Deployment, error)
+	GetVersionStats(botID string, limit int) ([]*models.VersionStats, error)
+}
+
+type BotRepoInterface interface {
+	GetBotByID(botID string) (*models.Bot, error)
+}
+
+// DefaultCompareLimit и MaxCompareLimit — число сравниваемых версий по умолчанию и наибольшее
+const (
+	DefaultCompareLimit = 5
+	MaxCompareLimit     = 50
+)
+
+type deployedVersion struct {
+	version    string
+	deployedAt time.Time
+}
+
+// DeploymentService хранит выкладки ботов и держит в памяти историю их версий за период, в который может попасть
+// время новых логов и запусков, чтобы проставлять им версию, выложенную на момент события
+type DeploymentService struct {
+	deploymentRepo DeploymentRepoInterface
+	botRepo        BotRepoInterface
+	config         configs.DeploymentsConfig
+	// historyWindow — насколько в прошлое хранится история версий (допустимое отклонение времени лога)
+	historyWindow time.Duration
+
+	versionsMu sync.RWMutex
+	// versions — выкладки каждого бота по возрастанию deployed_at
+	versions map[string][]deployedVersion
+}
+
+func NewDeploymentService(deploymentRepo DeploymentRepoInterface, botRepo BotRepoInterface, config configs.DeploymentsConfig, ingest configs.IngestConfig) *DeploymentService {
+	return &DeploymentService{
+		deploymentRepo: deploymentRepo,
+		botRepo:        botRepo,
+		config:         config,
+		historyWindow:  time.Duration(ingest.MaxPastSkewSec) * time.Second,
+		versions:       make(map[string][]deployedVersion),
+	}
+}
+
+// Start загружает историю версий ботов и запускает их периодическое перечитывание
+func (s *DeploymentService) Start(ctx context.Context) error {
+	if err := s.ReloadVersions(); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(s.config.RefreshIntervalSec) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.ReloadVersions(); err != nil {
+					log.Printf("Ошибка загрузки версий ботов: %v", err)
+					sentry.CaptureException(err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// ReloadVersions перечитывает из БД историю версий ботов: выкладки за historyWindow и действовавшую
+// на его начало выкладку каждого бота
+func (s *DeploymentService) ReloadVersions() error {
+	deployments, err := s.deploymentRepo.ListVersionHistory(time.Now().Add(-s.historyWindow))
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки версий ботов: %w", err)
+	}
+
+	versions := make(map[string][]deployedVersion)
+	for _, deployment := range deployments {
+		versions[deployment.BotID] = append(versions[deployment.BotID], deployedVersion{version: deployment.Version, deployedAt: deployment.DeployedAt})
+	}
+
+	s.versionsMu.Lock()
+	s.versions = versions
+	s.versionsMu.Unlock()
+
+	return nil
+}
+
+// VersionAt возвращает версию бота, выложенную на момент at (последнюю выкладку не позже at); нулевой at — текущее время.
+// nil — до at выкладок не было или at старше хранимой истории.
+func (s *DeploymentService) VersionAt(botID string, at time.Time) *string {
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	s.versionsMu.RLock()
+	defer s.versionsMu.RUnlock()
+
+	history := s.versions[botID]
+	i := sort.Search(len(history), func(i int) bool { return history[i].deployedAt.After(at) })
+	if i == 0 {
+		return nil
+	}
+	version := history[i-1].version
+	return &version
+}
+
+// CreateDeployment сохраняет выкладку версии бота. deployedAt — время выкладки (nil — время приёма);
+// выкладка задним числом встаёт в историю версий на своё время и не меняет версию более поздних событий.
+func (s *DeploymentService) CreateDeployment(botID, version string, gitSHA, host, changelog *string, deployedAt *time.Time) (*models.Deployment, error) {
+	if deployedAt != nil && deployedAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: время выкладки не может быть в будущем", customerrors.ErrInvalidInput)
+	}
+
+	deployment, err := s.deploymentRepo.CreateDeployment(botID, version, gitSHA, host, changelog, deployedAt)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сохранения выкладки: %w", err)
+	}
+
+	s.versionsMu.Lock()
+	history := s.versions[botID]
+	i := sort.Search(len(history), func(i int) bool { return history[i].deployedAt.After(deployment.DeployedAt) })
+	history = append(history, deployedVersion{})
+	copy(history[i+1:], history[i:])
+	history[i] = deployedVersion{version: deployment.Version, deployedAt: deployment.DeployedAt}
+	s.versions[botID] = history
+	s.versionsMu.Unlock()
+
+	return deployment, nil
+}
+
+// ListDeployments возвращает страницу выкладок по фильтру; cursor — курсор из предыдущей страницы
+func (s *DeploymentService) ListDeployments(filter *models.DeploymentFilter, cursor string) (*models.DeploymentPage, error) {
+	filter.Limit = pagination.Limit(filter.Limit)
+
+	c, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customerrors.ErrInvalidInput, err)
+	}
+	if c != nil {
+		if c.Time == nil || c.ID == "" {
+			return nil, fmt.Errorf("%w: %v", customerrors.ErrInvalidInput, pagination.ErrInvalidCursor)
+		}
+		filter.AfterDeployedAt = c.Time
+		filter.AfterID = &c.ID
+	}
+
+	deployments, err := s.deploymentRepo.ListDeployments(filter)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения выкладок: %w", err)
+	}
+
+	page := &models.DeploymentPage{Items: deployments}
+	if page.Items == nil {
+		page.Items = []*models.Deployment{}
+	}
+
+	if len(deployments) > filter.Limit {
+		page.Items = deployments[:filter.Limit]
+		last := page.Items[len(page.Items)-1]
+		next := (&pagination.Cursor{Time: &last.DeployedAt, ID: last.ID}).Encode()
+		page.NextCursor = &next
+	}
+
+	return page, nil
+}
+
+// ListDeploymentsInRange возвращает все выкладки по фильтру без постраничной выдачи
+// (например, для наложения на ленту запусков)
+func (s *DeploymentService) ListDeploymentsInRange(filter *models.DeploymentFilter) ([]*models.Deployment, error) {
+	filter.Limit = 0
+
+	deployments, err := s.deploymentRepo.ListDeployments(filter)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения выкладок: %w", err)
+	}
+	if deployments == nil {
+		deployments = []*models.Deployment{}
+	}
+	return deployments, nil
+}
+
+// CompareVersions сравнивает limit последних версий бота по доле успешных запусков и доле логов Error и Critical.
+// teamID — команда, которой должен принадлежать бот (nil — без проверки); бот другой команды считается не найденным.
+func (s *DeploymentService) CompareVersions(botID string, teamID *string, limit int) (*models.VersionComparison, error) {
+	if limit <= 0 {
+		limit = DefaultCompareLimit
+	}
+	if limit > MaxCompareLimit {
+		limit = MaxCompareLimit
+	}
+
+	bot, err := s.botRepo.GetBotByID(botID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: бот не найден", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка получения бота: %w", err)
+	}
+	if teamID != nil && (bot.TeamID == nil || *bot.TeamID != *teamID) {
+		return nil, fmt.Errorf("%w: бот не найден", customerrors.ErrNotFound)
+	}
+
+	stats, err := s.deploymentRepo.GetVersionStats(botID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка расчёта показателей версий: %w", err)
+	}
+	if stats == nil {
+		stats = []*models.VersionStats{}
+	}
+
+	for _, item := range stats {
+		if item.Runs > 0 {
+			rate := float64(item.RunsSuccess) / float64(item.Runs)
+			item.SuccessRate = &rate
+		}
+		if item.Logs > 0 {
+			rate := float64(item.ErrorLogs) / float64(item.Logs)
+			item.ErrorRate = &rate
+		}
+	}
+
+	return &models.VersionComparison{BotID: botID, Versions: stats}, nil
+}
@@ -1,30 +1,234 @@
 package effrunservice
 
 import (
+	"errors"
 	"fmt"
+	"logging_api/configs"
 	"logging_api/internal/models"
+	streamservice "logging_api/internal/service/stream_service"
+	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/pagination"
+	"strings"
 	"time"
 )
 
 type EffRunRepoInterface interface {
-	CreateEffRun(botID string, periodFrom, periodTo *time.Time, status string, host *string, extra models.JSONB) (*models.EffRun, error)
+	CreateEffRun(botID string, periodFrom, periodTo *time.Time, status string, host *string, extra models.JSONB, version *string) (*models.EffRun, error)
+	CreateEffRuns(effRuns []*models.EffRun, keys []*models.IdempotencyKey) ([]bool, error)
+	ListEffRuns(filter *models.EffRunFilter) ([]*models.EffRun, error)
+	StreamEffRuns(filter *models.EffRunFilter, fn func(*models.EffRun) error) error
+}
+
+type EventPublisher interface {
+	Publish(event streamservice.Event)
+}
+
+// DeploymentSource — выкладки ботов: версия бота на момент at (нулевой at — текущее время; nil — неизвестна)
+// и выкладки за период
+type DeploymentSource interface {
+	VersionAt(botID string, at time.Time) *string
+	ListDeploymentsInRange(filter *models.DeploymentFilter) ([]*models.Deployment, error)
+}
+
+// HostRecorder ведёт реестр хостов по сохранённым запускам
+type HostRecorder interface {
+	RecordRun(botID, host string, seenAt time.Time)
 }
 
 type EffRunService struct {
-	effRunRepo EffRunRepoInterface
+	effRunRepo  EffRunRepoInterface
+	publisher   EventPublisher
+	deployments DeploymentSource
+	hosts       HostRecorder
+	keyTTL      time.Duration
 }
 
-func NewEffRunService(effRunRepo EffRunRepoInterface) *EffRunService {
+// NewEffRunService создаёт сервис запусков. deployments может быть nil — тогда запуски сохраняются без версии,
+// а выкладки в ленту не добавляются; hosts может быть nil — тогда реестр хостов не ведётся.
+func NewEffRunService(effRunRepo EffRunRepoInterface, publisher EventPublisher, deployments DeploymentSource, hosts HostRecorder, idempotency configs.IdempotencyConfig) *EffRunService {
 	return &EffRunService{
-		effRunRepo: effRunRepo,
+		effRunRepo:  effRunRepo,
+		publisher:   publisher,
+		deployments: deployments,
+		hosts:       hosts,
+		keyTTL:      time.Duration(idempotency.TTLHours) * time.Hour,
 	}
 }
 
-func (s *EffRunService) CreateEffRun(botID string, periodFrom, periodTo *time.Time, status string, host *string, extra models.JSONB) (*models.EffRun, error) {
-	effRun, err := s.effRunRepo.CreateEffRun(botID, periodFrom, periodTo, status, host, extra)
+// CreateEffRun сохраняет запуск. key — ключ идемпотентности (nil — без ключа); если запуск с этим ключом
+// уже создан, возвращается он и признак created = false. Запуску проставляется версия бота на момент окончания запуска.
+// Хост записывается без пробелов по краям; пустой хост не сохраняется.
+func (s *EffRunService) CreateEffRun(botID string, periodFrom, periodTo *time.Time, status string, host *string, extra models.JSONB, key *models.IdempotencyKey) (*models.EffRun, bool, error) {
+	host = normalizeHost(host)
+
+	if key == nil {
+		effRun, err := s.effRunRepo.CreateEffRun(botID, periodFrom, periodTo, status, host, extra, s.versionAt(botID, periodFrom, periodTo))
+		if err != nil {
+			return nil, false, fmt.Errorf("ошибка создания записи о запуске: %w", err)
+		}
+
+		s.afterCreate(effRun)
+
+		return effRun, true, nil
+	}
+
+	effRun := &models.EffRun{BotID: botID, PeriodFrom: periodFrom, PeriodTo: periodTo, Status: status, Host: host, Extra: extra}
+	created, err := s.CreateEffRunBatch([]*models.EffRun{effRun}, []*models.IdempotencyKey{key})
 	if err != nil {
-		return nil, fmt.Errorf("ошибка создания записи о запуске: %w", err)
+		return nil, false, err
 	}
-	return effRun, nil
+
+	return effRun, created[0], nil
 }
 
+// CreateEffRunBatch сохраняет пачку запусков. keys — ключи идемпотентности по записям (nil-элемент — без ключа);
+// записи с уже использованным ключом заменяются ранее созданными запусками.
+// Записям без явной версии проставляется версия бота на момент окончания запуска.
+// Возвращает признак создания по каждой записи.
+func (s *EffRunService) CreateEffRunBatch(effRuns []*models.EffRun, keys []*models.IdempotencyKey) ([]bool, error) {
+	if len(effRuns) == 0 {
+		return []bool{}, nil
+	}
+
+	for _, effRun := range effRuns {
+		effRun.Host = normalizeHost(effRun.Host)
+		if effRun.Version == nil {
+			effRun.Version = s.versionAt(effRun.BotID, effRun.PeriodFrom, effRun.PeriodTo)
+		}
+	}
+
+	notBefore := time.Now().Add(-s.keyTTL)
+	for _, key := range keys {
+		if key != nil {
+			key.NotBefore = notBefore
+		}
+	}
+
+	created, err := s.effRunRepo.CreateEffRuns(effRuns, keys)
+	if err != nil {
+		if errors.Is(err, models.ErrIdempotencyKeyReused) {
+			return nil, fmt.Errorf("%w: ключ идемпотентности уже использован с другим телом запроса", customerrors.ErrUnprocessable)
+		}
+		return nil, fmt.Errorf("ошибка создания записей о запусках: %w", err)
+	}
+
+	for i, effRun := range effRuns {
+		if created[i] {
+			s.afterCreate(effRun)
+		}
+	}
+
+	return created, nil
+}
+
+// ListEffRuns возвращает страницу запусков по фильтру; cursor — курсор из предыдущей страницы.
+// С includeDeployments в страницу добавляются выкладки тех же ботов (по bot_id и team_id фильтра)
+// за промежуток времени, который покрывает страница, так что выкладки всех страниц не повторяются и не теряются.
+func (s *EffRunService) ListEffRuns(filter *models.EffRunFilter, cursor string, includeDeployments bool) (*models.EffRunPage, error) {
+	filter.Limit = pagination.Limit(filter.Limit)
+
+	c, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customerrors.ErrInvalidInput, err)
+	}
+	if c != nil {
+		if c.Time == nil || c.ID == "" {
+			return nil, fmt.Errorf("%w: %v", customerrors.ErrInvalidInput, pagination.ErrInvalidCursor)
+		}
+		filter.AfterCreatedAt = c.Time
+		filter.AfterID = &c.ID
+	}
+
+	effRuns, err := s.effRunRepo.ListEffRuns(filter)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения запусков: %w", err)
+	}
+
+	page := &models.EffRunPage{Items: effRuns}
+	if page.Items == nil {
+		page.Items = []*models.EffRun{}
+	}
+
+	if len(effRuns) > filter.Limit {
+		page.Items = effRuns[:filter.Limit]
+		last := page.Items[len(page.Items)-1]
+		next := (&pagination.Cursor{Time: &last.CreatedAt, ID: last.ID}).Encode()
+		page.NextCursor = &next
+	}
+
+	if includeDeployments && s.deployments != nil {
+		// Страница покрывает время от её последнего запуска (или начала периода, если страница последняя)
+		// до последнего запуска предыдущей страницы (или конца периода, если страница первая)
+		deploymentFilter := &models.DeploymentFilter{BotID: filter.BotID, TeamID: filter.TeamID, From: filter.From, To: filter.To}
+		if c != nil {
+			deploymentFilter.To = c.Time
+		}
+		if page.NextCursor != nil {
+			deploymentFilter.From = &page.Items[len(page.Items)-1].CreatedAt
+		}
+
+		deployments, err := s.deployments.ListDeploymentsInRange(deploymentFilter)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка получения выкладок: %w", err)
+		}
+		page.Deployments = deployments
+	}
+
+	return page, nil
+}
+
+// StreamEffRuns передаёт в fn все запуски по фильтру, не загружая их в память
+func (s *EffRunService) StreamEffRuns(filter *models.EffRunFilter, fn func(*models.EffRun) error) error {
+	if err := s.effRunRepo.StreamEffRuns(filter, fn); err != nil {
+		return fmt.Errorf("ошибка выгрузки запусков: %w", err)
+	}
+	return nil
+}
+
+func normalizeHost(host *string) *string {
+	if host == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*host)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// afterCreate публикует сохранённый запуск и учитывает его хост в реестре
+func (s *EffRunService) afterCreate(effRun *models.EffRun) {
+	s.publish(effRun)
+
+	if s.hosts != nil && effRun.Host != nil {
+		s.hosts.RecordRun(effRun.BotID, *effRun.Host, effRun.CreatedAt)
+	}
+}
+
+// versionAt возвращает версию бота на момент окончания запуска (period_to, иначе period_from, иначе время приёма)
+func (s *EffRunService) versionAt(botID string, periodFrom, periodTo *time.Time) *string {
+	if s.deployments == nil {
+		return nil
+	}
+
+	var at time.Time
+	if periodTo != nil {
+		at = *periodTo
+	} else if periodFrom != nil {
+		at = *periodFrom
+	}
+	return s.deployments.VersionAt(botID, at)
+}
+
+func (s *EffRunService) publish(effRun *models.EffRun) {
+	if s.publisher == nil {
+		return
+	}
+
+	s.publisher.Publish(streamservice.Event{
+		Channel: streamservice.ChannelEffRuns,
+		BotID:   effRun.BotID,
+		Status:  effRun.Status,
+		Payload: effRun,
+	})
+}
@@ -0,0 +1,300 @@
+package healthservice
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"logging_api/configs"
+	"logging_api/internal/models"
+
+	"github.com/getsentry/sentry-go"
+)
+
+type HealthRepoInterface interface {
+	GetBotSignals(since time.Time, recentRuns int) ([]*models.BotSignals, error)
+	ListHealthStates() (map[string]string, error)
+	CreateHealthStates(states map[string]string) error
+	UpdateHealthState(botID, from, to string) (changed bool, err error)
+}
+
+// HealthNotifier получает переходы ботов в другое состояние после каждого расчёта
+type HealthNotifier interface {
+	NotifyHealthChanges(changes []*models.HealthChange)
+}
+
+// stateOrder — порядок состояний в сводке: сначала требующие внимания
+var stateOrder = []string{
+	models.HealthFailing,
+	models.HealthDegraded,
+	models.HealthSilent,
+	models.HealthUnknown,
+	models.HealthHealthy,
+}
+
+// HealthService периодически рассчитывает состояние ботов по последним логам и запускам
+// и отдаёт последний расчёт
+type HealthService struct {
+	healthRepo HealthRepoInterface
+	notifier   HealthNotifier
+	config     configs.HealthConfig
+
+	mu        sync.RWMutex
+	items     []*models.BotHealthItem
+	byBot     map[string]*models.BotHealth
+	checkedAt *time.Time
+}
+
+func NewHealthService(healthRepo HealthRepoInterface, notifier HealthNotifier, config configs.HealthConfig) *HealthService {
+	return &HealthService{
+		healthRepo: healthRepo,
+		notifier:   notifier,
+		config:     config,
+		byBot:      make(map[string]*models.BotHealth),
+	}
+}
+
+// Start рассчитывает состояние сразу и затем раз в RefreshIntervalSec
+func (s *HealthService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Duration(s.config.RefreshIntervalSec) * time.Second)
+		defer ticker.Stop()
+
+		s.refresh()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.refresh()
+			}
+		}
+	}()
+}
+
+func (s *HealthService) refresh() {
+	if err := s.Refresh(); err != nil {
+		log.Printf("Ошибка расчёта состояния ботов: %v", err)
+		sentry.CaptureException(err)
+	}
+}
+
+// Refresh пересчитывает состояние всех ботов и сообщает о переходах ботов в другое состояние.
+// Переход считается от состояния, сохранённого в БД, и сообщается, только если этот экземпляр сервиса первым
+// сохранил его: при нескольких экземплярах уведомление отправляется один раз.
+// Боты без сохранённого состояния (первый расчёт, новые боты) переходами не считаются.
+func (s *HealthService) Refresh() error {
+	now := time.Now()
+	window := time.Duration(s.config.WindowMinutes) * time.Minute
+
+	signals, err := s.healthRepo.GetBotSignals(now.Add(-window), s.config.RecentRuns)
+	if err != nil {
+		return fmt.Errorf("ошибка получения последних логов и запусков: %w", err)
+	}
+
+	items := make([]*models.BotHealthItem, 0, len(signals))
+	byBot := make(map[string]*models.BotHealth, len(signals))
+	for _, signal := range signals {
+		health := evaluate(signal, s.config, now)
+		byBot[signal.BotID] = health
+		items = append(items, &models.BotHealthItem{
+			BotID:    signal.BotID,
+			Code:     signal.Code,
+			Name:     signal.Name,
+			IsActive: signal.IsActive,
+			TeamID:   signal.TeamID,
+			Health:   health,
+		})
+	}
+
+	slices.SortStableFunc(items, func(a, b *models.BotHealthItem) int {
+		if d := slices.Index(stateOrder, a.Health.State) - slices.Index(stateOrder, b.Health.State); d != 0 {
+			return d
+		}
+		if a.Code < b.Code {
+			return -1
+		}
+		if a.Code > b.Code {
+			return 1
+		}
+		return 0
+	})
+
+	s.mu.Lock()
+	s.items = items
+	s.byBot = byBot
+	s.checkedAt = &now
+	s.mu.Unlock()
+
+	if s.notifier == nil {
+		return nil
+	}
+
+	changes, err := s.saveStates(items)
+	if len(changes) > 0 {
+		s.notifier.NotifyHealthChanges(changes)
+	}
+	return err
+}
+
+// saveStates сохраняет рассчитанные состояния ботов в БД и возвращает переходы, которые сохранил этот экземпляр сервиса
+func (s *HealthService) saveStates(items []*models.BotHealthItem) ([]*models.HealthChange, error) {
+	saved, err := s.healthRepo.ListHealthStates()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения сохранённых состояний ботов: %w", err)
+	}
+
+	created := make(map[string]string)
+	var changes []*models.HealthChange
+	for _, item := range items {
+		before, ok := saved[item.BotID]
+		if !ok {
+			created[item.BotID] = item.Health.State
+			continue
+		}
+		if before == item.Health.State {
+			continue
+		}
+
+		changed, err := s.healthRepo.UpdateHealthState(item.BotID, before, item.Health.State)
+		if err != nil {
+			return changes, fmt.Errorf("ошибка сохранения состояния бота: %w", err)
+		}
+		if !changed {
+			continue
+		}
+		changes = append(changes, &models.HealthChange{
+			BotID:  item.BotID,
+			Code:   item.Code,
+			Name:   item.Name,
+			TeamID: item.TeamID,
+			From:   before,
+			To:     item.Health.State,
+			Health: item.Health,
+		})
+	}
+
+	if err := s.healthRepo.CreateHealthStates(created); err != nil {
+		return changes, fmt.Errorf("ошибка сохранения состояния ботов: %w", err)
+	}
+
+	return changes, nil
+}
+
+// Health возвращает состояние бота из последнего расчёта. Бот, созданный после расчёта, — unknown;
+// до первого расчёта — nil.
+func (s *HealthService) Health(botID string) *models.BotHealth {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.checkedAt == nil {
+		return nil
+	}
+	if health, ok := s.byBot[botID]; ok {
+		return health
+	}
+	return &models.BotHealth{State: models.HealthUnknown, CheckedAt: *s.checkedAt}
+}
+
+// Fleet возвращает сводку состояния ботов: сначала требующие внимания.
+// state — только боты в этом состоянии; teamID — только боты команды; неактивные боты включаются по includeInactive.
+func (s *HealthService) Fleet(state, teamID *string, includeInactive bool) *models.FleetHealth {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fleet := &models.FleetHealth{
+		Counts:    make(map[string]int, len(stateOrder)),
+		Bots:      []*models.BotHealthItem{},
+		CheckedAt: s.checkedAt,
+	}
+	for _, value := range stateOrder {
+		fleet.Counts[value] = 0
+	}
+
+	for _, item := range s.items {
+		if !item.IsActive && !includeInactive {
+			continue
+		}
+		if teamID != nil && (item.TeamID == nil || *item.TeamID != *teamID) {
+			continue
+		}
+		fleet.Counts[item.Health.State]++
+		if state != nil && item.Health.State != *state {
+			continue
+		}
+		fleet.Bots = append(fleet.Bots, item)
+	}
+
+	return fleet
+}
+
+// evaluate рассчитывает состояние бота по порогам конфигурации
+func evaluate(signal *models.BotSignals, config configs.HealthConfig, now time.Time) *models.BotHealth {
+	health := &models.BotHealth{
+		LastLogAt: signal.LastLogAt,
+		LastRunAt: signal.LastRunAt,
+		ErrorLogs: signal.ErrorLogs,
+		CheckedAt: now,
+	}
+
+	health.LastSignalAt = signal.LastLogAt
+	if signal.LastRunAt != nil && (health.LastSignalAt == nil || signal.LastRunAt.After(*health.LastSignalAt)) {
+		health.LastSignalAt = signal.LastRunAt
+	}
+
+	var problemRuns int
+	for _, status := range signal.RecentRuns {
+		switch status {
+		case "error":
+			health.FailedRuns++
+			problemRuns++
+		case "warning":
+			problemRuns++
+		}
+	}
+	if len(signal.RecentRuns) > 0 {
+		health.LastRunStatus = &signal.RecentRuns[0]
+	}
+
+	if health.LastSignalAt == nil {
+		health.State = models.HealthUnknown
+		health.Reasons = []string{"нет логов и запусков"}
+		return health
+	}
+
+	if now.Sub(*health.LastSignalAt) > time.Duration(config.SilentAfterMinutes)*time.Minute {
+		health.State = models.HealthSilent
+		health.Reasons = []string{fmt.Sprintf("нет логов и запусков дольше %d мин", config.SilentAfterMinutes)}
+		return health
+	}
+
+	errorLogsReason := fmt.Sprintf("%d логов Error/Critical за %d мин", signal.ErrorLogs, config.WindowMinutes)
+
+	if signal.ErrorLogs >= int64(config.FailingErrorLogs) {
+		health.Reasons = append(health.Reasons, errorLogsReason)
+	}
+	if health.FailedRuns >= config.FailingRuns {
+		health.Reasons = append(health.Reasons, fmt.Sprintf("%d из %d последних запусков с ошибкой", health.FailedRuns, len(signal.RecentRuns)))
+	}
+	if len(health.Reasons) > 0 {
+		health.State = models.HealthFailing
+		return health
+	}
+
+	if signal.ErrorLogs >= int64(config.DegradedErrorLogs) {
+		health.Reasons = append(health.Reasons, errorLogsReason)
+	}
+	if problemRuns > 0 {
+		health.Reasons = append(health.Reasons, fmt.Sprintf("%d из %d последних запусков с warning или error", problemRuns, len(signal.RecentRuns)))
+	}
+	if len(health.Reasons) > 0 {
+		health.State = models.HealthDegraded
+		return health
+	}
+
+	health.State = models.HealthHealthy
+	return health
+}
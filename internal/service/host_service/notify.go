@@ -0,0 +1,67 @@
+package hostservice
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"logging_api/internal/models"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// notify сообщает о смене состояния хоста: в лог, в Sentry (для замолчавшего хоста) и на hosts.webhook_url.
+// Отправка на webhook идёт в фоне; ошибки пишутся в лог.
+func (s *HostService) notify(host *models.Host, state string, interval time.Duration) {
+	notification := &models.HostNotification{
+		Host:                host.Name,
+		State:               state,
+		LastSeenAt:          host.LastSeenAt,
+		ExpectedIntervalSec: interval.Seconds(),
+		Bots:                []string{},
+	}
+
+	bots, err := s.hostRepo.ListHostBots(host.Name)
+	if err != nil {
+		log.Printf("Ошибка получения ботов хоста %s: %v", host.Name, err)
+	}
+	for _, bot := range bots {
+		notification.Bots = append(notification.Bots, bot.Code)
+	}
+
+	if state == models.HostSilent {
+		log.Printf("Хост %s не присылает запуски с %s (боты: %s)", host.Name, host.LastSeenAt.Format(time.RFC3339), strings.Join(notification.Bots, ", "))
+		sentry.CaptureMessage(fmt.Sprintf("Хост %s не присылает запуски", host.Name))
+	} else {
+		log.Printf("Хост %s снова присылает запуски", host.Name)
+	}
+
+	if s.config.WebhookURL == "" {
+		return
+	}
+	go func() {
+		if err := s.send(notification); err != nil {
+			log.Printf("Ошибка отправки уведомления о хосте %s: %v", host.Name, err)
+		}
+	}()
+}
+
+func (s *HostService) send(notification *models.HostNotification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("ошибка кодирования уведомления: %w", err)
+	}
+
+	resp, err := s.client.Post(s.config.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ошибка запроса к webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook ответил статусом %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package hostservice
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"logging_api/configs"
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+
+	"github.com/getsentry/sentry-go"
+)
+
+type HostRepoInterface interface {
+	RecordSightings(sightings []models.HostSighting) error
+	GetHost(name string) (*models.Host, error)
+	ListHosts(filter *models.HostFilter) ([]*models.Host, error)
+	ListMonitoredHosts(minRuns int64) ([]*models.Host, error)
+	ListHostBots(name string) ([]*models.HostBot, error)
+	UpdateHost(name string, update models.HostUpdate) error
+	SetSilent(name string, since *time.Time) (changed bool, err error)
+	DeleteHost(name string) error
+	GetHostStats(name string, from, to *time.Time) (*models.HostStats, error)
+}
+
+type sightingKey struct {
+	host  string
+	botID string
+}
+
+// HostService ведёт реестр хостов по запускам ботов и следит за хостами, которые перестали присылать запуски
+type HostService struct {
+	hostRepo HostRepoInterface
+	config   configs.HostsConfig
+	client   *http.Client
+
+	sightingsMu sync.Mutex
+	sightings   map[sightingKey]*models.HostSighting
+}
+
+func NewHostService(hostRepo HostRepoInterface, config configs.HostsConfig) *HostService {
+	return &HostService{
+		hostRepo:  hostRepo,
+		config:    config,
+		client:    &http.Client{Timeout: time.Duration(config.WebhookTimeoutSec) * time.Second},
+		sightings: make(map[sightingKey]*models.HostSighting),
+	}
+}
+
+// Start запускает периодическое сохранение появлений хостов и проверку их молчания
+func (s *HostService) Start(ctx context.Context) {
+	go func() {
+		flush := time.NewTicker(time.Duration(s.config.FlushIntervalSec) * time.Second)
+		defer flush.Stop()
+		check := time.NewTicker(time.Duration(s.config.CheckIntervalSec) * time.Second)
+		defer check.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-flush.C:
+				s.FlushSightings()
+			case <-check.C:
+				if err := s.CheckSilence(); err != nil {
+					log.Printf("Ошибка проверки молчания хостов: %v", err)
+					sentry.CaptureException(err)
+				}
+			}
+		}
+	}()
+}
+
+// RecordRun запоминает запуск бота с хоста; в реестр он попадёт при следующем сохранении.
+// Пустой хост не учитывается.
+func (s *HostService) RecordRun(botID, host string, seenAt time.Time) {
+	if host == "" {
+		return
+	}
+
+	key := sightingKey{host: host, botID: botID}
+
+	s.sightingsMu.Lock()
+	defer s.sightingsMu.Unlock()
+
+	sighting, ok := s.sightings[key]
+	if !ok {
+		s.sightings[key] = &models.HostSighting{Host: host, BotID: botID, FirstSeenAt: seenAt, LastSeenAt: seenAt, Runs: 1}
+		return
+	}
+	if seenAt.Before(sighting.FirstSeenAt) {
+		sighting.FirstSeenAt = seenAt
+	}
+	if seenAt.After(sighting.LastSeenAt) {
+		sighting.LastSeenAt = seenAt
+	}
+	sighting.Runs++
+}
+
+// FlushSightings сохраняет накопленные появления хостов; при ошибке они вернутся в следующую попытку
+func (s *HostService) FlushSightings() {
+	s.sightingsMu.Lock()
+	pending := s.sightings
+	s.sightings = make(map[sightingKey]*models.HostSighting)
+	s.sightingsMu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	sightings := make([]models.HostSighting, 0, len(pending))
+	for _, sighting := range pending {
+		sightings = append(sightings, *sighting)
+	}
+
+	if err := s.hostRepo.RecordSightings(sightings); err != nil {
+		log.Printf("Ошибка сохранения реестра хостов: %v", err)
+		sentry.CaptureException(err)
+
+		s.sightingsMu.Lock()
+		for _, sighting := range sightings {
+			s.merge(sighting)
+		}
+		s.sightingsMu.Unlock()
+	}
+}
+
+// merge возвращает несохранённое появление в очередь; вызывается под sightingsMu
+func (s *HostService) merge(sighting models.HostSighting) {
+	key := sightingKey{host: sighting.Host, botID: sighting.BotID}
+	current, ok := s.sightings[key]
+	if !ok {
+		s.sightings[key] = &sighting
+		return
+	}
+	if sighting.FirstSeenAt.Before(current.FirstSeenAt) {
+		current.FirstSeenAt = sighting.FirstSeenAt
+	}
+	if sighting.LastSeenAt.After(current.LastSeenAt) {
+		current.LastSeenAt = sighting.LastSeenAt
+	}
+	current.Runs += sighting.Runs
+}
+
+// CheckSilence отмечает замолчавшими хосты, от которых нет запусков дольше порога, и снимает отметку с хостов,
+// снова присылающих запуски. О каждой смене состояния отправляется уведомление — только тем экземпляром сервиса,
+// который первым сохранил новое состояние.
+func (s *HostService) CheckSilence() error {
+	hosts, err := s.hostRepo.ListMonitoredHosts(s.config.MinRuns)
+	if err != nil {
+		return fmt.Errorf("ошибка получения хостов: %w", err)
+	}
+
+	now := time.Now()
+	for _, host := range hosts {
+		interval := expectedInterval(host)
+		silent := now.Sub(host.LastSeenAt) > s.silentAfter(interval)
+
+		var state string
+		var since *time.Time
+		switch {
+		case silent && host.SilentSince == nil:
+			state = models.HostSilent
+			since = &now
+		case !silent && host.SilentSince != nil:
+			state = models.HostRecovered
+		default:
+			continue
+		}
+
+		changed, err := s.hostRepo.SetSilent(host.Name, since)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения состояния хоста: %w", err)
+		}
+		if !changed {
+			continue
+		}
+
+		s.notify(host, state, interval)
+	}
+
+	return nil
+}
+
+// expectedInterval — средний интервал между запусками с хоста за всё время наблюдения
+func expectedInterval(host *models.Host) time.Duration {
+	if host.RunsSeen < 2 {
+		return 0
+	}
+	return host.LastSeenAt.Sub(host.FirstSeenAt) / time.Duration(host.RunsSeen-1)
+}
+
+// silentAfter — сколько хост может молчать, не считаясь замолчавшим
+func (s *HostService) silentAfter(interval time.Duration) time.Duration {
+	threshold := time.Duration(float64(interval) * s.config.SilentFactor)
+	if minimum := time.Duration(s.config.SilentMinMinutes) * time.Minute; threshold < minimum {
+		return minimum
+	}
+	return threshold
+}
+
+func (s *HostService) GetHost(name string) (*models.Host, error) {
+	host, err := s.hostRepo.GetHost(name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: хост не найден", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка получения хоста: %w", err)
+	}
+
+	bots, err := s.hostRepo.ListHostBots(name)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения ботов хоста: %w", err)
+	}
+	host.Bots = bots
+	if host.Bots == nil {
+		host.Bots = []*models.HostBot{}
+	}
+
+	return host, nil
+}
+
+func (s *HostService) ListHosts(filter *models.HostFilter) ([]*models.Host, error) {
+	hosts, err := s.hostRepo.ListHosts(filter)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения списка хостов: %w", err)
+	}
+	if hosts == nil {
+		hosts = []*models.Host{}
+	}
+	return hosts, nil
+}
+
+// UpdateHost меняет описание хоста и признак слежения за его молчанием.
+// Если слежение выключено, отметка о молчании снимается.
+func (s *HostService) UpdateHost(name string, update models.HostUpdate) (*models.Host, error) {
+	if err := s.hostRepo.UpdateHost(name, update); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: хост не найден", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка обновления хоста: %w", err)
+	}
+
+	return s.GetHost(name)
+}
+
+// DeleteHost удаляет хост из реестра; при следующем запуске с него хост появится снова
+func (s *HostService) DeleteHost(name string) error {
+	if err := s.hostRepo.DeleteHost(name); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: хост не найден", customerrors.ErrNotFound)
+		}
+		return fmt.Errorf("ошибка удаления хоста: %w", err)
+	}
+	return nil
+}
+
+// GetHostStats возвращает показатели запусков с хоста за период [from, to)
+func (s *HostService) GetHostStats(name string, from, to *time.Time) (*models.HostStats, error) {
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, fmt.Errorf("%w: начало периода должно быть раньше конца", customerrors.ErrInvalidInput)
+	}
+
+	if _, err := s.hostRepo.GetHost(name); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: хост не найден", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка получения хоста: %w", err)
+	}
+
+	stats, err := s.hostRepo.GetHostStats(name, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка расчёта показателей хоста: %w", err)
+	}
+
+	if stats.Runs > 0 {
+		rate := float64(stats.RunsError) / float64(stats.Runs)
+		stats.FailureRate = &rate
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,59 @@
+package idempotencyservice
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"logging_api/configs"
+
+	"github.com/getsentry/sentry-go"
+)
+
+type IdempotencyRepoInterface interface {
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+type IdempotencyService struct {
+	idempotencyRepo IdempotencyRepoInterface
+	config          configs.IdempotencyConfig
+}
+
+func NewIdempotencyService(idempotencyRepo IdempotencyRepoInterface, config configs.IdempotencyConfig) *IdempotencyService {
+	return &IdempotencyService{
+		idempotencyRepo: idempotencyRepo,
+		config:          config,
+	}
+}
+
+// Start запускает фоновое удаление истёкших ключей: сразу и затем раз в CleanupIntervalMin минут
+func (s *IdempotencyService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Duration(s.config.CleanupIntervalMin) * time.Minute)
+		defer ticker.Stop()
+
+		s.Cleanup()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.Cleanup()
+			}
+		}
+	}()
+}
+
+// Cleanup удаляет ключи старше TTLHours
+func (s *IdempotencyService) Cleanup() {
+	before := time.Now().Add(-time.Duration(s.config.TTLHours) * time.Hour)
+	deleted, err := s.idempotencyRepo.DeleteExpired(before)
+	if err != nil {
+		log.Printf("Ошибка удаления истёкших ключей идемпотентности: %v", err)
+		sentry.CaptureException(err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Удалено %d истёкших ключей идемпотентности", deleted)
+	}
+}
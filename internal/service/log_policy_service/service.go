@@ -0,0 +1,313 @@
+package logpolicyservice
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"logging_api/configs"
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/loglevel"
+
+	"github.com/getsentry/sentry-go"
+)
+
+type LogPolicyRepoInterface interface {
+	GetPolicy(botID string) (*models.LogPolicy, error)
+	ListPolicies() ([]*models.LogPolicy, error)
+	SetPolicy(botID string, minLevel *string, sampleRates map[string]float64) (*models.LogPolicy, error)
+	SetOverride(botID string, level *string, until *time.Time) (*models.LogPolicy, error)
+	DeletePolicy(botID string) error
+	AddDropCounts(deltas []models.LogDropCountDelta) error
+	ListDropCounts(botID *string, from, to *time.Time) ([]*models.LogDropCount, error)
+}
+
+type BotRepoInterface interface {
+	GetBotByID(botID string) (*models.Bot, error)
+}
+
+// maxOverrideMinutes — наибольшая длительность временного уровня (сутки)
+const maxOverrideMinutes = 24 * 60
+
+type dropKey struct {
+	botID  string
+	day    string
+	status string
+	reason string
+}
+
+// LogPolicyService применяет к входящим логам политики хранения ботов:
+// минимальный уровень, семплирование по уровням и временное снижение уровня
+type LogPolicyService struct {
+	policyRepo LogPolicyRepoInterface
+	botRepo    BotRepoInterface
+	config     configs.LogPolicyConfig
+
+	policiesMu sync.RWMutex
+	policies   map[string]*models.LogPolicy
+
+	dropsMu sync.Mutex
+	drops   map[dropKey]int64
+}
+
+func NewLogPolicyService(policyRepo LogPolicyRepoInterface, botRepo BotRepoInterface, config configs.LogPolicyConfig) *LogPolicyService {
+	return &LogPolicyService{
+		policyRepo: policyRepo,
+		botRepo:    botRepo,
+		config:     config,
+		policies:   make(map[string]*models.LogPolicy),
+		drops:      make(map[dropKey]int64),
+	}
+}
+
+// Start загружает политики и запускает их периодическое перечитывание и сохранение счётчиков отброшенных логов
+func (s *LogPolicyService) Start(ctx context.Context) error {
+	if err := s.ReloadPolicies(); err != nil {
+		return err
+	}
+
+	go func() {
+		refresh := time.NewTicker(time.Duration(s.config.RefreshIntervalSec) * time.Second)
+		defer refresh.Stop()
+		flush := time.NewTicker(time.Duration(s.config.StatsFlushIntervalSec) * time.Second)
+		defer flush.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-refresh.C:
+				if err := s.ReloadPolicies(); err != nil {
+					log.Printf("Ошибка загрузки политик хранения логов: %v", err)
+					sentry.CaptureException(err)
+				}
+			case <-flush.C:
+				s.FlushCounts()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// ReloadPolicies перечитывает политики ботов из БД
+func (s *LogPolicyService) ReloadPolicies() error {
+	policies, err := s.policyRepo.ListPolicies()
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки политик хранения логов: %w", err)
+	}
+
+	byBot := make(map[string]*models.LogPolicy, len(policies))
+	for _, policy := range policies {
+		byBot[policy.BotID] = policy
+	}
+
+	s.policiesMu.Lock()
+	s.policies = byBot
+	s.policiesMu.Unlock()
+
+	return nil
+}
+
+// Keep решает, сохранять ли лог по политике его бота, и считает отброшенные.
+// Логи без бота и ботов без политики сохраняются всегда.
+func (s *LogPolicyService) Keep(entry *models.Log) bool {
+	if entry.BotID == nil {
+		return true
+	}
+
+	s.policiesMu.RLock()
+	policy := s.policies[*entry.BotID]
+	s.policiesMu.RUnlock()
+
+	if policy == nil {
+		return true
+	}
+
+	reason := decide(policy, entry.Status, time.Now())
+	if reason == "" {
+		return true
+	}
+
+	key := dropKey{
+		botID:  *entry.BotID,
+		day:    time.Now().UTC().Format("2006-01-02"),
+		status: entry.Status,
+		reason: reason,
+	}
+	s.dropsMu.Lock()
+	s.drops[key]++
+	s.dropsMu.Unlock()
+
+	return false
+}
+
+// decide возвращает причину отбрасывания лога уровня status или пустую строку, если его нужно сохранить.
+// Пока действует временный уровень, он заменяет минимальный, а логи с него сохраняются без семплирования.
+func decide(policy *models.LogPolicy, status string, now time.Time) string {
+	rank := loglevel.Rank(status)
+
+	if policy.OverrideLevel != nil && policy.OverrideUntil != nil && now.Before(*policy.OverrideUntil) {
+		if rank < loglevel.Rank(*policy.OverrideLevel) {
+			return models.DropReasonLevel
+		}
+		return ""
+	}
+
+	if policy.MinLevel != nil && rank < loglevel.Rank(*policy.MinLevel) {
+		return models.DropReasonLevel
+	}
+
+	if rate, ok := policy.SampleRates[status]; ok && rate < 1 && rand.Float64() >= rate {
+		return models.DropReasonSampled
+	}
+
+	return ""
+}
+
+// FlushCounts сохраняет накопленные счётчики отброшенных логов; при ошибке они вернутся в следующую попытку
+func (s *LogPolicyService) FlushCounts() {
+	s.dropsMu.Lock()
+	drops := s.drops
+	s.drops = make(map[dropKey]int64)
+	s.dropsMu.Unlock()
+
+	if len(drops) == 0 {
+		return
+	}
+
+	deltas := make([]models.LogDropCountDelta, 0, len(drops))
+	for key, count := range drops {
+		delta := models.LogDropCountDelta{BotID: key.botID, Status: key.status, Reason: key.reason, Count: count}
+		delta.Day, _ = time.Parse("2006-01-02", key.day)
+		deltas = append(deltas, delta)
+	}
+
+	if err := s.policyRepo.AddDropCounts(deltas); err != nil {
+		log.Printf("Ошибка сохранения счётчиков отброшенных логов: %v", err)
+		sentry.CaptureException(err)
+
+		s.dropsMu.Lock()
+		for key, count := range drops {
+			s.drops[key] += count
+		}
+		s.dropsMu.Unlock()
+	}
+}
+
+func (s *LogPolicyService) GetPolicy(botID string) (*models.LogPolicy, error) {
+	policy, err := s.policyRepo.GetPolicy(botID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: политика хранения логов не задана", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка получения политики хранения логов: %w", err)
+	}
+	return policy, nil
+}
+
+// SetPolicy задаёт боту минимальный уровень (nil — все уровни) и доли сохраняемых логов по уровням
+func (s *LogPolicyService) SetPolicy(botID string, minLevel *string, sampleRates map[string]float64) (*models.LogPolicy, error) {
+	if err := s.checkBot(botID); err != nil {
+		return nil, err
+	}
+
+	if sampleRates == nil {
+		sampleRates = map[string]float64{}
+	}
+	for level, rate := range sampleRates {
+		if loglevel.Rank(level) < 0 {
+			return nil, fmt.Errorf("%w: неизвестный уровень в sample_rates: %s", customerrors.ErrInvalidInput, level)
+		}
+		if rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("%w: доля для уровня %s должна быть от 0 до 1", customerrors.ErrInvalidInput, level)
+		}
+	}
+
+	policy, err := s.policyRepo.SetPolicy(botID, minLevel, sampleRates)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сохранения политики хранения логов: %w", err)
+	}
+
+	s.reloadAfterChange()
+	return policy, nil
+}
+
+// SetOverride временно, на durationMinutes минут, снижает минимальный уровень бота до level
+func (s *LogPolicyService) SetOverride(botID, level string, durationMinutes int) (*models.LogPolicy, error) {
+	if durationMinutes <= 0 || durationMinutes > maxOverrideMinutes {
+		return nil, fmt.Errorf("%w: длительность должна быть от 1 до %d минут", customerrors.ErrInvalidInput, maxOverrideMinutes)
+	}
+	if err := s.checkBot(botID); err != nil {
+		return nil, err
+	}
+
+	until := time.Now().Add(time.Duration(durationMinutes) * time.Minute)
+	policy, err := s.policyRepo.SetOverride(botID, &level, &until)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сохранения временного уровня: %w", err)
+	}
+
+	s.reloadAfterChange()
+	return policy, nil
+}
+
+// ClearOverride досрочно снимает временный уровень бота
+func (s *LogPolicyService) ClearOverride(botID string) (*models.LogPolicy, error) {
+	if _, err := s.GetPolicy(botID); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.policyRepo.SetOverride(botID, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка снятия временного уровня: %w", err)
+	}
+
+	s.reloadAfterChange()
+	return policy, nil
+}
+
+// DeletePolicy удаляет политику бота: его логи снова сохраняются полностью
+func (s *LogPolicyService) DeletePolicy(botID string) error {
+	if err := s.policyRepo.DeletePolicy(botID); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: политика хранения логов не задана", customerrors.ErrNotFound)
+		}
+		return fmt.Errorf("ошибка удаления политики хранения логов: %w", err)
+	}
+
+	s.reloadAfterChange()
+	return nil
+}
+
+// ListDropCounts возвращает число отброшенных логов по ботам, уровням и причинам за дни [from, to] (UTC).
+// Счётчики за последние StatsFlushIntervalSec секунд могут быть ещё не сохранены.
+func (s *LogPolicyService) ListDropCounts(botID *string, from, to *time.Time) ([]*models.LogDropCount, error) {
+	counts, err := s.policyRepo.ListDropCounts(botID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения счётчиков отброшенных логов: %w", err)
+	}
+	return counts, nil
+}
+
+func (s *LogPolicyService) checkBot(botID string) error {
+	if _, err := s.botRepo.GetBotByID(botID); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: бот не найден", customerrors.ErrNotFound)
+		}
+		return fmt.Errorf("ошибка получения бота: %w", err)
+	}
+	return nil
+}
+
+// reloadAfterChange применяет изменение политики сразу на этом экземпляре; остальные подхватят его при перечитывании
+func (s *LogPolicyService) reloadAfterChange() {
+	if err := s.ReloadPolicies(); err != nil {
+		log.Printf("Ошибка загрузки политик хранения логов: %v", err)
+	}
+}
@@ -0,0 +1,290 @@
+package logservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"logging_api/configs"
+	"logging_api/internal/models"
+	"logging_api/pkg/wal"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// Политики переполнения очереди асинхронной записи
+const (
+	OverflowBlock     = "block"
+	OverflowDropDebug = "drop_debug"
+	OverflowSpill     = "spill"
+)
+
+const (
+	// flushRetries — число повторов записи пачки перед тем, как сбросить её в журнал или отбросить
+	flushRetries = 3
+	// walReplayInterval — период попыток сохранить в БД записи из журнала на диске
+	walReplayInterval = time.Minute
+)
+
+// asyncWriter — ограниченная очередь логов и воркеры, сохраняющие её пачками через COPY
+type asyncWriter struct {
+	logRepo LogRepoInterface
+	config  configs.IngestAsyncConfig
+	after   func(*models.Log)
+
+	queue chan *models.Log
+	wal   *wal.WAL
+
+	// mu защищает закрытие очереди от параллельной отправки в неё
+	mu      sync.RWMutex
+	closed  bool
+	closing atomic.Bool
+
+	workers sync.WaitGroup
+	dropped atomic.Int64
+}
+
+func newAsyncWriter(logRepo LogRepoInterface, config configs.IngestAsyncConfig, after func(*models.Log)) (*asyncWriter, error) {
+	switch config.Overflow {
+	case OverflowBlock, OverflowDropDebug, OverflowSpill:
+	default:
+		return nil, fmt.Errorf("неизвестная политика переполнения очереди: %s", config.Overflow)
+	}
+
+	w := &asyncWriter{
+		logRepo: logRepo,
+		config:  config,
+		after:   after,
+		queue:   make(chan *models.Log, config.QueueSize),
+	}
+
+	if config.Overflow == OverflowSpill {
+		journal, err := wal.Open(config.WALDir)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка открытия журнала: %w", err)
+		}
+		w.wal = journal
+	}
+
+	return w, nil
+}
+
+// start запускает воркеры и, для политики spill, сохранение журнала: сразу (записи от прошлого запуска)
+// и затем раз в walReplayInterval
+func (w *asyncWriter) start(ctx context.Context) {
+	for i := 0; i < w.config.Workers; i++ {
+		w.workers.Add(1)
+		go w.worker()
+	}
+
+	if w.wal == nil {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(walReplayInterval)
+		defer ticker.Stop()
+
+		w.replay()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.replay()
+			}
+		}
+	}()
+}
+
+// enqueue ставит логи в очередь по политике переполнения.
+// Возвращает false, если очередь уже закрыта — тогда логи нужно сохранить синхронно.
+func (w *asyncWriter) enqueue(entries []*models.Log) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return false
+	}
+
+	var spill []*models.Log
+	for _, entry := range entries {
+		if w.config.Overflow == OverflowBlock {
+			w.queue <- entry
+			continue
+		}
+
+		select {
+		case w.queue <- entry:
+			continue
+		default:
+		}
+
+		switch {
+		case w.config.Overflow == OverflowDropDebug && entry.Status == "Debug":
+			if w.dropped.Add(1)%1000 == 1 {
+				log.Printf("Очередь записи логов заполнена, Debug-логи отбрасываются (всего отброшено: %d)", w.dropped.Load())
+			}
+		case w.config.Overflow == OverflowSpill:
+			spill = append(spill, entry)
+		default:
+			w.queue <- entry
+		}
+	}
+
+	if len(spill) > 0 {
+		if err := w.spill(spill); err != nil {
+			// Журнал недоступен — ждём места в очереди, чтобы не потерять логи
+			log.Printf("Не удалось записать логи в журнал, ожидание места в очереди: %v", err)
+			sentry.CaptureException(err)
+			for _, entry := range spill {
+				w.queue <- entry
+			}
+		}
+	}
+
+	return true
+}
+
+// close перестаёт принимать логи и ждёт, пока воркеры сохранят очередь
+func (w *asyncWriter) close(ctx context.Context) error {
+	w.closing.Store(true)
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		w.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("очередь записи логов не сохранена до конца (осталось %d): %w", len(w.queue), ctx.Err())
+	}
+
+	if w.wal != nil {
+		return w.wal.Close()
+	}
+	return nil
+}
+
+func (w *asyncWriter) worker() {
+	defer w.workers.Done()
+
+	ticker := time.NewTicker(time.Duration(w.config.FlushIntervalMs) * time.Millisecond)
+	defer ticker.Stop()
+
+	batch := make([]*models.Log, 0, w.config.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		w.flush(batch)
+		batch = make([]*models.Log, 0, w.config.BatchSize)
+	}
+
+	for {
+		select {
+		case entry, ok := <-w.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) >= w.config.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// flush сохраняет пачку с повторами. Если БД так и не ответила, пачка сбрасывается в журнал
+// (политика spill) или теряется. При остановке повторов нет, чтобы не затягивать её.
+func (w *asyncWriter) flush(batch []*models.Log) {
+	var err error
+	for attempt := 0; attempt <= flushRetries; attempt++ {
+		if attempt > 0 {
+			if w.closing.Load() {
+				break
+			}
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+		if err = w.logRepo.CopyLogs(batch); err == nil {
+			for _, entry := range batch {
+				w.after(entry)
+			}
+			return
+		}
+	}
+
+	log.Printf("Ошибка сохранения пачки логов (%d шт.): %v", len(batch), err)
+	sentry.CaptureException(err)
+
+	if w.wal == nil {
+		return
+	}
+	if err := w.spill(batch); err != nil {
+		log.Printf("Не удалось записать логи в журнал, %d логов потеряно: %v", len(batch), err)
+		sentry.CaptureException(err)
+	}
+}
+
+func (w *asyncWriter) spill(entries []*models.Log) error {
+	records := make([][]byte, 0, len(entries))
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("ошибка кодирования лога: %w", err)
+		}
+		records = append(records, data)
+	}
+	return w.wal.Append(records)
+}
+
+// replay сохраняет в БД логи из журнала. При ошибке оставшиеся записи ждут следующей попытки.
+func (w *asyncWriter) replay() {
+	var total int
+	err := w.wal.Replay(w.config.BatchSize, func(records [][]byte) error {
+		entries := make([]*models.Log, 0, len(records))
+		for _, record := range records {
+			var entry models.Log
+			if err := json.Unmarshal(record, &entry); err != nil {
+				log.Printf("Пропущена повреждённая запись журнала логов: %v", err)
+				continue
+			}
+			entries = append(entries, &entry)
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		if err := w.logRepo.CopyLogs(entries); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			w.after(entry)
+		}
+		total += len(entries)
+		return nil
+	})
+
+	if total > 0 {
+		log.Printf("Из журнала на диске сохранено %d логов", total)
+	}
+	if err != nil {
+		log.Printf("Ошибка сохранения логов из журнала: %v", err)
+		sentry.CaptureException(err)
+	}
+}
@@ -1,47 +1,505 @@
 package logservice
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"logging_api/configs"
 	"logging_api/internal/models"
+	streamservice "logging_api/internal/service/stream_service"
+	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/pagination"
 	"logging_api/pkg/sentry"
+	"strconv"
+	"sync"
+	"time"
 )
 
 type LogRepoInterface interface {
-	CreateLog(botID *string, status, msg string) (*models.Log, error)
+	CreateLog(botID *string, status, msg string, createdAt *time.Time, version *string) (*models.Log, error)
+	CreateLogs(logs []*models.Log, keys []*models.IdempotencyKey, keep func(*models.Log) bool) ([]models.WriteResult, error)
+	CopyLogs(logs []*models.Log) error
+	ListLogs(filter *models.LogFilter) ([]*models.LogHit, error)
+	StreamLogs(filter *models.LogFilter, fn func(*models.Log) error) error
 }
 
 type BotRepoInterface interface {
 	GetBotCodeAndNameByID(botID string) (code string, name string, err error)
+	GetBotIDByCode(code string) (string, error)
+}
+
+type EventPublisher interface {
+	Publish(event streamservice.Event)
+}
+
+// Redactor маскирует персональные данные в логе перед сохранением
+type Redactor interface {
+	Redact(entry *models.Log)
+}
+
+// Sampler решает по политике хранения бота, сохранять ли лог
+type Sampler interface {
+	Keep(entry *models.Log) bool
+}
+
+// VersionProvider возвращает версию бота, выложенную на момент at (нулевой at — текущее время); nil — версия неизвестна
+type VersionProvider interface {
+	VersionAt(botID string, at time.Time) *string
+}
+
+// sortReceivedAt — сортировка логов по времени получения сервером
+const sortReceivedAt = "received_at"
+
+// botCodeCacheTTL — время жизни сопоставления кода бота с его ID (в том числе отрицательного)
+const botCodeCacheTTL = time.Minute
+
+type botCodeCacheEntry struct {
+	botID     string
+	expiresAt time.Time
 }
 
 type LogService struct {
-	logRepo LogRepoInterface
-	botRepo BotRepoInterface
+	logRepo   LogRepoInterface
+	botRepo   BotRepoInterface
+	publisher EventPublisher
+	redactor  Redactor
+	sampler   Sampler
+	versions  VersionProvider
+	config    configs.IngestConfig
+	keyTTL    time.Duration
+	async     *asyncWriter
+
+	botCodesMu sync.Mutex
+	botCodes   map[string]botCodeCacheEntry
 }
 
-func NewLogService(logRepo LogRepoInterface, botRepo BotRepoInterface) *LogService {
+// NewLogService создаёт сервис логов. redactor может быть nil — тогда логи сохраняются без маскирования,
+// sampler может быть nil — тогда сохраняются все логи, versions может быть nil — тогда логи сохраняются без версии.
+func NewLogService(logRepo LogRepoInterface, botRepo BotRepoInterface, publisher EventPublisher, redactor Redactor, sampler Sampler, versions VersionProvider, config configs.IngestConfig, idempotency configs.IdempotencyConfig) *LogService {
 	return &LogService{
-		logRepo: logRepo,
-		botRepo: botRepo,
+		logRepo:   logRepo,
+		botRepo:   botRepo,
+		publisher: publisher,
+		redactor:  redactor,
+		sampler:   sampler,
+		versions:  versions,
+		config:    config,
+		keyTTL:    time.Duration(idempotency.TTLHours) * time.Hour,
+		botCodes:  make(map[string]botCodeCacheEntry),
 	}
 }
 
-func (s *LogService) CreateLog(botID *string, status, msg string) (*models.Log, error) {
-	logEntry, err := s.logRepo.CreateLog(botID, status, msg)
+// Start включает асинхронную запись логов, если она настроена (ingest.async.enabled)
+func (s *LogService) Start(ctx context.Context) error {
+	if !s.config.Async.Enabled {
+		return nil
+	}
+
+	writer, err := newAsyncWriter(s.logRepo, s.config.Async, s.afterCreate)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка создания лога: %w", err)
+		return err
 	}
+	writer.start(ctx)
+	s.async = writer
+
+	return nil
+}
+
+// Close дожидается сохранения логов из очереди асинхронной записи; после него логи пишутся синхронно
+func (s *LogService) Close(ctx context.Context) error {
+	if s.async == nil {
+		return nil
+	}
+	return s.async.close(ctx)
+}
 
-	if botID != nil && *botID != "" && (status == "Error" || status == "Critical") {
-		projectCode, botName, err := s.botRepo.GetBotCodeAndNameByID(*botID)
+// CreateLog сохраняет лог. timestamp — время события от клиента (nil — время приёма);
+// оно должно укладываться в допустимое отклонение от текущего времени.
+//
+// key — ключ идемпотентности (nil — без ключа). Если лог с этим ключом уже создан, возвращается он
+// и результат WriteReplayed. Логи без ключа при включённой асинхронной записи ставятся в очередь (WriteQueued),
+// у них ещё нет id. Лог, отброшенный политикой хранения бота, не сохраняется (WriteDropped).
+func (s *LogService) CreateLog(botID *string, status, msg string, timestamp *time.Time, key *models.IdempotencyKey) (*models.Log, models.WriteResult, error) {
+	if err := s.checkTimestamp(timestamp); err != nil {
+		return nil, 0, err
+	}
+
+	logEntry := &models.Log{BotID: botID, Status: status, Msg: msg}
+	if timestamp != nil {
+		logEntry.CreatedAt = *timestamp
+	}
+	s.stampVersion(logEntry)
+
+	if key == nil {
+		if !s.keep(logEntry) {
+			return logEntry, models.WriteDropped, nil
+		}
+
+		s.redact(logEntry)
+		if s.enqueue([]*models.Log{logEntry}) {
+			return logEntry, models.WriteQueued, nil
+		}
+
+		logEntry, err := s.logRepo.CreateLog(botID, status, logEntry.Msg, timestamp, logEntry.Version)
 		if err != nil {
-			log.Printf("Не удалось получить project_code и bot_name для bot_id %s: %v", *botID, err)
+			return nil, 0, fmt.Errorf("ошибка создания лога: %w", err)
+		}
+
+		s.afterCreate(logEntry)
+
+		return logEntry, models.WriteCreated, nil
+	}
+
+	results, err := s.CreateLogBatch([]*models.Log{logEntry}, []*models.IdempotencyKey{key})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return logEntry, results[0], nil
+}
+
+// CreateLogs сохраняет пачку логов от внешних агентов (OTLP, Loki, _bulk, syslog); у записей заполняются id и created_at.
+// Непустой CreatedAt записи — время события; запись, время которой не укладывается в допустимое отклонение
+// (как в CreateLog), не сохраняется (WriteRejected), остальные сохраняются.
+// Обработка после сохранения (Sentry, подписки) такая же, как у CreateLog.
+// При включённой асинхронной записи логи ставятся в очередь (WriteQueued), у них ещё нет id.
+// Логи, отброшенные политикой хранения бота, не сохраняются (WriteDropped). Возвращает результат по каждой записи.
+func (s *LogService) CreateLogs(entries []*models.Log) ([]models.WriteResult, error) {
+	results := make([]models.WriteResult, len(entries))
+
+	// Индексы сохраняемых записей в исходной пачке
+	indexes := make([]int, 0, len(entries))
+	kept := make([]*models.Log, 0, len(entries))
+	for i, entry := range entries {
+		if !entry.CreatedAt.IsZero() && s.checkTimestamp(&entry.CreatedAt) != nil {
+			results[i] = models.WriteRejected
+			continue
+		}
+		if !s.keep(entry) {
+			results[i] = models.WriteDropped
+			continue
+		}
+		indexes = append(indexes, i)
+		kept = append(kept, entry)
+	}
+
+	if len(kept) == 0 {
+		return results, nil
+	}
+
+	s.stampVersion(kept...)
+	s.redact(kept...)
+	if s.enqueue(kept) {
+		for _, i := range indexes {
+			results[i] = models.WriteQueued
+		}
+		return results, nil
+	}
+
+	if _, err := s.logRepo.CreateLogs(kept, nil, nil); err != nil {
+		return nil, fmt.Errorf("ошибка создания логов: %w", err)
+	}
+
+	for j, entry := range kept {
+		results[indexes[j]] = models.WriteCreated
+		s.afterCreate(entry)
+	}
+
+	return results, nil
+}
+
+// CreateLogBatch сохраняет пачку логов от клиента. Непустой CreatedAt записи — время события,
+// оно проверяется так же, как в CreateLog. keys — ключи идемпотентности по записям (nil-элемент — без ключа);
+// записи с уже использованным ключом заменяются ранее созданными логами, а если запись с этим ключом была отброшена
+// политикой хранения, повтор тоже отбрасывается. Пачка без ключей при включённой асинхронной записи ставится в очередь.
+// Записи, отброшенные политикой хранения бота, не сохраняются (WriteDropped).
+// Возвращает результат по каждой записи.
+func (s *LogService) CreateLogBatch(entries []*models.Log, keys []*models.IdempotencyKey) ([]models.WriteResult, error) {
+	results := make([]models.WriteResult, len(entries))
+	if len(entries) == 0 {
+		return results, nil
+	}
+
+	for i, entry := range entries {
+		if entry.CreatedAt.IsZero() {
+			continue
+		}
+		if err := s.checkTimestamp(&entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("запись %d: %w", i, err)
+		}
+	}
+
+	hasKeys := false
+	notBefore := time.Now().Add(-s.keyTTL)
+	for _, key := range keys {
+		if key != nil {
+			key.NotBefore = notBefore
+			hasKeys = true
+		}
+	}
+
+	// Повтор по ключу должен дать тот же результат, поэтому пачки с ключами пишутся синхронно,
+	// а политика хранения применяется к записи только после того, как занят её ключ
+	if hasKeys {
+		return s.createKeyed(entries, keys)
+	}
+
+	// Индексы сохраняемых записей в исходной пачке
+	indexes := make([]int, 0, len(entries))
+	kept := make([]*models.Log, 0, len(entries))
+	for i, entry := range entries {
+		if !s.keep(entry) {
+			results[i] = models.WriteDropped
+			continue
+		}
+		indexes = append(indexes, i)
+		kept = append(kept, entry)
+	}
+
+	if len(kept) == 0 {
+		return results, nil
+	}
+
+	s.stampVersion(kept...)
+	s.redact(kept...)
+
+	if s.enqueue(kept) {
+		for _, i := range indexes {
+			results[i] = models.WriteQueued
+		}
+		return results, nil
+	}
+
+	if _, err := s.logRepo.CreateLogs(kept, nil, nil); err != nil {
+		return nil, fmt.Errorf("ошибка создания логов: %w", err)
+	}
+
+	for j, entry := range kept {
+		results[indexes[j]] = models.WriteCreated
+		s.afterCreate(entry)
+	}
+
+	return results, nil
+}
+
+// createKeyed синхронно сохраняет пачку, в которой есть записи с ключами идемпотентности.
+// Политика хранения и маскирование применяются в репозитории только к записям, которые будут созданы
+func (s *LogService) createKeyed(entries []*models.Log, keys []*models.IdempotencyKey) ([]models.WriteResult, error) {
+	if len(keys) < len(entries) {
+		keys = append(keys, make([]*models.IdempotencyKey, len(entries)-len(keys))...)
+	}
+
+	s.stampVersion(entries...)
+
+	results, err := s.logRepo.CreateLogs(entries, keys[:len(entries)], func(entry *models.Log) bool {
+		if !s.keep(entry) {
+			return false
+		}
+		s.redact(entry)
+		return true
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrIdempotencyKeyReused) {
+			return nil, fmt.Errorf("%w: ключ идемпотентности уже использован с другим телом запроса", customerrors.ErrUnprocessable)
+		}
+		return nil, fmt.Errorf("ошибка создания логов: %w", err)
+	}
+
+	for i, entry := range entries {
+		if results[i] == models.WriteCreated {
+			s.afterCreate(entry)
+		}
+	}
+
+	return results, nil
+}
+
+func (s *LogService) keep(entry *models.Log) bool {
+	return s.sampler == nil || s.sampler.Keep(entry)
+}
+
+// stampVersion проставляет логам ботов без явной версии версию бота на момент события (CreatedAt; пустой — время приёма)
+func (s *LogService) stampVersion(entries ...*models.Log) {
+	if s.versions == nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.BotID != nil && entry.Version == nil {
+			entry.Version = s.versions.VersionAt(*entry.BotID, entry.CreatedAt)
+		}
+	}
+}
+
+func (s *LogService) redact(entries ...*models.Log) {
+	if s.redactor == nil {
+		return
+	}
+	for _, entry := range entries {
+		s.redactor.Redact(entry)
+	}
+}
+
+// enqueue ставит логи в очередь асинхронной записи, если она включена.
+// Пустой CreatedAt заменяется временем приёма, как при синхронной записи.
+func (s *LogService) enqueue(entries []*models.Log) bool {
+	if s.async == nil {
+		return false
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		entry.ReceivedAt = now
+		if entry.CreatedAt.IsZero() {
+			entry.CreatedAt = now
+		}
+	}
+
+	return s.async.enqueue(entries)
+}
+
+// checkTimestamp проверяет, что время события от клиента укладывается в допустимое отклонение
+func (s *LogService) checkTimestamp(timestamp *time.Time) error {
+	if timestamp == nil {
+		return nil
+	}
+
+	now := time.Now()
+	minTime := now.Add(-time.Duration(s.config.MaxPastSkewSec) * time.Second)
+	maxTime := now.Add(time.Duration(s.config.MaxFutureSkewSec) * time.Second)
+	if timestamp.Before(minTime) || timestamp.After(maxTime) {
+		return fmt.Errorf("%w: timestamp должен быть в диапазоне от %s до %s",
+			customerrors.ErrInvalidInput, minTime.UTC().Format(time.RFC3339), maxTime.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// ResolveBotID возвращает ID бота по его коду или nil, если бот не найден
+func (s *LogService) ResolveBotID(code string) *string {
+	if code == "" {
+		return nil
+	}
+
+	s.botCodesMu.Lock()
+	entry, ok := s.botCodes[code]
+	s.botCodesMu.Unlock()
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		botID, err := s.botRepo.GetBotIDByCode(code)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Не удалось найти бота по коду %s: %v", code, err)
+			return nil
+		}
+
+		entry = botCodeCacheEntry{botID: botID, expiresAt: time.Now().Add(botCodeCacheTTL)}
+		s.botCodesMu.Lock()
+		s.botCodes[code] = entry
+		s.botCodesMu.Unlock()
+	}
+
+	if entry.botID == "" {
+		return nil
+	}
+	botID := entry.botID
+	return &botID
+}
+
+func (s *LogService) afterCreate(logEntry *models.Log) {
+	if logEntry.BotID != nil && *logEntry.BotID != "" && (logEntry.Status == "Error" || logEntry.Status == "Critical") {
+		botID := *logEntry.BotID
+		projectCode, botName, err := s.botRepo.GetBotCodeAndNameByID(botID)
+		if err != nil {
+			log.Printf("Не удалось получить project_code и bot_name для bot_id %s: %v", botID, err)
 			projectCode = "unknown"
 			botName = "unknown"
 		}
-		sentry.SendLog(*botID, projectCode, botName, status, msg, logEntry.CreatedAt)
+		sentry.SendLog(botID, projectCode, botName, logEntry.Status, logEntry.Msg, logEntry.CreatedAt)
+	}
+
+	s.publish(logEntry)
+}
+
+// ListLogs возвращает страницу логов по фильтру; cursor — курсор из предыдущей страницы
+func (s *LogService) ListLogs(filter *models.LogFilter, cursor string) (*models.LogPage, error) {
+	filter.Limit = pagination.Limit(filter.Limit)
+	// Сортировка по created_at — по умолчанию; курсор хранит поле сортировки, чтобы не смешивать их
+	if filter.SortBy != sortReceivedAt {
+		filter.SortBy = ""
+	}
+
+	c, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customerrors.ErrInvalidInput, err)
+	}
+	if c != nil {
+		if filter.Query != "" {
+			filter.Offset = c.Offset
+		} else {
+			id, err := strconv.ParseInt(c.ID, 10, 64)
+			if err != nil || c.Time == nil {
+				return nil, fmt.Errorf("%w: %v", customerrors.ErrInvalidInput, pagination.ErrInvalidCursor)
+			}
+			if c.Value != filter.SortBy {
+				return nil, fmt.Errorf("%w: %v", customerrors.ErrInvalidInput, pagination.ErrInvalidCursor)
+			}
+			filter.AfterTime = c.Time
+			filter.AfterID = &id
+		}
+	}
+
+	hits, err := s.logRepo.ListLogs(filter)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения логов: %w", err)
+	}
+
+	page := &models.LogPage{Items: hits}
+	if page.Items == nil {
+		page.Items = []*models.LogHit{}
+	}
+
+	if len(hits) > filter.Limit {
+		page.Items = hits[:filter.Limit]
+		last := page.Items[len(page.Items)-1]
+
+		next := &pagination.Cursor{Offset: filter.Offset + filter.Limit}
+		if filter.Query == "" {
+			lastTime := last.CreatedAt
+			if filter.SortBy == sortReceivedAt {
+				lastTime = last.ReceivedAt
+			}
+			next = &pagination.Cursor{Time: &lastTime, ID: strconv.FormatInt(last.ID, 10), Value: filter.SortBy}
+		}
+		encoded := next.Encode()
+		page.NextCursor = &encoded
+	}
+
+	return page, nil
+}
+
+// StreamLogs передаёт в fn все логи по фильтру, не загружая их в память
+func (s *LogService) StreamLogs(filter *models.LogFilter, fn func(*models.Log) error) error {
+	if err := s.logRepo.StreamLogs(filter, fn); err != nil {
+		return fmt.Errorf("ошибка выгрузки логов: %w", err)
+	}
+	return nil
+}
+
+func (s *LogService) publish(logEntry *models.Log) {
+	if s.publisher == nil {
+		return
+	}
+
+	botID := ""
+	if logEntry.BotID != nil {
+		botID = *logEntry.BotID
 	}
 
-	return logEntry, nil
+	s.publisher.Publish(streamservice.Event{
+		Channel: streamservice.ChannelLogs,
+		BotID:   botID,
+		Status:  logEntry.Status,
+		Payload: logEntry,
+	})
 }
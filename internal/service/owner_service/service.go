@@ -5,14 +5,20 @@ import (
 	"fmt"
 	"logging_api/internal/models"
 	customerrors "logging_api/internal/utils/errors"
+	"logging_api/internal/utils/pagination"
+	"regexp"
+	"slices"
 )
 
 type OwnerRepoInterface interface {
-	CreateOwner(fullName string, isActive bool) (*models.Owner, error)
+	CreateOwner(fullName string, isActive bool, contacts models.OwnerContacts) (*models.Owner, error)
 	GetOwnerByID(ownerID string) (*models.Owner, error)
-	GetAllOwners() ([]*models.Owner, error)
-	UpdateOwner(ownerID string, fullName *string, isActive *bool) (*models.Owner, error)
-	DeleteOwner(ownerID string) error
+	ListOwners(filter *models.OwnerFilter) ([]*models.Owner, int, error)
+	UpdateOwner(ownerID string, fullName *string, isActive *bool, contacts *models.OwnerContacts) (*models.Owner, error)
+	ArchiveOwner(ownerID string, archivedBy *string) error
+	RestoreOwner(ownerID string) error
+	GetOwnerPurgeReport(ownerID string) (*models.OwnerPurgeReport, error)
+	PurgeOwner(ownerID string, purgedBy *string) (*models.OwnerPurgeReport, error)
 }
 
 type OwnerService struct {
@@ -25,8 +31,15 @@ func NewOwnerService(ownerRepo OwnerRepoInterface) *OwnerService {
 	}
 }
 
-func (s *OwnerService) CreateOwner(fullName string, isActive bool) (*models.Owner, error) {
-	owner, err := s.ownerRepo.CreateOwner(fullName, isActive)
+// telegramPattern — имя пользователя Telegram, @ в начале необязателен
+var telegramPattern = regexp.MustCompile(`^@?[A-Za-z0-9_]{5,32}$`)
+
+func (s *OwnerService) CreateOwner(fullName string, isActive bool, contacts models.OwnerContacts) (*models.Owner, error) {
+	if err := normalizeContacts(&contacts); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.ownerRepo.CreateOwner(fullName, isActive, contacts)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка создания владельца: %w", err)
 	}
@@ -44,16 +57,94 @@ func (s *OwnerService) GetOwnerByID(ownerID string) (*models.Owner, error) {
 	return owner, nil
 }
 
-func (s *OwnerService) GetAllOwners() ([]*models.Owner, error) {
-	owners, err := s.ownerRepo.GetAllOwners()
+// ListOwners возвращает страницу владельцев по фильтру; cursor — курсор из предыдущей страницы.
+// По умолчанию сортировка по created_at по убыванию, full_name — по возрастанию.
+func (s *OwnerService) ListOwners(filter *models.OwnerFilter, cursor string) (*models.OwnerPage, error) {
+	filter.Limit = pagination.Limit(filter.Limit)
+	byTime := defaultOwnerSort(filter)
+
+	c, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customerrors.ErrInvalidInput, err)
+	}
+	if c != nil {
+		if c.ID == "" || (byTime && c.Time == nil) || (!byTime && c.Value == "") {
+			return nil, fmt.Errorf("%w: %v", customerrors.ErrInvalidInput, pagination.ErrInvalidCursor)
+		}
+		filter.AfterID = &c.ID
+		if byTime {
+			filter.AfterTime = c.Time
+		} else {
+			filter.AfterValue = &c.Value
+		}
+	}
+
+	owners, total, err := s.ownerRepo.ListOwners(filter)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения списка владельцев: %w", err)
+	}
+
+	page := &models.OwnerPage{Items: owners, Total: total}
+	if page.Items == nil {
+		page.Items = []*models.Owner{}
+	}
+
+	if len(owners) > filter.Limit {
+		page.Items = owners[:filter.Limit]
+		last := page.Items[len(page.Items)-1]
+		next := &pagination.Cursor{ID: last.ID}
+		if byTime {
+			next.Time = &last.CreatedAt
+		} else {
+			next.Value = last.FullName
+		}
+		encoded := next.Encode()
+		page.NextCursor = &encoded
+	}
+
+	return page, nil
+}
+
+// ListAllOwners возвращает всех владельцев по фильтру без постраничной выдачи, с той же сортировкой, что ListOwners
+// (прежний формат GET /v1/owners без limit и cursor)
+func (s *OwnerService) ListAllOwners(filter *models.OwnerFilter) ([]*models.Owner, error) {
+	filter.Limit = 0
+	defaultOwnerSort(filter)
+
+	owners, _, err := s.ownerRepo.ListOwners(filter)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения списка владельцев: %w", err)
 	}
+	if owners == nil {
+		owners = []*models.Owner{}
+	}
 	return owners, nil
 }
 
-func (s *OwnerService) UpdateOwner(ownerID string, fullName *string, isActive *bool) (*models.Owner, error) {
-	owner, err := s.ownerRepo.UpdateOwner(ownerID, fullName, isActive)
+// defaultOwnerSort задаёт сортировку по умолчанию и возвращает, сортируются ли владельцы по времени
+func defaultOwnerSort(filter *models.OwnerFilter) bool {
+	if filter.SortBy == "" {
+		filter.SortBy = "created_at"
+	}
+	byTime := filter.SortBy == "created_at"
+	if filter.Order == "" {
+		filter.Order = "asc"
+		if byTime {
+			filter.Order = "desc"
+		}
+	}
+	return byTime
+}
+
+// UpdateOwner обновляет владельца: nil — поле без изменений; contacts заменяет все контакты целиком
+func (s *OwnerService) UpdateOwner(ownerID string, fullName *string, isActive *bool, contacts *models.OwnerContacts) (*models.Owner, error) {
+	if contacts != nil {
+		if err := normalizeContacts(contacts); err != nil {
+			return nil, err
+		}
+	}
+
+	owner, err := s.ownerRepo.UpdateOwner(ownerID, fullName, isActive, contacts)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("%w: владелец не найден", customerrors.ErrNotFound)
@@ -63,10 +154,99 @@ func (s *OwnerService) UpdateOwner(ownerID string, fullName *string, isActive *b
 	return owner, nil
 }
 
-func (s *OwnerService) DeleteOwner(ownerID string) error {
-	err := s.ownerRepo.DeleteOwner(ownerID)
-	if err != nil {
-		return fmt.Errorf("ошибка удаления владельца: %w", err)
+// UpdateProfile обновляет профиль владельца по его собственному токену: имя и контакты.
+// Активность и архив меняет только администратор.
+func (s *OwnerService) UpdateProfile(ownerID string, fullName *string, contacts *models.OwnerContacts) (*models.Owner, error) {
+	return s.UpdateOwner(ownerID, fullName, nil, contacts)
+}
+
+// normalizeContacts приводит имя Telegram к виду @name и проверяет, что для каждого
+// предпочитаемого канала указан контакт
+func normalizeContacts(contacts *models.OwnerContacts) error {
+	if contacts.Telegram != nil {
+		if !telegramPattern.MatchString(*contacts.Telegram) {
+			return fmt.Errorf("%w: некорректное имя пользователя Telegram", customerrors.ErrInvalidInput)
+		}
+		if (*contacts.Telegram)[0] != '@' {
+			telegram := "@" + *contacts.Telegram
+			contacts.Telegram = &telegram
+		}
+	}
+
+	channels := make([]string, 0, len(contacts.NotifyChannels))
+	for _, channel := range contacts.NotifyChannels {
+		if slices.Contains(channels, channel) {
+			continue
+		}
+		var contact *string
+		switch channel {
+		case models.ChannelEmail:
+			contact = contacts.Email
+		case models.ChannelTelegram:
+			contact = contacts.Telegram
+		case models.ChannelPhone:
+			contact = contacts.Phone
+		default:
+			return fmt.Errorf("%w: неизвестный канал уведомлений %q", customerrors.ErrInvalidInput, channel)
+		}
+		if contact == nil {
+			return fmt.Errorf("%w: для канала уведомлений %s не указан контакт", customerrors.ErrInvalidInput, channel)
+		}
+		channels = append(channels, channel)
 	}
+	contacts.NotifyChannels = channels
+
 	return nil
 }
+
+// ArchiveOwner переносит владельца в архив; archivedBy — ID админского токена. Повторный перенос возвращает владельца без изменений.
+func (s *OwnerService) ArchiveOwner(ownerID, archivedBy string) (*models.Owner, error) {
+	var by *string
+	if archivedBy != "" {
+		by = &archivedBy
+	}
+	if err := s.ownerRepo.ArchiveOwner(ownerID, by); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("ошибка переноса владельца в архив: %w", err)
+	}
+	return s.GetOwnerByID(ownerID)
+}
+
+// RestoreOwner возвращает владельца из архива. Для владельца не в архиве возвращает его без изменений.
+func (s *OwnerService) RestoreOwner(ownerID string) (*models.Owner, error) {
+	if err := s.ownerRepo.RestoreOwner(ownerID); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("ошибка восстановления владельца: %w", err)
+	}
+	return s.GetOwnerByID(ownerID)
+}
+
+// GetOwnerPurgeReport возвращает отчёт о последствиях окончательного удаления владельца
+func (s *OwnerService) GetOwnerPurgeReport(ownerID string) (*models.OwnerPurgeReport, error) {
+	report, err := s.ownerRepo.GetOwnerPurgeReport(ownerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: владелец не найден", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка подсчёта ботов владельца: %w", err)
+	}
+	return report, nil
+}
+
+// PurgeOwner окончательно удаляет владельца; удалить можно только владельца в архиве.
+// purgedBy — ID админского токена, он записывается в историю передачи ботов, оставшихся без владельца.
+func (s *OwnerService) PurgeOwner(ownerID, purgedBy string) (*models.OwnerPurgeReport, error) {
+	var by *string
+	if purgedBy != "" {
+		by = &purgedBy
+	}
+	report, err := s.ownerRepo.PurgeOwner(ownerID, by)
+	if err == sql.ErrNoRows {
+		if _, err := s.GetOwnerByID(ownerID); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: окончательно удалить можно только владельца в архиве", customerrors.ErrConflict)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка удаления владельца: %w", err)
+	}
+	return report, nil
+}
@@ -0,0 +1,199 @@
+package partitionservice
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"logging_api/configs"
+	"logging_api/internal/models"
+
+	"github.com/getsentry/sentry-go"
+)
+
+var logLevels = []string{"Debug", "Info", "Warning", "Error", "Critical"}
+
+type PartitionRepoInterface interface {
+	EnsurePartition(month time.Time) (string, error)
+	ListPartitions() ([]*models.LogPartition, error)
+	DropPartition(name string) error
+	DeleteLogsBefore(status string, before time.Time, limit int) (int64, error)
+}
+
+// Archiver выгружает логи в холодный архив перед удалением
+type Archiver interface {
+	ArchiveBefore(level string, before time.Time) (int64, error)
+}
+
+type PartitionService struct {
+	partitionRepo PartitionRepoInterface
+	archiver      Archiver
+	config        configs.RetentionConfig
+}
+
+// NewPartitionService создаёт сервис обслуживания секций. archiver может быть nil —
+// тогда устаревшие логи удаляются без выгрузки в архив.
+func NewPartitionService(partitionRepo PartitionRepoInterface, archiver Archiver, config configs.RetentionConfig) *PartitionService {
+	return &PartitionService{
+		partitionRepo: partitionRepo,
+		archiver:      archiver,
+		config:        config,
+	}
+}
+
+// Start запускает фоновое обслуживание секций: сразу и затем раз в IntervalMin минут
+func (s *PartitionService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Duration(s.config.IntervalMin) * time.Minute)
+		defer ticker.Stop()
+
+		s.RunMaintenance()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.RunMaintenance()
+			}
+		}
+	}()
+}
+
+// RunMaintenance создаёт будущие секции и применяет политику хранения
+func (s *PartitionService) RunMaintenance() {
+	now := time.Now().UTC()
+
+	if err := s.EnsurePartitions(now); err != nil {
+		log.Printf("Ошибка создания секций logs: %v", err)
+		sentry.CaptureException(err)
+	}
+
+	if err := s.ApplyRetention(now); err != nil {
+		log.Printf("Ошибка применения политики хранения логов: %v", err)
+		sentry.CaptureException(err)
+	}
+}
+
+// EnsurePartitions создаёт секции на текущий месяц и PartitionsAhead месяцев вперёд
+func (s *PartitionService) EnsurePartitions(now time.Time) error {
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i <= s.config.PartitionsAhead; i++ {
+		if _, err := s.partitionRepo.EnsurePartition(month.AddDate(0, i, 0)); err != nil {
+			return fmt.Errorf("ошибка создания секции: %w", err)
+		}
+	}
+	return nil
+}
+
+// ApplyRetention удаляет секции, которые старше срока хранения всех уровней,
+// и подрезает логи уровней с более коротким сроком хранения.
+// Если включён архив, логи сначала выгружаются в него по дням, и только потом удаляются.
+func (s *PartitionService) ApplyRetention(now time.Time) error {
+	if s.archiver != nil {
+		if err := s.archiveExpired(now); err != nil {
+			return err
+		}
+	}
+
+	maxDays, allLimited := s.maxRetentionDays()
+
+	if allLimited {
+		cutoff := now.AddDate(0, 0, -maxDays)
+		partitions, err := s.partitionRepo.ListPartitions()
+		if err != nil {
+			return fmt.Errorf("ошибка получения списка секций: %w", err)
+		}
+
+		for _, partition := range partitions {
+			if partition.IsDefault || partition.To == nil || partition.To.After(cutoff) {
+				continue
+			}
+			if err := s.partitionRepo.DropPartition(partition.Name); err != nil {
+				return fmt.Errorf("ошибка удаления секции: %w", err)
+			}
+			log.Printf("Секция %s удалена по политике хранения", partition.Name)
+		}
+	}
+
+	if s.archiver != nil {
+		return nil
+	}
+
+	for _, level := range logLevels {
+		days := s.config.DaysByLevel[level]
+		if days <= 0 {
+			continue
+		}
+
+		cutoff := now.AddDate(0, 0, -days)
+		var total int64
+		for {
+			deleted, err := s.partitionRepo.DeleteLogsBefore(level, cutoff, s.config.DeleteBatchSize)
+			if err != nil {
+				return fmt.Errorf("ошибка удаления логов уровня %s: %w", level, err)
+			}
+			total += deleted
+			if deleted < int64(s.config.DeleteBatchSize) {
+				break
+			}
+		}
+
+		if total > 0 {
+			log.Printf("Удалено %d логов уровня %s старше %d дней", total, level, days)
+		}
+	}
+
+	return nil
+}
+
+func (s *PartitionService) archiveExpired(now time.Time) error {
+	for _, level := range logLevels {
+		days := s.config.DaysByLevel[level]
+		if days <= 0 {
+			continue
+		}
+
+		archived, err := s.archiver.ArchiveBefore(level, now.AddDate(0, 0, -days))
+		if err != nil {
+			return fmt.Errorf("ошибка архивации логов уровня %s: %w", level, err)
+		}
+		if archived > 0 {
+			log.Printf("Выгружено в архив и удалено %d логов уровня %s старше %d дней", archived, level, days)
+		}
+	}
+	return nil
+}
+
+func (s *PartitionService) GetPartitions() ([]*models.LogPartition, error) {
+	partitions, err := s.partitionRepo.ListPartitions()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения списка секций: %w", err)
+	}
+	return partitions, nil
+}
+
+// RetentionDays возвращает сроки хранения по уровням (0 — бессрочно)
+func (s *PartitionService) RetentionDays() map[string]int {
+	days := make(map[string]int, len(logLevels))
+	for _, level := range logLevels {
+		days[level] = s.config.DaysByLevel[level]
+	}
+	return days
+}
+
+// maxRetentionDays возвращает наибольший срок хранения и признак того,
+// что срок ограничен для всех уровней (только тогда секции можно удалять целиком)
+func (s *PartitionService) maxRetentionDays() (int, bool) {
+	maxDays := 0
+	for _, level := range logLevels {
+		days := s.config.DaysByLevel[level]
+		if days <= 0 {
+			return 0, false
+		}
+		if days > maxDays {
+			maxDays = days
+		}
+	}
+	return maxDays, true
+}
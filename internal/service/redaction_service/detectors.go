@@ -0,0 +1,133 @@
+package redactionservice
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Встроенные детекторы. Порядок важен: номер карты проверяется раньше телефона,
+// чтобы часть номера карты не была принята за телефон.
+var builtinDetectors = []struct {
+	name    string
+	pattern string
+	valid   func(string) bool
+}{
+	{
+		name:    "email",
+		pattern: `[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`,
+	},
+	{
+		name:    "card",
+		pattern: `\b(?:\d[ \-]?){12,18}\d\b`,
+		valid:   luhnValid,
+	},
+	{
+		// Серия и номер паспорта РФ рядом со словом «паспорт»; маскируется только номер
+		name:    "passport",
+		pattern: `(?i)(?:паспорт|passport)[^\d\n]{0,20}(?P<pii>\d{2}\s?\d{2}\s?\d{6})\b`,
+	},
+	{
+		// Российские номера (+7, 8) и международные в формате с «+»
+		name:    "phone",
+		pattern: `(?:(?:\+7|\b8)[\s\-]?\(?\d{3}\)?[\s\-]?\d{3}[\s\-]?\d{2}[\s\-]?\d{2}|\+\d{1,3}[\s\-]?\(?\d{1,4}\)?(?:[\s\-]?\d){6,10})\b`,
+	},
+}
+
+// builtinNames — имена встроенных детекторов
+func builtinNames() []string {
+	names := make([]string, 0, len(builtinDetectors))
+	for _, detector := range builtinDetectors {
+		names = append(names, detector.name)
+	}
+	return names
+}
+
+// luhnValid проверяет контрольную сумму номера карты (алгоритм Луна)
+func luhnValid(value string) bool {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(value)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// compiledRule — правило маскирования, готовое к применению
+type compiledRule struct {
+	name        string
+	re          *regexp.Regexp
+	replacement string
+	valid       func(string) bool
+	// group — индекс группы pii; 0 — маскируется всё совпадение
+	group int
+}
+
+func compileRule(name, pattern string, replacement *string, valid func(string) bool) (*compiledRule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &compiledRule{
+		name:        name,
+		re:          re,
+		replacement: "[REDACTED:" + name + "]",
+		valid:       valid,
+	}
+	if replacement != nil {
+		rule.replacement = *replacement
+	}
+	if i := re.SubexpIndex("pii"); i > 0 {
+		rule.group = i
+	}
+
+	return rule, nil
+}
+
+// apply маскирует совпадения в text и возвращает результат и число замен
+func (r *compiledRule) apply(text string) (string, int) {
+	matches := r.re.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text, 0
+	}
+
+	var b strings.Builder
+	last, n := 0, 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if r.group > 0 {
+			start, end = m[2*r.group], m[2*r.group+1]
+		}
+		if start < 0 || start == end {
+			continue
+		}
+		if r.valid != nil && !r.valid(text[start:end]) {
+			continue
+		}
+
+		b.WriteString(text[last:start])
+		b.WriteString(r.replacement)
+		last = end
+		n++
+	}
+	if n == 0 {
+		return text, 0
+	}
+
+	b.WriteString(text[last:])
+	return b.String(), n
+}
@@ -0,0 +1,186 @@
+package redactionservice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func builtinRules(t *testing.T) []*compiledRule {
+	t.Helper()
+
+	rules := make([]*compiledRule, 0, len(builtinDetectors))
+	for _, detector := range builtinDetectors {
+		rule, err := compileRule(detector.name, detector.pattern, nil, detector.valid)
+		if err != nil {
+			t.Fatalf("compileRule(%s) error = %v", detector.name, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func TestLuhnValid(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{value: "4111111111111111", want: true},
+		{value: "4111 1111 1111 1111", want: true},
+		{value: "4111-1111-1111-1111", want: true},
+		{value: "5555555555554444", want: true},
+		{value: "378282246310005", want: true},
+		{value: "6011000990139424", want: true},
+		{value: "4111111111111112", want: false},
+		{value: "1234567812345678", want: false},
+		{value: "411111111111", want: false},
+		{value: "41111111111111111111", want: false},
+		{value: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := luhnValid(tt.value); got != tt.want {
+				t.Errorf("luhnValid(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuiltinDetectors(t *testing.T) {
+	rules := builtinRules(t)
+
+	tests := []struct {
+		name      string
+		text      string
+		want      string
+		wantFound map[string]int
+	}{
+		{
+			name:      "email",
+			text:      "письмо для ivan.petrov+logs@mail.example.co.uk отправлено",
+			want:      "письмо для [REDACTED:email] отправлено",
+			wantFound: map[string]int{"email": 1},
+		},
+		{
+			name:      "two emails",
+			text:      "from a@b.ru to c.d@e-f.com",
+			want:      "from [REDACTED:email] to [REDACTED:email]",
+			wantFound: map[string]int{"email": 2},
+		},
+		{
+			name:      "card with spaces",
+			text:      "оплата картой 4111 1111 1111 1111 отклонена",
+			want:      "оплата картой [REDACTED:card] отклонена",
+			wantFound: map[string]int{"card": 1},
+		},
+		{
+			name:      "card with dashes",
+			text:      "card=5555-5555-5555-4444;",
+			want:      "card=[REDACTED:card];",
+			wantFound: map[string]int{"card": 1},
+		},
+		{
+			name:      "number failing luhn is kept",
+			text:      "заказ 4111111111111112 отправлен",
+			want:      "заказ 4111111111111112 отправлен",
+			wantFound: map[string]int{},
+		},
+		{
+			name:      "short number is kept",
+			text:      "order 123456789012 total 100",
+			want:      "order 123456789012 total 100",
+			wantFound: map[string]int{},
+		},
+		{
+			name:      "passport number only",
+			text:      "Паспорт: 45 08 123456 выдан ОВД",
+			want:      "Паспорт: [REDACTED:passport] выдан ОВД",
+			wantFound: map[string]int{"passport": 1},
+		},
+		{
+			name:      "digits without passport keyword",
+			text:      "ticket 4508123456 closed",
+			want:      "ticket 4508123456 closed",
+			wantFound: map[string]int{},
+		},
+		{
+			name:      "russian phone",
+			text:      "позвоните +7 (912) 345-67-89",
+			want:      "позвоните [REDACTED:phone]",
+			wantFound: map[string]int{"phone": 1},
+		},
+		{
+			name:      "russian phone with 8",
+			text:      "тел. 8 912 345 67 89, спросить Ивана",
+			want:      "тел. [REDACTED:phone], спросить Ивана",
+			wantFound: map[string]int{"phone": 1},
+		},
+		{
+			name:      "international phone",
+			text:      "office +44 20 7946 0958",
+			want:      "office [REDACTED:phone]",
+			wantFound: map[string]int{"phone": 1},
+		},
+		{
+			name:      "several kinds",
+			text:      "user a@b.ru paid with 4111111111111111, phone +79123456789",
+			want:      "user [REDACTED:email] paid with [REDACTED:card], phone [REDACTED:phone]",
+			wantFound: map[string]int{"email": 1, "card": 1, "phone": 1},
+		},
+		{
+			name:      "nothing to redact",
+			text:      "processed 1500 records in 42 ms",
+			want:      "processed 1500 records in 42 ms",
+			wantFound: map[string]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := map[string]int{}
+			if got := redactText(tt.text, rules, found); got != tt.want {
+				t.Errorf("redactText() = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(found, tt.wantFound) {
+				t.Errorf("found = %v, want %v", found, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestCompiledRulePIIGroup(t *testing.T) {
+	replacement := "***"
+	rule, err := compileRule("inn", `ИНН\s*(?P<pii>\d{10,12})`, &replacement, nil)
+	if err != nil {
+		t.Fatalf("compileRule() error = %v", err)
+	}
+
+	got, n := rule.apply("ИНН 7707083893, ИНН 500100732259")
+	if want := "ИНН ***, ИНН ***"; got != want || n != 2 {
+		t.Errorf("apply() = %q, %d, want %q, 2", got, n, want)
+	}
+}
+
+func TestRedactValue(t *testing.T) {
+	rules := builtinRules(t)
+	value := map[string]interface{}{
+		"user":   map[string]interface{}{"email": "a@b.ru", "age": 30},
+		"phones": []interface{}{"+79123456789", "none"},
+		"ok":     true,
+	}
+
+	found := map[string]int{}
+	got := redactValue(value, rules, found)
+
+	want := map[string]interface{}{
+		"user":   map[string]interface{}{"email": "[REDACTED:email]", "age": 30},
+		"phones": []interface{}{"[REDACTED:phone]", "none"},
+		"ok":     true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("redactValue() = %#v, want %#v", got, want)
+	}
+	if wantFound := map[string]int{"email": 1, "phone": 1}; !reflect.DeepEqual(found, wantFound) {
+		t.Errorf("found = %v, want %v", found, wantFound)
+	}
+}
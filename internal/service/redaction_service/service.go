@@ -0,0 +1,356 @@
+package redactionservice
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"logging_api/configs"
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+
+	"github.com/getsentry/sentry-go"
+)
+
+type RedactionRepoInterface interface {
+	CreateRule(botID *string, name, pattern string, replacement *string, isActive bool) (*models.RedactionRule, error)
+	GetRuleByID(ruleID string) (*models.RedactionRule, error)
+	ListRules(botID *string, activeOnly bool) ([]*models.RedactionRule, error)
+	UpdateRule(ruleID string, name, pattern, replacement *string, isActive *bool) (*models.RedactionRule, error)
+	DeleteRule(ruleID string) error
+	AddCounts(deltas []models.RedactionCountDelta) error
+	ListCounts(botID *string, from, to *time.Time) ([]*models.RedactionCount, error)
+}
+
+type countKey struct {
+	botID string
+	rule  string
+	day   string
+}
+
+// RedactionService маскирует персональные данные в логах до сохранения и отправки в Sentry:
+// встроенными детекторами и правилами администраторов (глобальными и для отдельных ботов)
+type RedactionService struct {
+	redactionRepo RedactionRepoInterface
+	config        configs.RedactionConfig
+
+	builtin []*compiledRule
+
+	rulesMu sync.RWMutex
+	global  []*compiledRule
+	byBot   map[string][]*compiledRule
+
+	countsMu sync.Mutex
+	counts   map[countKey]int64
+}
+
+func NewRedactionService(redactionRepo RedactionRepoInterface, config configs.RedactionConfig) *RedactionService {
+	return &RedactionService{
+		redactionRepo: redactionRepo,
+		config:        config,
+		byBot:         make(map[string][]*compiledRule),
+		counts:        make(map[countKey]int64),
+	}
+}
+
+// Start включает встроенные детекторы, загружает правила и запускает их периодическое
+// перечитывание и сохранение счётчиков замен
+func (s *RedactionService) Start(ctx context.Context) error {
+	if !s.config.Enabled {
+		return nil
+	}
+
+	enabled := s.config.Detectors
+	if len(enabled) == 0 {
+		enabled = builtinNames()
+	}
+	for _, name := range enabled {
+		if !slices.Contains(builtinNames(), name) {
+			return fmt.Errorf("неизвестный детектор: %s", name)
+		}
+	}
+	for _, detector := range builtinDetectors {
+		if !slices.Contains(enabled, detector.name) {
+			continue
+		}
+		rule, err := compileRule(detector.name, detector.pattern, nil, detector.valid)
+		if err != nil {
+			return fmt.Errorf("ошибка детектора %s: %w", detector.name, err)
+		}
+		s.builtin = append(s.builtin, rule)
+	}
+
+	if err := s.ReloadRules(); err != nil {
+		return err
+	}
+
+	go func() {
+		refresh := time.NewTicker(time.Duration(s.config.RefreshIntervalSec) * time.Second)
+		defer refresh.Stop()
+		flush := time.NewTicker(time.Duration(s.config.StatsFlushIntervalSec) * time.Second)
+		defer flush.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-refresh.C:
+				if err := s.ReloadRules(); err != nil {
+					log.Printf("Ошибка загрузки правил маскирования: %v", err)
+					sentry.CaptureException(err)
+				}
+			case <-flush.C:
+				s.FlushCounts()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// ReloadRules перечитывает включённые правила администраторов из БД.
+// Правило с некорректным выражением пропускается.
+func (s *RedactionService) ReloadRules() error {
+	rules, err := s.redactionRepo.ListRules(nil, true)
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки правил маскирования: %w", err)
+	}
+
+	global := make([]*compiledRule, 0)
+	byBot := make(map[string][]*compiledRule)
+	for _, rule := range rules {
+		compiled, err := compileRule(rule.Name, rule.Pattern, rule.Replacement, nil)
+		if err != nil {
+			log.Printf("Правило маскирования %s (%s) пропущено: %v", rule.Name, rule.ID, err)
+			continue
+		}
+		if rule.BotID == nil {
+			global = append(global, compiled)
+		} else {
+			byBot[*rule.BotID] = append(byBot[*rule.BotID], compiled)
+		}
+	}
+
+	s.rulesMu.Lock()
+	s.global = global
+	s.byBot = byBot
+	s.rulesMu.Unlock()
+
+	return nil
+}
+
+// Redact маскирует совпадения в сообщении и строковых атрибутах лога
+func (s *RedactionService) Redact(entry *models.Log) {
+	if !s.config.Enabled {
+		return
+	}
+
+	botID := ""
+	if entry.BotID != nil {
+		botID = *entry.BotID
+	}
+
+	s.rulesMu.RLock()
+	rules := make([]*compiledRule, 0, len(s.builtin)+len(s.global)+len(s.byBot[botID]))
+	rules = append(rules, s.builtin...)
+	rules = append(rules, s.global...)
+	rules = append(rules, s.byBot[botID]...)
+	s.rulesMu.RUnlock()
+
+	if len(rules) == 0 {
+		return
+	}
+
+	found := make(map[string]int)
+	entry.Msg = redactText(entry.Msg, rules, found)
+	for key, value := range entry.Attributes {
+		entry.Attributes[key] = redactValue(value, rules, found)
+	}
+
+	if len(found) == 0 {
+		return
+	}
+
+	day := time.Now().UTC().Format("2006-01-02")
+	s.countsMu.Lock()
+	for rule, n := range found {
+		s.counts[countKey{botID: botID, rule: rule, day: day}] += int64(n)
+	}
+	s.countsMu.Unlock()
+}
+
+func redactText(text string, rules []*compiledRule, found map[string]int) string {
+	for _, rule := range rules {
+		var n int
+		text, n = rule.apply(text)
+		if n > 0 {
+			found[rule.name] += n
+		}
+	}
+	return text
+}
+
+func redactValue(value interface{}, rules []*compiledRule, found map[string]int) interface{} {
+	switch v := value.(type) {
+	case string:
+		return redactText(v, rules, found)
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = redactValue(item, rules, found)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item, rules, found)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// FlushCounts сохраняет накопленные счётчики замен; при ошибке они вернутся в следующую попытку
+func (s *RedactionService) FlushCounts() {
+	s.countsMu.Lock()
+	counts := s.counts
+	s.counts = make(map[countKey]int64)
+	s.countsMu.Unlock()
+
+	if len(counts) == 0 {
+		return
+	}
+
+	deltas := make([]models.RedactionCountDelta, 0, len(counts))
+	for key, count := range counts {
+		delta := models.RedactionCountDelta{Rule: key.rule, Count: count}
+		if key.botID != "" {
+			botID := key.botID
+			delta.BotID = &botID
+		}
+		delta.Day, _ = time.Parse("2006-01-02", key.day)
+		deltas = append(deltas, delta)
+	}
+
+	if err := s.redactionRepo.AddCounts(deltas); err != nil {
+		log.Printf("Ошибка сохранения счётчиков маскирования: %v", err)
+		sentry.CaptureException(err)
+
+		s.countsMu.Lock()
+		for key, count := range counts {
+			s.counts[key] += count
+		}
+		s.countsMu.Unlock()
+	}
+}
+
+func (s *RedactionService) CreateRule(botID *string, name, pattern string, replacement *string, isActive bool) (*models.RedactionRule, error) {
+	if err := validateRule(name, pattern); err != nil {
+		return nil, err
+	}
+
+	rule, err := s.redactionRepo.CreateRule(botID, name, pattern, replacement, isActive)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания правила маскирования: %w", err)
+	}
+
+	s.reloadAfterChange()
+	return rule, nil
+}
+
+func (s *RedactionService) GetRuleByID(ruleID string) (*models.RedactionRule, error) {
+	rule, err := s.redactionRepo.GetRuleByID(ruleID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: правило маскирования не найдено", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка получения правила маскирования: %w", err)
+	}
+	return rule, nil
+}
+
+// ListRules возвращает правила бота вместе с глобальными (botID = nil — все правила)
+func (s *RedactionService) ListRules(botID *string) ([]*models.RedactionRule, error) {
+	rules, err := s.redactionRepo.ListRules(botID, false)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения правил маскирования: %w", err)
+	}
+	if rules == nil {
+		rules = []*models.RedactionRule{}
+	}
+	return rules, nil
+}
+
+func (s *RedactionService) UpdateRule(ruleID string, name, pattern, replacement *string, isActive *bool) (*models.RedactionRule, error) {
+	current, err := s.GetRuleByID(ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	newName, newPattern := current.Name, current.Pattern
+	if name != nil {
+		newName = *name
+	}
+	if pattern != nil {
+		newPattern = *pattern
+	}
+	if err := validateRule(newName, newPattern); err != nil {
+		return nil, err
+	}
+
+	rule, err := s.redactionRepo.UpdateRule(ruleID, name, pattern, replacement, isActive)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: правило маскирования не найдено", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка обновления правила маскирования: %w", err)
+	}
+
+	s.reloadAfterChange()
+	return rule, nil
+}
+
+func (s *RedactionService) DeleteRule(ruleID string) error {
+	if err := s.redactionRepo.DeleteRule(ruleID); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: правило маскирования не найдено", customerrors.ErrNotFound)
+		}
+		return fmt.Errorf("ошибка удаления правила маскирования: %w", err)
+	}
+
+	s.reloadAfterChange()
+	return nil
+}
+
+// ListCounts возвращает число замен по ботам и правилам за дни [from, to] (UTC).
+// Счётчики за последние StatsFlushIntervalSec секунд могут быть ещё не сохранены.
+func (s *RedactionService) ListCounts(botID *string, from, to *time.Time) ([]*models.RedactionCount, error) {
+	counts, err := s.redactionRepo.ListCounts(botID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения счётчиков маскирования: %w", err)
+	}
+	return counts, nil
+}
+
+// reloadAfterChange применяет изменение правил сразу на этом экземпляре; остальные подхватят его при перечитывании
+func (s *RedactionService) reloadAfterChange() {
+	if !s.config.Enabled {
+		return
+	}
+	if err := s.ReloadRules(); err != nil {
+		log.Printf("Ошибка загрузки правил маскирования: %v", err)
+	}
+}
+
+func validateRule(name, pattern string) error {
+	if slices.Contains(builtinNames(), name) {
+		return fmt.Errorf("%w: имя %s занято встроенным детектором", customerrors.ErrInvalidInput, name)
+	}
+	if _, err := compileRule(name, pattern, nil, nil); err != nil {
+		return fmt.Errorf("%w: некорректное регулярное выражение: %v", customerrors.ErrInvalidInput, err)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package streamservice
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+)
+
+const (
+	ChannelLogs    = "logs"
+	ChannelEffRuns = "eff_runs"
+)
+
+var ErrTooManySubscriptions = errors.New("превышено максимальное количество подписок")
+
+// Event описывает событие, которое рассылается подписчикам
+type Event struct {
+	Channel string
+	BotID   string
+	Status  string
+	Payload interface{}
+}
+
+// Filter ограничивает набор событий, попадающих в подписку.
+// Пустые списки означают «без ограничений».
+type Filter struct {
+	BotIDs   []string
+	Statuses []string
+}
+
+// Message — исходящее сообщение протокола подписок
+type Message struct {
+	Type         string          `json:"type"`
+	ID           string          `json:"id,omitempty"`
+	Subscription string          `json:"subscription,omitempty"`
+	Channel      string          `json:"channel,omitempty"`
+	Data         json.RawMessage `json:"data,omitempty"`
+	Error        string          `json:"error,omitempty"`
+}
+
+type subscription struct {
+	channel  string
+	botIDs   map[string]struct{}
+	statuses map[string]struct{}
+}
+
+func newSubscription(channel string, filter Filter) *subscription {
+	sub := &subscription{channel: channel}
+	if len(filter.BotIDs) > 0 {
+		sub.botIDs = make(map[string]struct{}, len(filter.BotIDs))
+		for _, id := range filter.BotIDs {
+			sub.botIDs[id] = struct{}{}
+		}
+	}
+	if len(filter.Statuses) > 0 {
+		sub.statuses = make(map[string]struct{}, len(filter.Statuses))
+		for _, status := range filter.Statuses {
+			sub.statuses[status] = struct{}{}
+		}
+	}
+	return sub
+}
+
+func (s *subscription) match(event Event) bool {
+	if s.channel != event.Channel {
+		return false
+	}
+	if s.botIDs != nil {
+		if _, ok := s.botIDs[event.BotID]; !ok {
+			return false
+		}
+	}
+	if s.statuses != nil {
+		if _, ok := s.statuses[event.Status]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Client — подписчик хаба (одно WebSocket соединение).
+// Исходящие сообщения складываются в ограниченный буфер; если клиент
+// не успевает их забирать, хаб отключает его вместо бесконечной буферизации.
+type Client struct {
+	hub     *Hub
+	send    chan []byte
+	done    chan struct{}
+	maxSubs int
+
+	mu   sync.RWMutex
+	subs map[string]*subscription
+
+	closeOnce sync.Once
+	dropped   bool
+}
+
+// Send возвращает канал исходящих сообщений клиента
+func (c *Client) Send() <-chan []byte {
+	return c.send
+}
+
+// Done закрывается, когда клиент отключён от хаба
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Dropped сообщает, был ли клиент отключён из-за переполнения буфера
+func (c *Client) Dropped() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.dropped
+}
+
+// Subscribe добавляет или заменяет подписку с указанным ID
+func (c *Client) Subscribe(id, channel string, filter Filter) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.subs[id]; !exists && len(c.subs) >= c.maxSubs {
+		return ErrTooManySubscriptions
+	}
+	c.subs[id] = newSubscription(channel, filter)
+	return nil
+}
+
+// Unsubscribe удаляет подписку, возвращает false если её не было
+func (c *Client) Unsubscribe(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.subs[id]; !exists {
+		return false
+	}
+	delete(c.subs, id)
+	return true
+}
+
+// Enqueue ставит сообщение в очередь без блокировки.
+// При переполнении буфера клиент отключается.
+func (c *Client) Enqueue(msg Message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("Не удалось сериализовать сообщение подписки: %v", err)
+		return
+	}
+	c.enqueueRaw(data)
+}
+
+func (c *Client) enqueueRaw(data []byte) {
+	select {
+	case <-c.done:
+		return
+	default:
+	}
+
+	select {
+	case c.send <- data:
+	default:
+		c.mu.Lock()
+		c.dropped = true
+		c.mu.Unlock()
+		c.Close()
+	}
+}
+
+func (c *Client) matchingSubscriptions(event Event) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var ids []string
+	for id, sub := range c.subs {
+		if sub.match(event) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Close отключает клиента от хаба
+func (c *Client) Close() {
+	c.closeOnce.Do(func() {
+		c.hub.unregister(c)
+		close(c.done)
+	})
+}
+
+// Hub рассылает события логов и запусков подписанным клиентам
+type Hub struct {
+	mu      sync.RWMutex
+	clients map[*Client]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		clients: make(map[*Client]struct{}),
+	}
+}
+
+// NewClient регистрирует нового подписчика с буфером sendBuffer сообщений
+func (h *Hub) NewClient(sendBuffer, maxSubscriptions int) *Client {
+	client := &Client{
+		hub:     h,
+		send:    make(chan []byte, sendBuffer),
+		done:    make(chan struct{}),
+		maxSubs: maxSubscriptions,
+		subs:    make(map[string]*subscription),
+	}
+
+	h.mu.Lock()
+	h.clients[client] = struct{}{}
+	h.mu.Unlock()
+
+	return client
+}
+
+func (h *Hub) unregister(client *Client) {
+	h.mu.Lock()
+	delete(h.clients, client)
+	h.mu.Unlock()
+}
+
+// Publish рассылает событие всем клиентам с подходящими подписками.
+// Никогда не блокируется на медленных клиентах.
+func (h *Hub) Publish(event Event) {
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.clients))
+	for client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	if len(clients) == 0 {
+		return
+	}
+
+	var payload json.RawMessage
+	for _, client := range clients {
+		ids := client.matchingSubscriptions(event)
+		if len(ids) == 0 {
+			continue
+		}
+
+		if payload == nil {
+			data, err := json.Marshal(event.Payload)
+			if err != nil {
+				log.Printf("Не удалось сериализовать событие %s: %v", event.Channel, err)
+				return
+			}
+			payload = data
+		}
+
+		for _, id := range ids {
+			client.Enqueue(Message{
+				Type:         "event",
+				Subscription: id,
+				Channel:      event.Channel,
+				Data:         payload,
+			})
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package syslogservice
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"time"
+)
+
+// tcpIdleTimeout — время, после которого молчащее TCP-соединение закрывается
+const tcpIdleTimeout = 10 * time.Minute
+
+func (s *SyslogService) listen(ctx context.Context) error {
+	var closers []io.Closer
+	closeAll := func() {
+		for _, closer := range closers {
+			closer.Close()
+		}
+	}
+
+	if s.config.UDPAddr != "" {
+		conn, err := net.ListenPacket("udp", s.config.UDPAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen syslog udp %s: %w", s.config.UDPAddr, err)
+		}
+		closers = append(closers, conn)
+		go s.serveUDP(conn)
+		log.Printf("Syslog UDP слушает %s", s.config.UDPAddr)
+	}
+
+	if s.config.TCPAddr != "" {
+		listener, err := net.Listen("tcp", s.config.TCPAddr)
+		if err != nil {
+			closeAll()
+			return fmt.Errorf("failed to listen syslog tcp %s: %w", s.config.TCPAddr, err)
+		}
+		closers = append(closers, listener)
+		go s.serveTCP(listener)
+		log.Printf("Syslog TCP слушает %s", s.config.TCPAddr)
+	}
+
+	if s.config.TLSAddr != "" {
+		cert, err := tls.LoadX509KeyPair(s.config.TLSCertFile, s.config.TLSKeyFile)
+		if err != nil {
+			closeAll()
+			return fmt.Errorf("failed to load syslog tls certificate: %w", err)
+		}
+		listener, err := tls.Listen("tcp", s.config.TLSAddr, &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		})
+		if err != nil {
+			closeAll()
+			return fmt.Errorf("failed to listen syslog tls %s: %w", s.config.TLSAddr, err)
+		}
+		closers = append(closers, listener)
+		go s.serveTCP(listener)
+		log.Printf("Syslog TLS слушает %s", s.config.TLSAddr)
+	}
+
+	go func() {
+		<-ctx.Done()
+		closeAll()
+	}()
+
+	return nil
+}
+
+func (s *SyslogService) serveUDP(conn net.PacketConn) {
+	buf := make([]byte, s.config.MaxMessageBytes)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			log.Printf("Ошибка чтения syslog udp: %v", err)
+			continue
+		}
+		s.Handle(buf[:n], addr.String())
+	}
+}
+
+func (s *SyslogService) serveTCP(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			log.Printf("Ошибка приёма syslog-соединения: %v", err)
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn читает поток сообщений с поддержкой обоих вариантов кадрирования RFC 6587:
+// octet counting ("<длина> <сообщение>") и разделение переводом строки
+func (s *SyslogService) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	remoteAddr := conn.RemoteAddr().String()
+	reader := bufio.NewReaderSize(conn, 64<<10)
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(tcpIdleTimeout))
+
+		first, err := reader.Peek(1)
+		if err != nil {
+			return
+		}
+
+		var frame []byte
+		if first[0] >= '0' && first[0] <= '9' {
+			frame, err = s.readOctetCounted(reader)
+		} else {
+			frame, err = s.readLine(reader)
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Printf("Ошибка чтения syslog от %s: %v", remoteAddr, err)
+			}
+			return
+		}
+
+		if len(bytes.TrimSpace(frame)) > 0 {
+			s.Handle(frame, remoteAddr)
+		}
+	}
+}
+
+// readOctetCounted читает кадр "<длина> <сообщение>". Префикс длины читается побайтно и не длиннее,
+// чем запись max_message_bytes, чтобы поток цифр без пробела не копился в памяти
+func (s *SyslogService) readOctetCounted(reader *bufio.Reader) ([]byte, error) {
+	maxPrefix := len(strconv.Itoa(s.config.MaxMessageBytes))
+
+	var prefix []byte
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b == ' ' {
+			break
+		}
+		prefix = append(prefix, b)
+		if len(prefix) > maxPrefix {
+			return nil, fmt.Errorf("префикс длины кадра длиннее %d символов", maxPrefix)
+		}
+	}
+
+	length, err := strconv.Atoi(string(prefix))
+	if err != nil || length <= 0 || length > s.config.MaxMessageBytes {
+		return nil, fmt.Errorf("некорректная длина кадра %q", prefix)
+	}
+
+	frame := make([]byte, length)
+	if _, err := io.ReadFull(reader, frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+func (s *SyslogService) readLine(reader *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := reader.ReadLine()
+		if err != nil {
+			if len(line) > 0 && errors.Is(err, io.EOF) {
+				return line, nil
+			}
+			return nil, err
+		}
+		if len(line)+len(chunk) > s.config.MaxMessageBytes {
+			return nil, fmt.Errorf("сообщение длиннее %d байт", s.config.MaxMessageBytes)
+		}
+		line = append(line, chunk...)
+		if !isPrefix {
+			return line, nil
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package syslogservice
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"logging_api/configs"
+	"logging_api/internal/models"
+)
+
+type stubLogService struct{}
+
+func (stubLogService) CreateLogs(entries []*models.Log) ([]models.WriteResult, error) {
+	return make([]models.WriteResult, len(entries)), nil
+}
+
+func (stubLogService) ResolveBotID(code string) *string {
+	return nil
+}
+
+func TestServeConnFraming(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		want   []string
+	}{
+		{name: "newline", stream: "<13>one\n<13>two\n", want: []string{"one", "two"}},
+		{name: "crlf", stream: "<13>one\r\n<13>two\r\n", want: []string{"one", "two"}},
+		{name: "last line without newline", stream: "<13>one\n<13>two", want: []string{"one", "two"}},
+		{name: "blank lines", stream: "\n\n<13>one\n\n", want: []string{"one"}},
+		{name: "octet counting", stream: "7 <13>one7 <13>two", want: []string{"one", "two"}},
+		{name: "octet counted frame with newline", stream: "15 <13>line1\nline2", want: []string{"line1\nline2"}},
+		{name: "mixed framing", stream: "7 <13>one<13>two\n10 <13>три", want: []string{"one", "two", "три"}},
+		{name: "invalid message skipped", stream: "<13>one\nnot syslog\n<13>two\n", want: []string{"one", "two"}},
+		{name: "frame longer than limit", stream: "7 <13>one100 <13>two", want: []string{"one"}},
+		{name: "line longer than limit", stream: "<13>one\n<13>" + strings.Repeat("x", 64) + "\n<13>two\n", want: []string{"one"}},
+		{name: "invalid frame length", stream: "7x <13>one\n", want: nil},
+		{name: "zero frame length", stream: "0 <13>one\n", want: nil},
+		{name: "truncated frame", stream: "7 <13>one20 <13>short", want: []string{"one"}},
+		{name: "length prefix without space", stream: "7 <13>one" + strings.Repeat("1", 1000), want: []string{"one"}},
+		{name: "length prefix longer than limit", stream: "007 <13>one", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSyslogService(stubLogService{}, configs.SyslogConfig{MaxMessageBytes: 64, QueueSize: 16})
+
+			client, server := net.Pipe()
+			go func() {
+				client.Write([]byte(tt.stream))
+				client.Close()
+			}()
+			s.serveConn(server)
+
+			var got []string
+			for len(s.queue) > 0 {
+				got = append(got, (<-s.queue).Msg)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("messages = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
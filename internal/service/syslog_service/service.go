@@ -0,0 +1,187 @@
+package syslogservice
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"logging_api/configs"
+	"logging_api/internal/models"
+	"logging_api/pkg/syslog"
+)
+
+type LogService interface {
+	CreateLogs(entries []*models.Log) ([]models.WriteResult, error)
+	ResolveBotID(code string) *string
+}
+
+// SyslogService принимает syslog по UDP/TCP/TLS и пишет сообщения в logs через LogService.
+// Сообщения копятся в очереди и сохраняются пачками; при переполнении очереди новые сообщения отбрасываются.
+type SyslogService struct {
+	logService LogService
+	config     configs.SyslogConfig
+	queue      chan *models.Log
+	dropped    atomic.Int64
+	invalid    atomic.Int64
+	rejected   atomic.Int64
+	// done закрывается, когда writeLoop сохранил последнюю пачку
+	done chan struct{}
+}
+
+func NewSyslogService(logService LogService, config configs.SyslogConfig) *SyslogService {
+	return &SyslogService{
+		logService: logService,
+		config:     config,
+		queue:      make(chan *models.Log, config.QueueSize),
+	}
+}
+
+// Start открывает настроенные слушатели и запускает запись в базу. Слушатели закрываются при отмене ctx,
+// оставшиеся в очереди сообщения сохраняются (дождаться этого можно через Close).
+func (s *SyslogService) Start(ctx context.Context) error {
+	if err := s.listen(ctx); err != nil {
+		return err
+	}
+
+	s.done = make(chan struct{})
+	go s.writeLoop(ctx)
+	return nil
+}
+
+// Close дожидается сохранения сообщений, оставшихся в очереди после отмены ctx, переданного в Start.
+// Вызывается до закрытия LogService, чтобы последняя пачка успела попасть в его очередь.
+func (s *SyslogService) Close(ctx context.Context) error {
+	if s.done == nil {
+		return nil
+	}
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("очередь syslog-сообщений не сохранена до конца (осталось %d): %w", len(s.queue), ctx.Err())
+	}
+}
+
+// Handle разбирает одно сообщение и ставит его в очередь на запись
+func (s *SyslogService) Handle(data []byte, remoteAddr string) {
+	msg, err := syslog.Parse(data, time.Now())
+	if err != nil {
+		if s.invalid.Add(1)%1000 == 1 {
+			log.Printf("Некорректное syslog-сообщение от %s: %v (всего: %d)", remoteAddr, err, s.invalid.Load())
+		}
+		return
+	}
+
+	entry := &models.Log{
+		BotID:      s.resolveBot(msg),
+		Status:     syslog.Level(msg.Severity),
+		Msg:        msg.Message,
+		Attributes: attributes(msg, remoteAddr),
+	}
+	if entry.Msg == "" {
+		entry.Msg = "-"
+	}
+	if msg.Timestamp != nil {
+		entry.CreatedAt = *msg.Timestamp
+	}
+
+	select {
+	case s.queue <- entry:
+	default:
+		if s.dropped.Add(1)%1000 == 1 {
+			log.Printf("Очередь syslog переполнена, сообщения отбрасываются (всего: %d)", s.dropped.Load())
+		}
+	}
+}
+
+// resolveBot ищет бота по таблице сопоставлений, затем по default_bot_code
+func (s *SyslogService) resolveBot(msg *syslog.Message) *string {
+	for _, mapping := range s.config.Mappings {
+		if mapping.Hostname == "" && mapping.AppName == "" {
+			continue
+		}
+		if mapping.Hostname != "" && !strings.EqualFold(mapping.Hostname, msg.Hostname) {
+			continue
+		}
+		if mapping.AppName != "" && !strings.EqualFold(mapping.AppName, msg.AppName) {
+			continue
+		}
+		return s.logService.ResolveBotID(mapping.BotCode)
+	}
+
+	return s.logService.ResolveBotID(s.config.DefaultBotCode)
+}
+
+func attributes(msg *syslog.Message, remoteAddr string) models.JSONB {
+	result := models.JSONB{
+		"syslog.facility": msg.Facility,
+		"syslog.severity": msg.Severity,
+		"syslog.remote":   remoteAddr,
+	}
+	if msg.Hostname != "" {
+		result["syslog.hostname"] = msg.Hostname
+	}
+	if msg.AppName != "" {
+		result["syslog.app_name"] = msg.AppName
+	}
+	if msg.ProcID != "" {
+		result["syslog.proc_id"] = msg.ProcID
+	}
+	if msg.MsgID != "" {
+		result["syslog.msg_id"] = msg.MsgID
+	}
+	if len(msg.StructuredData) > 0 {
+		result["syslog.structured_data"] = msg.StructuredData
+	}
+	return result
+}
+
+func (s *SyslogService) writeLoop(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(time.Duration(s.config.FlushIntervalMs) * time.Millisecond)
+	defer ticker.Stop()
+
+	batch := make([]*models.Log, 0, s.config.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		results, err := s.logService.CreateLogs(batch)
+		if err != nil {
+			log.Printf("Ошибка сохранения syslog-сообщений (%d шт.): %v", len(batch), err)
+		}
+		for _, result := range results {
+			if result == models.WriteRejected && s.rejected.Add(1)%1000 == 1 {
+				log.Printf("Syslog-сообщения со временем вне допустимого диапазона отбрасываются (всего: %d)", s.rejected.Load())
+			}
+		}
+		batch = make([]*models.Log, 0, s.config.BatchSize)
+	}
+
+	for {
+		select {
+		case entry := <-s.queue:
+			batch = append(batch, entry)
+			if len(batch) >= s.config.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-ctx.Done():
+			for {
+				select {
+				case entry := <-s.queue:
+					batch = append(batch, entry)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
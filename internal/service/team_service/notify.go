@@ -0,0 +1,75 @@
+package teamservice
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"logging_api/internal/models"
+	"slices"
+)
+
+// NotifyHealthChanges отправляет на webhook команд переходы их ботов в состояния из настроек команды:
+// одно уведомление на команду за расчёт. Отправка идёт в фоне; ошибки пишутся в лог.
+func (s *TeamService) NotifyHealthChanges(changes []*models.HealthChange) {
+	byTeam := make(map[string][]*models.HealthChange)
+	var teamIDs []string
+	for _, change := range changes {
+		if change.TeamID == nil {
+			continue
+		}
+		if _, ok := byTeam[*change.TeamID]; !ok {
+			teamIDs = append(teamIDs, *change.TeamID)
+		}
+		byTeam[*change.TeamID] = append(byTeam[*change.TeamID], change)
+	}
+	if len(teamIDs) == 0 {
+		return
+	}
+
+	teams, err := s.teamRepo.GetTeamsByIDs(teamIDs)
+	if err != nil {
+		log.Printf("Ошибка получения настроек уведомлений команд: %v", err)
+		return
+	}
+
+	for _, team := range teams {
+		if team.Notifications.WebhookURL == nil {
+			continue
+		}
+
+		notification := &models.TeamNotification{TeamID: team.ID, TeamName: team.Name}
+		for _, change := range byTeam[team.ID] {
+			if slices.Contains(team.Notifications.States, change.To) {
+				notification.Changes = append(notification.Changes, change)
+			}
+		}
+		if len(notification.Changes) == 0 {
+			continue
+		}
+
+		go func(url string) {
+			if err := s.send(url, notification); err != nil {
+				log.Printf("Ошибка отправки уведомления команде %s: %v", notification.TeamName, err)
+			}
+		}(*team.Notifications.WebhookURL)
+	}
+}
+
+func (s *TeamService) send(url string, notification *models.TeamNotification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("ошибка кодирования уведомления: %w", err)
+	}
+
+	resp, err := s.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ошибка запроса к webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook ответил статусом %d", resp.StatusCode)
+	}
+	return nil
+}
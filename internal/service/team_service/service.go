@@ -0,0 +1,184 @@
+package teamservice
+
+import (
+	"database/sql"
+	"fmt"
+	"logging_api/configs"
+	"logging_api/internal/models"
+	customerrors "logging_api/internal/utils/errors"
+	"net/http"
+	"time"
+)
+
+type TeamRepoInterface interface {
+	CreateTeam(name string, description *string, notifications models.TeamNotifications) (string, error)
+	GetTeamByID(teamID string) (*models.Team, error)
+	GetTeamByName(name string) (*models.Team, error)
+	ListTeams(filter *models.TeamFilter) ([]*models.Team, error)
+	GetTeamsByIDs(teamIDs []string) ([]*models.Team, error)
+	UpdateTeam(teamID string, name, description *string) error
+	SetNotifications(teamID string, notifications models.TeamNotifications) error
+	DeleteTeam(teamID string) error
+	ListMembers(teamID string) ([]*models.TeamMember, error)
+	SetMember(teamID, ownerID, role string) (*models.TeamMember, error)
+	RemoveMember(teamID, ownerID string) error
+}
+
+type OwnerRepoInterface interface {
+	GetOwnerByID(ownerID string) (*models.Owner, error)
+}
+
+type TeamService struct {
+	teamRepo  TeamRepoInterface
+	ownerRepo OwnerRepoInterface
+	client    *http.Client
+}
+
+func NewTeamService(teamRepo TeamRepoInterface, ownerRepo OwnerRepoInterface, config configs.TeamsConfig) *TeamService {
+	return &TeamService{
+		teamRepo:  teamRepo,
+		ownerRepo: ownerRepo,
+		client:    &http.Client{Timeout: time.Duration(config.WebhookTimeoutSec) * time.Second},
+	}
+}
+
+// CreateTeam создаёт команду; имя команды уникально
+func (s *TeamService) CreateTeam(name string, description *string, notifications models.TeamNotifications) (*models.Team, error) {
+	if err := s.checkNameFree(name, ""); err != nil {
+		return nil, err
+	}
+
+	teamID, err := s.teamRepo.CreateTeam(name, description, notifications)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания команды: %w", err)
+	}
+
+	return s.GetTeamByID(teamID)
+}
+
+// GetTeamByID возвращает команду вместе с её участниками
+func (s *TeamService) GetTeamByID(teamID string) (*models.Team, error) {
+	team, err := s.teamRepo.GetTeamByID(teamID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: команда не найдена", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка получения команды: %w", err)
+	}
+
+	members, err := s.teamRepo.ListMembers(teamID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения участников команды: %w", err)
+	}
+	team.Members = members
+	if team.Members == nil {
+		team.Members = []*models.TeamMember{}
+	}
+
+	return team, nil
+}
+
+func (s *TeamService) ListTeams(filter *models.TeamFilter) ([]*models.Team, error) {
+	teams, err := s.teamRepo.ListTeams(filter)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения списка команд: %w", err)
+	}
+	if teams == nil {
+		teams = []*models.Team{}
+	}
+	return teams, nil
+}
+
+// UpdateTeam меняет имя и описание команды (nil — без изменений)
+func (s *TeamService) UpdateTeam(teamID string, name, description *string) (*models.Team, error) {
+	if name != nil {
+		if err := s.checkNameFree(*name, teamID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.teamRepo.UpdateTeam(teamID, name, description); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: команда не найдена", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка обновления команды: %w", err)
+	}
+
+	return s.GetTeamByID(teamID)
+}
+
+// SetNotifications заменяет настройки уведомлений команды
+func (s *TeamService) SetNotifications(teamID string, notifications models.TeamNotifications) (*models.Team, error) {
+	if err := s.teamRepo.SetNotifications(teamID, notifications); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: команда не найдена", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка сохранения настроек уведомлений: %w", err)
+	}
+
+	return s.GetTeamByID(teamID)
+}
+
+// DeleteTeam удаляет команду: её боты остаются без команды, токены команды удаляются
+func (s *TeamService) DeleteTeam(teamID string) error {
+	if err := s.teamRepo.DeleteTeam(teamID); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: команда не найдена", customerrors.ErrNotFound)
+		}
+		return fmt.Errorf("ошибка удаления команды: %w", err)
+	}
+	return nil
+}
+
+// SetMember добавляет владельца в команду с ролью role или меняет его роль.
+// Владельца в архиве добавить нельзя.
+func (s *TeamService) SetMember(teamID, ownerID, role string) (*models.TeamMember, error) {
+	if _, err := s.teamRepo.GetTeamByID(teamID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: команда не найдена", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка получения команды: %w", err)
+	}
+
+	owner, err := s.ownerRepo.GetOwnerByID(ownerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: владелец не найден", customerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка получения владельца: %w", err)
+	}
+	if owner.ArchivedAt != nil {
+		return nil, fmt.Errorf("%w: владелец в архиве", customerrors.ErrConflict)
+	}
+
+	member, err := s.teamRepo.SetMember(teamID, ownerID, role)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка добавления владельца в команду: %w", err)
+	}
+	return member, nil
+}
+
+func (s *TeamService) RemoveMember(teamID, ownerID string) error {
+	if err := s.teamRepo.RemoveMember(teamID, ownerID); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: владелец не состоит в команде", customerrors.ErrNotFound)
+		}
+		return fmt.Errorf("ошибка исключения владельца из команды: %w", err)
+	}
+	return nil
+}
+
+// checkNameFree возвращает ErrConflict, если имя занято другой командой (не exceptID)
+func (s *TeamService) checkNameFree(name, exceptID string) error {
+	existing, err := s.teamRepo.GetTeamByName(name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return fmt.Errorf("ошибка проверки имени команды: %w", err)
+	}
+	if existing.ID != exceptID {
+		return fmt.Errorf("%w: команда с именем %q уже существует", customerrors.ErrConflict, name)
+	}
+	return nil
+}
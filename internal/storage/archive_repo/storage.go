@@ -0,0 +1,188 @@
+package archiverepo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"logging_api/internal/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type ArchiveRepo struct {
+	db *sql.DB
+}
+
+func NewArchiveRepo(db *sql.DB) *ArchiveRepo {
+	return &ArchiveRepo{db: db}
+}
+
+// GetDaysBefore возвращает дни (UTC), за которые есть логи уровня status старше before
+func (r *ArchiveRepo) GetDaysBefore(status string, before time.Time) ([]time.Time, error) {
+	query := `
+		SELECT DISTINCT date_trunc('day', created_at AT TIME ZONE 'UTC')
+		FROM logs
+		WHERE status = $1 AND created_at < $2
+		ORDER BY 1
+	`
+
+	rows, err := r.db.Query(query, status, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get days: %w", err)
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			return nil, fmt.Errorf("failed to scan day: %w", err)
+		}
+		days = append(days, time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return days, nil
+}
+
+// StreamLogs построчно передаёт в fn логи уровня status за [from, to), упорядоченные по боту и id
+func (r *ArchiveRepo) StreamLogs(status string, from, to time.Time, fn func(*models.Log) error) error {
+	query := `
+		SELECT id, bot_id, status, msg, attributes, version, created_at, received_at
+		FROM logs
+		WHERE status = $1 AND created_at >= $2 AND created_at < $3
+		ORDER BY bot_id NULLS FIRST, id
+	`
+
+	rows, err := r.db.Query(query, status, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to get logs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var log models.Log
+		err := rows.Scan(
+			&log.ID,
+			&log.BotID,
+			&log.Status,
+			&log.Msg,
+			&log.Attributes,
+			&log.Version,
+			&log.CreatedAt,
+			&log.ReceivedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan log: %w", err)
+		}
+		if err := fn(&log); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// DeleteLogs удаляет логи уровня status за [from, to) одним запросом
+func (r *ArchiveRepo) DeleteLogs(status string, from, to time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM logs WHERE status = $1 AND created_at >= $2 AND created_at < $3`, status, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete logs: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *ArchiveRepo) CreateImportTable(table string) error {
+	query := fmt.Sprintf(`
+		CREATE TABLE %s (
+			id BIGINT NOT NULL,
+			bot_id UUID,
+			status log_status NOT NULL,
+			msg TEXT NOT NULL,
+			attributes JSONB,
+			version VARCHAR(100),
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			received_at TIMESTAMP WITH TIME ZONE
+		)
+	`, pq.QuoteIdentifier(table))
+
+	if _, err := r.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create import table: %w", err)
+	}
+
+	_, err := r.db.Exec(fmt.Sprintf(`COMMENT ON TABLE %s IS %s`,
+		pq.QuoteIdentifier(table),
+		pq.QuoteLiteral("Временная таблица с логами, загруженными из архива для расследования"),
+	))
+	return err
+}
+
+// ImportLogs загружает логи во временную таблицу через COPY
+func (r *ArchiveRepo) ImportLogs(table string, logs []*models.Log) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(pq.CopyIn(table, "id", "bot_id", "status", "msg", "attributes", "version", "created_at", "received_at"))
+	if err != nil {
+		return fmt.Errorf("failed to prepare copy: %w", err)
+	}
+
+	for _, log := range logs {
+		// COPY кодирует []byte как bytea, поэтому JSONB передаётся строкой
+		var attributes interface{}
+		if log.Attributes != nil {
+			data, err := json.Marshal(log.Attributes)
+			if err != nil {
+				stmt.Close()
+				return fmt.Errorf("failed to encode log attributes: %w", err)
+			}
+			attributes = string(data)
+		}
+
+		// В архивах, выгруженных до появления received_at, поля нет
+		var receivedAt interface{}
+		if !log.ReceivedAt.IsZero() {
+			receivedAt = log.ReceivedAt
+		}
+
+		if _, err := stmt.Exec(log.ID, log.BotID, log.Status, log.Msg, attributes, log.Version, log.CreatedAt, receivedAt); err != nil {
+			stmt.Close()
+			return fmt.Errorf("failed to copy log: %w", err)
+		}
+	}
+
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return fmt.Errorf("failed to flush copy: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return fmt.Errorf("failed to close copy: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// DropImportTable удаляет временную таблицу, возвращает sql.ErrNoRows если её нет
+func (r *ArchiveRepo) DropImportTable(table string) error {
+	var exists bool
+	if err := r.db.QueryRow(`SELECT to_regclass($1) IS NOT NULL`, table).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check import table: %w", err)
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	_, err := r.db.Exec(fmt.Sprintf(`DROP TABLE %s`, pq.QuoteIdentifier(table)))
+	if err != nil {
+		return fmt.Errorf("failed to drop import table: %w", err)
+	}
+	return nil
+}
@@ -16,17 +16,19 @@ func NewAuthRepo(db *sql.DB) *AuthRepo {
 	}
 }
 
-func (r *AuthRepo) CreateToken(botID *string, name string, isAdmin bool) (*models.Token, error) {
+func (r *AuthRepo) CreateToken(botID, teamID, ownerID *string, name string, isAdmin bool) (*models.Token, error) {
 	query := `
-		INSERT INTO tokens (bot_id, name, is_active, is_admin)
-		VALUES ($1, $2, true, $3)
-		RETURNING id, bot_id, name, is_active, is_admin, created_at
+		INSERT INTO tokens (bot_id, team_id, owner_id, name, is_active, is_admin)
+		VALUES ($1, $2, $3, $4, true, $5)
+		RETURNING id, bot_id, team_id, owner_id, name, is_active, is_admin, created_at
 	`
 
 	var token models.Token
-	err := r.db.QueryRow(query, botID, name, isAdmin).Scan(
+	err := r.db.QueryRow(query, botID, teamID, ownerID, name, isAdmin).Scan(
 		&token.ID,
 		&token.BotID,
+		&token.TeamID,
+		&token.OwnerID,
 		&token.Name,
 		&token.IsActive,
 		&token.IsAdmin,
@@ -41,7 +43,7 @@ func (r *AuthRepo) CreateToken(botID *string, name string, isAdmin bool) (*model
 
 func (r *AuthRepo) GetTokenByID(tokenID string) (*models.Token, error) {
 	query := `
-		SELECT id, bot_id, name, is_active, is_admin, created_at
+		SELECT id, bot_id, team_id, owner_id, name, is_active, is_admin, created_at
 		FROM tokens
 		WHERE id = $1
 	`
@@ -50,6 +52,8 @@ func (r *AuthRepo) GetTokenByID(tokenID string) (*models.Token, error) {
 	err := r.db.QueryRow(query, tokenID).Scan(
 		&token.ID,
 		&token.BotID,
+		&token.TeamID,
+		&token.OwnerID,
 		&token.Name,
 		&token.IsActive,
 		&token.IsAdmin,
@@ -62,34 +66,67 @@ func (r *AuthRepo) GetTokenByID(tokenID string) (*models.Token, error) {
 	return &token, nil
 }
 
-func (r *AuthRepo) GetTokenWithOwner(tokenID string) (token *models.Token, ownerID string, err error) {
+// GetTokenWithOwner возвращает токен, владельца его бота, признак того, что бот токена в архиве,
+// и признак того, что в архиве владелец токена (для токенов владельцев)
+func (r *AuthRepo) GetTokenWithOwner(tokenID string) (token *models.Token, ownerID string, botArchived, ownerArchived bool, err error) {
 	query := `
-		SELECT t.id, t.bot_id, t.name, t.is_active, t.is_admin, t.created_at, b.owner_id
+		SELECT t.id, t.bot_id, t.team_id, t.owner_id, t.name, t.is_active, t.is_admin, t.created_at,
+			b.owner_id, b.archived_at IS NOT NULL, o.archived_at IS NOT NULL
 		FROM tokens t
 		LEFT JOIN bots b ON t.bot_id = b.id
+		LEFT JOIN owners o ON t.owner_id = o.id
 		WHERE t.id = $1
 	`
 
 	var token2 models.Token
 	var ownerIDPtr *string
+	var archived, ownerArchivedNull sql.NullBool
 	err = r.db.QueryRow(query, tokenID).Scan(
 		&token2.ID,
 		&token2.BotID,
+		&token2.TeamID,
+		&token2.OwnerID,
 		&token2.Name,
 		&token2.IsActive,
 		&token2.IsAdmin,
 		&token2.CreatedAt,
 		&ownerIDPtr,
+		&archived,
+		&ownerArchivedNull,
 	)
 	if err != nil {
-		return nil, "", err
+		return nil, "", false, false, err
 	}
 
 	if ownerIDPtr != nil {
 		ownerID = *ownerIDPtr
 	}
 
-	return &token2, ownerID, nil
+	return &token2, ownerID, archived.Bool, ownerArchivedNull.Bool, nil
+}
+
+// GetTeamBotIDs возвращает ID ботов команды не в архиве
+func (r *AuthRepo) GetTeamBotIDs(teamID string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT id FROM bots WHERE team_id = $1 AND archived_at IS NULL`, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team bots: %w", err)
+	}
+	defer rows.Close()
+
+	var botIDs []string
+	for rows.Next() {
+		var botID string
+		if err := rows.Scan(&botID); err != nil {
+			return nil, fmt.Errorf("failed to scan team bot: %w", err)
+		}
+		botIDs = append(botIDs, botID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return botIDs, nil
 }
 
 func (r *AuthRepo) UpdateToken(tokenID, newName string) (*models.Token, error) {
@@ -97,13 +134,15 @@ func (r *AuthRepo) UpdateToken(tokenID, newName string) (*models.Token, error) {
 		UPDATE tokens
 		SET name = $2
 		WHERE id = $1
-		RETURNING id, bot_id, name, is_active, is_admin, created_at
+		RETURNING id, bot_id, team_id, owner_id, name, is_active, is_admin, created_at
 	`
 
 	var token models.Token
 	err := r.db.QueryRow(query, tokenID, newName).Scan(
 		&token.ID,
 		&token.BotID,
+		&token.TeamID,
+		&token.OwnerID,
 		&token.Name,
 		&token.IsActive,
 		&token.IsAdmin,
@@ -0,0 +1,144 @@
+package botreferencerepo
+
+import (
+	"database/sql"
+	"fmt"
+	"logging_api/internal/models"
+)
+
+// botColumns — колонка bots, ссылающаяся на справочник
+var botColumns = map[string]string{
+	models.BotReferenceTypes:     "bot_type",
+	models.BotReferenceLanguages: "language",
+}
+
+type BotReferenceRepo struct {
+	db *sql.DB
+}
+
+func NewBotReferenceRepo(db *sql.DB) *BotReferenceRepo {
+	return &BotReferenceRepo{db: db}
+}
+
+// referenceSelect выбирает значения справочника kind с числом ботов, которые их используют
+func referenceSelect(kind string) (string, error) {
+	column, ok := botColumns[kind]
+	if !ok {
+		return "", fmt.Errorf("unknown bot reference: %s", kind)
+	}
+	return fmt.Sprintf(`
+		SELECT r.code, r.description, r.created_at, r.updated_at,
+			(SELECT COUNT(*) FROM bots b WHERE b.%s = r.code)
+		FROM %s r
+	`, column, kind), nil
+}
+
+func scanReference(row interface{ Scan(...interface{}) error }) (*models.BotReference, error) {
+	var reference models.BotReference
+	err := row.Scan(
+		&reference.Code,
+		&reference.Description,
+		&reference.CreatedAt,
+		&reference.UpdatedAt,
+		&reference.BotCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &reference, nil
+}
+
+// ListReferences возвращает значения справочника, упорядоченные по коду
+func (r *BotReferenceRepo) ListReferences(kind string) ([]*models.BotReference, error) {
+	query, err := referenceSelect(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(query + ` ORDER BY r.code`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", kind, err)
+	}
+	defer rows.Close()
+
+	var references []*models.BotReference
+	for rows.Next() {
+		reference, err := scanReference(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", kind, err)
+		}
+		references = append(references, reference)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return references, nil
+}
+
+// GetReference возвращает значение справочника, sql.ErrNoRows если его нет
+func (r *BotReferenceRepo) GetReference(kind, code string) (*models.BotReference, error) {
+	query, err := referenceSelect(kind)
+	if err != nil {
+		return nil, err
+	}
+	return scanReference(r.db.QueryRow(query+` WHERE r.code = $1`, code))
+}
+
+func (r *BotReferenceRepo) CreateReference(kind, code string, description *string) error {
+	if _, ok := botColumns[kind]; !ok {
+		return fmt.Errorf("unknown bot reference: %s", kind)
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (code, description) VALUES ($1, $2)`, kind)
+	if _, err := r.db.Exec(query, code, description); err != nil {
+		return fmt.Errorf("failed to create %s value: %w", kind, err)
+	}
+	return nil
+}
+
+// UpdateReference меняет код и описание значения (nil — без изменений); новый код переносится на ботов.
+// Возвращает sql.ErrNoRows если значения нет.
+func (r *BotReferenceRepo) UpdateReference(kind, code string, newCode, description *string) error {
+	if _, ok := botColumns[kind]; !ok {
+		return fmt.Errorf("unknown bot reference: %s", kind)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET
+			code = COALESCE($2, code),
+			description = COALESCE($3, description),
+			updated_at = NOW()
+		WHERE code = $1
+	`, kind)
+
+	return execAffected(r.db, query, code, newCode, description)
+}
+
+// DeleteReference удаляет значение справочника, возвращает sql.ErrNoRows если его нет.
+// Значение, которое используют боты, удалить нельзя (внешний ключ bots).
+func (r *BotReferenceRepo) DeleteReference(kind, code string) error {
+	if _, ok := botColumns[kind]; !ok {
+		return fmt.Errorf("unknown bot reference: %s", kind)
+	}
+	return execAffected(r.db, fmt.Sprintf(`DELETE FROM %s WHERE code = $1`, kind), code)
+}
+
+func execAffected(db *sql.DB, query string, args ...interface{}) error {
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"fmt"
 	"logging_api/internal/models"
+	"logging_api/pkg/postgres"
+	"strings"
 
 	"github.com/lib/pq"
 )
@@ -20,7 +22,7 @@ func NewBotRepo(db *sql.DB) *BotRepo {
 
 func (r *BotRepo) GetBotByID(botID string) (*models.Bot, error) {
 	query := `
-		SELECT id, code, name, bot_type, language, description, tags, owner_id, is_active, created_at, updated_at
+		SELECT id, code, name, bot_type, language, description, tags, owner_id, team_id, is_active, created_at, updated_at, archived_at, archived_by
 		FROM bots
 		WHERE id = $1
 	`
@@ -35,9 +37,12 @@ func (r *BotRepo) GetBotByID(botID string) (*models.Bot, error) {
 		&bot.Description,
 		pq.Array(&bot.Tags),
 		&bot.OwnerID,
+		&bot.TeamID,
 		&bot.IsActive,
 		&bot.CreatedAt,
 		&bot.UpdatedAt,
+		&bot.ArchivedAt,
+		&bot.ArchivedBy,
 	)
 	if err != nil {
 		return nil, err
@@ -48,7 +53,7 @@ func (r *BotRepo) GetBotByID(botID string) (*models.Bot, error) {
 
 func (r *BotRepo) GetBotByCode(code string) (*models.Bot, error) {
 	query := `
-		SELECT id, code, name, bot_type, language, description, tags, owner_id, is_active, created_at, updated_at
+		SELECT id, code, name, bot_type, language, description, tags, owner_id, team_id, is_active, created_at, updated_at, archived_at, archived_by
 		FROM bots
 		WHERE code = $1
 	`
@@ -63,9 +68,12 @@ func (r *BotRepo) GetBotByCode(code string) (*models.Bot, error) {
 		&bot.Description,
 		pq.Array(&bot.Tags),
 		&bot.OwnerID,
+		&bot.TeamID,
 		&bot.IsActive,
 		&bot.CreatedAt,
 		&bot.UpdatedAt,
+		&bot.ArchivedAt,
+		&bot.ArchivedBy,
 	)
 	if err != nil {
 		return nil, err
@@ -76,8 +84,8 @@ func (r *BotRepo) GetBotByCode(code string) (*models.Bot, error) {
 
 func (r *BotRepo) CreateBot(bot *models.Bot) (*models.Bot, error) {
 	query := `
-		INSERT INTO bots (code, name, bot_type, language, description, tags, owner_id, is_active)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO bots (code, name, bot_type, language, description, tags, owner_id, team_id, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id, created_at, updated_at
 	`
 
@@ -90,6 +98,7 @@ func (r *BotRepo) CreateBot(bot *models.Bot) (*models.Bot, error) {
 		bot.Description,
 		pq.Array(bot.Tags),
 		bot.OwnerID,
+		bot.TeamID,
 		bot.IsActive,
 	).Scan(&bot.ID, &bot.CreatedAt, &bot.UpdatedAt)
 
@@ -100,22 +109,39 @@ func (r *BotRepo) CreateBot(bot *models.Bot) (*models.Bot, error) {
 	return bot, nil
 }
 
-func (r *BotRepo) UpdateBot(bot *models.Bot) (*models.Bot, error) {
+// UpdateBot обновляет данные бота одной транзакцией. Если задан bot.OwnerID и он отличается от текущего,
+// бот передаётся этому владельцу с записью в историю передачи (changedBy — кто передал); nil — владелец не меняется.
+// Для несуществующего бота — sql.ErrNoRows.
+func (r *BotRepo) UpdateBot(bot *models.Bot, changedBy *string) (*models.Bot, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if bot.OwnerID != nil {
+		if _, err := transferBot(tx, bot.ID, bot.OwnerID, changedBy, nil); err != nil {
+			return nil, err
+		}
+	}
+
 	query := `
 		UPDATE bots
-		SET name = $2, bot_type = $3, language = $4, description = $5, tags = $6, is_active = $7, updated_at = NOW()
+		SET code = $2, name = $3, bot_type = $4, language = $5, description = $6, tags = $7, team_id = $8, is_active = $9, updated_at = NOW()
 		WHERE id = $1
 		RETURNING updated_at
 	`
 
-	err := r.db.QueryRow(
+	err = tx.QueryRow(
 		query,
 		bot.ID,
+		bot.Code,
 		bot.Name,
 		bot.BotType,
 		bot.Language,
 		bot.Description,
 		pq.Array(bot.Tags),
+		bot.TeamID,
 		bot.IsActive,
 	).Scan(&bot.UpdatedAt)
 
@@ -123,32 +149,249 @@ func (r *BotRepo) UpdateBot(bot *models.Bot) (*models.Bot, error) {
 		return nil, err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return bot, nil
 }
 
-func (r *BotRepo) DeleteBot(botID string) error {
-	query := `DELETE FROM bots WHERE id = $1`
+// SetBotTeam включает бота в команду teamID (nil — исключить из команды), возвращает sql.ErrNoRows если бота нет
+func (r *BotRepo) SetBotTeam(botID string, teamID *string) error {
+	result, err := r.db.Exec(`UPDATE bots SET team_id = $2, updated_at = NOW() WHERE id = $1`, botID, teamID)
+	if err != nil {
+		return fmt.Errorf("failed to set bot team: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// TransferBot передаёт бота владельцу toOwnerID (nil — снять владельца) и записывает передачу в историю.
+// Возвращает false, если бот уже принадлежит этому владельцу; для несуществующего бота — sql.ErrNoRows.
+func (r *BotRepo) TransferBot(botID string, toOwnerID, changedBy, reason *string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	transferred, err := transferBot(tx, botID, toOwnerID, changedBy, reason)
+	if err != nil || !transferred {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// transferBot меняет владельца бота в транзакции tx и записывает передачу в историю; строка бота блокируется
+// до конца транзакции. Возвращает false, если бот уже принадлежит этому владельцу.
+func transferBot(tx *sql.Tx, botID string, toOwnerID, changedBy, reason *string) (bool, error) {
+	var fromOwnerID *string
+	if err := tx.QueryRow(`SELECT owner_id FROM bots WHERE id = $1 FOR UPDATE`, botID).Scan(&fromOwnerID); err != nil {
+		return false, err
+	}
+	if (fromOwnerID == nil && toOwnerID == nil) || (fromOwnerID != nil && toOwnerID != nil && *fromOwnerID == *toOwnerID) {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(`UPDATE bots SET owner_id = $2, updated_at = NOW() WHERE id = $1`, botID, toOwnerID); err != nil {
+		return false, fmt.Errorf("failed to update bot owner: %w", err)
+	}
+
+	insertQuery := `
+		INSERT INTO bot_owner_history (bot_id, from_owner_id, to_owner_id, changed_by, reason)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := tx.Exec(insertQuery, botID, fromOwnerID, toOwnerID, changedBy, reason); err != nil {
+		return false, fmt.Errorf("failed to insert owner history: %w", err)
+	}
+
+	return true, nil
+}
+
+// ListOwnerHistory возвращает историю передачи бота, новые записи первыми
+func (r *BotRepo) ListOwnerHistory(botID string) ([]*models.BotOwnerChange, error) {
+	query := `
+		SELECT id, bot_id, from_owner_id, to_owner_id, changed_at, changed_by, reason
+		FROM bot_owner_history
+		WHERE bot_id = $1
+		ORDER BY changed_at DESC, id DESC
+	`
+
+	rows, err := r.db.Query(query, botID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get owner history: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*models.BotOwnerChange
+	for rows.Next() {
+		var change models.BotOwnerChange
+		err := rows.Scan(
+			&change.ID,
+			&change.BotID,
+			&change.FromOwnerID,
+			&change.ToOwnerID,
+			&change.ChangedAt,
+			&change.ChangedBy,
+			&change.Reason,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan owner history: %w", err)
+		}
+		changes = append(changes, &change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return changes, nil
+}
+
+// ArchiveBot переносит бота в архив; для бота, уже находящегося в архиве, или несуществующего возвращает sql.ErrNoRows
+func (r *BotRepo) ArchiveBot(botID string, archivedBy *string) error {
+	query := `
+		UPDATE bots
+		SET archived_at = NOW(), archived_by = $2, updated_at = NOW()
+		WHERE id = $1 AND archived_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, botID, archivedBy)
+	if err != nil {
+		return fmt.Errorf("failed to archive bot: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// RestoreBot возвращает бота из архива; для бота не в архиве или несуществующего возвращает sql.ErrNoRows
+func (r *BotRepo) RestoreBot(botID string) error {
+	query := `
+		UPDATE bots
+		SET archived_at = NULL, archived_by = NULL, updated_at = NOW()
+		WHERE id = $1 AND archived_at IS NOT NULL
+	`
 
 	result, err := r.db.Exec(query, botID)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to restore bot: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("bot not found")
+		return sql.ErrNoRows
 	}
 
 	return nil
 }
 
+// botPurgeCountsQuery считает записи, которых касается удаление бота: удаляемые каскадно и логи, у которых обнулится bot_id
+const botPurgeCountsQuery = `
+	SELECT
+		(SELECT COUNT(*) FROM tokens WHERE bot_id = $1),
+		(SELECT COUNT(*) FROM eff_runs WHERE bot_id = $1),
+		(SELECT COUNT(*) FROM redaction_rules WHERE bot_id = $1),
+		(SELECT COUNT(*) FROM redaction_counts WHERE bot_id = $1),
+		(SELECT COUNT(*) FROM log_policies WHERE bot_id = $1),
+		(SELECT COUNT(*) FROM log_drop_counts WHERE bot_id = $1),
+		(SELECT COUNT(*) FROM bot_owner_history WHERE bot_id = $1),
+		(SELECT COUNT(*) FROM deployments WHERE bot_id = $1),
+		(SELECT COUNT(*) FROM host_bots WHERE bot_id = $1),
+		(SELECT COUNT(*) FROM bot_health_states WHERE bot_id = $1),
+		(SELECT COUNT(*) FROM logs WHERE bot_id = $1)
+`
+
+func scanBotPurgeCounts(row *sql.Row, report *models.BotPurgeReport) error {
+	return row.Scan(
+		&report.Tokens,
+		&report.EffRuns,
+		&report.RedactionRules,
+		&report.RedactionCounts,
+		&report.LogPolicies,
+		&report.LogDropCounts,
+		&report.OwnerHistory,
+		&report.Deployments,
+		&report.HostBots,
+		&report.HealthStates,
+		&report.LogsDetached,
+	)
+}
+
+// GetBotPurgeReport возвращает отчёт о том, что будет удалено вместе с ботом
+func (r *BotRepo) GetBotPurgeReport(botID string) (*models.BotPurgeReport, error) {
+	report := &models.BotPurgeReport{BotID: botID}
+	if err := r.db.QueryRow(`SELECT code FROM bots WHERE id = $1`, botID).Scan(&report.Code); err != nil {
+		return nil, err
+	}
+
+	if err := scanBotPurgeCounts(r.db.QueryRow(botPurgeCountsQuery, botID), report); err != nil {
+		return nil, fmt.Errorf("failed to count bot references: %w", err)
+	}
+
+	return report, nil
+}
+
+// PurgeBot окончательно удаляет бота из архива и возвращает отчёт об удалённом.
+// Строка бота блокируется до конца транзакции, поэтому новые записи со ссылкой на него не появятся между подсчётом и удалением.
+// Для бота не в архиве или несуществующего возвращает sql.ErrNoRows.
+func (r *BotRepo) PurgeBot(botID string) (*models.BotPurgeReport, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	report := &models.BotPurgeReport{BotID: botID}
+	lockQuery := `SELECT code FROM bots WHERE id = $1 AND archived_at IS NOT NULL FOR UPDATE`
+	if err := tx.QueryRow(lockQuery, botID).Scan(&report.Code); err != nil {
+		return nil, err
+	}
+
+	if err := scanBotPurgeCounts(tx.QueryRow(botPurgeCountsQuery, botID), report); err != nil {
+		return nil, fmt.Errorf("failed to count bot references: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM bots WHERE id = $1`, botID); err != nil {
+		return nil, fmt.Errorf("failed to delete bot: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	report.Purged = true
+	return report, nil
+}
+
 func (r *BotRepo) GetBotsByOwner(ownerID string) ([]*models.Bot, error) {
 	query := `
-		SELECT id, code, name, bot_type, language, description, tags, owner_id, is_active, created_at, updated_at
+		SELECT id, code, name, bot_type, language, description, tags, owner_id, team_id, is_active, created_at, updated_at, archived_at, archived_by
 		FROM bots
 		WHERE owner_id = $1
 		ORDER BY created_at DESC
@@ -172,9 +415,12 @@ func (r *BotRepo) GetBotsByOwner(ownerID string) ([]*models.Bot, error) {
 			&bot.Description,
 			pq.Array(&bot.Tags),
 			&bot.OwnerID,
+			&bot.TeamID,
 			&bot.IsActive,
 			&bot.CreatedAt,
 			&bot.UpdatedAt,
+			&bot.ArchivedAt,
+			&bot.ArchivedBy,
 		)
 		if err != nil {
 			return nil, err
@@ -185,16 +431,91 @@ func (r *BotRepo) GetBotsByOwner(ownerID string) ([]*models.Bot, error) {
 	return bots, nil
 }
 
-func (r *BotRepo) GetAllBots() ([]*models.Bot, error) {
-	query := `
-		SELECT id, code, name, bot_type, language, description, tags, owner_id, is_active, created_at, updated_at
-		FROM bots
-		ORDER BY created_at DESC
-	`
+// botSortColumns — допустимые поля сортировки ботов
+var botSortColumns = map[string]string{
+	"created_at": "b.created_at",
+	"updated_at": "b.updated_at",
+	"code":       "b.code",
+	"name":       "b.name",
+}
+
+// ListBots возвращает страницу ботов по фильтру (на одну запись больше Limit — признак следующей страницы;
+// Limit = 0 — все боты) и общее число ботов, подходящих под фильтр
+func (r *BotRepo) ListBots(filter *models.BotFilter) ([]*models.Bot, int, error) {
+	var args queryArgs
+	var conditions []string
+	if filter.BotType != nil {
+		conditions = append(conditions, "b.bot_type = "+args.add(*filter.BotType))
+	}
+	if filter.Language != nil {
+		conditions = append(conditions, "b.language = "+args.add(*filter.Language))
+	}
+	if filter.OwnerID != nil {
+		conditions = append(conditions, "b.owner_id = "+args.add(*filter.OwnerID))
+	}
+	if filter.TeamID != nil {
+		conditions = append(conditions, "b.team_id = "+args.add(*filter.TeamID))
+	}
+	if filter.IsActive != nil {
+		conditions = append(conditions, "b.is_active = "+args.add(*filter.IsActive))
+	}
+	if len(filter.Tags) > 0 {
+		if filter.TagsAll {
+			conditions = append(conditions, "b.tags @> "+args.add(pq.Array(filter.Tags))+"::text[]")
+		} else {
+			conditions = append(conditions, "b.tags && "+args.add(pq.Array(filter.Tags))+"::text[]")
+		}
+	}
+	if filter.Query != "" {
+		pattern := args.add(postgres.ContainsPattern(filter.Query))
+		conditions = append(conditions, fmt.Sprintf("(b.code ILIKE %[1]s OR b.name ILIKE %[1]s OR b.description ILIKE %[1]s)", pattern))
+	}
+	if filter.Archived == models.ArchivedOnly {
+		conditions = append(conditions, "b.archived_at IS NOT NULL")
+	} else if filter.Archived != models.ArchivedInclude {
+		conditions = append(conditions, "b.archived_at IS NULL")
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM bots b ` + whereClause(conditions)
+	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count bots: %w", err)
+	}
 
-	rows, err := r.db.Query(query)
+	column, ok := botSortColumns[filter.SortBy]
+	if !ok {
+		column = botSortColumns["created_at"]
+	}
+	direction, compare := "ASC", ">"
+	if filter.Order == "desc" {
+		direction, compare = "DESC", "<"
+	}
+
+	if filter.AfterID != nil {
+		var after interface{}
+		if filter.AfterTime != nil {
+			after = *filter.AfterTime
+		} else if filter.AfterValue != nil {
+			after = *filter.AfterValue
+		}
+		if after != nil {
+			conditions = append(conditions, fmt.Sprintf("(%s, b.id) %s (%s, %s::uuid)", column, compare, args.add(after), args.add(*filter.AfterID)))
+		}
+	}
+
+	query := fmt.Sprintf(`
+		SELECT b.id, b.code, b.name, b.bot_type, b.language, b.description, b.tags, b.owner_id, b.team_id, b.is_active, b.created_at, b.updated_at, b.archived_at, b.archived_by
+		FROM bots b
+		%s
+		ORDER BY %s %s, b.id %s
+	`, whereClause(conditions), column, direction, direction)
+	if filter.Limit > 0 {
+		query += ` LIMIT ` + args.add(filter.Limit+1)
+	}
+
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, fmt.Errorf("failed to get bots: %w", err)
 	}
 	defer rows.Close()
 
@@ -210,17 +531,39 @@ func (r *BotRepo) GetAllBots() ([]*models.Bot, error) {
 			&bot.Description,
 			pq.Array(&bot.Tags),
 			&bot.OwnerID,
+			&bot.TeamID,
 			&bot.IsActive,
 			&bot.CreatedAt,
 			&bot.UpdatedAt,
+			&bot.ArchivedAt,
+			&bot.ArchivedBy,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, fmt.Errorf("failed to scan bot: %w", err)
 		}
 		bots = append(bots, &bot)
 	}
 
-	return bots, nil
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return bots, total, nil
+}
+
+// queryArgs накапливает аргументы запроса и возвращает их плейсхолдеры
+type queryArgs []interface{}
+
+func (a *queryArgs) add(value interface{}) string {
+	*a = append(*a, value)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(conditions, " AND ")
 }
 
 func (r *BotRepo) GetBotCodeByID(botID string) (string, error) {
@@ -246,3 +589,22 @@ func (r *BotRepo) GetBotCodeAndNameByID(botID string) (code string, name string,
 
 	return code, name, nil
 }
+
+// GetBotIDByCode возвращает ID бота по коду без учёта ботов в архиве; при нескольких ботах с одним кодом
+// предпочитает активного и более нового
+func (r *BotRepo) GetBotIDByCode(code string) (string, error) {
+	query := `
+		SELECT id FROM bots
+		WHERE code = $1 AND archived_at IS NULL
+		ORDER BY is_active DESC, created_at DESC
+		LIMIT 1
+	`
+
+	var botID string
+	err := r.db.QueryRow(query, code).Scan(&botID)
+	if err != nil {
+		return "", err
+	}
+
+	return botID, nil
+}
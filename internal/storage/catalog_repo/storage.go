@@ -0,0 +1,310 @@
+package catalogrepo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"logging_api/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type CatalogRepo struct {
+	db *sql.DB
+}
+
+func NewCatalogRepo(db *sql.DB) *CatalogRepo {
+	return &CatalogRepo{db: db}
+}
+
+// querier — общее у *sql.DB и *sql.Tx, чтобы состояние каталога читалось и внутри транзакции импорта
+type querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// GetCatalogState возвращает владельцев и ботов не в архиве, политики хранения логов этих ботов, команды
+// и справочники типов и языков ботов
+func (r *CatalogRepo) GetCatalogState() (*models.CatalogState, error) {
+	return getCatalogState(r.db)
+}
+
+func getCatalogState(q querier) (*models.CatalogState, error) {
+	state := &models.CatalogState{Policies: make(map[string]*models.LogPolicy)}
+
+	ownerRows, err := q.Query(`
+		SELECT id, full_name, is_active, created_at
+		FROM owners
+		WHERE archived_at IS NULL
+		ORDER BY full_name, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get owners: %w", err)
+	}
+	defer ownerRows.Close()
+
+	for ownerRows.Next() {
+		var owner models.Owner
+		if err := ownerRows.Scan(&owner.ID, &owner.FullName, &owner.IsActive, &owner.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan owner: %w", err)
+		}
+		state.Owners = append(state.Owners, &owner)
+	}
+	if err := ownerRows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating owners: %w", err)
+	}
+
+	botRows, err := q.Query(`
+		SELECT id, code, name, bot_type, language, description, tags, owner_id, team_id, is_active, created_at, updated_at
+		FROM bots
+		WHERE archived_at IS NULL
+		ORDER BY code, created_at
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bots: %w", err)
+	}
+	defer botRows.Close()
+
+	for botRows.Next() {
+		var bot models.Bot
+		err := botRows.Scan(
+			&bot.ID,
+			&bot.Code,
+			&bot.Name,
+			&bot.BotType,
+			&bot.Language,
+			&bot.Description,
+			pq.Array(&bot.Tags),
+			&bot.OwnerID,
+			&bot.TeamID,
+			&bot.IsActive,
+			&bot.CreatedAt,
+			&bot.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bot: %w", err)
+		}
+		state.Bots = append(state.Bots, &bot)
+	}
+	if err := botRows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating bots: %w", err)
+	}
+
+	policyRows, err := q.Query(`
+		SELECT p.bot_id, p.min_level, p.sample_rates
+		FROM log_policies p
+		JOIN bots b ON b.id = p.bot_id
+		WHERE b.archived_at IS NULL
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log policies: %w", err)
+	}
+	defer policyRows.Close()
+
+	for policyRows.Next() {
+		var policy models.LogPolicy
+		var rates []byte
+		if err := policyRows.Scan(&policy.BotID, &policy.MinLevel, &rates); err != nil {
+			return nil, fmt.Errorf("failed to scan log policy: %w", err)
+		}
+		if err := json.Unmarshal(rates, &policy.SampleRates); err != nil {
+			return nil, fmt.Errorf("failed to decode sample rates: %w", err)
+		}
+		state.Policies[policy.BotID] = &policy
+	}
+	if err := policyRows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating log policies: %w", err)
+	}
+
+	teamRows, err := q.Query(`SELECT id, name FROM teams ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teams: %w", err)
+	}
+	defer teamRows.Close()
+
+	for teamRows.Next() {
+		var team models.Team
+		if err := teamRows.Scan(&team.ID, &team.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan team: %w", err)
+		}
+		state.Teams = append(state.Teams, &team)
+	}
+	if err := teamRows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating teams: %w", err)
+	}
+
+	if state.BotTypes, err = listCodes(q, `SELECT code FROM bot_types ORDER BY code`); err != nil {
+		return nil, fmt.Errorf("failed to get bot types: %w", err)
+	}
+	if state.BotLanguages, err = listCodes(q, `SELECT code FROM bot_languages ORDER BY code`); err != nil {
+		return nil, fmt.Errorf("failed to get bot languages: %w", err)
+	}
+
+	return state, nil
+}
+
+// listCodes возвращает коды справочника
+func listCodes(q querier, query string) ([]string, error) {
+	rows, err := q.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var codes []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, rows.Err()
+}
+
+// ImportCatalog выполняет импорт одной транзакцией: блокирует владельцев, ботов и политики хранения от изменений
+// другими запросами, читает текущее состояние каталога, строит по нему план (build) и применяет его.
+// Если build вернул ошибку или nil-план (пробный запуск, нет изменений), транзакция откатывается.
+// changedBy — ID админского токена, выполняющего импорт.
+func (r *CatalogRepo) ImportCatalog(build func(state *models.CatalogState) (*models.CatalogPlan, error), changedBy *string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// SHARE ROW EXCLUSIVE не мешает чтению и вставке логов (внешние ключи берут только блокировки строк),
+	// но не даёт другим транзакциям создавать и менять ботов и владельцев, пока план строится и применяется
+	if _, err := tx.Exec(`LOCK TABLE owners, bots, log_policies IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("failed to lock catalog tables: %w", err)
+	}
+
+	state, err := getCatalogState(tx)
+	if err != nil {
+		return err
+	}
+
+	plan, err := build(state)
+	if err != nil || plan == nil {
+		return err
+	}
+
+	if err := applyCatalogPlan(tx, plan, changedBy); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// applyCatalogPlan применяет план импорта: создаёт и обновляет владельцев, создаёт и обновляет ботов
+// (смена владельца записывается в историю передачи), сохраняет политики хранения и переносит в архив ботов из ArchiveBots
+func applyCatalogPlan(tx *sql.Tx, plan *models.CatalogPlan, changedBy *string) error {
+	ownerIDs := make(map[string]string, len(plan.OwnerIDs)+len(plan.CreateOwners))
+	for name, id := range plan.OwnerIDs {
+		ownerIDs[name] = id
+	}
+
+	for _, owner := range plan.CreateOwners {
+		var id string
+		query := `INSERT INTO owners (full_name, is_active, created_at) VALUES ($1, $2, NOW()) RETURNING id`
+		if err := tx.QueryRow(query, owner.FullName, owner.IsActive == nil || *owner.IsActive).Scan(&id); err != nil {
+			return fmt.Errorf("failed to create owner %q: %w", owner.FullName, err)
+		}
+		ownerIDs[owner.FullName] = id
+	}
+
+	for _, owner := range plan.UpdateOwners {
+		if _, err := tx.Exec(`UPDATE owners SET is_active = $2 WHERE id = $1`, owner.ID, owner.IsActive); err != nil {
+			return fmt.Errorf("failed to update owner %q: %w", owner.FullName, err)
+		}
+	}
+
+	for _, op := range plan.Bots {
+		bot := op.Bot
+		var ownerID *string
+		if bot.Owner != nil {
+			id, ok := ownerIDs[*bot.Owner]
+			if !ok {
+				return fmt.Errorf("owner %q of bot %q is not resolved", *bot.Owner, bot.Code)
+			}
+			ownerID = &id
+		}
+		isActive := bot.IsActive == nil || *bot.IsActive
+
+		botID := op.BotID
+		if botID == "" {
+			query := `
+				INSERT INTO bots (code, name, bot_type, language, description, tags, owner_id, team_id, is_active)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+				RETURNING id
+			`
+			err := tx.QueryRow(query, bot.Code, bot.Name, bot.BotType, bot.Language, bot.Description, pq.Array(bot.Tags), ownerID, op.TeamID, isActive).Scan(&botID)
+			if err != nil {
+				return fmt.Errorf("failed to create bot %q: %w", bot.Code, err)
+			}
+		} else {
+			query := `
+				UPDATE bots
+				SET name = $2, bot_type = $3, language = $4, description = $5, tags = $6, owner_id = $7, team_id = $8, is_active = $9, updated_at = NOW()
+				WHERE id = $1
+			`
+			_, err := tx.Exec(query, botID, bot.Name, bot.BotType, bot.Language, bot.Description, pq.Array(bot.Tags), ownerID, op.TeamID, isActive)
+			if err != nil {
+				return fmt.Errorf("failed to update bot %q: %w", bot.Code, err)
+			}
+
+			if op.OwnerChanged {
+				historyQuery := `
+					INSERT INTO bot_owner_history (bot_id, from_owner_id, to_owner_id, changed_by, reason)
+					VALUES ($1, $2, $3, $4, 'импорт каталога')
+				`
+				if _, err := tx.Exec(historyQuery, botID, op.FromOwnerID, ownerID, changedBy); err != nil {
+					return fmt.Errorf("failed to insert owner history of bot %q: %w", bot.Code, err)
+				}
+			}
+		}
+
+		if op.PolicyChanged {
+			var minLevel *string
+			rates := map[string]float64{}
+			if bot.LogPolicy != nil {
+				minLevel = bot.LogPolicy.MinLevel
+				if bot.LogPolicy.SampleRates != nil {
+					rates = bot.LogPolicy.SampleRates
+				}
+			}
+			encoded, err := json.Marshal(rates)
+			if err != nil {
+				return fmt.Errorf("failed to encode sample rates of bot %q: %w", bot.Code, err)
+			}
+
+			query := `
+				INSERT INTO log_policies (bot_id, min_level, sample_rates, updated_at)
+				VALUES ($1, $2::log_status, $3::jsonb, NOW())
+				ON CONFLICT (bot_id) DO UPDATE SET
+					min_level = EXCLUDED.min_level,
+					sample_rates = EXCLUDED.sample_rates,
+					updated_at = NOW()
+			`
+			if _, err := tx.Exec(query, botID, minLevel, string(encoded)); err != nil {
+				return fmt.Errorf("failed to set log policy of bot %q: %w", bot.Code, err)
+			}
+		}
+	}
+
+	if len(plan.ArchiveBots) > 0 {
+		query := `
+			UPDATE bots
+			SET archived_at = NOW(), archived_by = $2, updated_at = NOW()
+			WHERE id = ANY($1::uuid[]) AND archived_at IS NULL
+		`
+		if _, err := tx.Exec(query, pq.Array(plan.ArchiveBots), changedBy); err != nil {
+			return fmt.Errorf("failed to archive bots: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,259 @@
+package deploymentrepo
+
+import (
+	"database/sql"
+	"fmt"
+	"logging_api/internal/models"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type DeploymentRepo struct {
+	db *sql.DB
+}
+
+func NewDeploymentRepo(db *sql.DB) *DeploymentRepo {
+	return &DeploymentRepo{db: db}
+}
+
+const deploymentColumns = `d.id, d.bot_id, d.version, d.git_sha, d.host, d.changelog, d.deployed_at, d.created_at`
+
+func scanDeployment(row interface{ Scan(...interface{}) error }) (*models.Deployment, error) {
+	var deployment models.Deployment
+	err := row.Scan(
+		&deployment.ID,
+		&deployment.BotID,
+		&deployment.Version,
+		&deployment.GitSHA,
+		&deployment.Host,
+		&deployment.Changelog,
+		&deployment.DeployedAt,
+		&deployment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+// CreateDeployment сохраняет выкладку; deployedAt = nil — время приёма
+func (r *DeploymentRepo) CreateDeployment(botID, version string, gitSHA, host, changelog *string, deployedAt *time.Time) (*models.Deployment, error) {
+	query := `
+		INSERT INTO deployments AS d (bot_id, version, git_sha, host, changelog, deployed_at)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6, NOW()))
+		RETURNING ` + deploymentColumns
+
+	deployment, err := scanDeployment(r.db.QueryRow(query, botID, version, gitSHA, host, changelog, deployedAt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deployment: %w", err)
+	}
+
+	return deployment, nil
+}
+
+// queryArgs накапливает аргументы запроса и возвращает их плейсхолдеры
+type queryArgs []interface{}
+
+func (a *queryArgs) add(value interface{}) string {
+	*a = append(*a, value)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(conditions, " AND ")
+}
+
+// ListDeployments возвращает выкладки по фильтру (новые первыми). Limit = 0 — без ограничения,
+// иначе не более Limit+1 записей, чтобы определить наличие следующей страницы.
+func (r *DeploymentRepo) ListDeployments(filter *models.DeploymentFilter) ([]*models.Deployment, error) {
+	var args queryArgs
+	var conditions []string
+	if filter.BotID != nil {
+		conditions = append(conditions, "d.bot_id = "+args.add(*filter.BotID))
+	}
+	if filter.TeamID != nil {
+		conditions = append(conditions, "d.bot_id IN (SELECT id FROM bots WHERE team_id = "+args.add(*filter.TeamID)+")")
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "d.deployed_at >= "+args.add(*filter.From))
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "d.deployed_at < "+args.add(*filter.To))
+	}
+	if filter.AfterDeployedAt != nil && filter.AfterID != nil {
+		conditions = append(conditions, fmt.Sprintf("(d.deployed_at, d.id) < (%s, %s::uuid)", args.add(*filter.AfterDeployedAt), args.add(*filter.AfterID)))
+	}
+
+	query := `SELECT ` + deploymentColumns + ` FROM deployments d ` + whereClause(conditions) + ` ORDER BY d.deployed_at DESC, d.id DESC`
+	if filter.Limit > 0 {
+		query += ` LIMIT ` + args.add(filter.Limit+1)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployments: %w", err)
+	}
+	defer rows.Close()
+
+	var deployments []*models.Deployment
+	for rows.Next() {
+		deployment, err := scanDeployment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deployment: %w", err)
+		}
+		deployments = append(deployments, deployment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return deployments, nil
+}
+
+// ListVersionHistory возвращает выкладки ботов не раньше since и последнюю выкладку каждого бота до since
+// (версию, действовавшую на since), упорядоченные по боту и времени выкладки
+func (r *DeploymentRepo) ListVersionHistory(since time.Time) ([]*models.Deployment, error) {
+	query := `
+		SELECT * FROM (
+			SELECT ` + deploymentColumns + `
+			FROM deployments d
+			WHERE d.deployed_at >= $1
+			UNION ALL
+			(
+				SELECT DISTINCT ON (d.bot_id) ` + deploymentColumns + `
+				FROM deployments d
+				WHERE d.deployed_at < $1
+				ORDER BY d.bot_id, d.deployed_at DESC, d.created_at DESC
+			)
+		) d
+		ORDER BY d.bot_id, d.deployed_at, d.created_at
+	`
+
+	rows, err := r.db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version history: %w", err)
+	}
+	defer rows.Close()
+
+	var deployments []*models.Deployment
+	for rows.Next() {
+		deployment, err := scanDeployment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deployment: %w", err)
+		}
+		deployments = append(deployments, deployment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return deployments, nil
+}
+
+// GetVersionStats возвращает показатели limit последних выложенных версий бота (по последней выкладке версии,
+// новые первыми): число запусков по статусам и число логов, в том числе Error и Critical
+func (r *DeploymentRepo) GetVersionStats(botID string, limit int) ([]*models.VersionStats, error) {
+	versionsQuery := `
+		SELECT version, MIN(deployed_at), MAX(deployed_at), COUNT(*)
+		FROM deployments
+		WHERE bot_id = $1
+		GROUP BY version
+		ORDER BY MAX(deployed_at) DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(versionsQuery, botID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get versions: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*models.VersionStats
+	byVersion := make(map[string]*models.VersionStats)
+	var versions []string
+	var since time.Time
+	for rows.Next() {
+		var item models.VersionStats
+		if err := rows.Scan(&item.Version, &item.FirstDeployedAt, &item.LastDeployedAt, &item.Deployments); err != nil {
+			return nil, fmt.Errorf("failed to scan version: %w", err)
+		}
+		stats = append(stats, &item)
+		byVersion[item.Version] = &item
+		versions = append(versions, item.Version)
+		if since.IsZero() || item.FirstDeployedAt.Before(since) {
+			since = item.FirstDeployedAt
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+	if len(stats) == 0 {
+		return stats, nil
+	}
+
+	// Записи с версией не старше её первой выкладки: created_at ограничивает просматриваемые секции logs
+	runsQuery := `
+		SELECT version,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'success'),
+			COUNT(*) FILTER (WHERE status = 'warning'),
+			COUNT(*) FILTER (WHERE status = 'error')
+		FROM eff_runs
+		WHERE bot_id = $1 AND version = ANY($2) AND created_at >= $3
+		GROUP BY version
+	`
+	runRows, err := r.db.Query(runsQuery, botID, pq.Array(versions), since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version runs: %w", err)
+	}
+	defer runRows.Close()
+
+	for runRows.Next() {
+		var version string
+		var total, success, warning, failed int64
+		if err := runRows.Scan(&version, &total, &success, &warning, &failed); err != nil {
+			return nil, fmt.Errorf("failed to scan version runs: %w", err)
+		}
+		if item, ok := byVersion[version]; ok {
+			item.Runs, item.RunsSuccess, item.RunsWarning, item.RunsError = total, success, warning, failed
+		}
+	}
+	if err = runRows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	logsQuery := `
+		SELECT version, COUNT(*), COUNT(*) FILTER (WHERE status IN ('Error', 'Critical'))
+		FROM logs
+		WHERE bot_id = $1 AND version = ANY($2) AND created_at >= $3
+		GROUP BY version
+	`
+	logRows, err := r.db.Query(logsQuery, botID, pq.Array(versions), since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version logs: %w", err)
+	}
+	defer logRows.Close()
+
+	for logRows.Next() {
+		var version string
+		var total, errors int64
+		if err := logRows.Scan(&version, &total, &errors); err != nil {
+			return nil, fmt.Errorf("failed to scan version logs: %w", err)
+		}
+		if item, ok := byVersion[version]; ok {
+			item.Logs, item.ErrorLogs = total, errors
+		}
+	}
+	if err = logRows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return stats, nil
+}
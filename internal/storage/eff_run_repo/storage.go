@@ -4,7 +4,13 @@ import (
 	"database/sql"
 	"fmt"
 	"logging_api/internal/models"
+	idempotencyrepo "logging_api/internal/storage/idempotency_repo"
+	"logging_api/pkg/postgres"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type EffRunRepo struct {
@@ -15,15 +21,15 @@ func NewEffRunRepo(db *sql.DB) *EffRunRepo {
 	return &EffRunRepo{db: db}
 }
 
-func (r *EffRunRepo) CreateEffRun(botID string, periodFrom, periodTo *time.Time, status string, host *string, extra models.JSONB) (*models.EffRun, error) {
+func (r *EffRunRepo) CreateEffRun(botID string, periodFrom, periodTo *time.Time, status string, host *string, extra models.JSONB, version *string) (*models.EffRun, error) {
 	query := `
-		INSERT INTO eff_runs (bot_id, period_from, period_to, status, host, extra, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, NOW())
-		RETURNING id, bot_id, period_from, period_to, status, host, extra, created_at
+		INSERT INTO eff_runs (bot_id, period_from, period_to, status, host, extra, version, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		RETURNING id, bot_id, period_from, period_to, status, host, extra, version, created_at
 	`
 
 	var effRun models.EffRun
-	err := r.db.QueryRow(query, botID, periodFrom, periodTo, status, host, extra).Scan(
+	err := r.db.QueryRow(query, botID, periodFrom, periodTo, status, host, extra, version).Scan(
 		&effRun.ID,
 		&effRun.BotID,
 		&effRun.PeriodFrom,
@@ -31,6 +37,7 @@ func (r *EffRunRepo) CreateEffRun(botID string, periodFrom, periodTo *time.Time,
 		&effRun.Status,
 		&effRun.Host,
 		&effRun.Extra,
+		&effRun.Version,
 		&effRun.CreatedAt,
 	)
 	if err != nil {
@@ -40,3 +47,308 @@ func (r *EffRunRepo) CreateEffRun(botID string, periodFrom, periodTo *time.Time,
 	return &effRun, nil
 }
 
+// createEffRunsChunk — максимальное число строк в одном INSERT пакетной вставки
+const createEffRunsChunk = 1000
+
+// CreateEffRuns сохраняет пачку запусков и заполняет у записей id и created_at.
+//
+// keys — ключи идемпотентности по записям (nil или nil-элементы — без ключа). Для записи с уже занятым ключом
+// новая строка не создаётся: запись заменяется ранее сохранённым запуском. Возвращает признак создания по каждой записи.
+func (r *EffRunRepo) CreateEffRuns(effRuns []*models.EffRun, keys []*models.IdempotencyKey) ([]bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	created := make([]bool, len(effRuns))
+	insert := make([]int, 0, len(effRuns))
+	replays := map[int]string{}
+	duplicates := map[int]int{}
+
+	if keys == nil {
+		for i := range effRuns {
+			insert = append(insert, i)
+		}
+	} else {
+		first := map[models.IdempotencyKey]int{}
+		for i, key := range keys {
+			if key == nil {
+				insert = append(insert, i)
+				continue
+			}
+
+			// Повтор ключа внутри одной пачки — та же запись, если совпадает и тело
+			id := models.IdempotencyKey{Namespace: key.Namespace, Key: key.Key}
+			if j, ok := first[id]; ok {
+				if keys[j].RequestHash != key.RequestHash {
+					return nil, models.ErrIdempotencyKeyReused
+				}
+				duplicates[i] = j
+				continue
+			}
+			first[id] = i
+
+			existing, err := idempotencyrepo.Claim(tx, idempotencyrepo.ScopeEffRuns, key)
+			if err != nil {
+				return nil, err
+			}
+			if existing == nil || *existing == "" {
+				insert = append(insert, i)
+				continue
+			}
+			replays[i] = *existing
+		}
+	}
+
+	if len(replays) > 0 {
+		ids := make([]string, 0, len(replays))
+		for _, id := range replays {
+			ids = append(ids, id)
+		}
+		found, err := getEffRunsByIDs(tx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for i, id := range replays {
+			if effRun, ok := found[id]; ok {
+				*effRuns[i] = *effRun
+			} else {
+				// Исходный запуск удалён — сохраняем заново
+				insert = append(insert, i)
+			}
+		}
+		sort.Ints(insert)
+	}
+
+	for start := 0; start < len(insert); start += createEffRunsChunk {
+		chunk := insert[start:min(start+createEffRunsChunk, len(insert))]
+
+		var args queryArgs
+		values := make([]string, 0, len(chunk))
+		for _, i := range chunk {
+			effRun := effRuns[i]
+			values = append(values, fmt.Sprintf("(%s::uuid, %s::timestamptz, %s::timestamptz, %s::eff_status, %s, %s::jsonb, %s, NOW())",
+				args.add(effRun.BotID), args.add(effRun.PeriodFrom), args.add(effRun.PeriodTo),
+				args.add(effRun.Status), args.add(effRun.Host), args.add(effRun.Extra), args.add(effRun.Version)))
+		}
+
+		query := `
+			INSERT INTO eff_runs (bot_id, period_from, period_to, status, host, extra, version, created_at)
+			VALUES ` + strings.Join(values, ", ") + `
+			RETURNING id, created_at
+		`
+
+		rows, err := tx.Query(query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create eff_runs: %w", err)
+		}
+
+		n := 0
+		for rows.Next() {
+			if n >= len(chunk) {
+				break
+			}
+			effRun := effRuns[chunk[n]]
+			if err := rows.Scan(&effRun.ID, &effRun.CreatedAt); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan created eff_run: %w", err)
+			}
+			n++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error after iterating rows: %w", err)
+		}
+	}
+
+	for _, i := range insert {
+		created[i] = true
+		if keys != nil && keys[i] != nil {
+			if err := idempotencyrepo.Complete(tx, idempotencyrepo.ScopeEffRuns, keys[i], effRuns[i].ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit eff_runs: %w", err)
+	}
+
+	for i, j := range duplicates {
+		*effRuns[i] = *effRuns[j]
+	}
+
+	return created, nil
+}
+
+func getEffRunsByIDs(tx *sql.Tx, ids []string) (map[string]*models.EffRun, error) {
+	rows, err := tx.Query(effRunSelect+`
+		WHERE e.id = ANY($1::uuid[])
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get eff_runs: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]*models.EffRun, len(ids))
+	for rows.Next() {
+		effRun, err := scanEffRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		result[effRun.ID] = effRun
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// effRunSelect выбирает запуски вместе с владельцем бота на момент запуска (по created_at): это новый владелец
+// из последней передачи бота до запуска, а если до запуска передач не было — прежний владелец из первой передачи
+// после него. Для бота без истории передач — текущий владелец.
+const effRunSelect = `
+	SELECT e.id, e.bot_id, e.period_from, e.period_to, e.status, e.host, e.extra, e.version, e.created_at, ` + effRunOwner + `
+	FROM eff_runs e
+	LEFT JOIN bots b ON b.id = e.bot_id
+	LEFT JOIN LATERAL (
+		SELECT true AS found, h.to_owner_id AS owner_id
+		FROM bot_owner_history h
+		WHERE h.bot_id = e.bot_id AND h.changed_at <= e.created_at
+		ORDER BY h.changed_at DESC
+		LIMIT 1
+	) hb ON true
+	LEFT JOIN LATERAL (
+		SELECT true AS found, h.from_owner_id AS owner_id
+		FROM bot_owner_history h
+		WHERE h.bot_id = e.bot_id AND h.changed_at > e.created_at
+		ORDER BY h.changed_at
+		LIMIT 1
+	) ha ON true
+`
+
+// effRunOwner — владелец бота на момент запуска в выборке effRunSelect
+const effRunOwner = `CASE WHEN hb.found THEN hb.owner_id WHEN ha.found THEN ha.owner_id ELSE b.owner_id END`
+
+// queryArgs накапливает аргументы запроса и возвращает их плейсхолдеры
+type queryArgs []interface{}
+
+func (a *queryArgs) add(value interface{}) string {
+	*a = append(*a, value)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+// effRunConditions строит условия WHERE по фильтру (без пагинации) для выборки effRunSelect
+func effRunConditions(filter *models.EffRunFilter, args *queryArgs) []string {
+	var conditions []string
+	if filter.BotID != nil {
+		conditions = append(conditions, "e.bot_id = "+args.add(*filter.BotID))
+	}
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, "e.status::text = ANY("+args.add(pq.Array(filter.Statuses))+")")
+	}
+	if filter.OwnerID != nil {
+		conditions = append(conditions, effRunOwner+" = "+args.add(*filter.OwnerID))
+	}
+	if filter.TeamID != nil {
+		conditions = append(conditions, "e.bot_id IN (SELECT id FROM bots WHERE team_id = "+args.add(*filter.TeamID)+")")
+	}
+	if filter.Host != nil {
+		conditions = append(conditions, "e.host = "+args.add(*filter.Host))
+	}
+	if filter.Version != nil {
+		conditions = append(conditions, "e.version = "+args.add(*filter.Version))
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "e.created_at >= "+args.add(*filter.From))
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "e.created_at < "+args.add(*filter.To))
+	}
+	return conditions
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(conditions, " AND ")
+}
+
+func scanEffRun(rows *sql.Rows) (*models.EffRun, error) {
+	var effRun models.EffRun
+	err := rows.Scan(
+		&effRun.ID,
+		&effRun.BotID,
+		&effRun.PeriodFrom,
+		&effRun.PeriodTo,
+		&effRun.Status,
+		&effRun.Host,
+		&effRun.Extra,
+		&effRun.Version,
+		&effRun.CreatedAt,
+		&effRun.OwnerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan eff_run: %w", err)
+	}
+	return &effRun, nil
+}
+
+// ListEffRuns возвращает запуски по фильтру (новые первыми, не более filter.Limit+1 записей)
+func (r *EffRunRepo) ListEffRuns(filter *models.EffRunFilter) ([]*models.EffRun, error) {
+	var args queryArgs
+	conditions := effRunConditions(filter, &args)
+	if filter.AfterCreatedAt != nil && filter.AfterID != nil {
+		conditions = append(conditions, fmt.Sprintf("(e.created_at, e.id) < (%s, %s)", args.add(*filter.AfterCreatedAt), args.add(*filter.AfterID)))
+	}
+
+	query := fmt.Sprintf(effRunSelect+`
+		%s
+		ORDER BY e.created_at DESC, e.id DESC
+		LIMIT %s
+	`, whereClause(conditions), args.add(filter.Limit+1))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get eff_runs: %w", err)
+	}
+	defer rows.Close()
+
+	var effRuns []*models.EffRun
+	for rows.Next() {
+		effRun, err := scanEffRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		effRuns = append(effRuns, effRun)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return effRuns, nil
+}
+
+// StreamEffRuns передаёт в fn все запуски по фильтру (в порядке создания) через серверный курсор
+func (r *EffRunRepo) StreamEffRuns(filter *models.EffRunFilter, fn func(*models.EffRun) error) error {
+	var args queryArgs
+	conditions := effRunConditions(filter, &args)
+
+	query := fmt.Sprintf(effRunSelect+`
+		%s
+		ORDER BY e.created_at, e.id
+	`, whereClause(conditions))
+
+	return postgres.StreamCursor(r.db, postgres.DefaultFetchSize, func(rows *sql.Rows) error {
+		effRun, err := scanEffRun(rows)
+		if err != nil {
+			return err
+		}
+		return fn(effRun)
+	}, query, args...)
+}
@@ -0,0 +1,136 @@
+package healthrepo
+
+import (
+	"database/sql"
+	"fmt"
+	"logging_api/internal/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type HealthRepo struct {
+	db *sql.DB
+}
+
+func NewHealthRepo(db *sql.DB) *HealthRepo {
+	return &HealthRepo{db: db}
+}
+
+// GetBotSignals возвращает по каждому боту не в архиве его код, имя, активность, команду, время последнего лога и запуска, число логов Error/Critical,
+// полученных начиная с since, и статусы recentRuns последних запусков
+func (r *HealthRepo) GetBotSignals(since time.Time, recentRuns int) ([]*models.BotSignals, error) {
+	query := `
+		SELECT b.id, b.code, b.name, b.is_active, b.team_id, l.last_log_at, e.error_logs, r.last_run_at, COALESCE(r.statuses, '{}')
+		FROM bots b
+		LEFT JOIN LATERAL (
+			SELECT MAX(received_at) AS last_log_at FROM logs WHERE bot_id = b.id
+		) l ON true
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) AS error_logs
+			FROM logs
+			WHERE bot_id = b.id AND received_at >= $1 AND status IN ('Error', 'Critical')
+		) e ON true
+		LEFT JOIN LATERAL (
+			SELECT MAX(created_at) AS last_run_at, array_agg(status::text ORDER BY created_at DESC) AS statuses
+			FROM (
+				SELECT status, created_at
+				FROM eff_runs
+				WHERE bot_id = b.id
+				ORDER BY created_at DESC
+				LIMIT $2
+			) recent
+		) r ON true
+		WHERE b.archived_at IS NULL
+	`
+
+	rows, err := r.db.Query(query, since, recentRuns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bot signals: %w", err)
+	}
+	defer rows.Close()
+
+	var signals []*models.BotSignals
+	for rows.Next() {
+		var s models.BotSignals
+		if err := rows.Scan(&s.BotID, &s.Code, &s.Name, &s.IsActive, &s.TeamID, &s.LastLogAt, &s.ErrorLogs, &s.LastRunAt, pq.Array(&s.RecentRuns)); err != nil {
+			return nil, fmt.Errorf("failed to scan bot signals: %w", err)
+		}
+		signals = append(signals, &s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return signals, nil
+}
+
+// ListHealthStates возвращает сохранённое состояние каждого бота
+func (r *HealthRepo) ListHealthStates() (map[string]string, error) {
+	rows, err := r.db.Query(`SELECT bot_id, state FROM bot_health_states`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get health states: %w", err)
+	}
+	defer rows.Close()
+
+	states := make(map[string]string)
+	for rows.Next() {
+		var botID, state string
+		if err := rows.Scan(&botID, &state); err != nil {
+			return nil, fmt.Errorf("failed to scan health state: %w", err)
+		}
+		states[botID] = state
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return states, nil
+}
+
+// CreateHealthStates сохраняет состояние ботов, у которых его ещё нет; уже сохранённые не меняются
+func (r *HealthRepo) CreateHealthStates(states map[string]string) error {
+	if len(states) == 0 {
+		return nil
+	}
+
+	botIDs := make([]string, 0, len(states))
+	values := make([]string, 0, len(states))
+	for botID, state := range states {
+		botIDs = append(botIDs, botID)
+		values = append(values, state)
+	}
+
+	query := `
+		INSERT INTO bot_health_states (bot_id, state)
+		SELECT s.bot_id, s.state
+		FROM unnest($1::uuid[], $2::text[]) AS s (bot_id, state)
+		JOIN bots b ON b.id = s.bot_id
+		ON CONFLICT (bot_id) DO NOTHING
+	`
+	if _, err := r.db.Exec(query, pq.Array(botIDs), pq.Array(values)); err != nil {
+		return fmt.Errorf("failed to create health states: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateHealthState меняет сохранённое состояние бота с from на to. changed = false — состояние уже не from
+// (переход сохранил другой экземпляр сервиса) или бот удалён.
+func (r *HealthRepo) UpdateHealthState(botID, from, to string) (changed bool, err error) {
+	query := `UPDATE bot_health_states SET state = $3, changed_at = NOW() WHERE bot_id = $1 AND state = $2`
+
+	result, err := r.db.Exec(query, botID, from, to)
+	if err != nil {
+		return false, fmt.Errorf("failed to update health state: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
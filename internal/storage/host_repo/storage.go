@@ -0,0 +1,333 @@
+package hostrepo
+
+import (
+	"database/sql"
+	"fmt"
+	"logging_api/internal/models"
+	"logging_api/pkg/postgres"
+	"strings"
+	"time"
+)
+
+type HostRepo struct {
+	db *sql.DB
+}
+
+func NewHostRepo(db *sql.DB) *HostRepo {
+	return &HostRepo{db: db}
+}
+
+const hostColumns = `
+	h.name, h.description, h.os, host(h.ip), h.monitored, h.first_seen_at, h.last_seen_at, h.runs_seen,
+	(SELECT COUNT(*) FROM host_bots hb WHERE hb.host_name = h.name),
+	h.silent_since, h.created_at, h.updated_at
+`
+
+func scanHost(row interface{ Scan(...interface{}) error }) (*models.Host, error) {
+	var host models.Host
+	err := row.Scan(
+		&host.Name,
+		&host.Description,
+		&host.OS,
+		&host.IP,
+		&host.Monitored,
+		&host.FirstSeenAt,
+		&host.LastSeenAt,
+		&host.RunsSeen,
+		&host.BotCount,
+		&host.SilentSince,
+		&host.CreatedAt,
+		&host.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &host, nil
+}
+
+// RecordSightings добавляет в реестр запуски ботов с хостов: новые хосты и пары хост–бот создаются,
+// у известных сдвигаются первое и последнее появление и растёт число запусков.
+// Запуски удалённых к этому моменту ботов пропускаются.
+func (r *HostRepo) RecordSightings(sightings []models.HostSighting) error {
+	if len(sightings) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(sightings))
+	args := make([]interface{}, 0, len(sightings)*5)
+	for _, sighting := range sightings {
+		n := len(args)
+		values = append(values, fmt.Sprintf("($%d, $%d::uuid, $%d::timestamptz, $%d::timestamptz, $%d::bigint)", n+1, n+2, n+3, n+4, n+5))
+		args = append(args, sighting.Host, sighting.BotID, sighting.FirstSeenAt, sighting.LastSeenAt, sighting.Runs)
+	}
+	source := `
+		SELECT v.host, v.bot_id, v.first_seen_at, v.last_seen_at, v.runs
+		FROM (VALUES ` + strings.Join(values, ", ") + `) AS v (host, bot_id, first_seen_at, last_seen_at, runs)
+		JOIN bots b ON b.id = v.bot_id
+	`
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Хосты сортируются, чтобы параллельные сохранения блокировали строки в одном порядке
+	hostsQuery := `
+		INSERT INTO hosts (name, first_seen_at, last_seen_at, runs_seen)
+		SELECT s.host, MIN(s.first_seen_at), MAX(s.last_seen_at), SUM(s.runs)
+		FROM (` + source + `) s
+		GROUP BY s.host
+		ORDER BY s.host
+		ON CONFLICT (name) DO UPDATE SET
+			first_seen_at = LEAST(hosts.first_seen_at, EXCLUDED.first_seen_at),
+			last_seen_at = GREATEST(hosts.last_seen_at, EXCLUDED.last_seen_at),
+			runs_seen = hosts.runs_seen + EXCLUDED.runs_seen
+	`
+	if _, err := tx.Exec(hostsQuery, args...); err != nil {
+		return fmt.Errorf("failed to upsert hosts: %w", err)
+	}
+
+	hostBotsQuery := `
+		INSERT INTO host_bots (host_name, bot_id, first_seen_at, last_seen_at, runs_seen)
+		SELECT s.host, s.bot_id, MIN(s.first_seen_at), MAX(s.last_seen_at), SUM(s.runs)
+		FROM (` + source + `) s
+		GROUP BY s.host, s.bot_id
+		ORDER BY s.host, s.bot_id
+		ON CONFLICT (host_name, bot_id) DO UPDATE SET
+			first_seen_at = LEAST(host_bots.first_seen_at, EXCLUDED.first_seen_at),
+			last_seen_at = GREATEST(host_bots.last_seen_at, EXCLUDED.last_seen_at),
+			runs_seen = host_bots.runs_seen + EXCLUDED.runs_seen
+	`
+	if _, err := tx.Exec(hostBotsQuery, args...); err != nil {
+		return fmt.Errorf("failed to upsert host bots: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *HostRepo) GetHost(name string) (*models.Host, error) {
+	query := `SELECT ` + hostColumns + ` FROM hosts h WHERE h.name = $1`
+	return scanHost(r.db.QueryRow(query, name))
+}
+
+// ListHosts возвращает хосты по фильтру: сначала замолчавшие, затем по имени
+func (r *HostRepo) ListHosts(filter *models.HostFilter) ([]*models.Host, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Query != "" {
+		args = append(args, postgres.ContainsPattern(filter.Query))
+		conditions = append(conditions, fmt.Sprintf("(h.name ILIKE $%d OR h.description ILIKE $%d OR host(h.ip) ILIKE $%d)", len(args), len(args), len(args)))
+	}
+	if filter.Silent != nil {
+		if *filter.Silent {
+			conditions = append(conditions, "h.silent_since IS NOT NULL")
+		} else {
+			conditions = append(conditions, "h.silent_since IS NULL")
+		}
+	}
+	if filter.BotID != nil {
+		args = append(args, *filter.BotID)
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM host_bots hb WHERE hb.host_name = h.name AND hb.bot_id = $%d)", len(args)))
+	}
+
+	query := `SELECT ` + hostColumns + ` FROM hosts h`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY h.silent_since IS NULL, h.name`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hosts: %w", err)
+	}
+	defer rows.Close()
+
+	var hosts []*models.Host
+	for rows.Next() {
+		host, err := scanHost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan host: %w", err)
+		}
+		hosts = append(hosts, host)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return hosts, nil
+}
+
+// ListMonitoredHosts возвращает хосты, за молчанием которых следят, с не менее чем minRuns запусков
+func (r *HostRepo) ListMonitoredHosts(minRuns int64) ([]*models.Host, error) {
+	query := `SELECT ` + hostColumns + ` FROM hosts h WHERE h.monitored AND h.runs_seen >= $1 ORDER BY h.name`
+
+	rows, err := r.db.Query(query, minRuns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monitored hosts: %w", err)
+	}
+	defer rows.Close()
+
+	var hosts []*models.Host
+	for rows.Next() {
+		host, err := scanHost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan host: %w", err)
+		}
+		hosts = append(hosts, host)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return hosts, nil
+}
+
+// ListHostBots возвращает ботов хоста, последние появившиеся первыми
+func (r *HostRepo) ListHostBots(name string) ([]*models.HostBot, error) {
+	query := `
+		SELECT hb.bot_id, b.code, b.name, hb.first_seen_at, hb.last_seen_at, hb.runs_seen
+		FROM host_bots hb
+		JOIN bots b ON b.id = hb.bot_id
+		WHERE hb.host_name = $1
+		ORDER BY hb.last_seen_at DESC, b.code
+	`
+
+	rows, err := r.db.Query(query, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get host bots: %w", err)
+	}
+	defer rows.Close()
+
+	var bots []*models.HostBot
+	for rows.Next() {
+		var bot models.HostBot
+		if err := rows.Scan(&bot.BotID, &bot.Code, &bot.Name, &bot.FirstSeenAt, &bot.LastSeenAt, &bot.RunsSeen); err != nil {
+			return nil, fmt.Errorf("failed to scan host bot: %w", err)
+		}
+		bots = append(bots, &bot)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return bots, nil
+}
+
+// UpdateHost меняет описание хоста (nil — без изменений), возвращает sql.ErrNoRows если хоста нет
+func (r *HostRepo) UpdateHost(name string, update models.HostUpdate) error {
+	query := `
+		UPDATE hosts
+		SET description = COALESCE($2, description),
+			os = COALESCE($3, os),
+			ip = COALESCE($4::inet, ip),
+			monitored = COALESCE($5, monitored),
+			silent_since = CASE WHEN $5 = FALSE THEN NULL ELSE silent_since END,
+			updated_at = NOW()
+		WHERE name = $1
+	`
+
+	result, err := r.db.Exec(query, name, update.Description, update.OS, update.IP, update.Monitored)
+	if err != nil {
+		return fmt.Errorf("failed to update host: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// SetSilent отмечает хост замолчавшим с момента since (nil — хост снова присылает запуски).
+// Отметка меняется, только если хост ещё в прежнем состоянии; changed = false — её уже поменял
+// другой экземпляр сервиса (или хост удалён), и уведомлять о смене не нужно.
+func (r *HostRepo) SetSilent(name string, since *time.Time) (changed bool, err error) {
+	query := `UPDATE hosts SET silent_since = $2 WHERE name = $1 AND silent_since IS NULL`
+	if since == nil {
+		query = `UPDATE hosts SET silent_since = $2 WHERE name = $1 AND silent_since IS NOT NULL`
+	}
+
+	result, err := r.db.Exec(query, name, since)
+	if err != nil {
+		return false, fmt.Errorf("failed to set host silent: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+// DeleteHost удаляет хост из реестра, возвращает sql.ErrNoRows если хоста нет
+func (r *HostRepo) DeleteHost(name string) error {
+	result, err := r.db.Exec(`DELETE FROM hosts WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete host: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetHostStats считает запуски с хоста за период [from, to) по created_at (nil — без границы)
+func (r *HostRepo) GetHostStats(name string, from, to *time.Time) (*models.HostStats, error) {
+	stats := &models.HostStats{Host: name, From: from, To: to}
+
+	countsQuery := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'success'),
+			COUNT(*) FILTER (WHERE status = 'warning'),
+			COUNT(*) FILTER (WHERE status = 'error'),
+			COUNT(DISTINCT bot_id)
+		FROM eff_runs
+		WHERE host = $1 AND ($2::timestamptz IS NULL OR created_at >= $2) AND ($3::timestamptz IS NULL OR created_at < $3)
+	`
+	err := r.db.QueryRow(countsQuery, name, from, to).Scan(&stats.Runs, &stats.RunsSuccess, &stats.RunsWarning, &stats.RunsError, &stats.Bots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count host runs: %w", err)
+	}
+
+	// Начало периода запуска — +1, конец — −1; при равном времени конец идёт раньше,
+	// чтобы запуски, следующие друг за другом, не считались одновременными
+	peakQuery := `
+		WITH runs AS (
+			SELECT period_from, period_to
+			FROM eff_runs
+			WHERE host = $1 AND ($2::timestamptz IS NULL OR created_at >= $2) AND ($3::timestamptz IS NULL OR created_at < $3)
+				AND period_from IS NOT NULL AND period_to > period_from
+		), events AS (
+			SELECT period_from AS at, 1 AS delta FROM runs
+			UNION ALL
+			SELECT period_to, -1 FROM runs
+		)
+		SELECT COALESCE(MAX(running), 0)
+		FROM (SELECT SUM(delta) OVER (ORDER BY at, delta ROWS UNBOUNDED PRECEDING) AS running FROM events) e
+	`
+	if err := r.db.QueryRow(peakQuery, name, from, to).Scan(&stats.PeakConcurrentBots); err != nil {
+		return nil, fmt.Errorf("failed to count concurrent host runs: %w", err)
+	}
+
+	return stats, nil
+}
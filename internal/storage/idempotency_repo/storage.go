@@ -0,0 +1,87 @@
+package idempotencyrepo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"logging_api/internal/models"
+	"time"
+)
+
+const (
+	ScopeLogs    = "logs"
+	ScopeEffRuns = "eff_runs"
+
+	// ResourceDropped — resource_id ключа, запись по которому отброшена политикой хранения бота
+	ResourceDropped = "dropped"
+)
+
+type IdempotencyRepo struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepo(db *sql.DB) *IdempotencyRepo {
+	return &IdempotencyRepo{db: db}
+}
+
+// DeleteExpired удаляет ключи, созданные раньше before
+func (r *IdempotencyRepo) DeleteExpired(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// Claim занимает ключ в транзакции tx. Если ключ свободен или истёк, возвращает nil —
+// вызывающий создаёт ресурс и вызывает Complete в той же транзакции.
+// Если ключ уже занят, возвращает ID ранее созданного ресурса, а если он занят запросом с другим хешем —
+// models.ErrIdempotencyKeyReused. Параллельный запрос с тем же ключом ждёт завершения транзакции, занявшей ключ.
+func Claim(tx *sql.Tx, scope string, key *models.IdempotencyKey) (*string, error) {
+	query := `
+		INSERT INTO idempotency_keys (scope, namespace, key, request_hash, resource_id, created_at)
+		VALUES ($1, $2, $3, NULLIF($5, ''), NULL, NOW())
+		ON CONFLICT (scope, namespace, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, resource_id = NULL, created_at = NOW()
+		WHERE idempotency_keys.created_at < $4
+		RETURNING true
+	`
+
+	var claimed bool
+	err := tx.QueryRow(query, scope, key.Namespace, key.Key, key.NotBefore, key.RequestHash).Scan(&claimed)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+
+	var resourceID, requestHash sql.NullString
+	err = tx.QueryRow(
+		`SELECT resource_id, request_hash FROM idempotency_keys WHERE scope = $1 AND namespace = $2 AND key = $3`,
+		scope, key.Namespace, key.Key,
+	).Scan(&resourceID, &requestHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	// Ключи без хеша созданы до появления проверки
+	if requestHash.Valid && key.RequestHash != "" && requestHash.String != key.RequestHash {
+		return nil, models.ErrIdempotencyKeyReused
+	}
+
+	return &resourceID.String, nil
+}
+
+// Complete сохраняет ID ресурса, созданного по занятому ключу (ResourceDropped — запись отброшена)
+func Complete(tx *sql.Tx, scope string, key *models.IdempotencyKey, resourceID string) error {
+	_, err := tx.Exec(
+		`UPDATE idempotency_keys SET resource_id = $4 WHERE scope = $1 AND namespace = $2 AND key = $3`,
+		scope, key.Namespace, key.Key, resourceID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return nil
+}
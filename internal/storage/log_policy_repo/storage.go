@@ -0,0 +1,213 @@
+package logpolicyrepo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"logging_api/internal/models"
+	"strings"
+	"time"
+)
+
+type LogPolicyRepo struct {
+	db *sql.DB
+}
+
+func NewLogPolicyRepo(db *sql.DB) *LogPolicyRepo {
+	return &LogPolicyRepo{db: db}
+}
+
+const policyColumns = `bot_id, min_level, sample_rates, override_level, override_until, updated_at`
+
+func scanPolicy(row interface{ Scan(...interface{}) error }) (*models.LogPolicy, error) {
+	var policy models.LogPolicy
+	var sampleRates []byte
+	err := row.Scan(
+		&policy.BotID,
+		&policy.MinLevel,
+		&sampleRates,
+		&policy.OverrideLevel,
+		&policy.OverrideUntil,
+		&policy.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	policy.SampleRates = map[string]float64{}
+	if len(sampleRates) > 0 {
+		if err := json.Unmarshal(sampleRates, &policy.SampleRates); err != nil {
+			return nil, fmt.Errorf("failed to decode sample rates: %w", err)
+		}
+	}
+
+	return &policy, nil
+}
+
+func (r *LogPolicyRepo) GetPolicy(botID string) (*models.LogPolicy, error) {
+	query := `SELECT ` + policyColumns + ` FROM log_policies WHERE bot_id = $1`
+
+	return scanPolicy(r.db.QueryRow(query, botID))
+}
+
+func (r *LogPolicyRepo) ListPolicies() ([]*models.LogPolicy, error) {
+	rows, err := r.db.Query(`SELECT ` + policyColumns + ` FROM log_policies`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []*models.LogPolicy
+	for rows.Next() {
+		policy, err := scanPolicy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan log policy: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating log policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+// SetPolicy задаёт минимальный уровень и семплирование бота; временный уровень не меняется
+func (r *LogPolicyRepo) SetPolicy(botID string, minLevel *string, sampleRates map[string]float64) (*models.LogPolicy, error) {
+	rates, err := json.Marshal(sampleRates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode sample rates: %w", err)
+	}
+
+	query := `
+		INSERT INTO log_policies (bot_id, min_level, sample_rates, updated_at)
+		VALUES ($1, $2::log_status, $3::jsonb, NOW())
+		ON CONFLICT (bot_id) DO UPDATE SET
+			min_level = EXCLUDED.min_level,
+			sample_rates = EXCLUDED.sample_rates,
+			updated_at = NOW()
+		RETURNING ` + policyColumns
+
+	policy, err := scanPolicy(r.db.QueryRow(query, botID, minLevel, string(rates)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set log policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// SetOverride задаёт временный минимальный уровень бота (level = nil — снимает его)
+func (r *LogPolicyRepo) SetOverride(botID string, level *string, until *time.Time) (*models.LogPolicy, error) {
+	query := `
+		INSERT INTO log_policies (bot_id, override_level, override_until, updated_at)
+		VALUES ($1, $2::log_status, $3, NOW())
+		ON CONFLICT (bot_id) DO UPDATE SET
+			override_level = EXCLUDED.override_level,
+			override_until = EXCLUDED.override_until,
+			updated_at = NOW()
+		RETURNING ` + policyColumns
+
+	policy, err := scanPolicy(r.db.QueryRow(query, botID, level, until))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set log policy override: %w", err)
+	}
+
+	return policy, nil
+}
+
+// DeletePolicy удаляет политику бота, возвращает sql.ErrNoRows если её нет
+func (r *LogPolicyRepo) DeletePolicy(botID string) error {
+	result, err := r.db.Exec(`DELETE FROM log_policies WHERE bot_id = $1`, botID)
+	if err != nil {
+		return fmt.Errorf("failed to delete log policy: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// AddDropCounts прибавляет приросты к счётчикам отброшенных логов одним запросом
+func (r *LogPolicyRepo) AddDropCounts(deltas []models.LogDropCountDelta) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(deltas))
+	args := make([]interface{}, 0, len(deltas)*5)
+	for _, delta := range deltas {
+		n := len(args)
+		values = append(values, fmt.Sprintf("($%d::uuid, $%d::date, $%d::log_status, $%d, $%d::bigint)", n+1, n+2, n+3, n+4, n+5))
+		args = append(args, delta.BotID, delta.Day.Format("2006-01-02"), delta.Status, delta.Reason, delta.Count)
+	}
+
+	query := `
+		INSERT INTO log_drop_counts (bot_id, day, status, reason, count)
+		VALUES ` + strings.Join(values, ", ") + `
+		ON CONFLICT (bot_id, day, status, reason)
+		DO UPDATE SET count = log_drop_counts.count + EXCLUDED.count
+	`
+
+	if _, err := r.db.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to add log drop counts: %w", err)
+	}
+
+	return nil
+}
+
+// ListDropCounts возвращает число отброшенных логов по ботам, уровням и причинам за дни [from, to]
+func (r *LogPolicyRepo) ListDropCounts(botID *string, from, to *time.Time) ([]*models.LogDropCount, error) {
+	var conditions []string
+	var args []interface{}
+	if botID != nil {
+		args = append(args, *botID)
+		conditions = append(conditions, fmt.Sprintf("c.bot_id = $%d", len(args)))
+	}
+	if from != nil {
+		args = append(args, from.Format("2006-01-02"))
+		conditions = append(conditions, fmt.Sprintf("c.day >= $%d::date", len(args)))
+	}
+	if to != nil {
+		args = append(args, to.Format("2006-01-02"))
+		conditions = append(conditions, fmt.Sprintf("c.day <= $%d::date", len(args)))
+	}
+
+	query := `
+		SELECT c.bot_id, b.code, c.status, c.reason, SUM(c.count)
+		FROM log_drop_counts c
+		LEFT JOIN bots b ON b.id = c.bot_id`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += `
+		GROUP BY c.bot_id, b.code, c.status, c.reason
+		ORDER BY SUM(c.count) DESC`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log drop counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []*models.LogDropCount{}
+	for rows.Next() {
+		var count models.LogDropCount
+		if err := rows.Scan(&count.BotID, &count.BotCode, &count.Status, &count.Reason, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan log drop count: %w", err)
+		}
+		counts = append(counts, &count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating log drop counts: %w", err)
+	}
+
+	return counts, nil
+}
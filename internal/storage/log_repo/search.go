@@ -0,0 +1,90 @@
+package logrepo
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// searchTerm — слово или "фраза" запроса в синтаксисе websearch_to_tsquery
+type searchTerm struct {
+	text    string
+	negated bool
+}
+
+// parseSearchQuery разбирает запрос в синтаксисе websearch_to_tsquery на группы, соединённые OR;
+// термы внутри группы соединяются И (у OR приоритет ниже, как в websearch_to_tsquery)
+func parseSearchQuery(query string) [][]searchTerm {
+	var groups [][]searchTerm
+	var group []searchTerm
+
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		negated := false
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			negated = true
+			i++
+		}
+
+		start := i
+		if runes[i] == '"' {
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				i++
+			}
+			if i < len(runes) {
+				i++
+			}
+		} else {
+			for i < len(runes) && !unicode.IsSpace(runes[i]) {
+				i++
+			}
+		}
+		text := string(runes[start:i])
+
+		if !negated && strings.EqualFold(text, "or") {
+			if len(group) > 0 {
+				groups = append(groups, group)
+				group = nil
+			}
+			continue
+		}
+		group = append(group, searchTerm{text: text, negated: negated})
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// buildTSQuery строит выражение tsquery для запроса. Каждый терм ищется в конфигурациях russian (с учётом словоформ)
+// и simple (точная форма, в том числе стоп-слова); исключённый терм отрицается целиком, поэтому
+// "-ошибка" исключает и "ошибки", а не только точную форму
+func buildTSQuery(query string, args *queryArgs) string {
+	groups := parseSearchQuery(query)
+	if len(groups) == 0 {
+		return fmt.Sprintf(`websearch_to_tsquery('simple', %s)`, args.add(query))
+	}
+
+	alternatives := make([]string, len(groups))
+	for i, group := range groups {
+		terms := make([]string, len(group))
+		for j, term := range group {
+			placeholder := args.add(term.text)
+			expr := fmt.Sprintf(`(websearch_to_tsquery('russian', %[1]s) || websearch_to_tsquery('simple', %[1]s))`, placeholder)
+			if term.negated {
+				expr = "!!" + expr
+			}
+			terms[j] = expr
+		}
+		alternatives[i] = "(" + strings.Join(terms, " && ") + ")"
+	}
+
+	return "(" + strings.Join(alternatives, " || ") + ")"
+}
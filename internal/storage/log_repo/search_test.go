@@ -0,0 +1,57 @@
+package logrepo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  [][]searchTerm
+	}{
+		{name: "words", query: "timeout  redis", want: [][]searchTerm{{{text: "timeout"}, {text: "redis"}}}},
+		{name: "negation", query: "timeout -ошибка", want: [][]searchTerm{{{text: "timeout"}, {text: "ошибка", negated: true}}}},
+		{
+			name:  "phrase and negated phrase",
+			query: `"не удалось подключиться" -"segmentation fault"`,
+			want:  [][]searchTerm{{{text: `"не удалось подключиться"`}, {text: `"segmentation fault"`, negated: true}}},
+		},
+		{name: "unterminated phrase", query: `"cut phrase`, want: [][]searchTerm{{{text: `"cut phrase`}}}},
+		{
+			name:  "or",
+			query: "a b OR c -d",
+			want:  [][]searchTerm{{{text: "a"}, {text: "b"}}, {{text: "c"}, {text: "d", negated: true}}},
+		},
+		{name: "leading and trailing or", query: "or a or", want: [][]searchTerm{{{text: "a"}}}},
+		{name: "negated or is a word", query: "a -or", want: [][]searchTerm{{{text: "a"}, {text: "or", negated: true}}}},
+		{name: "lone dash", query: "a - b", want: [][]searchTerm{{{text: "a"}, {text: "-"}, {text: "b"}}}},
+		{name: "dash inside word", query: "read-only", want: [][]searchTerm{{{text: "read-only"}}}},
+		{name: "empty", query: "  ", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseSearchQuery(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSearchQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTSQuery(t *testing.T) {
+	var args queryArgs
+	got := buildTSQuery("timeout -ошибка OR redis", &args)
+
+	want := "((" +
+		"(websearch_to_tsquery('russian', $1) || websearch_to_tsquery('simple', $1)) && " +
+		"!!(websearch_to_tsquery('russian', $2) || websearch_to_tsquery('simple', $2))) || (" +
+		"(websearch_to_tsquery('russian', $3) || websearch_to_tsquery('simple', $3))))"
+	if got != want {
+		t.Errorf("buildTSQuery() = %s, want %s", got, want)
+	}
+	if wantArgs := (queryArgs{"timeout", "ошибка", "redis"}); !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+}
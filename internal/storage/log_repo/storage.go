@@ -2,8 +2,17 @@ package logrepo
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"logging_api/internal/models"
+	idempotencyrepo "logging_api/internal/storage/idempotency_repo"
+	"logging_api/pkg/postgres"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type LogRepo struct {
@@ -14,20 +23,24 @@ func NewLogRepo(db *sql.DB) *LogRepo {
 	return &LogRepo{db: db}
 }
 
-func (r *LogRepo) CreateLog(botID *string, status, msg string) (*models.Log, error) {
+// CreateLog сохраняет лог; createdAt — время события от клиента, nil — время приёма
+func (r *LogRepo) CreateLog(botID *string, status, msg string, createdAt *time.Time, version *string) (*models.Log, error) {
 	query := `
-		INSERT INTO logs (bot_id, status, msg, created_at)
-		VALUES ($1, $2, $3, NOW())
-		RETURNING id, bot_id, status, msg, created_at
+		INSERT INTO logs (bot_id, status, msg, version, created_at, received_at)
+		VALUES ($1, $2, $3, $5, COALESCE($4, NOW()), NOW())
+		RETURNING id, bot_id, status, msg, attributes, version, created_at, received_at
 	`
 
 	var log models.Log
-	err := r.db.QueryRow(query, botID, status, msg).Scan(
+	err := r.db.QueryRow(query, botID, status, msg, createdAt, version).Scan(
 		&log.ID,
 		&log.BotID,
 		&log.Status,
 		&log.Msg,
+		&log.Attributes,
+		&log.Version,
 		&log.CreatedAt,
+		&log.ReceivedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create log: %w", err)
@@ -36,3 +49,418 @@ func (r *LogRepo) CreateLog(botID *string, status, msg string) (*models.Log, err
 	return &log, nil
 }
 
+// createLogsChunk — максимальное число строк в одном INSERT пакетной вставки
+const createLogsChunk = 1000
+
+// CreateLogs сохраняет пачку логов многострочными INSERT и заполняет у записей id, created_at и received_at.
+// Нулевой CreatedAt означает время приёма.
+//
+// keys — ключи идемпотентности по записям (nil или nil-элементы — без ключа). Для записи с уже занятым ключом
+// новая строка не создаётся: запись заменяется ранее сохранённым логом (WriteReplayed), а если лог по ключу
+// был отброшен — снова отбрасывается (WriteDropped).
+// keep (nil — сохранять все) решает, сохранять ли новую запись; вызывается только для записей, которые будут созданы,
+// уже после того, как занят их ключ. Ключ отброшенной записи запоминается, чтобы повтор дал тот же результат.
+// Возвращает результат по каждой записи.
+func (r *LogRepo) CreateLogs(logs []*models.Log, keys []*models.IdempotencyKey, keep func(*models.Log) bool) ([]models.WriteResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	results := make([]models.WriteResult, len(logs))
+	candidates := make([]int, 0, len(logs))
+	replays := map[int]int64{}
+	duplicates := map[int]int{}
+
+	if keys == nil {
+		for i := range logs {
+			candidates = append(candidates, i)
+		}
+	} else {
+		first := map[models.IdempotencyKey]int{}
+		for i, key := range keys {
+			if key == nil {
+				candidates = append(candidates, i)
+				continue
+			}
+
+			// Повтор ключа внутри одной пачки — та же запись, если совпадает и тело
+			id := models.IdempotencyKey{Namespace: key.Namespace, Key: key.Key}
+			if j, ok := first[id]; ok {
+				if keys[j].RequestHash != key.RequestHash {
+					return nil, models.ErrIdempotencyKeyReused
+				}
+				duplicates[i] = j
+				continue
+			}
+			first[id] = i
+
+			existing, err := idempotencyrepo.Claim(tx, idempotencyrepo.ScopeLogs, key)
+			if err != nil {
+				return nil, err
+			}
+			if existing == nil {
+				candidates = append(candidates, i)
+				continue
+			}
+			if *existing == idempotencyrepo.ResourceDropped {
+				results[i] = models.WriteDropped
+				continue
+			}
+
+			logID, err := strconv.ParseInt(*existing, 10, 64)
+			if err != nil {
+				// Ресурс не был сохранён — создаём заново
+				candidates = append(candidates, i)
+				continue
+			}
+			replays[i] = logID
+		}
+	}
+
+	if len(replays) > 0 {
+		ids := make([]int64, 0, len(replays))
+		for _, id := range replays {
+			ids = append(ids, id)
+		}
+		found, err := getLogsByIDs(tx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for i, id := range replays {
+			if log, ok := found[id]; ok {
+				*logs[i] = *log
+				results[i] = models.WriteReplayed
+			} else {
+				// Исходный лог уже удалён политикой хранения — сохраняем заново
+				candidates = append(candidates, i)
+			}
+		}
+		sort.Ints(candidates)
+	}
+
+	insert := make([]int, 0, len(candidates))
+	for _, i := range candidates {
+		if keep != nil && !keep(logs[i]) {
+			results[i] = models.WriteDropped
+			if keys != nil && keys[i] != nil {
+				if err := idempotencyrepo.Complete(tx, idempotencyrepo.ScopeLogs, keys[i], idempotencyrepo.ResourceDropped); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		insert = append(insert, i)
+	}
+
+	for start := 0; start < len(insert); start += createLogsChunk {
+		chunk := insert[start:min(start+createLogsChunk, len(insert))]
+
+		var args queryArgs
+		values := make([]string, 0, len(chunk))
+		for _, i := range chunk {
+			log := logs[i]
+			var createdAt *time.Time
+			if !log.CreatedAt.IsZero() {
+				createdAt = &log.CreatedAt
+			}
+			values = append(values, fmt.Sprintf("(%s, %s::log_status, %s, %s::jsonb, %s, COALESCE(%s::timestamptz, NOW()), NOW())",
+				args.add(log.BotID), args.add(log.Status), args.add(log.Msg), args.add(log.Attributes), args.add(log.Version), args.add(createdAt)))
+		}
+
+		query := `
+			INSERT INTO logs (bot_id, status, msg, attributes, version, created_at, received_at)
+			VALUES ` + strings.Join(values, ", ") + `
+			RETURNING id, created_at, received_at
+		`
+
+		rows, err := tx.Query(query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create logs: %w", err)
+		}
+
+		n := 0
+		for rows.Next() {
+			if n >= len(chunk) {
+				break
+			}
+			log := logs[chunk[n]]
+			if err := rows.Scan(&log.ID, &log.CreatedAt, &log.ReceivedAt); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan created log: %w", err)
+			}
+			n++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error after iterating rows: %w", err)
+		}
+	}
+
+	for _, i := range insert {
+		results[i] = models.WriteCreated
+		if keys != nil && keys[i] != nil {
+			if err := idempotencyrepo.Complete(tx, idempotencyrepo.ScopeLogs, keys[i], strconv.FormatInt(logs[i].ID, 10)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit logs: %w", err)
+	}
+
+	for i, j := range duplicates {
+		*logs[i] = *logs[j]
+		results[i] = results[j]
+		if results[j] == models.WriteCreated {
+			results[i] = models.WriteReplayed
+		}
+	}
+
+	return results, nil
+}
+
+// CopyLogs сохраняет пачку логов через COPY. В отличие от CreateLogs, id записей не заполняются;
+// у записей должны быть заданы CreatedAt и ReceivedAt.
+func (r *LogRepo) CopyLogs(logs []*models.Log) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(pq.CopyIn("logs", "bot_id", "status", "msg", "attributes", "version", "created_at", "received_at"))
+	if err != nil {
+		return fmt.Errorf("failed to prepare copy: %w", err)
+	}
+
+	for _, log := range logs {
+		// COPY кодирует []byte как bytea, поэтому JSONB передаётся строкой
+		var attributes interface{}
+		if log.Attributes != nil {
+			data, err := json.Marshal(log.Attributes)
+			if err != nil {
+				stmt.Close()
+				return fmt.Errorf("failed to encode log attributes: %w", err)
+			}
+			attributes = string(data)
+		}
+
+		if _, err := stmt.Exec(log.BotID, log.Status, log.Msg, attributes, log.Version, log.CreatedAt, log.ReceivedAt); err != nil {
+			stmt.Close()
+			return fmt.Errorf("failed to copy log: %w", err)
+		}
+	}
+
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return fmt.Errorf("failed to flush copy: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return fmt.Errorf("failed to close copy: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func getLogsByIDs(tx *sql.Tx, ids []int64) (map[int64]*models.Log, error) {
+	rows, err := tx.Query(`
+		SELECT id, bot_id, status, msg, attributes, version, created_at, received_at
+		FROM logs
+		WHERE id = ANY($1)
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int64]*models.Log, len(ids))
+	for rows.Next() {
+		var log models.Log
+		err := rows.Scan(
+			&log.ID,
+			&log.BotID,
+			&log.Status,
+			&log.Msg,
+			&log.Attributes,
+			&log.Version,
+			&log.CreatedAt,
+			&log.ReceivedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan log: %w", err)
+		}
+		result[log.ID] = &log
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return result, nil
+}
+
+const (
+	// headlineConfig — конфигурация russian без стоп-слов (миграция 021): в подсветке находятся и термы,
+	// совпавшие только через конфигурацию simple
+	headlineConfig = "logs_headline"
+	headlineOpts   = `StartSel=<b>, StopSel=</b>, MaxWords=35, MinWords=15, MaxFragments=2`
+)
+
+// queryArgs накапливает аргументы запроса и возвращает их плейсхолдеры
+type queryArgs []interface{}
+
+func (a *queryArgs) add(value interface{}) string {
+	*a = append(*a, value)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+// logConditions строит условия WHERE по фильтру (без пагинации).
+// Возвращает также выражение tsquery, если задан полнотекстовый запрос.
+func logConditions(filter *models.LogFilter, args *queryArgs) (conditions []string, tsQuery string) {
+	if filter.BotID != nil {
+		conditions = append(conditions, "l.bot_id = "+args.add(*filter.BotID))
+	}
+	if filter.TeamID != nil {
+		conditions = append(conditions, "l.bot_id IN (SELECT id FROM bots WHERE team_id = "+args.add(*filter.TeamID)+")")
+	}
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, "l.status::text = ANY("+args.add(pq.Array(filter.Statuses))+")")
+	}
+	if filter.Version != nil {
+		conditions = append(conditions, "l.version = "+args.add(*filter.Version))
+	}
+	timeColumn := sortColumn(filter)
+	if filter.From != nil {
+		conditions = append(conditions, timeColumn+" >= "+args.add(*filter.From))
+	}
+	if filter.To != nil {
+		conditions = append(conditions, timeColumn+" < "+args.add(*filter.To))
+	}
+	if filter.Query != "" {
+		tsQuery = buildTSQuery(filter.Query, args)
+		conditions = append(conditions, "l.msg_tsv @@ "+tsQuery)
+	}
+	return conditions, tsQuery
+}
+
+// sortColumn возвращает колонку времени для сортировки и периода выборки
+func sortColumn(filter *models.LogFilter) string {
+	if filter.SortBy == "received_at" {
+		return "l.received_at"
+	}
+	return "l.created_at"
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(conditions, " AND ")
+}
+
+// ListLogs возвращает логи по фильтру (не более filter.Limit+1 записей, чтобы определить наличие следующей страницы).
+// Без полнотекстового запроса сортировка по времени (новые первыми) с keyset-пагинацией,
+// с запросом — по релевантности с подсветкой совпадений.
+func (r *LogRepo) ListLogs(filter *models.LogFilter) ([]*models.LogHit, error) {
+	var args queryArgs
+	conditions, tsQuery := logConditions(filter, &args)
+
+	var query string
+	if tsQuery != "" {
+		query = fmt.Sprintf(`
+			SELECT s.id, s.bot_id, s.status, s.msg, s.attributes, s.version, s.created_at, s.received_at, s.rank,
+			       ts_headline('%[6]s', s.msg, %[1]s, '%[2]s')
+			FROM (
+				SELECT l.id, l.bot_id, l.status, l.msg, l.attributes, l.version, l.created_at, l.received_at,
+				       ts_rank_cd(l.msg_tsv, %[1]s) AS rank
+				FROM logs l
+				%[3]s
+				ORDER BY rank DESC, l.created_at DESC, l.id DESC
+				LIMIT %[4]s OFFSET %[5]s
+			) s
+			ORDER BY s.rank DESC, s.created_at DESC, s.id DESC
+		`, tsQuery, headlineOpts, whereClause(conditions), args.add(filter.Limit+1), args.add(filter.Offset), headlineConfig)
+	} else {
+		timeColumn := sortColumn(filter)
+		if filter.AfterTime != nil && filter.AfterID != nil {
+			conditions = append(conditions, fmt.Sprintf("(%s, l.id) < (%s, %s)", timeColumn, args.add(*filter.AfterTime), args.add(*filter.AfterID)))
+		}
+
+		query = fmt.Sprintf(`
+			SELECT l.id, l.bot_id, l.status, l.msg, l.attributes, l.version, l.created_at, l.received_at
+			FROM logs l
+			%s
+			ORDER BY %s DESC, l.id DESC
+			LIMIT %s
+		`, whereClause(conditions), timeColumn, args.add(filter.Limit+1))
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []*models.LogHit
+	for rows.Next() {
+		var hit models.LogHit
+		dest := []interface{}{
+			&hit.ID,
+			&hit.BotID,
+			&hit.Status,
+			&hit.Msg,
+			&hit.Attributes,
+			&hit.Version,
+			&hit.CreatedAt,
+			&hit.ReceivedAt,
+		}
+		if tsQuery != "" {
+			dest = append(dest, &hit.Rank, &hit.Headline)
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan log: %w", err)
+		}
+		hits = append(hits, &hit)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return hits, nil
+}
+
+// StreamLogs передаёт в fn все логи по фильтру (в порядке создания) через серверный курсор
+func (r *LogRepo) StreamLogs(filter *models.LogFilter, fn func(*models.Log) error) error {
+	var args queryArgs
+	conditions, _ := logConditions(filter, &args)
+
+	query := fmt.Sprintf(`
+		SELECT l.id, l.bot_id, l.status, l.msg, l.attributes, l.version, l.created_at, l.received_at
+		FROM logs l
+		%s
+		ORDER BY %s, l.id
+	`, whereClause(conditions), sortColumn(filter))
+
+	return postgres.StreamCursor(r.db, postgres.DefaultFetchSize, func(rows *sql.Rows) error {
+		var log models.Log
+		err := rows.Scan(
+			&log.ID,
+			&log.BotID,
+			&log.Status,
+			&log.Msg,
+			&log.Attributes,
+			&log.Version,
+			&log.CreatedAt,
+			&log.ReceivedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan log: %w", err)
+		}
+		return fn(&log)
+	}, query, args...)
+}
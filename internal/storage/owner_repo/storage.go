@@ -4,6 +4,10 @@ import (
 	"database/sql"
 	"fmt"
 	"logging_api/internal/models"
+	"logging_api/pkg/postgres"
+	"strings"
+
+	"github.com/lib/pq"
 )
 
 type OwnerRepo struct {
@@ -14,113 +18,222 @@ func NewOwnerRepo(db *sql.DB) *OwnerRepo {
 	return &OwnerRepo{db: db}
 }
 
-func (r *OwnerRepo) CreateOwner(fullName string, isActive bool) (*models.Owner, error) {
-	query := `
-		INSERT INTO owners (full_name, is_active, created_at)
-		VALUES ($1, $2, NOW())
-		RETURNING id, full_name, is_active, created_at
-	`
+// ownerColumns — поля владельца в порядке scanOwner
+const ownerColumns = `id, full_name, is_active, email, telegram, phone, timezone, notify_channels, created_at, archived_at, archived_by`
 
+func scanOwner(row interface{ Scan(...interface{}) error }) (*models.Owner, error) {
 	var owner models.Owner
-	err := r.db.QueryRow(query, fullName, isActive).Scan(
+	err := row.Scan(
 		&owner.ID,
 		&owner.FullName,
 		&owner.IsActive,
+		&owner.Contacts.Email,
+		&owner.Contacts.Telegram,
+		&owner.Contacts.Phone,
+		&owner.Contacts.Timezone,
+		pq.Array(&owner.Contacts.NotifyChannels),
 		&owner.CreatedAt,
+		&owner.ArchivedAt,
+		&owner.ArchivedBy,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create owner: %w", err)
+		return nil, err
+	}
+	if owner.Contacts.NotifyChannels == nil {
+		owner.Contacts.NotifyChannels = []string{}
 	}
-
 	return &owner, nil
 }
 
-func (r *OwnerRepo) GetOwnerByID(ownerID string) (*models.Owner, error) {
+func (r *OwnerRepo) CreateOwner(fullName string, isActive bool, contacts models.OwnerContacts) (*models.Owner, error) {
 	query := `
-		SELECT id, full_name, is_active, created_at
-		FROM owners
-		WHERE id = $1
-	`
+		INSERT INTO owners (full_name, is_active, email, telegram, phone, timezone, notify_channels, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		RETURNING ` + ownerColumns
 
-	var owner models.Owner
-	err := r.db.QueryRow(query, ownerID).Scan(
-		&owner.ID,
-		&owner.FullName,
-		&owner.IsActive,
-		&owner.CreatedAt,
-	)
+	owner, err := scanOwner(r.db.QueryRow(query, fullName, isActive,
+		contacts.Email, contacts.Telegram, contacts.Phone, contacts.Timezone, channelsArray(contacts.NotifyChannels)))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to create owner: %w", err)
 	}
 
-	return &owner, nil
+	return owner, nil
 }
 
-func (r *OwnerRepo) GetAllOwners() ([]*models.Owner, error) {
-	query := `
-		SELECT id, full_name, is_active, created_at
-		FROM owners
-		ORDER BY created_at DESC
-	`
+func (r *OwnerRepo) GetOwnerByID(ownerID string) (*models.Owner, error) {
+	query := `SELECT ` + ownerColumns + ` FROM owners WHERE id = $1`
+
+	return scanOwner(r.db.QueryRow(query, ownerID))
+}
 
-	rows, err := r.db.Query(query)
+// ownerSortColumns — допустимые поля сортировки владельцев
+var ownerSortColumns = map[string]string{
+	"created_at": "o.created_at",
+	"full_name":  "o.full_name",
+}
+
+// ListOwners возвращает страницу владельцев по фильтру (на одну запись больше Limit — признак следующей страницы;
+// Limit = 0 — все владельцы) и общее число владельцев, подходящих под фильтр
+func (r *OwnerRepo) ListOwners(filter *models.OwnerFilter) ([]*models.Owner, int, error) {
+	var args queryArgs
+	var conditions []string
+	if filter.IsActive != nil {
+		conditions = append(conditions, "o.is_active = "+args.add(*filter.IsActive))
+	}
+	if filter.Query != "" {
+		conditions = append(conditions, "o.full_name ILIKE "+args.add(postgres.ContainsPattern(filter.Query)))
+	}
+	if filter.TeamID != nil {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM team_members m WHERE m.owner_id = o.id AND m.team_id = "+args.add(*filter.TeamID)+")")
+	}
+	if filter.Archived == models.ArchivedOnly {
+		conditions = append(conditions, "o.archived_at IS NOT NULL")
+	} else if filter.Archived != models.ArchivedInclude {
+		conditions = append(conditions, "o.archived_at IS NULL")
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM owners o ` + whereClause(conditions)
+	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count owners: %w", err)
+	}
+
+	column, ok := ownerSortColumns[filter.SortBy]
+	if !ok {
+		column = ownerSortColumns["created_at"]
+	}
+	direction, compare := "ASC", ">"
+	if filter.Order == "desc" {
+		direction, compare = "DESC", "<"
+	}
+
+	if filter.AfterID != nil {
+		var after interface{}
+		if filter.AfterTime != nil {
+			after = *filter.AfterTime
+		} else if filter.AfterValue != nil {
+			after = *filter.AfterValue
+		}
+		if after != nil {
+			conditions = append(conditions, fmt.Sprintf("(%s, o.id) %s (%s, %s::uuid)", column, compare, args.add(after), args.add(*filter.AfterID)))
+		}
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM owners o
+		%s
+		ORDER BY %s %s, o.id %s
+	`, ownerColumns, whereClause(conditions), column, direction, direction)
+	if filter.Limit > 0 {
+		query += ` LIMIT ` + args.add(filter.Limit+1)
+	}
+
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get owners: %w", err)
+		return nil, 0, fmt.Errorf("failed to get owners: %w", err)
 	}
 	defer rows.Close()
 
 	var owners []*models.Owner
 	for rows.Next() {
-		var owner models.Owner
-		err := rows.Scan(
-			&owner.ID,
-			&owner.FullName,
-			&owner.IsActive,
-			&owner.CreatedAt,
-		)
+		owner, err := scanOwner(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan owner: %w", err)
+			return nil, 0, fmt.Errorf("failed to scan owner: %w", err)
 		}
-		owners = append(owners, &owner)
+		owners = append(owners, owner)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error after iterating rows: %w", err)
+		return nil, 0, fmt.Errorf("error after iterating rows: %w", err)
 	}
 
-	return owners, nil
+	return owners, total, nil
 }
 
-func (r *OwnerRepo) UpdateOwner(ownerID string, fullName *string, isActive *bool) (*models.Owner, error) {
+// queryArgs накапливает аргументы запроса и возвращает их плейсхолдеры
+type queryArgs []interface{}
+
+func (a *queryArgs) add(value interface{}) string {
+	*a = append(*a, value)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(conditions, " AND ")
+}
+
+// UpdateOwner обновляет владельца: nil — поле без изменений; contacts заменяет все контакты целиком
+func (r *OwnerRepo) UpdateOwner(ownerID string, fullName *string, isActive *bool, contacts *models.OwnerContacts) (*models.Owner, error) {
 	query := `
 		UPDATE owners
 		SET 
 			full_name = COALESCE($2, full_name),
-			is_active = COALESCE($3, is_active)
+			is_active = COALESCE($3, is_active),
+			email = CASE WHEN $4 THEN $5 ELSE email END,
+			telegram = CASE WHEN $4 THEN $6 ELSE telegram END,
+			phone = CASE WHEN $4 THEN $7 ELSE phone END,
+			timezone = CASE WHEN $4 THEN $8 ELSE timezone END,
+			notify_channels = CASE WHEN $4 THEN $9 ELSE notify_channels END
 		WHERE id = $1
-		RETURNING id, full_name, is_active, created_at
+		RETURNING ` + ownerColumns
+
+	replace := contacts != nil
+	if contacts == nil {
+		contacts = &models.OwnerContacts{}
+	}
+
+	return scanOwner(r.db.QueryRow(query, ownerID, fullName, isActive, replace,
+		contacts.Email, contacts.Telegram, contacts.Phone, contacts.Timezone, channelsArray(contacts.NotifyChannels)))
+}
+
+// channelsArray готовит каналы уведомлений к записи: nil записывается как пустой массив
+func channelsArray(channels []string) interface{} {
+	if channels == nil {
+		channels = []string{}
+	}
+	return pq.Array(channels)
+}
+
+// ArchiveOwner переносит владельца в архив; для владельца, уже находящегося в архиве, или несуществующего возвращает sql.ErrNoRows
+func (r *OwnerRepo) ArchiveOwner(ownerID string, archivedBy *string) error {
+	query := `
+		UPDATE owners
+		SET archived_at = NOW(), archived_by = $2
+		WHERE id = $1 AND archived_at IS NULL
 	`
 
-	var owner models.Owner
-	err := r.db.QueryRow(query, ownerID, fullName, isActive).Scan(
-		&owner.ID,
-		&owner.FullName,
-		&owner.IsActive,
-		&owner.CreatedAt,
-	)
+	result, err := r.db.Exec(query, ownerID, archivedBy)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to archive owner: %w", err)
 	}
 
-	return &owner, nil
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
 }
 
-func (r *OwnerRepo) DeleteOwner(ownerID string) error {
-	query := `DELETE FROM owners WHERE id = $1`
+// RestoreOwner возвращает владельца из архива; для владельца не в архиве или несуществующего возвращает sql.ErrNoRows
+func (r *OwnerRepo) RestoreOwner(ownerID string) error {
+	query := `
+		UPDATE owners
+		SET archived_at = NULL, archived_by = NULL
+		WHERE id = $1 AND archived_at IS NOT NULL
+	`
 
 	result, err := r.db.Exec(query, ownerID)
 	if err != nil {
-		return fmt.Errorf("failed to delete owner: %w", err)
+		return fmt.Errorf("failed to restore owner: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -129,8 +242,76 @@ func (r *OwnerRepo) DeleteOwner(ownerID string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("owner not found")
+		return sql.ErrNoRows
 	}
 
 	return nil
 }
+
+// GetOwnerPurgeReport возвращает отчёт о последствиях удаления владельца
+func (r *OwnerRepo) GetOwnerPurgeReport(ownerID string) (*models.OwnerPurgeReport, error) {
+	query := `
+		SELECT o.full_name,
+			(SELECT COUNT(*) FROM bots WHERE owner_id = o.id),
+			(SELECT COUNT(*) FROM team_members WHERE owner_id = o.id),
+			(SELECT COUNT(*) FROM tokens WHERE owner_id = o.id)
+		FROM owners o
+		WHERE o.id = $1
+	`
+
+	report := &models.OwnerPurgeReport{OwnerID: ownerID}
+	if err := r.db.QueryRow(query, ownerID).Scan(&report.FullName, &report.BotsDetached, &report.TeamMemberships, &report.Tokens); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// PurgeOwner окончательно удаляет владельца из архива и возвращает отчёт об удалённом.
+// Строка владельца блокируется до конца транзакции, поэтому число отвязанных ботов совпадает с фактическим.
+// Снятие владельца с его ботов записывается в историю передачи от имени purgedBy.
+// Для владельца не в архиве или несуществующего возвращает sql.ErrNoRows.
+func (r *OwnerRepo) PurgeOwner(ownerID string, purgedBy *string) (*models.OwnerPurgeReport, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	report := &models.OwnerPurgeReport{OwnerID: ownerID}
+	lockQuery := `SELECT full_name FROM owners WHERE id = $1 AND archived_at IS NOT NULL FOR UPDATE`
+	if err := tx.QueryRow(lockQuery, ownerID).Scan(&report.FullName); err != nil {
+		return nil, err
+	}
+
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM bots WHERE owner_id = $1`, ownerID).Scan(&report.BotsDetached); err != nil {
+		return nil, fmt.Errorf("failed to count owner bots: %w", err)
+	}
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM team_members WHERE owner_id = $1`, ownerID).Scan(&report.TeamMemberships); err != nil {
+		return nil, fmt.Errorf("failed to count owner team memberships: %w", err)
+	}
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM tokens WHERE owner_id = $1`, ownerID).Scan(&report.Tokens); err != nil {
+		return nil, fmt.Errorf("failed to count owner tokens: %w", err)
+	}
+
+	historyQuery := `
+		INSERT INTO bot_owner_history (bot_id, from_owner_id, to_owner_id, changed_by, reason)
+		SELECT id, owner_id, NULL, $2, 'владелец удалён'
+		FROM bots
+		WHERE owner_id = $1
+	`
+	if _, err := tx.Exec(historyQuery, ownerID, purgedBy); err != nil {
+		return nil, fmt.Errorf("failed to insert owner history: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM owners WHERE id = $1`, ownerID); err != nil {
+		return nil, fmt.Errorf("failed to delete owner: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	report.Purged = true
+	return report, nil
+}
@@ -0,0 +1,138 @@
+package partitionrepo
+
+import (
+	"database/sql"
+	"fmt"
+	"logging_api/internal/models"
+	"regexp"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var boundRe = regexp.MustCompile(`FROM \('([^']+)'\) TO \('([^']+)'\)`)
+
+var boundLayouts = []string{
+	"2006-01-02 15:04:05-07",
+	"2006-01-02 15:04:05-07:00",
+	"2006-01-02 15:04:05.999999-07",
+	"2006-01-02 15:04:05.999999-07:00",
+}
+
+type PartitionRepo struct {
+	db *sql.DB
+}
+
+func NewPartitionRepo(db *sql.DB) *PartitionRepo {
+	return &PartitionRepo{db: db}
+}
+
+// EnsurePartition создаёт секцию logs за месяц указанной даты, если её ещё нет
+func (r *PartitionRepo) EnsurePartition(month time.Time) (string, error) {
+	var name string
+	err := r.db.QueryRow(`SELECT create_logs_partition($1::date)`, month.Format("2006-01-02")).Scan(&name)
+	if err != nil {
+		return "", fmt.Errorf("failed to create partition: %w", err)
+	}
+
+	return name, nil
+}
+
+func (r *PartitionRepo) ListPartitions() ([]*models.LogPartition, error) {
+	query := `
+		SELECT c.relname,
+		       pg_get_expr(c.relpartbound, c.oid),
+		       GREATEST(c.reltuples, 0)::bigint,
+		       pg_total_relation_size(c.oid),
+		       pg_size_pretty(pg_total_relation_size(c.oid))
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'logs'::regclass
+		ORDER BY c.relname
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partitions: %w", err)
+	}
+	defer rows.Close()
+
+	var partitions []*models.LogPartition
+	for rows.Next() {
+		var partition models.LogPartition
+		var bound string
+		err := rows.Scan(
+			&partition.Name,
+			&bound,
+			&partition.RowsApprox,
+			&partition.TotalBytes,
+			&partition.TotalSize,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan partition: %w", err)
+		}
+
+		if bound == "DEFAULT" {
+			partition.IsDefault = true
+		} else if m := boundRe.FindStringSubmatch(bound); m != nil {
+			partition.From = parseBound(m[1])
+			partition.To = parseBound(m[2])
+		}
+
+		partitions = append(partitions, &partition)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return partitions, nil
+}
+
+// DropPartition отсоединяет и удаляет секцию целиком
+func (r *PartitionRepo) DropPartition(name string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(fmt.Sprintf(`ALTER TABLE logs DETACH PARTITION %s`, pq.QuoteIdentifier(name))); err != nil {
+		return fmt.Errorf("failed to detach partition %s: %w", name, err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf(`DROP TABLE %s`, pq.QuoteIdentifier(name))); err != nil {
+		return fmt.Errorf("failed to drop partition %s: %w", name, err)
+	}
+
+	return tx.Commit()
+}
+
+// DeleteLogsBefore удаляет не более limit логов указанного уровня старше before
+func (r *PartitionRepo) DeleteLogsBefore(status string, before time.Time, limit int) (int64, error) {
+	query := `
+		DELETE FROM logs
+		WHERE (id, created_at) IN (
+			SELECT id, created_at
+			FROM logs
+			WHERE status = $1 AND created_at < $2
+			LIMIT $3
+		)
+	`
+
+	result, err := r.db.Exec(query, status, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete logs: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+func parseBound(value string) *time.Time {
+	for _, layout := range boundLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			t = t.UTC()
+			return &t
+		}
+	}
+	return nil
+}
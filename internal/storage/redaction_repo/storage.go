@@ -0,0 +1,207 @@
+package redactionrepo
+
+import (
+	"database/sql"
+	"fmt"
+	"logging_api/internal/models"
+	"strings"
+	"time"
+)
+
+type RedactionRepo struct {
+	db *sql.DB
+}
+
+func NewRedactionRepo(db *sql.DB) *RedactionRepo {
+	return &RedactionRepo{db: db}
+}
+
+const ruleColumns = `id, bot_id, name, pattern, replacement, is_active, created_at`
+
+func scanRule(row interface{ Scan(...interface{}) error }) (*models.RedactionRule, error) {
+	var rule models.RedactionRule
+	err := row.Scan(
+		&rule.ID,
+		&rule.BotID,
+		&rule.Name,
+		&rule.Pattern,
+		&rule.Replacement,
+		&rule.IsActive,
+		&rule.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *RedactionRepo) CreateRule(botID *string, name, pattern string, replacement *string, isActive bool) (*models.RedactionRule, error) {
+	query := `
+		INSERT INTO redaction_rules (bot_id, name, pattern, replacement, is_active, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING ` + ruleColumns
+
+	rule, err := scanRule(r.db.QueryRow(query, botID, name, pattern, replacement, isActive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create redaction rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (r *RedactionRepo) GetRuleByID(ruleID string) (*models.RedactionRule, error) {
+	query := `SELECT ` + ruleColumns + ` FROM redaction_rules WHERE id = $1`
+
+	return scanRule(r.db.QueryRow(query, ruleID))
+}
+
+// ListRules возвращает правила бота и глобальные правила (botID = nil — все правила);
+// activeOnly оставляет только включённые
+func (r *RedactionRepo) ListRules(botID *string, activeOnly bool) ([]*models.RedactionRule, error) {
+	var conditions []string
+	var args []interface{}
+	if botID != nil {
+		args = append(args, *botID)
+		conditions = append(conditions, fmt.Sprintf("(bot_id = $%d OR bot_id IS NULL)", len(args)))
+	}
+	if activeOnly {
+		conditions = append(conditions, "is_active")
+	}
+
+	query := `SELECT ` + ruleColumns + ` FROM redaction_rules`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY created_at`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get redaction rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*models.RedactionRule
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan redaction rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *RedactionRepo) UpdateRule(ruleID string, name, pattern, replacement *string, isActive *bool) (*models.RedactionRule, error) {
+	query := `
+		UPDATE redaction_rules
+		SET
+			name = COALESCE($2, name),
+			pattern = COALESCE($3, pattern),
+			replacement = COALESCE($4, replacement),
+			is_active = COALESCE($5, is_active)
+		WHERE id = $1
+		RETURNING ` + ruleColumns
+
+	return scanRule(r.db.QueryRow(query, ruleID, name, pattern, replacement, isActive))
+}
+
+// DeleteRule удаляет правило, возвращает sql.ErrNoRows если его нет
+func (r *RedactionRepo) DeleteRule(ruleID string) error {
+	result, err := r.db.Exec(`DELETE FROM redaction_rules WHERE id = $1`, ruleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete redaction rule: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// AddCounts прибавляет приросты к счётчикам замен одним запросом
+func (r *RedactionRepo) AddCounts(deltas []models.RedactionCountDelta) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(deltas))
+	args := make([]interface{}, 0, len(deltas)*4)
+	for _, delta := range deltas {
+		n := len(args)
+		values = append(values, fmt.Sprintf("($%d::uuid, $%d, $%d::date, $%d::bigint)", n+1, n+2, n+3, n+4))
+		args = append(args, delta.BotID, delta.Rule, delta.Day.Format("2006-01-02"), delta.Count)
+	}
+
+	query := `
+		INSERT INTO redaction_counts (bot_id, rule, day, count)
+		VALUES ` + strings.Join(values, ", ") + `
+		ON CONFLICT ((COALESCE(bot_id, '00000000-0000-0000-0000-000000000000'::uuid)), rule, day)
+		DO UPDATE SET count = redaction_counts.count + EXCLUDED.count
+	`
+
+	if _, err := r.db.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to add redaction counts: %w", err)
+	}
+
+	return nil
+}
+
+// ListCounts возвращает число замен по ботам и правилам за дни [from, to]
+func (r *RedactionRepo) ListCounts(botID *string, from, to *time.Time) ([]*models.RedactionCount, error) {
+	var conditions []string
+	var args []interface{}
+	if botID != nil {
+		args = append(args, *botID)
+		conditions = append(conditions, fmt.Sprintf("c.bot_id = $%d", len(args)))
+	}
+	if from != nil {
+		args = append(args, from.Format("2006-01-02"))
+		conditions = append(conditions, fmt.Sprintf("c.day >= $%d::date", len(args)))
+	}
+	if to != nil {
+		args = append(args, to.Format("2006-01-02"))
+		conditions = append(conditions, fmt.Sprintf("c.day <= $%d::date", len(args)))
+	}
+
+	query := `
+		SELECT c.bot_id, b.code, c.rule, SUM(c.count)
+		FROM redaction_counts c
+		LEFT JOIN bots b ON b.id = c.bot_id`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += `
+		GROUP BY c.bot_id, b.code, c.rule
+		ORDER BY SUM(c.count) DESC`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get redaction counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []*models.RedactionCount{}
+	for rows.Next() {
+		var count models.RedactionCount
+		if err := rows.Scan(&count.BotID, &count.BotCode, &count.Rule, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan redaction count: %w", err)
+		}
+		counts = append(counts, &count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,266 @@
+package teamrepo
+
+import (
+	"database/sql"
+	"fmt"
+	"logging_api/internal/models"
+	"logging_api/pkg/postgres"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+type TeamRepo struct {
+	db *sql.DB
+}
+
+func NewTeamRepo(db *sql.DB) *TeamRepo {
+	return &TeamRepo{db: db}
+}
+
+// teamSelect выбирает команду с числом участников и ботов не в архиве
+const teamSelect = `
+	SELECT t.id, t.name, t.description, t.notify_webhook_url, t.notify_states, t.created_at, t.updated_at,
+		(SELECT COUNT(*) FROM team_members m WHERE m.team_id = t.id),
+		(SELECT COUNT(*) FROM bots b WHERE b.team_id = t.id AND b.archived_at IS NULL)
+	FROM teams t
+`
+
+func scanTeam(row interface{ Scan(...interface{}) error }) (*models.Team, error) {
+	var team models.Team
+	err := row.Scan(
+		&team.ID,
+		&team.Name,
+		&team.Description,
+		&team.Notifications.WebhookURL,
+		pq.Array(&team.Notifications.States),
+		&team.CreatedAt,
+		&team.UpdatedAt,
+		&team.MemberCount,
+		&team.BotCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if team.Notifications.States == nil {
+		team.Notifications.States = []string{}
+	}
+	return &team, nil
+}
+
+// CreateTeam создаёт команду; States = nil — состояния по умолчанию (failing, silent)
+func (r *TeamRepo) CreateTeam(name string, description *string, notifications models.TeamNotifications) (string, error) {
+	query := `
+		INSERT INTO teams (name, description, notify_webhook_url, notify_states)
+		VALUES ($1, $2, $3, COALESCE($4, '{failing,silent}'::text[]))
+		RETURNING id
+	`
+
+	var states interface{}
+	if notifications.States != nil {
+		states = pq.Array(notifications.States)
+	}
+
+	var id string
+	if err := r.db.QueryRow(query, name, description, notifications.WebhookURL, states).Scan(&id); err != nil {
+		return "", fmt.Errorf("failed to create team: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *TeamRepo) GetTeamByID(teamID string) (*models.Team, error) {
+	return scanTeam(r.db.QueryRow(teamSelect+` WHERE t.id = $1`, teamID))
+}
+
+func (r *TeamRepo) GetTeamByName(name string) (*models.Team, error) {
+	return scanTeam(r.db.QueryRow(teamSelect+` WHERE t.name = $1`, name))
+}
+
+// ListTeams возвращает команды по фильтру, упорядоченные по имени
+func (r *TeamRepo) ListTeams(filter *models.TeamFilter) ([]*models.Team, error) {
+	var args queryArgs
+	var conditions []string
+	if filter.Query != "" {
+		conditions = append(conditions, "t.name ILIKE "+args.add(postgres.ContainsPattern(filter.Query)))
+	}
+	if filter.OwnerID != nil {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM team_members m WHERE m.team_id = t.id AND m.owner_id = "+args.add(*filter.OwnerID)+")")
+	}
+
+	query := teamSelect + whereClause(conditions) + ` ORDER BY t.name`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []*models.Team
+	for rows.Next() {
+		team, err := scanTeam(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan team: %w", err)
+		}
+		teams = append(teams, team)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return teams, nil
+}
+
+// GetTeamsByIDs возвращает команды с указанными ID
+func (r *TeamRepo) GetTeamsByIDs(teamIDs []string) ([]*models.Team, error) {
+	rows, err := r.db.Query(teamSelect+` WHERE t.id = ANY($1::uuid[])`, pq.Array(teamIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []*models.Team
+	for rows.Next() {
+		team, err := scanTeam(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan team: %w", err)
+		}
+		teams = append(teams, team)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return teams, nil
+}
+
+// queryArgs накапливает аргументы запроса и возвращает их плейсхолдеры
+type queryArgs []interface{}
+
+func (a *queryArgs) add(value interface{}) string {
+	*a = append(*a, value)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(conditions, " AND ")
+}
+
+// UpdateTeam меняет имя и описание команды (nil — без изменений), возвращает sql.ErrNoRows если её нет
+func (r *TeamRepo) UpdateTeam(teamID string, name, description *string) error {
+	query := `
+		UPDATE teams
+		SET
+			name = COALESCE($2, name),
+			description = COALESCE($3, description),
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return execAffected(r.db, query, teamID, name, description)
+}
+
+// SetNotifications заменяет настройки уведомлений команды, возвращает sql.ErrNoRows если её нет
+func (r *TeamRepo) SetNotifications(teamID string, notifications models.TeamNotifications) error {
+	query := `
+		UPDATE teams
+		SET notify_webhook_url = $2, notify_states = $3, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	states := notifications.States
+	if states == nil {
+		states = []string{}
+	}
+
+	return execAffected(r.db, query, teamID, notifications.WebhookURL, pq.Array(states))
+}
+
+// DeleteTeam удаляет команду: боты остаются без команды, токены команды удаляются.
+// Возвращает sql.ErrNoRows если команды нет.
+func (r *TeamRepo) DeleteTeam(teamID string) error {
+	return execAffected(r.db, `DELETE FROM teams WHERE id = $1`, teamID)
+}
+
+// ListMembers возвращает участников команды: сначала руководители, затем по имени
+func (r *TeamRepo) ListMembers(teamID string) ([]*models.TeamMember, error) {
+	query := `
+		SELECT m.owner_id, o.full_name, m.role, m.added_at
+		FROM team_members m
+		JOIN owners o ON o.id = m.owner_id
+		WHERE m.team_id = $1
+		ORDER BY m.role = 'lead' DESC, o.full_name, m.owner_id
+	`
+
+	rows, err := r.db.Query(query, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []*models.TeamMember
+	for rows.Next() {
+		var member models.TeamMember
+		if err := rows.Scan(&member.OwnerID, &member.FullName, &member.Role, &member.AddedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan team member: %w", err)
+		}
+		members = append(members, &member)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after iterating rows: %w", err)
+	}
+
+	return members, nil
+}
+
+// SetMember добавляет владельца в команду или меняет его роль
+func (r *TeamRepo) SetMember(teamID, ownerID, role string) (*models.TeamMember, error) {
+	query := `
+		WITH member AS (
+			INSERT INTO team_members (team_id, owner_id, role)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (team_id, owner_id) DO UPDATE SET role = EXCLUDED.role
+			RETURNING owner_id, role, added_at
+		)
+		SELECT member.owner_id, o.full_name, member.role, member.added_at
+		FROM member
+		JOIN owners o ON o.id = member.owner_id
+	`
+
+	var member models.TeamMember
+	err := r.db.QueryRow(query, teamID, ownerID, role).Scan(&member.OwnerID, &member.FullName, &member.Role, &member.AddedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set team member: %w", err)
+	}
+
+	return &member, nil
+}
+
+// RemoveMember исключает владельца из команды, возвращает sql.ErrNoRows если он в ней не состоит
+func (r *TeamRepo) RemoveMember(teamID, ownerID string) error {
+	return execAffected(r.db, `DELETE FROM team_members WHERE team_id = $1 AND owner_id = $2`, teamID, ownerID)
+}
+
+// execAffected выполняет запрос и возвращает sql.ErrNoRows, если он не затронул ни одной строки
+func execAffected(db *sql.DB, query string, args ...interface{}) error {
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
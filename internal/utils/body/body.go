@@ -0,0 +1,65 @@
+package body
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	ErrTooLarge            = errors.New("тело запроса слишком большое")
+	ErrUnsupportedEncoding = errors.New("неподдерживаемый Content-Encoding")
+)
+
+// zstdMaxWindow ограничивает окно zstd-декодера (и память на запрос); окна до 8 МБ дают уровни сжатия по умолчанию
+const zstdMaxWindow = 8 << 20
+
+// Read читает тело запроса с учётом Content-Encoding (identity, gzip, zstd).
+// maxBytes ограничивает размер после распаковки (защита от «zip-бомб»); при превышении возвращается ErrTooLarge.
+func Read(r *http.Request, maxBytes int64) ([]byte, error) {
+	var reader io.Reader = r.Body
+
+	switch encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))); encoding {
+	case "", "identity":
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("некорректные gzip-данные: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	case "zstd":
+		zr, err := zstd.NewReader(r.Body, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxWindow(zstdMaxWindow))
+		if err != nil {
+			return nil, fmt.Errorf("некорректные zstd-данные: %w", err)
+		}
+		defer zr.Close()
+		reader = zr
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, encoding)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения тела запроса: %w", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, ErrTooLarge
+	}
+
+	return data, nil
+}
+
+// MediaType возвращает тип содержимого без параметров в нижнем регистре
+func MediaType(r *http.Request) string {
+	contentType := r.Header.Get("Content-Type")
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
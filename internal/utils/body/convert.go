@@ -0,0 +1,91 @@
+package body
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/ugorji/go/codec"
+)
+
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{}
+	// Карты и строки — в виде, который кодируется в JSON
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	h.RawToString = true
+	return h
+}()
+
+// IsNDJSON сообщает, что тип содержимого — JSON-объекты по одному на строку
+func IsNDJSON(mediaType string) bool {
+	return mediaType == "application/x-ndjson" || mediaType == "application/ndjson"
+}
+
+// IsMsgpack сообщает, что тип содержимого — MessagePack
+func IsMsgpack(mediaType string) bool {
+	return mediaType == "application/msgpack" || mediaType == "application/x-msgpack" || mediaType == "application/vnd.msgpack"
+}
+
+// NDJSONToJSON собирает строки NDJSON в JSON-массив; пустые строки пропускаются
+func NDJSONToJSON(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+	out.WriteByte('[')
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64<<10), len(data)+1)
+	line, n := 0, 0
+	for scanner.Scan() {
+		line++
+		record := bytes.TrimSpace(scanner.Bytes())
+		if len(record) == 0 {
+			continue
+		}
+		if !json.Valid(record) {
+			return nil, fmt.Errorf("строка %d: некорректный JSON", line)
+		}
+		if n > 0 {
+			out.WriteByte(',')
+		}
+		out.Write(record)
+		n++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения NDJSON: %w", err)
+	}
+
+	out.WriteByte(']')
+	return out.Bytes(), nil
+}
+
+// MsgpackToJSON перекодирует MessagePack в JSON. Одно значение перекодируется как есть,
+// несколько значений подряд (поток записей) — в JSON-массив.
+func MsgpackToJSON(data []byte) ([]byte, error) {
+	decoder := codec.NewDecoderBytes(data, msgpackHandle)
+
+	var values []interface{}
+	for decoder.NumBytesRead() < len(data) {
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			return nil, fmt.Errorf("некорректные данные MessagePack: %w", err)
+		}
+		values = append(values, value)
+	}
+
+	if len(values) == 0 {
+		return nil, errors.New("пустое тело MessagePack")
+	}
+
+	var result interface{} = values
+	if len(values) == 1 {
+		result = values[0]
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("данные MessagePack не представимы в JSON: %w", err)
+	}
+	return encoded, nil
+}
@@ -7,8 +7,23 @@ var (
 	ErrAlreadyExists = errors.New("ресурс уже существует")
 	ErrUnauthorized  = errors.New("не авторизован")
 	ErrForbidden     = errors.New("доступ запрещён")
+	ErrInvalidInput  = errors.New("некорректные параметры запроса")
+	ErrConflict      = errors.New("операция недоступна в текущем состоянии ресурса")
+	ErrUnprocessable = errors.New("запрос не может быть обработан")
 )
 
 func IsNotFound(err error) bool {
 	return errors.Is(err, ErrNotFound)
 }
+
+func IsInvalidInput(err error) bool {
+	return errors.Is(err, ErrInvalidInput)
+}
+
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+func IsUnprocessable(err error) bool {
+	return errors.Is(err, ErrUnprocessable)
+}
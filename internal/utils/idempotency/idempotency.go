@@ -0,0 +1,102 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"logging_api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// HeaderKey — заголовок с ключом идемпотентности запроса
+	HeaderKey = "Idempotency-Key"
+	// HeaderReplayed выставляется в ответе, если вместо создания возвращена ранее созданная запись
+	HeaderReplayed = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+)
+
+var (
+	ErrKeyTooLong  = errors.New("ключ идемпотентности длиннее 255 символов")
+	ErrKeyMismatch = errors.New("заголовок Idempotency-Key не совпадает с event_id")
+)
+
+// Key возвращает ключ идемпотентности одиночного запроса из заголовка Idempotency-Key или поля event_id.
+// Если переданы оба, они должны совпадать. request — разобранное тело запроса, его хеш хранится вместе с ключом.
+// nil — запрос без ключа.
+func Key(c *gin.Context, eventID *string, request interface{}) (*models.IdempotencyKey, error) {
+	header := strings.TrimSpace(c.GetHeader(HeaderKey))
+
+	key := header
+	if eventID != nil && *eventID != "" {
+		if header != "" && header != *eventID {
+			return nil, ErrKeyMismatch
+		}
+		key = *eventID
+	}
+
+	return newKey(c, key, request)
+}
+
+// ItemKey возвращает ключ идемпотентности записи пакета: event_id записи, иначе заголовок Idempotency-Key
+// с номером записи (повтор пакета целиком с тем же заголовком не создаёт дублей). item — разобранная запись,
+// её хеш хранится вместе с ключом. nil — запись без ключа.
+func ItemKey(c *gin.Context, eventID *string, index int, item interface{}) (*models.IdempotencyKey, error) {
+	if eventID != nil && *eventID != "" {
+		return newKey(c, *eventID, item)
+	}
+
+	header := strings.TrimSpace(c.GetHeader(HeaderKey))
+	if header == "" {
+		return nil, nil
+	}
+
+	return newKey(c, header+":"+strconv.Itoa(index), item)
+}
+
+// MarkReplayed помечает ответ как повтор ранее выполненного запроса
+func MarkReplayed(c *gin.Context) {
+	c.Header(HeaderReplayed, "true")
+}
+
+// newKey привязывает ключ к боту токена, а для токенов без бота — к самому токену,
+// чтобы одинаковые ключи разных клиентов не пересекались
+func newKey(c *gin.Context, key string, request interface{}) (*models.IdempotencyKey, error) {
+	if key == "" {
+		return nil, nil
+	}
+	if len(key) > maxKeyLength {
+		return nil, ErrKeyTooLong
+	}
+
+	namespace := c.GetString("bot_id")
+	if namespace == "" {
+		namespace = "token:" + c.GetString("token_id")
+	}
+
+	hash, err := requestHash(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.IdempotencyKey{Namespace: namespace, Key: key, RequestHash: hash}, nil
+}
+
+// requestHash возвращает SHA-256 (hex) разобранного запроса. Хешируется JSON-представление, а не исходное тело,
+// поэтому один и тот же запрос в JSON, NDJSON или MessagePack даёт одинаковый хеш
+func requestHash(request interface{}) (string, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("не удалось вычислить хеш запроса: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
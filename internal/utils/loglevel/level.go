@@ -0,0 +1,34 @@
+package loglevel
+
+import "strings"
+
+// FromText сопоставляет текстовое обозначение уровня (как пишут агенты и библиотеки логирования)
+// значению log_status. Для неизвестного или пустого значения возвращается fallback.
+func FromText(text, fallback string) string {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "trace", "debug", "dbug", "verbose":
+		return "Debug"
+	case "info", "information", "informational", "notice", "inf":
+		return "Info"
+	case "warn", "warning", "wrn":
+		return "Warning"
+	case "error", "err", "eror":
+		return "Error"
+	case "critical", "crit", "fatal", "panic", "emerg", "emergency", "alert":
+		return "Critical"
+	}
+	return fallback
+}
+
+// Levels — значения log_status по возрастанию важности
+var Levels = []string{"Debug", "Info", "Warning", "Error", "Critical"}
+
+// Rank возвращает порядковый номер уровня (Debug — 0, Critical — 4); для неизвестного значения -1
+func Rank(level string) int {
+	for i, value := range Levels {
+		if value == level {
+			return i
+		}
+	}
+	return -1
+}
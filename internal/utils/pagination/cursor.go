@@ -0,0 +1,59 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+var ErrInvalidCursor = errors.New("некорректный курсор")
+
+// Cursor — непрозрачный курсор постраничной выдачи.
+// Для сортировки по времени хранит ключ последней записи (Time, ID),
+// для сортировки по релевантности — смещение.
+type Cursor struct {
+	Time   *time.Time `json:"t,omitempty"`
+	ID     string     `json:"id,omitempty"`
+	Value  string     `json:"v,omitempty"`
+	Offset int        `json:"o,omitempty"`
+}
+
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode разбирает курсор; пустая строка означает первую страницу
+func Decode(value string) (*Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// Limit возвращает лимит по умолчанию для нулевого значения и ограничивает максимум
+func Limit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	if limit > MaxLimit {
+		return MaxLimit
+	}
+	return limit
+}
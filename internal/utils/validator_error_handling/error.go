@@ -3,16 +3,23 @@ package validator_error_handling
 import "github.com/go-playground/validator/v10"
 
 var validationErrors = map[string]string{
-	"required":   "поле обязательно для заполнения",
-	"email":      "некорректный email",
-	"min":        "значение должно быть больше минимального",
-	"max":        "значение должно быть меньше максимального",
-	"uuid":       "некорректный формат UUID",
-	"oneof":      "недопустимое значение",
-	"unique":     "значение должно быть уникальным",
-	"exists":     "значение должно существовать",
-	"not_exists": "значение не должно существовать",
-	"not_empty":  "значение не должно быть пустым",
+	"required":    "поле обязательно для заполнения",
+	"email":       "некорректный email",
+	"min":         "значение должно быть больше минимального",
+	"max":         "значение должно быть меньше максимального",
+	"uuid":        "некорректный формат UUID",
+	"oneof":       "недопустимое значение",
+	"unique":      "значение должно быть уникальным",
+	"exists":      "значение должно существовать",
+	"not_exists":  "значение не должно существовать",
+	"not_empty":   "значение не должно быть пустым",
+	"datetime":    "некорректный формат даты",
+	"url":         "некорректный URL",
+	"e164":        "телефон должен быть в формате +79991234567",
+	"telegram":    "Telegram должен быть в формате @username",
+	"timezone":    "неизвестный часовой пояс",
+	"hexadecimal": "значение должно быть шестнадцатеричным",
+	"ip":          "некорректный IP-адрес",
 }
 
 type ValidationError struct {
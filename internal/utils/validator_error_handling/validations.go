@@ -0,0 +1,26 @@
+package validator_error_handling
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// telegramPattern — имя пользователя Telegram с @: 5–32 латинских буквы, цифры и _, первая — буква
+var telegramPattern = regexp.MustCompile(`^@[A-Za-z][A-Za-z0-9_]{4,31}$`)
+
+// Собственные правила регистрируются в валидаторе gin при импорте пакета — его импортируют все обработчики
+func init() {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	if err := engine.RegisterValidation("telegram", validateTelegram); err != nil {
+		panic(err)
+	}
+}
+
+func validateTelegram(fl validator.FieldLevel) bool {
+	return telegramPattern.MatchString(fl.Field().String())
+}
@@ -0,0 +1,40 @@
+package validator_error_handling
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestTelegramValidation(t *testing.T) {
+	type request struct {
+		Telegram *string `binding:"omitempty,max=33,telegram"`
+	}
+
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{value: "@ivanov", valid: true},
+		{value: "@Ivan_Petrov_2", valid: true},
+		{value: "@abcde", valid: true},
+		{value: "@abcdefghijklmnopqrstuvwxyz012345", valid: true},
+		{value: "ivanov", valid: false},
+		{value: "@iva", valid: false},
+		{value: "@1vanov", valid: false},
+		{value: "@ivan-ov", valid: false},
+		{value: "@иванов", valid: false},
+		{value: "@ivanov ", valid: false},
+		{value: "@abcdefghijklmnopqrstuvwxyz0123456", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			value := tt.value
+			err := binding.Validator.ValidateStruct(&request{Telegram: &value})
+			if (err == nil) != tt.valid {
+				t.Errorf("validate(%q) error = %v, want valid %v", tt.value, err, tt.valid)
+			}
+		})
+	}
+}
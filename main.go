@@ -1,28 +1,76 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"logging_api/configs"
 	_ "logging_api/docs"
 	"logging_api/internal/handlers"
+	"logging_api/internal/handlers/archive_handler"
 	"logging_api/internal/handlers/auth_handler"
 	"logging_api/internal/handlers/bot_handler"
+	"logging_api/internal/handlers/bot_reference_handler"
+	"logging_api/internal/handlers/catalog_handler"
+	"logging_api/internal/handlers/deployment_handler"
 	"logging_api/internal/handlers/eff_run_handler"
+	"logging_api/internal/handlers/es_handler"
+	"logging_api/internal/handlers/export_handler"
+	"logging_api/internal/handlers/health_handler"
+	"logging_api/internal/handlers/host_handler"
 	"logging_api/internal/handlers/log_handler"
+	"logging_api/internal/handlers/log_policy_handler"
+	"logging_api/internal/handlers/loki_handler"
+	"logging_api/internal/handlers/otlp_handler"
 	"logging_api/internal/handlers/owner_handler"
+	"logging_api/internal/handlers/partition_handler"
+	"logging_api/internal/handlers/profile_handler"
+	"logging_api/internal/handlers/redaction_handler"
+	"logging_api/internal/handlers/team_handler"
+	"logging_api/internal/handlers/ws_handler"
 	"logging_api/internal/middleware"
+	archiveservice "logging_api/internal/service/archive_service"
 	authservice "logging_api/internal/service/auth_service"
+	botreferenceservice "logging_api/internal/service/bot_reference_service"
 	botservice "logging_api/internal/service/bot_service"
+	catalogservice "logging_api/internal/service/catalog_service"
+	deploymentservice "logging_api/internal/service/deployment_service"
 	effrunservice "logging_api/internal/service/eff_run_service"
+	healthservice "logging_api/internal/service/health_service"
+	hostservice "logging_api/internal/service/host_service"
+	idempotencyservice "logging_api/internal/service/idempotency_service"
+	logpolicyservice "logging_api/internal/service/log_policy_service"
 	logservice "logging_api/internal/service/log_service"
 	ownerservice "logging_api/internal/service/owner_service"
+	partitionservice "logging_api/internal/service/partition_service"
+	redactionservice "logging_api/internal/service/redaction_service"
+	streamservice "logging_api/internal/service/stream_service"
+	syslogservice "logging_api/internal/service/syslog_service"
+	teamservice "logging_api/internal/service/team_service"
+	archiverepo "logging_api/internal/storage/archive_repo"
 	authrepo "logging_api/internal/storage/auth_repo"
+	botreferencerepo "logging_api/internal/storage/bot_reference_repo"
 	botrepo "logging_api/internal/storage/bot_repo"
+	catalogrepo "logging_api/internal/storage/catalog_repo"
+	deploymentrepo "logging_api/internal/storage/deployment_repo"
 	effrunrepo "logging_api/internal/storage/eff_run_repo"
+	healthrepo "logging_api/internal/storage/health_repo"
+	hostrepo "logging_api/internal/storage/host_repo"
+	idempotencyrepo "logging_api/internal/storage/idempotency_repo"
+	logpolicyrepo "logging_api/internal/storage/log_policy_repo"
 	logrepo "logging_api/internal/storage/log_repo"
 	ownerrepo "logging_api/internal/storage/owner_repo"
+	partitionrepo "logging_api/internal/storage/partition_repo"
+	redactionrepo "logging_api/internal/storage/redaction_repo"
+	teamrepo "logging_api/internal/storage/team_repo"
+	"logging_api/pkg/archive"
 	"logging_api/pkg/postgres"
 	"logging_api/pkg/sentry"
 )
@@ -64,28 +112,136 @@ func main() {
 	ownerRepo := ownerrepo.NewOwnerRepo(db)
 	logRepo := logrepo.NewLogRepo(db)
 	effRunRepo := effrunrepo.NewEffRunRepo(db)
+	partitionRepo := partitionrepo.NewPartitionRepo(db)
+	archiveRepo := archiverepo.NewArchiveRepo(db)
+	idempotencyRepo := idempotencyrepo.NewIdempotencyRepo(db)
+	redactionRepo := redactionrepo.NewRedactionRepo(db)
+	logPolicyRepo := logpolicyrepo.NewLogPolicyRepo(db)
+	healthRepo := healthrepo.NewHealthRepo(db)
+	catalogRepo := catalogrepo.NewCatalogRepo(db)
+	teamRepo := teamrepo.NewTeamRepo(db)
+	deploymentRepo := deploymentrepo.NewDeploymentRepo(db)
+	hostRepo := hostrepo.NewHostRepo(db)
+	botReferenceRepo := botreferencerepo.NewBotReferenceRepo(db)
 
-	authService := authservice.NewAuthService(authRepo, botRepo)
-	botService := botservice.NewBotService(botRepo)
+	archiveStorage, err := archive.NewStorage(&config.Archive)
+	if err != nil {
+		log.Fatalf("Failed to init archive storage: %v", err)
+	}
+
+	streamHub := streamservice.NewHub()
+
+	authService := authservice.NewAuthService(authRepo, botRepo, teamRepo, ownerRepo)
+	teamService := teamservice.NewTeamService(teamRepo, ownerRepo, config.Teams)
+	healthService := healthservice.NewHealthService(healthRepo, teamService, config.Health)
+	botService := botservice.NewBotService(botRepo, ownerRepo, teamRepo, botReferenceRepo, healthService)
+	botReferenceService := botreferenceservice.NewBotReferenceService(botReferenceRepo)
 	ownerService := ownerservice.NewOwnerService(ownerRepo)
-	logService := logservice.NewLogService(logRepo, botRepo)
-	effRunService := effrunservice.NewEffRunService(effRunRepo)
+	redactionService := redactionservice.NewRedactionService(redactionRepo, config.Redaction)
+	logPolicyService := logpolicyservice.NewLogPolicyService(logPolicyRepo, botRepo, config.LogPolicy)
+	deploymentService := deploymentservice.NewDeploymentService(deploymentRepo, botRepo, config.Deployments, config.Ingest)
+	logService := logservice.NewLogService(logRepo, botRepo, streamHub, redactionService, logPolicyService, deploymentService, config.Ingest, config.Idempotency)
+	catalogService := catalogservice.NewCatalogService(catalogRepo, logPolicyService)
+	hostService := hostservice.NewHostService(hostRepo, config.Hosts)
+	effRunService := effrunservice.NewEffRunService(effRunRepo, streamHub, deploymentService, hostService, config.Idempotency)
+	archiveService := archiveservice.NewArchiveService(archiveRepo, archiveStorage)
+
+	var archiver partitionservice.Archiver
+	if config.Archive.Enabled {
+		archiver = archiveService
+	}
+	partitionService := partitionservice.NewPartitionService(partitionRepo, archiver, config.Retention)
+	idempotencyService := idempotencyservice.NewIdempotencyService(idempotencyRepo, config.Idempotency)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := redactionService.Start(ctx); err != nil {
+		log.Fatalf("Failed to start redaction: %v", err)
+	}
+	if err := logPolicyService.Start(ctx); err != nil {
+		log.Fatalf("Failed to load log policies: %v", err)
+	}
+	if err := deploymentService.Start(ctx); err != nil {
+		log.Fatalf("Failed to load bot versions: %v", err)
+	}
+	if err := logService.Start(ctx); err != nil {
+		log.Fatalf("Failed to start async log writer: %v", err)
+	}
+	partitionService.Start(ctx)
+	healthService.Start(ctx)
+	hostService.Start(ctx)
+	idempotencyService.Start(ctx)
+
+	var syslogService *syslogservice.SyslogService
+	if config.Syslog.Enabled {
+		syslogService = syslogservice.NewSyslogService(logService, config.Syslog)
+		if err := syslogService.Start(ctx); err != nil {
+			log.Fatalf("Failed to start syslog listener: %v", err)
+		}
+	}
 
 	authMiddleware := middleware.NewAuthMiddleware(authService)
+	bodyMiddleware := middleware.NewBodyMiddleware(config.Ingest.MaxBodyBytes)
 
 	authHandler := auth_handler.NewAuthHandler(authService)
 	botHandler := bot_handler.NewBotHandler(botService)
 	ownerHandler := owner_handler.NewOwnerHandler(ownerService)
+	profileHandler := profile_handler.NewProfileHandler(ownerService)
 	logHandler := log_handler.NewLogHandler(logService)
 	effRunHandler := eff_run_handler.NewEffRunHandler(effRunService)
+	wsHandler := ws_handler.NewWSHandler(streamHub, config.WebSocket)
+	partitionHandler := partition_handler.NewPartitionHandler(partitionService)
+	archiveHandler := archive_handler.NewArchiveHandler(archiveService)
+	exportHandler := export_handler.NewExportHandler(logService, effRunService)
+	otlpHandler := otlp_handler.NewOTLPHandler(logService, config.Ingest.MaxBodyBytes)
+	lokiHandler := loki_handler.NewLokiHandler(logService, config.Ingest.MaxBodyBytes)
+	esHandler := es_handler.NewESHandler(logService, config.Ingest.MaxBodyBytes)
+	redactionHandler := redaction_handler.NewRedactionHandler(redactionService)
+	logPolicyHandler := log_policy_handler.NewLogPolicyHandler(logPolicyService)
+	healthHandler := health_handler.NewHealthHandler(healthService)
+	catalogHandler := catalog_handler.NewCatalogHandler(catalogService)
+	teamHandler := team_handler.NewTeamHandler(teamService)
+	deploymentHandler := deployment_handler.NewDeploymentHandler(deploymentService)
+	hostHandler := host_handler.NewHostHandler(hostService)
+	botReferenceHandler := bot_reference_handler.NewBotReferenceHandler(botReferenceService)
 
-	router := handlers.SetupRoutes(authHandler, botHandler, ownerHandler, logHandler, effRunHandler, authMiddleware)
+	router := handlers.SetupRoutes(authHandler, botHandler, ownerHandler, profileHandler, logHandler, effRunHandler, wsHandler, partitionHandler, archiveHandler, exportHandler, otlpHandler, lokiHandler, esHandler, redactionHandler, logPolicyHandler, healthHandler, catalogHandler, teamHandler, deploymentHandler, hostHandler, botReferenceHandler, authMiddleware, bodyMiddleware)
 
 	addr := fmt.Sprintf("%s:%d", config.Server.Host, config.Server.Port)
 	log.Printf("Starting server on %s", addr)
 	log.Printf("Swagger доступен по адресу: http://%s/swagger/index.html", addr)
 
-	if err := router.Run(addr); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	server := &http.Server{Addr: addr, Handler: router}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+
+	// Сначала перестаём принимать запросы, затем останавливаем фоновые задачи
+	// и дожидаемся записи логов из очереди
+	log.Println("Shutting down server...")
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), time.Duration(config.Ingest.Async.DrainTimeoutSec)*time.Second)
+	defer shutdownCancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Failed to shut down server: %v", err)
+	}
+	cancel()
+	if syslogService != nil {
+		if err := syslogService.Close(shutdownCtx); err != nil {
+			log.Printf("Failed to drain syslog queue: %v", err)
+		}
+	}
+	if err := logService.Close(shutdownCtx); err != nil {
+		log.Printf("Failed to drain log queue: %v", err)
 	}
+	redactionService.FlushCounts()
+	logPolicyService.FlushCounts()
+	hostService.FlushSightings()
 }
@@ -0,0 +1,108 @@
+-- Миграция: секционирование таблицы logs по месяцам (created_at)
+-- Дата: 2025-12-XX
+-- Причина: таблица только растёт, удаление старых строк надолго блокирует её.
+-- Старые секции удаляются целиком, политика хранения задаётся по уровням логов.
+
+BEGIN;
+
+-- Освобождаем имена индексов и первичного ключа для новой таблицы
+ALTER TABLE logs RENAME TO logs_legacy;
+ALTER TABLE logs_legacy RENAME CONSTRAINT logs_pkey TO logs_legacy_pkey;
+DROP INDEX IF EXISTS idx_logs_bot;
+DROP INDEX IF EXISTS idx_logs_status;
+DROP INDEX IF EXISTS idx_logs_created;
+
+CREATE TABLE logs (
+    id BIGINT NOT NULL DEFAULT nextval('logs_id_seq'),
+    bot_id UUID REFERENCES bots(id) ON DELETE SET NULL,
+    status log_status NOT NULL DEFAULT 'Info'::log_status,
+    msg TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+-- Последовательность теперь принадлежит новой таблице и не удалится вместе со старой
+ALTER SEQUENCE logs_id_seq OWNED BY logs.id;
+
+COMMENT ON TABLE logs IS 'Логи от ботов. Сохраняются даже после удаления бота. Секционирована по месяцам (created_at)';
+COMMENT ON COLUMN logs.id IS 'Уникальный идентификатор лога (автоинкремент)';
+COMMENT ON COLUMN logs.bot_id IS 'Бот, от которого пришёл лог (может быть NULL если бот удалён)';
+COMMENT ON COLUMN logs.status IS 'Уровень лога: Debug (отладка), Info (информация), Warning (предупреждение), Error (ошибка), Critical (критическая ошибка)';
+COMMENT ON COLUMN logs.msg IS 'Текст сообщения лога';
+COMMENT ON COLUMN logs.created_at IS 'Дата и время создания лога';
+
+-- Секция по умолчанию принимает строки, для которых ещё нет месячной секции
+CREATE TABLE logs_default PARTITION OF logs DEFAULT;
+
+-- ============================================
+-- Создание месячной секции
+-- ============================================
+-- Если в секции по умолчанию уже есть строки из этого месяца,
+-- они переносятся в новую секцию.
+CREATE OR REPLACE FUNCTION create_logs_partition(p_month DATE)
+RETURNS TEXT
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_start DATE := date_trunc('month', p_month)::date;
+    v_name TEXT := 'logs_p' || to_char(v_start, 'YYYYMM');
+    v_from TIMESTAMP WITH TIME ZONE := v_start::timestamp AT TIME ZONE 'UTC';
+    v_to TIMESTAMP WITH TIME ZONE := (v_start + INTERVAL '1 month')::timestamp AT TIME ZONE 'UTC';
+    v_cols TEXT;
+BEGIN
+    IF to_regclass(v_name) IS NOT NULL THEN
+        RETURN v_name;
+    END IF;
+
+    IF EXISTS (SELECT 1 FROM logs_default WHERE created_at >= v_from AND created_at < v_to) THEN
+        -- Генерируемые столбцы нельзя вставлять явно, поэтому берём только обычные
+        SELECT string_agg(quote_ident(attname), ', ' ORDER BY attnum)
+        INTO v_cols
+        FROM pg_attribute
+        WHERE attrelid = 'logs'::regclass AND attnum > 0 AND NOT attisdropped AND attgenerated = '';
+
+        EXECUTE format(
+            'CREATE TEMP TABLE logs_default_moved AS SELECT %s FROM logs_default WHERE created_at >= %L AND created_at < %L',
+            v_cols, v_from, v_to
+        );
+        DELETE FROM logs_default WHERE created_at >= v_from AND created_at < v_to;
+        EXECUTE format('CREATE TABLE %I PARTITION OF logs FOR VALUES FROM (%L) TO (%L)', v_name, v_from, v_to);
+        EXECUTE format('INSERT INTO logs (%s) SELECT %s FROM logs_default_moved', v_cols, v_cols);
+        DROP TABLE logs_default_moved;
+    ELSE
+        EXECUTE format('CREATE TABLE %I PARTITION OF logs FOR VALUES FROM (%L) TO (%L)', v_name, v_from, v_to);
+    END IF;
+
+    RETURN v_name;
+END;
+$$;
+
+COMMENT ON FUNCTION create_logs_partition(DATE) IS 'Создаёт месячную секцию logs_pYYYYMM для месяца указанной даты (UTC)';
+
+-- Секции для существующих данных и на несколько месяцев вперёд
+DO $$
+DECLARE
+    v_month DATE;
+BEGIN
+    SELECT COALESCE(date_trunc('month', MIN(created_at) AT TIME ZONE 'UTC')::date, date_trunc('month', NOW() AT TIME ZONE 'UTC')::date)
+    INTO v_month
+    FROM logs_legacy;
+
+    WHILE v_month <= (date_trunc('month', NOW() AT TIME ZONE 'UTC') + INTERVAL '3 months')::date LOOP
+        PERFORM create_logs_partition(v_month);
+        v_month := (v_month + INTERVAL '1 month')::date;
+    END LOOP;
+END;
+$$;
+
+INSERT INTO logs (id, bot_id, status, msg, created_at)
+SELECT id, bot_id, status, msg, created_at FROM logs_legacy;
+
+DROP TABLE logs_legacy;
+
+-- Индексы создаются на родительской таблице и наследуются секциями
+CREATE INDEX idx_logs_bot ON logs(bot_id);
+CREATE INDEX idx_logs_status_created ON logs(status, created_at);
+CREATE INDEX idx_logs_created ON logs(created_at DESC);
+
+COMMIT;
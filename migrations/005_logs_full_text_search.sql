@@ -0,0 +1,12 @@
+-- Миграция: полнотекстовый поиск по сообщениям логов
+-- Дата: 2025-12-XX
+-- Причина: ILIKE по logs.msg неприменим на нашем объёме. Сообщения смешанные (русский и английский),
+-- поэтому вектор строится из двух конфигураций: russian (стемминг) и simple (точные слова, коды, идентификаторы).
+
+ALTER TABLE logs
+    ADD COLUMN msg_tsv tsvector
+    GENERATED ALWAYS AS (to_tsvector('russian'::regconfig, msg) || to_tsvector('simple'::regconfig, msg)) STORED;
+
+COMMENT ON COLUMN logs.msg_tsv IS 'Полнотекстовый вектор сообщения (russian + simple), вычисляется автоматически';
+
+CREATE INDEX idx_logs_msg_tsv ON logs USING gin(msg_tsv);
@@ -0,0 +1,10 @@
+-- Миграция: структурированные атрибуты логов
+-- Дата: 2025-12-XX
+-- Причина: логи из OpenTelemetry (и других агентов) несут атрибуты ресурса и записи,
+-- которые нужно сохранять как структурированные поля, а не в тексте сообщения.
+
+ALTER TABLE logs ADD COLUMN attributes JSONB;
+
+COMMENT ON COLUMN logs.attributes IS 'Структурированные атрибуты лога (JSON), например атрибуты OpenTelemetry';
+
+CREATE INDEX idx_logs_attributes_gin ON logs USING gin(attributes jsonb_path_ops);
@@ -0,0 +1,17 @@
+-- Миграция: время получения лога сервером
+-- Дата: 2025-12-XX
+-- Причина: роботы копят логи офлайн и отправляют позже. created_at теперь может задавать клиент,
+-- а фактическое время приёма хранится отдельно в received_at.
+
+ALTER TABLE logs ADD COLUMN received_at TIMESTAMP WITH TIME ZONE;
+
+-- Для уже сохранённых логов время создания и есть время приёма
+UPDATE logs SET received_at = created_at WHERE received_at IS NULL;
+
+ALTER TABLE logs ALTER COLUMN received_at SET DEFAULT NOW();
+ALTER TABLE logs ALTER COLUMN received_at SET NOT NULL;
+
+COMMENT ON COLUMN logs.created_at IS 'Время события (передаётся клиентом или равно времени приёма)';
+COMMENT ON COLUMN logs.received_at IS 'Время получения лога сервером';
+
+CREATE INDEX idx_logs_received ON logs(received_at DESC);
@@ -0,0 +1,22 @@
+-- Миграция: ключи идемпотентности для создания логов и запусков
+-- Дата: 2025-12-XX
+-- Причина: боты повторяют запросы при таймаутах, повторы дублируют логи и eff_runs.
+-- Ключ (Idempotency-Key или event_id) запоминается вместе с созданным ресурсом;
+-- повтор с тем же ключом в течение срока хранения возвращает исходный ресурс.
+
+CREATE TABLE idempotency_keys (
+    scope TEXT NOT NULL,
+    namespace TEXT NOT NULL,
+    key TEXT NOT NULL,
+    resource_id TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (scope, namespace, key)
+);
+
+COMMENT ON TABLE idempotency_keys IS 'Ключи идемпотентности запросов на создание. Устаревшие ключи удаляются фоновой задачей';
+COMMENT ON COLUMN idempotency_keys.scope IS 'Тип ресурса: logs или eff_runs';
+COMMENT ON COLUMN idempotency_keys.namespace IS 'Пространство ключей: ID бота или token:<ID токена> для токенов без бота';
+COMMENT ON COLUMN idempotency_keys.key IS 'Значение Idempotency-Key или event_id от клиента';
+COMMENT ON COLUMN idempotency_keys.resource_id IS 'ID созданного ресурса';
+
+CREATE INDEX idx_idempotency_keys_created ON idempotency_keys(created_at);
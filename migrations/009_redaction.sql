@@ -0,0 +1,37 @@
+-- Миграция: маскирование персональных данных при приёме логов
+-- Дата: 2025-12-XX
+-- Причина: боты пишут в msg телефоны, email, номера карт и паспортные данные, которые попадают в Postgres и Sentry.
+-- Кроме встроенных детекторов администраторы задают свои регулярные выражения — глобально или для бота.
+-- Число замен копится по ботам и дням для разбора.
+
+CREATE TABLE redaction_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bot_id UUID REFERENCES bots(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    pattern TEXT NOT NULL,
+    replacement TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE redaction_rules IS 'Пользовательские правила маскирования сообщений логов';
+COMMENT ON COLUMN redaction_rules.bot_id IS 'Бот, к логам которого применяется правило; NULL — ко всем логам';
+COMMENT ON COLUMN redaction_rules.pattern IS 'Регулярное выражение (синтаксис RE2); если есть группа (?P<pii>...), маскируется только она';
+COMMENT ON COLUMN redaction_rules.replacement IS 'Текст замены; NULL — [REDACTED:<name>]';
+
+CREATE INDEX idx_redaction_rules_bot ON redaction_rules(bot_id);
+
+CREATE TABLE redaction_counts (
+    bot_id UUID REFERENCES bots(id) ON DELETE CASCADE,
+    rule VARCHAR(100) NOT NULL,
+    day DATE NOT NULL,
+    count BIGINT NOT NULL DEFAULT 0
+);
+
+COMMENT ON TABLE redaction_counts IS 'Число замаскированных фрагментов по ботам, правилам и дням';
+COMMENT ON COLUMN redaction_counts.bot_id IS 'Бот; NULL — логи без бота';
+COMMENT ON COLUMN redaction_counts.rule IS 'Встроенный детектор (email, phone, card, passport) или имя правила';
+
+CREATE UNIQUE INDEX idx_redaction_counts_key
+    ON redaction_counts ((COALESCE(bot_id, '00000000-0000-0000-0000-000000000000'::uuid)), rule, day);
+CREATE INDEX idx_redaction_counts_day ON redaction_counts(day);
@@ -0,0 +1,34 @@
+-- Миграция: политики хранения логов по ботам (минимальный уровень и семплирование)
+-- Дата: 2025-12-XX
+-- Причина: часть ботов пишет большие объёмы Debug-логов, которые никто не читает.
+-- Администратор задаёт боту минимальный сохраняемый уровень и долю сохраняемых логов по уровням,
+-- а на время разбора проблемы — временное снижение уровня. Отброшенные логи считаются по дням.
+
+CREATE TABLE log_policies (
+    bot_id UUID PRIMARY KEY REFERENCES bots(id) ON DELETE CASCADE,
+    min_level log_status,
+    sample_rates JSONB NOT NULL DEFAULT '{}'::jsonb,
+    override_level log_status,
+    override_until TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE log_policies IS 'Политики хранения логов ботов';
+COMMENT ON COLUMN log_policies.min_level IS 'Минимальный сохраняемый уровень; NULL — все уровни';
+COMMENT ON COLUMN log_policies.sample_rates IS 'Доля сохраняемых логов по уровням (0..1), например {"Info": 0.01}; уровень без значения сохраняется полностью';
+COMMENT ON COLUMN log_policies.override_level IS 'Временный минимальный уровень, действует до override_until; логи с этого уровня сохраняются без семплирования';
+COMMENT ON COLUMN log_policies.override_until IS 'Окончание временного уровня';
+
+CREATE TABLE log_drop_counts (
+    bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    status log_status NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (bot_id, day, status, reason)
+);
+
+COMMENT ON TABLE log_drop_counts IS 'Число логов, отброшенных политикой хранения, по ботам, дням и уровням';
+COMMENT ON COLUMN log_drop_counts.reason IS 'Причина: level (ниже минимального уровня) или sampled (не попал в выборку)';
+
+CREATE INDEX idx_log_drop_counts_day ON log_drop_counts(day);
@@ -0,0 +1,8 @@
+-- Миграция: индексы для расчёта состояния ботов
+-- Дата: 2025-12-XX
+-- Причина: состояние бота (healthy, degraded, failing, silent, unknown) периодически считается
+-- по последним логам и запускам каждого бота: время последнего сигнала, число ошибок в логах за окно
+-- и статусы последних запусков. Без индексов по боту и времени это полный просмотр таблиц.
+
+CREATE INDEX idx_logs_bot_received ON logs(bot_id, received_at DESC);
+CREATE INDEX idx_eff_runs_bot_created ON eff_runs(bot_id, created_at DESC);
@@ -0,0 +1,19 @@
+-- Миграция: архивирование ботов и владельцев вместо удаления
+-- Дата: 2025-12-XX
+-- Причина: удаление бота каскадно удаляло его запуски и токены, а у логов обнулялся bot_id —
+-- история теряла привязку к боту. Теперь бот и владелец переносятся в архив (archived_at, archived_by)
+-- и могут быть восстановлены; окончательное удаление — отдельная явная операция администратора.
+
+ALTER TABLE bots
+    ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN archived_by UUID;
+
+COMMENT ON COLUMN bots.archived_at IS 'Время переноса в архив; NULL — бот не в архиве';
+COMMENT ON COLUMN bots.archived_by IS 'ID админского токена, которым бот перенесён в архив';
+
+ALTER TABLE owners
+    ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN archived_by UUID;
+
+COMMENT ON COLUMN owners.archived_at IS 'Время переноса в архив; NULL — владелец не в архиве';
+COMMENT ON COLUMN owners.archived_by IS 'ID админского токена, которым владелец перенесён в архив';
@@ -0,0 +1,22 @@
+-- Миграция: история передачи ботов между владельцами
+-- Дата: 2025-12-XX
+-- Причина: владельца бота нельзя было сменить через API, а при смене прямо в таблице терялось, кому бот
+-- принадлежал раньше. Каждая передача записывается в историю, и запуски в отчётах относятся к владельцу,
+-- за которым бот числился в момент запуска.
+
+CREATE TABLE bot_owner_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+    from_owner_id UUID,
+    to_owner_id UUID,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    changed_by UUID,
+    reason TEXT
+);
+
+COMMENT ON TABLE bot_owner_history IS 'История передачи ботов между владельцами';
+COMMENT ON COLUMN bot_owner_history.from_owner_id IS 'Прежний владелец; NULL — у бота не было владельца. Без внешнего ключа, чтобы история пережила удаление владельца';
+COMMENT ON COLUMN bot_owner_history.to_owner_id IS 'Новый владелец; NULL — владелец снят';
+COMMENT ON COLUMN bot_owner_history.changed_by IS 'ID админского токена, которым выполнена передача';
+
+CREATE INDEX idx_bot_owner_history_bot ON bot_owner_history(bot_id, changed_at DESC);
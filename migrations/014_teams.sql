@@ -0,0 +1,46 @@
+-- Миграция: команды владельцев
+-- Дата: 2025-12-XX
+-- Причина: владельцы — плоский список людей, а за ботами отвечают команды. Команда объединяет владельцев,
+-- бот может принадлежать команде; у команды есть настройки уведомлений о смене состояния её ботов,
+-- а токен команды даёт доступ на чтение логов и запусков всех её ботов.
+
+CREATE TABLE teams (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT,
+    notify_webhook_url TEXT,
+    notify_states TEXT[] NOT NULL DEFAULT '{failing,silent}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE teams IS 'Команды владельцев, совместно отвечающие за ботов';
+COMMENT ON COLUMN teams.notify_webhook_url IS 'Адрес для POST-уведомлений о смене состояния ботов команды; NULL — уведомления не отправляются';
+COMMENT ON COLUMN teams.notify_states IS 'Состояния бота, переход в которые вызывает уведомление';
+
+CREATE TABLE team_members (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    owner_id UUID NOT NULL REFERENCES owners(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'member' CHECK (role IN ('lead', 'member')),
+    added_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_id, owner_id)
+);
+
+COMMENT ON TABLE team_members IS 'Владельцы в командах';
+COMMENT ON COLUMN team_members.role IS 'Роль в команде: lead — руководитель, member — участник';
+
+CREATE INDEX idx_team_members_owner ON team_members(owner_id);
+
+ALTER TABLE bots
+    ADD COLUMN team_id UUID REFERENCES teams(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN bots.team_id IS 'Команда, отвечающая за бота; NULL — бот вне команд';
+
+CREATE INDEX idx_bots_team ON bots(team_id);
+
+ALTER TABLE tokens
+    ADD COLUMN team_id UUID REFERENCES teams(id) ON DELETE CASCADE;
+
+COMMENT ON COLUMN tokens.team_id IS 'Команда токена: токен даёт доступ на чтение данных всех ботов команды';
+
+CREATE INDEX idx_tokens_team ON tokens(team_id);
@@ -0,0 +1,25 @@
+-- Миграция: контакты владельцев и токены владельцев
+-- Дата: 2025-12-XX
+-- Причина: у владельца были только имя и флаг активности — связаться с ним по поводу его ботов было нельзя.
+-- Добавляются email, Telegram, телефон, часовой пояс и предпочитаемые каналы уведомлений, а также токен
+-- владельца, с которым он сам просматривает и правит свой профиль без прав администратора.
+
+ALTER TABLE owners
+    ADD COLUMN email VARCHAR(255),
+    ADD COLUMN telegram VARCHAR(33),
+    ADD COLUMN phone VARCHAR(16),
+    ADD COLUMN timezone VARCHAR(64),
+    ADD COLUMN notify_channels TEXT[] NOT NULL DEFAULT '{}'
+        CHECK (notify_channels <@ ARRAY['email', 'telegram', 'phone']::TEXT[]);
+
+COMMENT ON COLUMN owners.telegram IS 'Имя пользователя Telegram с @';
+COMMENT ON COLUMN owners.phone IS 'Телефон в формате E.164';
+COMMENT ON COLUMN owners.timezone IS 'Часовой пояс IANA, например Europe/Moscow';
+COMMENT ON COLUMN owners.notify_channels IS 'Предпочитаемые каналы уведомлений: email, telegram, phone';
+
+ALTER TABLE tokens
+    ADD COLUMN owner_id UUID REFERENCES owners(id) ON DELETE CASCADE;
+
+COMMENT ON COLUMN tokens.owner_id IS 'Владелец токена: токен даёт доступ только к профилю этого владельца';
+
+CREATE INDEX idx_tokens_owner ON tokens(owner_id);
@@ -0,0 +1,36 @@
+-- Миграция: выкладки ботов
+-- Дата: 2025-12-XX
+-- Причина: когда бот начинает падать, первый вопрос — что выкладывали. Боты сообщают о выкладках (версия,
+-- git sha, хост, changelog), а новые логи и запуски помечаются версией, которая была выложена на момент записи,
+-- чтобы сравнивать версии по доле ошибок и успешных запусков.
+
+CREATE TABLE deployments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+    version VARCHAR(100) NOT NULL,
+    git_sha VARCHAR(40),
+    host VARCHAR(255),
+    changelog TEXT,
+    deployed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE deployments IS 'Выкладки ботов';
+COMMENT ON COLUMN deployments.deployed_at IS 'Время выкладки; текущая версия бота — версия его последней выкладки';
+
+CREATE INDEX idx_deployments_bot ON deployments(bot_id, deployed_at DESC);
+CREATE INDEX idx_deployments_deployed_at ON deployments(deployed_at DESC);
+
+ALTER TABLE eff_runs
+    ADD COLUMN version VARCHAR(100);
+
+COMMENT ON COLUMN eff_runs.version IS 'Версия бота на момент записи запуска; NULL — выкладок не было';
+
+ALTER TABLE logs
+    ADD COLUMN version VARCHAR(100);
+
+COMMENT ON COLUMN logs.version IS 'Версия бота на момент записи лога; NULL — выкладок не было';
+
+-- Сравнение версий бота и фильтр version
+CREATE INDEX idx_eff_runs_bot_version ON eff_runs(bot_id, version) WHERE version IS NOT NULL;
+CREATE INDEX idx_logs_bot_version ON logs(bot_id, version) WHERE version IS NOT NULL;
@@ -0,0 +1,57 @@
+-- Миграция: реестр хостов
+-- Дата: 2025-12-XX
+-- Причина: eff_runs.host — свободный текст, который нигде не используется. Хосты собираются в реестр из запусков
+-- (когда появились, когда были последний раз, какие боты на них работают) с описанием ОС и IP, чтобы видеть нагрузку
+-- на хост и замечать хосты, которые перестали присылать запуски (обычно это упавшая виртуальная машина).
+
+CREATE TABLE hosts (
+    name TEXT PRIMARY KEY,
+    description TEXT,
+    os VARCHAR(100),
+    ip INET,
+    monitored BOOLEAN NOT NULL DEFAULT TRUE,
+    first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    runs_seen BIGINT NOT NULL DEFAULT 0,
+    silent_since TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE hosts IS 'Хосты, с которых приходили запуски ботов (eff_runs.host)';
+COMMENT ON COLUMN hosts.monitored IS 'Следить ли за молчанием хоста';
+COMMENT ON COLUMN hosts.runs_seen IS 'Число запусков с хоста с момента его появления в реестре';
+COMMENT ON COLUMN hosts.silent_since IS 'С какого момента хост считается замолчавшим; NULL — хост присылает запуски';
+
+CREATE TABLE host_bots (
+    host_name TEXT NOT NULL REFERENCES hosts(name) ON DELETE CASCADE,
+    bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+    first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    runs_seen BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (host_name, bot_id)
+);
+
+COMMENT ON TABLE host_bots IS 'Боты, запуски которых приходили с хоста';
+
+CREATE INDEX idx_host_bots_bot ON host_bots(bot_id);
+CREATE INDEX idx_eff_runs_host_created ON eff_runs(host, created_at DESC) WHERE host IS NOT NULL;
+
+-- Хосты уже сохранённых запусков приводятся к виду, в котором их записывает сервис (без пробельных символов
+-- по краям, пустой — NULL), чтобы "srv1 " и "srv1" были одним хостом и в реестре, и в фильтрах по host
+UPDATE eff_runs
+SET host = NULLIF(regexp_replace(host, '^\s+|\s+$', '', 'g'), '')
+WHERE host ~ '^\s|\s$' OR host = '';
+
+-- Реестр заполняется по уже сохранённым запускам
+INSERT INTO hosts (name, first_seen_at, last_seen_at, runs_seen)
+SELECT host, MIN(created_at), MAX(created_at), COUNT(*)
+FROM eff_runs
+WHERE host IS NOT NULL
+GROUP BY host;
+
+INSERT INTO host_bots (host_name, bot_id, first_seen_at, last_seen_at, runs_seen)
+SELECT host, bot_id, MIN(created_at), MAX(created_at), COUNT(*)
+FROM eff_runs
+WHERE host IS NOT NULL
+GROUP BY host, bot_id;
@@ -0,0 +1,55 @@
+-- Миграция: справочники типов и языков ботов
+-- Дата: 2025-12-XX
+-- Причина: bot_type и bot_lang были ENUM-типами, а их значения дублировались в проверках API — каждая новая
+-- технология (например, UiPath или Kotlin) требовала миграции и выкладки. Типы и языки переносятся в справочники,
+-- которые ведут администраторы через API; bots ссылается на них внешними ключами.
+
+CREATE TABLE bot_types (
+    code VARCHAR(50) PRIMARY KEY,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE bot_types IS 'Справочник типов ботов';
+COMMENT ON COLUMN bot_types.code IS 'Тип бота, как он хранится в bots.bot_type';
+
+CREATE TABLE bot_languages (
+    code VARCHAR(50) PRIMARY KEY,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE bot_languages IS 'Справочник языков и технологий, на которых реализованы боты';
+COMMENT ON COLUMN bot_languages.code IS 'Язык бота, как он хранится в bots.language';
+
+-- Все значения ENUM-типов, включая добавленные вручную
+INSERT INTO bot_types (code)
+SELECT unnest(enum_range(NULL::bot_type))::text;
+
+INSERT INTO bot_languages (code)
+SELECT unnest(enum_range(NULL::bot_lang))::text;
+
+UPDATE bot_types SET description = 'Системы на основе моделей ИИ' WHERE code = 'AI';
+UPDATE bot_types SET description = 'Серверные системы и интеграции' WHERE code = 'Backend';
+UPDATE bot_types SET description = 'Пользовательские интерфейсы' WHERE code = 'Frontend';
+UPDATE bot_types SET description = 'Роботы автоматизации процессов' WHERE code = 'Robot';
+UPDATE bot_languages SET description = 'Прочие языки и технологии' WHERE code = 'Other';
+
+ALTER TABLE bots
+    ALTER COLUMN bot_type TYPE VARCHAR(50) USING bot_type::text,
+    ALTER COLUMN language TYPE VARCHAR(50) USING language::text;
+
+-- Переименование значения справочника переносится на ботов; удалить значение, которое используют боты, нельзя
+ALTER TABLE bots
+    ADD CONSTRAINT bots_bot_type_fkey FOREIGN KEY (bot_type) REFERENCES bot_types(code) ON UPDATE CASCADE,
+    ADD CONSTRAINT bots_language_fkey FOREIGN KEY (language) REFERENCES bot_languages(code) ON UPDATE CASCADE;
+
+CREATE INDEX idx_bots_language ON bots(language);
+
+COMMENT ON COLUMN bots.bot_type IS 'Тип бота из справочника bot_types';
+COMMENT ON COLUMN bots.language IS 'Язык или технология реализации из справочника bot_languages';
+
+DROP TYPE bot_type;
+DROP TYPE bot_lang;
@@ -0,0 +1,14 @@
+-- Миграция: последнее сохранённое состояние ботов
+-- Дата: 2025-12-XX
+-- Причина: состояние ботов пересчитывает каждый экземпляр сервиса, и каждый отправлял командам уведомление
+-- о смене состояния — при нескольких экземплярах одно и то же уведомление приходило несколько раз.
+-- Состояние, о котором уже сообщено, хранится в БД; уведомляет только экземпляр, первым сохранивший переход.
+
+CREATE TABLE bot_health_states (
+    bot_id UUID PRIMARY KEY REFERENCES bots(id) ON DELETE CASCADE,
+    state VARCHAR(20) NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE bot_health_states IS 'Последнее состояние ботов, о переходе в которое сообщено командам';
+COMMENT ON COLUMN bot_health_states.changed_at IS 'Время сохранения перехода в это состояние';
@@ -0,0 +1,10 @@
+-- Миграция: хеш запроса для ключей идемпотентности
+-- Дата: 2025-12-XX
+-- Причина: повтор ключа с другим телом запроса молча возвращал ранее созданную запись,
+-- и клиент не узнавал, что его новые данные не сохранены. Хеш тела запроса хранится вместе с ключом;
+-- повтор ключа с другим телом отклоняется (422). У ключей, созданных до миграции, хеша нет — они не проверяются.
+
+ALTER TABLE idempotency_keys ADD COLUMN request_hash TEXT;
+
+COMMENT ON COLUMN idempotency_keys.request_hash IS 'SHA-256 тела запроса (hex); NULL — ключ создан до появления проверки';
+COMMENT ON COLUMN idempotency_keys.resource_id IS 'ID созданного ресурса; dropped — лог отброшен политикой хранения бота';
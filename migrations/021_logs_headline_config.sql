@@ -0,0 +1,17 @@
+-- Миграция: конфигурация полнотекстового поиска для подсветки совпадений
+-- Дата: 2025-12-XX
+-- Причина: вектор сообщений строится из конфигураций russian и simple, а ts_headline разбирает текст одной
+-- конфигурацией. С russian не подсвечивались термы, совпавшие только через simple, — стоп-слова ("не", "the").
+-- logs_headline — копия russian со стеммерами без списков стоп-слов: слова получают те же основы, что и в russian,
+-- а стоп-слова остаются в тексте и совпадают с точной формой из simple.
+
+CREATE TEXT SEARCH DICTIONARY russian_stem_nostop (TEMPLATE = snowball, Language = russian);
+CREATE TEXT SEARCH DICTIONARY english_stem_nostop (TEMPLATE = snowball, Language = english);
+
+CREATE TEXT SEARCH CONFIGURATION logs_headline (COPY = russian);
+ALTER TEXT SEARCH CONFIGURATION logs_headline
+    ALTER MAPPING FOR word, hword, hword_part WITH russian_stem_nostop;
+ALTER TEXT SEARCH CONFIGURATION logs_headline
+    ALTER MAPPING FOR asciiword, asciihword, hword_asciipart WITH english_stem_nostop;
+
+COMMENT ON TEXT SEARCH CONFIGURATION logs_headline IS 'Конфигурация для ts_headline по логам: russian без стоп-слов';
@@ -0,0 +1,127 @@
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"logging_api/configs"
+)
+
+var ErrNotFound = errors.New("archive object not found")
+
+// Storage — хранилище архивных файлов (локальный каталог или S3-совместимое хранилище).
+// Ключи всегда разделяются символом "/".
+type Storage interface {
+	Put(key string, data io.Reader, size int64) error
+	Get(key string) (io.ReadCloser, error)
+	List(prefix string) ([]string, error)
+}
+
+// NewStorage создаёт хранилище по конфигурации архива
+func NewStorage(config *configs.ArchiveConfig) (Storage, error) {
+	switch config.Storage {
+	case "", "local":
+		return NewLocalStorage(config.LocalDir)
+	case "s3":
+		return NewS3Storage(&config.S3)
+	default:
+		return nil, fmt.Errorf("unknown archive storage: %s", config.Storage)
+	}
+}
+
+// LocalStorage хранит архивы в локальном каталоге
+type LocalStorage struct {
+	dir string
+}
+
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("archive local_dir is not set")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create archive dir: %w", err)
+	}
+	return &LocalStorage{dir: dir}, nil
+}
+
+// Put записывает файл атомарно: сначала во временный файл, затем переименовывает
+func (s *LocalStorage) Put(key string, data io.Reader, size int64) error {
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create archive dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close archive file: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalStorage) Get(key string) (io.ReadCloser, error) {
+	file, err := os.Open(s.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+func (s *LocalStorage) List(prefix string) ([]string, error) {
+	var keys []string
+
+	// Обходим только каталог, в котором лежат ключи с этим префиксом
+	root := s.dir
+	if idx := strings.LastIndex(prefix, "/"); idx >= 0 {
+		root = s.path(prefix[:idx])
+	}
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".tmp-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archive: %w", err)
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *LocalStorage) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
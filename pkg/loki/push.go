@@ -0,0 +1,269 @@
+package loki
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var (
+	errMalformed = errors.New("malformed protobuf")
+
+	// ErrTooLarge — распакованный запрос больше допустимого размера
+	ErrTooLarge = errors.New("decompressed push request is too large")
+)
+
+// PushRequest — содержимое запроса POST /loki/api/v1/push
+type PushRequest struct {
+	Streams []Stream
+}
+
+type Stream struct {
+	Labels  map[string]string
+	Entries []Entry
+}
+
+type Entry struct {
+	Timestamp          time.Time
+	Line               string
+	StructuredMetadata map[string]string
+}
+
+// DecodePushJSON разбирает JSON-вариант: {"streams":[{"stream":{...},"values":[["<ns>","line",{...}]]}]}
+func DecodePushJSON(data []byte) (*PushRequest, error) {
+	var raw struct {
+		Streams []struct {
+			Stream map[string]string   `json:"stream"`
+			Values [][]json.RawMessage `json:"values"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode loki push: %w", err)
+	}
+
+	request := &PushRequest{}
+	for _, rawStream := range raw.Streams {
+		stream := Stream{Labels: rawStream.Stream}
+		for _, value := range rawStream.Values {
+			if len(value) < 2 || len(value) > 3 {
+				return nil, fmt.Errorf("failed to decode loki push: entry must be [timestamp, line] or [timestamp, line, metadata]")
+			}
+
+			var tsText, line string
+			if err := json.Unmarshal(value[0], &tsText); err != nil {
+				return nil, fmt.Errorf("failed to decode loki push: invalid timestamp: %w", err)
+			}
+			nanos, err := strconv.ParseInt(tsText, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode loki push: invalid timestamp %q", tsText)
+			}
+			if err := json.Unmarshal(value[1], &line); err != nil {
+				return nil, fmt.Errorf("failed to decode loki push: invalid line: %w", err)
+			}
+
+			entry := Entry{Timestamp: time.Unix(0, nanos).UTC(), Line: line}
+			if len(value) == 3 {
+				if err := json.Unmarshal(value[2], &entry.StructuredMetadata); err != nil {
+					return nil, fmt.Errorf("failed to decode loki push: invalid structured metadata: %w", err)
+				}
+			}
+			stream.Entries = append(stream.Entries, entry)
+		}
+		request.Streams = append(request.Streams, stream)
+	}
+	return request, nil
+}
+
+// DecodePushProto разбирает snappy-сжатый protobuf logproto.PushRequest.
+// maxBytes ограничивает размер после распаковки.
+func DecodePushProto(compressed []byte, maxBytes int64) (*PushRequest, error) {
+	size, err := s2.DecodedLen(compressed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress loki push: %w", err)
+	}
+	if int64(size) > maxBytes {
+		return nil, ErrTooLarge
+	}
+
+	data, err := s2.Decode(nil, compressed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress loki push: %w", err)
+	}
+
+	request := &PushRequest{}
+	err = walk(data, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+		if num != 1 || typ != protowire.BytesType {
+			return nil
+		}
+		stream, err := decodeStream(value)
+		if err != nil {
+			return err
+		}
+		request.Streams = append(request.Streams, *stream)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode loki push: %w", err)
+	}
+	return request, nil
+}
+
+// StreamAdapter: labels = 1, entries = 2
+func decodeStream(data []byte) (*Stream, error) {
+	var stream Stream
+	err := walk(data, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case 1:
+			labels, err := ParseLabels(string(value))
+			if err != nil {
+				return err
+			}
+			stream.Labels = labels
+		case 2:
+			entry, err := decodeEntry(value)
+			if err != nil {
+				return err
+			}
+			stream.Entries = append(stream.Entries, *entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &stream, nil
+}
+
+// EntryAdapter: timestamp = 1 (google.protobuf.Timestamp), line = 2, structuredMetadata = 3
+func decodeEntry(data []byte) (*Entry, error) {
+	var entry Entry
+	err := walk(data, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case 1:
+			var seconds, nanos int64
+			err := walk(value, func(num protowire.Number, typ protowire.Type, _ []byte, scalar uint64) error {
+				if typ == protowire.VarintType {
+					switch num {
+					case 1:
+						seconds = int64(scalar)
+					case 2:
+						nanos = int64(int32(scalar))
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			entry.Timestamp = time.Unix(seconds, nanos).UTC()
+		case 2:
+			entry.Line = string(value)
+		case 3:
+			var name, labelValue string
+			err := walk(value, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+				if typ == protowire.BytesType {
+					switch num {
+					case 1:
+						name = string(value)
+					case 2:
+						labelValue = string(value)
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if entry.StructuredMetadata == nil {
+				entry.StructuredMetadata = map[string]string{}
+			}
+			entry.StructuredMetadata[name] = labelValue
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ParseLabels разбирает набор меток в формате Prometheus: {job="app", level="error"}
+func ParseLabels(text string) (map[string]string, error) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "{") || !strings.HasSuffix(text, "}") {
+		return nil, fmt.Errorf("invalid labels %q", text)
+	}
+	rest := strings.TrimSpace(text[1 : len(text)-1])
+
+	labels := map[string]string{}
+	for rest != "" {
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("invalid labels %q", text)
+		}
+		name := strings.TrimSpace(rest[:eq])
+		rest = strings.TrimSpace(rest[eq+1:])
+
+		quoted, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid labels %q", text)
+		}
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, fmt.Errorf("invalid labels %q", text)
+		}
+		labels[name] = value
+
+		rest = strings.TrimSpace(rest[len(quoted):])
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, ","))
+	}
+	return labels, nil
+}
+
+func walk(data []byte, fn func(num protowire.Number, typ protowire.Type, value []byte, scalar uint64) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return errMalformed
+		}
+		data = data[n:]
+
+		var value []byte
+		var scalar uint64
+		switch typ {
+		case protowire.VarintType:
+			scalar, n = protowire.ConsumeVarint(data)
+		case protowire.Fixed64Type:
+			scalar, n = protowire.ConsumeFixed64(data)
+		case protowire.Fixed32Type:
+			var v uint32
+			v, n = protowire.ConsumeFixed32(data)
+			scalar = uint64(v)
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return errMalformed
+		}
+		data = data[n:]
+
+		if err := fn(num, typ, value, scalar); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package otlp
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Представление OTLP/JSON: поля в camelCase, 64-битные числа могут приходить строкой,
+// trace/span id — hex-строкой, bytesValue — base64.
+
+type jsonRequest struct {
+	ResourceLogs []struct {
+		Resource struct {
+			Attributes []jsonKeyValue `json:"attributes"`
+		} `json:"resource"`
+		ScopeLogs []struct {
+			Scope struct {
+				Name       string         `json:"name"`
+				Version    string         `json:"version"`
+				Attributes []jsonKeyValue `json:"attributes"`
+			} `json:"scope"`
+			LogRecords []struct {
+				TimeUnixNano         jsonUint64     `json:"timeUnixNano"`
+				ObservedTimeUnixNano jsonUint64     `json:"observedTimeUnixNano"`
+				SeverityNumber       int32          `json:"severityNumber"`
+				SeverityText         string         `json:"severityText"`
+				Body                 *jsonAnyValue  `json:"body"`
+				Attributes           []jsonKeyValue `json:"attributes"`
+				TraceID              string         `json:"traceId"`
+				SpanID               string         `json:"spanId"`
+				EventName            string         `json:"eventName"`
+			} `json:"logRecords"`
+		} `json:"scopeLogs"`
+	} `json:"resourceLogs"`
+}
+
+type jsonKeyValue struct {
+	Key   string        `json:"key"`
+	Value *jsonAnyValue `json:"value"`
+}
+
+type jsonAnyValue struct {
+	StringValue *string     `json:"stringValue"`
+	BoolValue   *bool       `json:"boolValue"`
+	IntValue    *jsonUint64 `json:"intValue"`
+	DoubleValue *float64    `json:"doubleValue"`
+	ArrayValue  *struct {
+		Values []*jsonAnyValue `json:"values"`
+	} `json:"arrayValue"`
+	KvlistValue *struct {
+		Values []jsonKeyValue `json:"values"`
+	} `json:"kvlistValue"`
+	BytesValue *string `json:"bytesValue"`
+}
+
+// jsonUint64 принимает число как в виде JSON-числа, так и строкой
+type jsonUint64 uint64
+
+func (v *jsonUint64) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		data = []byte(s)
+	}
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	if n, err := strconv.ParseUint(string(data), 10, 64); err == nil {
+		*v = jsonUint64(n)
+		return nil
+	}
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid integer %q", string(data))
+	}
+	*v = jsonUint64(n)
+	return nil
+}
+
+// DecodeLogsJSON разбирает ExportLogsServiceRequest в кодировке OTLP/JSON
+func DecodeLogsJSON(data []byte) (*ExportLogsRequest, error) {
+	var raw jsonRequest
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode otlp logs: %w", err)
+	}
+
+	request := &ExportLogsRequest{}
+	for _, rl := range raw.ResourceLogs {
+		resourceAttributes, err := convertKeyValues(rl.Resource.Attributes, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode otlp logs: %w", err)
+		}
+		resourceLogs := ResourceLogs{Resource: Resource{Attributes: resourceAttributes}}
+		for _, sl := range rl.ScopeLogs {
+			scopeAttributes, err := convertKeyValues(sl.Scope.Attributes, 0)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode otlp logs: %w", err)
+			}
+			scopeLogs := ScopeLogs{Scope: Scope{
+				Name:       sl.Scope.Name,
+				Version:    sl.Scope.Version,
+				Attributes: scopeAttributes,
+			}}
+			for _, lr := range sl.LogRecords {
+				body, err := convertAnyValue(lr.Body, 0)
+				if err != nil {
+					return nil, fmt.Errorf("failed to decode otlp logs: %w", err)
+				}
+				attributes, err := convertKeyValues(lr.Attributes, 0)
+				if err != nil {
+					return nil, fmt.Errorf("failed to decode otlp logs: %w", err)
+				}
+				record := LogRecord{
+					TimeUnixNano:         uint64(lr.TimeUnixNano),
+					ObservedTimeUnixNano: uint64(lr.ObservedTimeUnixNano),
+					SeverityNumber:       lr.SeverityNumber,
+					SeverityText:         lr.SeverityText,
+					Body:                 body,
+					Attributes:           attributes,
+					EventName:            lr.EventName,
+				}
+				if record.TraceID, err = decodeHexID(lr.TraceID); err != nil {
+					return nil, fmt.Errorf("failed to decode otlp logs: invalid traceId: %w", err)
+				}
+				if record.SpanID, err = decodeHexID(lr.SpanID); err != nil {
+					return nil, fmt.Errorf("failed to decode otlp logs: invalid spanId: %w", err)
+				}
+				scopeLogs.LogRecords = append(scopeLogs.LogRecords, record)
+			}
+			resourceLogs.ScopeLogs = append(resourceLogs.ScopeLogs, scopeLogs)
+		}
+		request.ResourceLogs = append(request.ResourceLogs, resourceLogs)
+	}
+	return request, nil
+}
+
+// EncodeExportResponseJSON — JSON-вариант EncodeExportResponse
+func EncodeExportResponseJSON(rejected int64, message string) []byte {
+	if rejected == 0 && message == "" {
+		return []byte("{}")
+	}
+	partial := map[string]interface{}{}
+	if rejected != 0 {
+		partial["rejectedLogRecords"] = strconv.FormatInt(rejected, 10)
+	}
+	if message != "" {
+		partial["errorMessage"] = message
+	}
+	data, _ := json.Marshal(map[string]interface{}{"partialSuccess": partial})
+	return data
+}
+
+// EncodeStatusJSON — JSON-вариант EncodeStatus
+func EncodeStatusJSON(code int32, message string) []byte {
+	data, _ := json.Marshal(map[string]interface{}{"code": code, "message": message})
+	return data
+}
+
+func decodeHexID(value string) ([]byte, error) {
+	if value == "" {
+		return nil, nil
+	}
+	return hex.DecodeString(value)
+}
+
+// convertKeyValues переводит атрибуты; depth — вложенность их значений, как в decodeKeyValue
+func convertKeyValues(values []jsonKeyValue, depth int) ([]KeyValue, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	result := make([]KeyValue, 0, len(values))
+	for _, kv := range values {
+		value, err := convertAnyValue(kv.Value, depth)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, KeyValue{Key: kv.Key, Value: value})
+	}
+	return result, nil
+}
+
+// convertAnyValue переводит значение; глубже maxDepth вложенных arrayValue/kvlistValue — ошибка
+func convertAnyValue(v *jsonAnyValue, depth int) (*AnyValue, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if depth > maxDepth {
+		return nil, errTooDeep
+	}
+	switch {
+	case v.StringValue != nil:
+		return &AnyValue{Value: *v.StringValue}, nil
+	case v.BoolValue != nil:
+		return &AnyValue{Value: *v.BoolValue}, nil
+	case v.IntValue != nil:
+		return &AnyValue{Value: int64(*v.IntValue)}, nil
+	case v.DoubleValue != nil:
+		return &AnyValue{Value: *v.DoubleValue}, nil
+	case v.ArrayValue != nil:
+		values := make([]interface{}, 0, len(v.ArrayValue.Values))
+		for _, item := range v.ArrayValue.Values {
+			value, err := convertAnyValue(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value.Interface())
+		}
+		return &AnyValue{Value: values}, nil
+	case v.KvlistValue != nil:
+		kvs, err := convertKeyValues(v.KvlistValue.Values, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[string]interface{}, len(kvs))
+		for _, kv := range kvs {
+			values[kv.Key] = kv.Value.Interface()
+		}
+		return &AnyValue{Value: values}, nil
+	case v.BytesValue != nil:
+		data, err := base64.StdEncoding.DecodeString(*v.BytesValue)
+		if err != nil {
+			return &AnyValue{Value: *v.BytesValue}, nil
+		}
+		return &AnyValue{Value: data}, nil
+	}
+	return &AnyValue{}, nil
+}
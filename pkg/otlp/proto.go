@@ -0,0 +1,306 @@
+package otlp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// maxDepth — наибольшая вложенность arrayValue/kvlistValue; глубже значения не разбираются,
+// чтобы рекурсия не переполнила стек
+const maxDepth = 64
+
+var (
+	errMalformed = errors.New("malformed protobuf")
+	errTooDeep   = fmt.Errorf("value nesting exceeds %d levels", maxDepth)
+)
+
+// DecodeLogsProto разбирает ExportLogsServiceRequest в бинарном protobuf.
+// Неизвестные поля пропускаются, как того требует protobuf.
+func DecodeLogsProto(data []byte) (*ExportLogsRequest, error) {
+	var request ExportLogsRequest
+	err := walk(data, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+		if num == 1 && typ == protowire.BytesType {
+			resourceLogs, err := decodeResourceLogs(value)
+			if err != nil {
+				return err
+			}
+			request.ResourceLogs = append(request.ResourceLogs, *resourceLogs)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode otlp logs: %w", err)
+	}
+	return &request, nil
+}
+
+// EncodeExportResponse кодирует ExportLogsServiceResponse; partial_success пишется только при отклонённых записях
+func EncodeExportResponse(rejected int64, message string) []byte {
+	if rejected == 0 && message == "" {
+		return []byte{}
+	}
+
+	var partial []byte
+	if rejected != 0 {
+		partial = protowire.AppendTag(partial, 1, protowire.VarintType)
+		partial = protowire.AppendVarint(partial, uint64(rejected))
+	}
+	if message != "" {
+		partial = protowire.AppendTag(partial, 2, protowire.BytesType)
+		partial = protowire.AppendString(partial, message)
+	}
+
+	var out []byte
+	out = protowire.AppendTag(out, 1, protowire.BytesType)
+	out = protowire.AppendBytes(out, partial)
+	return out
+}
+
+// EncodeStatus кодирует google.rpc.Status для ответов с ошибкой
+func EncodeStatus(code int32, message string) []byte {
+	var out []byte
+	out = protowire.AppendTag(out, 1, protowire.VarintType)
+	out = protowire.AppendVarint(out, uint64(code))
+	out = protowire.AppendTag(out, 2, protowire.BytesType)
+	out = protowire.AppendString(out, message)
+	return out
+}
+
+// walk обходит поля сообщения. Для varint и fixed полей значение передаётся в scalar,
+// для length-delimited — в value.
+func walk(data []byte, fn func(num protowire.Number, typ protowire.Type, value []byte, scalar uint64) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return errMalformed
+		}
+		data = data[n:]
+
+		var value []byte
+		var scalar uint64
+		switch typ {
+		case protowire.VarintType:
+			scalar, n = protowire.ConsumeVarint(data)
+		case protowire.Fixed64Type:
+			scalar, n = protowire.ConsumeFixed64(data)
+		case protowire.Fixed32Type:
+			var v uint32
+			v, n = protowire.ConsumeFixed32(data)
+			scalar = uint64(v)
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return errMalformed
+		}
+		data = data[n:]
+
+		if err := fn(num, typ, value, scalar); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeResourceLogs(data []byte) (*ResourceLogs, error) {
+	var resourceLogs ResourceLogs
+	err := walk(data, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case 1:
+			return walk(value, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+				if num == 1 && typ == protowire.BytesType {
+					kv, err := decodeKeyValue(value, 0)
+					if err != nil {
+						return err
+					}
+					resourceLogs.Resource.Attributes = append(resourceLogs.Resource.Attributes, *kv)
+				}
+				return nil
+			})
+		case 2:
+			scopeLogs, err := decodeScopeLogs(value)
+			if err != nil {
+				return err
+			}
+			resourceLogs.ScopeLogs = append(resourceLogs.ScopeLogs, *scopeLogs)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resourceLogs, nil
+}
+
+func decodeScopeLogs(data []byte) (*ScopeLogs, error) {
+	var scopeLogs ScopeLogs
+	err := walk(data, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case 1:
+			return walk(value, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+				if typ != protowire.BytesType {
+					return nil
+				}
+				switch num {
+				case 1:
+					scopeLogs.Scope.Name = string(value)
+				case 2:
+					scopeLogs.Scope.Version = string(value)
+				case 3:
+					kv, err := decodeKeyValue(value, 0)
+					if err != nil {
+						return err
+					}
+					scopeLogs.Scope.Attributes = append(scopeLogs.Scope.Attributes, *kv)
+				}
+				return nil
+			})
+		case 2:
+			record, err := decodeLogRecord(value)
+			if err != nil {
+				return err
+			}
+			scopeLogs.LogRecords = append(scopeLogs.LogRecords, *record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &scopeLogs, nil
+}
+
+func decodeLogRecord(data []byte) (*LogRecord, error) {
+	var record LogRecord
+	err := walk(data, func(num protowire.Number, typ protowire.Type, value []byte, scalar uint64) error {
+		switch {
+		case num == 1 && typ == protowire.Fixed64Type:
+			record.TimeUnixNano = scalar
+		case num == 11 && typ == protowire.Fixed64Type:
+			record.ObservedTimeUnixNano = scalar
+		case num == 2 && typ == protowire.VarintType:
+			record.SeverityNumber = int32(scalar)
+		case num == 3 && typ == protowire.BytesType:
+			record.SeverityText = string(value)
+		case num == 5 && typ == protowire.BytesType:
+			body, err := decodeAnyValue(value, 0)
+			if err != nil {
+				return err
+			}
+			record.Body = body
+		case num == 6 && typ == protowire.BytesType:
+			kv, err := decodeKeyValue(value, 0)
+			if err != nil {
+				return err
+			}
+			record.Attributes = append(record.Attributes, *kv)
+		case num == 9 && typ == protowire.BytesType:
+			record.TraceID = append([]byte(nil), value...)
+		case num == 10 && typ == protowire.BytesType:
+			record.SpanID = append([]byte(nil), value...)
+		case num == 12 && typ == protowire.BytesType:
+			record.EventName = string(value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// decodeKeyValue разбирает KeyValue; depth — вложенность значения
+func decodeKeyValue(data []byte, depth int) (*KeyValue, error) {
+	var kv KeyValue
+	err := walk(data, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case 1:
+			kv.Key = string(value)
+		case 2:
+			v, err := decodeAnyValue(value, depth)
+			if err != nil {
+				return err
+			}
+			kv.Value = v
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &kv, nil
+}
+
+// decodeAnyValue разбирает AnyValue; depth — число arrayValue/kvlistValue, в которые оно вложено
+func decodeAnyValue(data []byte, depth int) (*AnyValue, error) {
+	if depth > maxDepth {
+		return nil, errTooDeep
+	}
+
+	var v AnyValue
+	err := walk(data, func(num protowire.Number, typ protowire.Type, value []byte, scalar uint64) error {
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			v.Value = string(value)
+		case num == 2 && typ == protowire.VarintType:
+			v.Value = scalar != 0
+		case num == 3 && typ == protowire.VarintType:
+			v.Value = int64(scalar)
+		case num == 4 && typ == protowire.Fixed64Type:
+			v.Value = math.Float64frombits(scalar)
+		case num == 5 && typ == protowire.BytesType:
+			values := []interface{}{}
+			err := walk(value, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+				if num == 1 && typ == protowire.BytesType {
+					item, err := decodeAnyValue(value, depth+1)
+					if err != nil {
+						return err
+					}
+					values = append(values, item.Interface())
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			v.Value = values
+		case num == 6 && typ == protowire.BytesType:
+			values := map[string]interface{}{}
+			err := walk(value, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+				if num == 1 && typ == protowire.BytesType {
+					kv, err := decodeKeyValue(value, depth+1)
+					if err != nil {
+						return err
+					}
+					values[kv.Key] = kv.Value.Interface()
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			v.Value = values
+		case num == 7 && typ == protowire.BytesType:
+			v.Value = append([]byte(nil), value...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
@@ -0,0 +1,183 @@
+package otlp
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func bytesField(num protowire.Number, value []byte) []byte {
+	out := protowire.AppendTag(nil, num, protowire.BytesType)
+	return protowire.AppendBytes(out, value)
+}
+
+func stringValue(s string) []byte {
+	return bytesField(1, []byte(s))
+}
+
+// arrayValue оборачивает значение в AnyValue{arrayValue: {values: [value]}}
+func arrayValue(value []byte) []byte {
+	return bytesField(5, bytesField(1, value))
+}
+
+// kvlistValue оборачивает значение в AnyValue{kvlistValue: {values: [{key, value}]}}
+func kvlistValue(key string, value []byte) []byte {
+	kv := append(bytesField(1, []byte(key)), bytesField(2, value)...)
+	return bytesField(6, bytesField(1, kv))
+}
+
+// requestWithBody собирает запрос из одной записи с телом body и атрибутами attributes
+func requestWithBody(body []byte, attributes ...[]byte) []byte {
+	var record []byte
+	record = protowire.AppendTag(record, 1, protowire.Fixed64Type)
+	record = protowire.AppendFixed64(record, 1700000000000000000)
+	record = protowire.AppendTag(record, 2, protowire.VarintType)
+	record = protowire.AppendVarint(record, 17)
+	record = append(record, bytesField(3, []byte("ERROR"))...)
+	if body != nil {
+		record = append(record, bytesField(5, body)...)
+	}
+	for _, attribute := range attributes {
+		record = append(record, bytesField(6, attribute)...)
+	}
+	scopeLogs := bytesField(2, record)
+	resourceLogs := bytesField(2, scopeLogs)
+	return bytesField(1, resourceLogs)
+}
+
+func nestedArrays(depth int) []byte {
+	value := stringValue("leaf")
+	for i := 0; i < depth; i++ {
+		value = arrayValue(value)
+	}
+	return value
+}
+
+func TestDecodeLogsProto(t *testing.T) {
+	doubleValue := protowire.AppendTag(nil, 4, protowire.Fixed64Type)
+	doubleValue = protowire.AppendFixed64(doubleValue, math.Float64bits(1.5))
+	attribute := append(bytesField(1, []byte("order")), bytesField(2, kvlistValue("id", doubleValue))...)
+
+	request, err := DecodeLogsProto(requestWithBody(arrayValue(stringValue("hello")), attribute))
+	if err != nil {
+		t.Fatalf("DecodeLogsProto() error = %v", err)
+	}
+	if len(request.ResourceLogs) != 1 || len(request.ResourceLogs[0].ScopeLogs) != 1 {
+		t.Fatalf("unexpected structure: %+v", request)
+	}
+	records := request.ResourceLogs[0].ScopeLogs[0].LogRecords
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+
+	record := records[0]
+	if record.TimeUnixNano != 1700000000000000000 || record.SeverityNumber != 17 || record.SeverityText != "ERROR" {
+		t.Errorf("unexpected record fields: %+v", record)
+	}
+	if got, want := record.Body.Interface(), []interface{}{"hello"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("body = %#v, want %#v", got, want)
+	}
+	want := map[string]interface{}{"order": map[string]interface{}{"id": 1.5}}
+	if got := AttributesMap(record.Attributes); !reflect.DeepEqual(got, want) {
+		t.Errorf("attributes = %#v, want %#v", got, want)
+	}
+}
+
+func TestDecodeLogsProtoMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "truncated tag", data: []byte{0x80}},
+		{name: "length past end", data: []byte{0x0a, 0x10, 0x01}},
+		{name: "truncated varint", data: []byte{0x08, 0xff}},
+		{name: "truncated fixed64", data: []byte{0x09, 0x01, 0x02}},
+		{name: "invalid wire type", data: []byte{0x0f}},
+		{name: "malformed nested record", data: bytesField(1, bytesField(2, bytesField(2, []byte{0x2a, 0x05})))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeLogsProto(tt.data); err == nil {
+				t.Fatal("DecodeLogsProto() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestDecodeLogsProtoSkipsUnknownFields(t *testing.T) {
+	data := protowire.AppendTag(nil, 99, protowire.VarintType)
+	data = protowire.AppendVarint(data, 1)
+	data = append(data, requestWithBody(stringValue("ok"))...)
+
+	request, err := DecodeLogsProto(data)
+	if err != nil {
+		t.Fatalf("DecodeLogsProto() error = %v", err)
+	}
+	if got := request.ResourceLogs[0].ScopeLogs[0].LogRecords[0].Body.Interface(); got != "ok" {
+		t.Errorf("body = %#v, want %q", got, "ok")
+	}
+}
+
+func TestDecodeLogsProtoDepthLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{name: "body at limit", data: requestWithBody(nestedArrays(maxDepth))},
+		{name: "body past limit", data: requestWithBody(nestedArrays(maxDepth + 1)), wantErr: true},
+		{name: "deeply nested body", data: requestWithBody(nestedArrays(5000)), wantErr: true},
+		{
+			name:    "attribute past limit",
+			data:    requestWithBody(nil, append(bytesField(1, []byte("a")), bytesField(2, nestedArrays(maxDepth+1))...)),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeLogsProto(tt.data)
+			if tt.wantErr {
+				if !errors.Is(err, errTooDeep) {
+					t.Fatalf("DecodeLogsProto() error = %v, want %v", err, errTooDeep)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeLogsProto() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeLogsJSONDepthLimit(t *testing.T) {
+	nested := func(depth int) string {
+		return strings.Repeat(`{"arrayValue":{"values":[`, depth) + `{"stringValue":"leaf"}` + strings.Repeat(`]}}`, depth)
+	}
+	request := func(body string) []byte {
+		return []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":` + body + `}]}]}]}`)
+	}
+
+	if _, err := DecodeLogsJSON(request(nested(maxDepth))); err != nil {
+		t.Fatalf("DecodeLogsJSON() at limit error = %v", err)
+	}
+	if _, err := DecodeLogsJSON(request(nested(maxDepth + 1))); !errors.Is(err, errTooDeep) {
+		t.Fatalf("DecodeLogsJSON() past limit error = %v, want %v", err, errTooDeep)
+	}
+}
+
+func FuzzDecodeLogsProto(f *testing.F) {
+	f.Add(requestWithBody(stringValue("hello")))
+	f.Add(requestWithBody(nestedArrays(maxDepth + 1)))
+	f.Add(requestWithBody(kvlistValue("k", stringValue("v"))))
+	f.Add([]byte{0x0a, 0x10, 0x01})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = DecodeLogsProto(data)
+	})
+}
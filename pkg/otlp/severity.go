@@ -0,0 +1,32 @@
+package otlp
+
+import "strings"
+
+// Level сопоставляет severity_number OpenTelemetry уровню log_status.
+// Если номер не задан, используется severity_text, иначе Info.
+func Level(severityNumber int32, severityText string) string {
+	switch {
+	case severityNumber >= 1 && severityNumber <= 8:
+		return "Debug"
+	case severityNumber >= 9 && severityNumber <= 12:
+		return "Info"
+	case severityNumber >= 13 && severityNumber <= 16:
+		return "Warning"
+	case severityNumber >= 17 && severityNumber <= 20:
+		return "Error"
+	case severityNumber >= 21:
+		return "Critical"
+	}
+
+	switch strings.ToUpper(strings.TrimSpace(severityText)) {
+	case "TRACE", "DEBUG":
+		return "Debug"
+	case "WARN", "WARNING":
+		return "Warning"
+	case "ERROR":
+		return "Error"
+	case "FATAL", "CRITICAL":
+		return "Critical"
+	}
+	return "Info"
+}
@@ -0,0 +1,70 @@
+package otlp
+
+// Типы повторяют схему opentelemetry/proto/logs/v1 в объёме, нужном приёмнику логов.
+
+type ExportLogsRequest struct {
+	ResourceLogs []ResourceLogs
+}
+
+type ResourceLogs struct {
+	Resource  Resource
+	ScopeLogs []ScopeLogs
+}
+
+type Resource struct {
+	Attributes []KeyValue
+}
+
+type ScopeLogs struct {
+	Scope      Scope
+	LogRecords []LogRecord
+}
+
+type Scope struct {
+	Name       string
+	Version    string
+	Attributes []KeyValue
+}
+
+type LogRecord struct {
+	TimeUnixNano         uint64
+	ObservedTimeUnixNano uint64
+	SeverityNumber       int32
+	SeverityText         string
+	Body                 *AnyValue
+	Attributes           []KeyValue
+	TraceID              []byte
+	SpanID               []byte
+	EventName            string
+}
+
+type KeyValue struct {
+	Key   string
+	Value *AnyValue
+}
+
+// AnyValue хранит значение атрибута как Go значение:
+// string, bool, int64, float64, []byte, []interface{} или map[string]interface{}
+type AnyValue struct {
+	Value interface{}
+}
+
+// Interface возвращает значение, пригодное для сериализации в JSON
+func (v *AnyValue) Interface() interface{} {
+	if v == nil {
+		return nil
+	}
+	return v.Value
+}
+
+// AttributesMap превращает список атрибутов в map
+func AttributesMap(attributes []KeyValue) map[string]interface{} {
+	if len(attributes) == 0 {
+		return nil
+	}
+	result := make(map[string]interface{}, len(attributes))
+	for _, kv := range attributes {
+		result[kv.Key] = kv.Value.Interface()
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const DefaultFetchSize = 1000
+
+// StreamCursor выполняет query через серверный курсор и передаёт строки в scan порциями по fetchSize.
+// Результат не загружается в память целиком, поэтому подходит для выгрузок любого размера.
+func StreamCursor(db *sql.DB, fetchSize int, scan func(rows *sql.Rows) error, query string, args ...interface{}) error {
+	if fetchSize <= 0 {
+		fetchSize = DefaultFetchSize
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SET TRANSACTION READ ONLY`); err != nil {
+		return fmt.Errorf("failed to set read only: %w", err)
+	}
+
+	if _, err := tx.Exec(`DECLARE export_cursor NO SCROLL CURSOR FOR `+query, args...); err != nil {
+		return fmt.Errorf("failed to declare cursor: %w", err)
+	}
+
+	fetch := fmt.Sprintf(`FETCH FORWARD %d FROM export_cursor`, fetchSize)
+	for {
+		rows, err := tx.Query(fetch)
+		if err != nil {
+			return fmt.Errorf("failed to fetch from cursor: %w", err)
+		}
+
+		count := 0
+		for rows.Next() {
+			count++
+			if err := scan(rows); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return fmt.Errorf("error after iterating rows: %w", err)
+		}
+		rows.Close()
+
+		if count < fetchSize {
+			break
+		}
+	}
+
+	if _, err := tx.Exec(`CLOSE export_cursor`); err != nil {
+		return fmt.Errorf("failed to close cursor: %w", err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,10 @@
+package postgres
+
+import "strings"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ContainsPattern возвращает шаблон LIKE/ILIKE для поиска подстроки s: спецсимволы % и _ экранируются
+func ContainsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
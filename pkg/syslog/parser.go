@@ -0,0 +1,218 @@
+package syslog
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidMessage = errors.New("invalid syslog message")
+
+// Message — разобранное syslog-сообщение (RFC 5424 или RFC 3164).
+// Отсутствующие поля ("-" в RFC 5424) остаются пустыми.
+type Message struct {
+	Facility       int
+	Severity       int
+	Timestamp      *time.Time
+	Hostname       string
+	AppName        string
+	ProcID         string
+	MsgID          string
+	StructuredData map[string]map[string]string
+	Message        string
+	RFC5424        bool
+}
+
+// Level сопоставляет severity syslog уровню log_status
+func Level(severity int) string {
+	switch severity {
+	case 0, 1, 2:
+		return "Critical"
+	case 3:
+		return "Error"
+	case 4:
+		return "Warning"
+	case 7:
+		return "Debug"
+	}
+	return "Info"
+}
+
+// Parse разбирает сообщение; формат определяется по версии после PRI.
+// now используется для RFC 3164, где в метке времени нет года и зоны.
+func Parse(data []byte, now time.Time) (*Message, error) {
+	raw := strings.TrimRight(string(data), "\r\n\x00")
+
+	pri, rest, err := parsePriority(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &Message{Facility: pri / 8, Severity: pri % 8}
+	if strings.HasPrefix(rest, "1 ") {
+		msg.RFC5424 = true
+		if err := parse5424(msg, rest[2:]); err != nil {
+			return nil, err
+		}
+		return msg, nil
+	}
+
+	parse3164(msg, rest, now)
+	return msg, nil
+}
+
+func parsePriority(raw string) (int, string, error) {
+	if !strings.HasPrefix(raw, "<") {
+		return 0, "", ErrInvalidMessage
+	}
+	end := strings.IndexByte(raw, '>')
+	if end < 2 || end > 4 {
+		return 0, "", ErrInvalidMessage
+	}
+	pri, err := strconv.Atoi(raw[1:end])
+	if err != nil || pri < 0 || pri > 191 {
+		return 0, "", ErrInvalidMessage
+	}
+	return pri, raw[end+1:], nil
+}
+
+// parse5424: TIMESTAMP SP HOSTNAME SP APP-NAME SP PROCID SP MSGID SP STRUCTURED-DATA [SP MSG]
+func parse5424(msg *Message, rest string) error {
+	var fields [5]string
+	for i := range fields {
+		field, tail, ok := strings.Cut(rest, " ")
+		if !ok {
+			return ErrInvalidMessage
+		}
+		fields[i] = nilValue(field)
+		rest = tail
+	}
+
+	if fields[0] != "" {
+		ts, err := time.Parse(time.RFC3339Nano, fields[0])
+		if err != nil {
+			return ErrInvalidMessage
+		}
+		msg.Timestamp = &ts
+	}
+	msg.Hostname = fields[1]
+	msg.AppName = fields[2]
+	msg.ProcID = fields[3]
+	msg.MsgID = fields[4]
+
+	sd, rest, err := parseStructuredData(rest)
+	if err != nil {
+		return err
+	}
+	msg.StructuredData = sd
+
+	rest = strings.TrimPrefix(rest, " ")
+	msg.Message = strings.TrimPrefix(rest, "\uFEFF")
+	return nil
+}
+
+func nilValue(field string) string {
+	if field == "-" {
+		return ""
+	}
+	return field
+}
+
+// parseStructuredData разбирает "-" или последовательность [SD-ID PARAM="VALUE" ...]
+func parseStructuredData(rest string) (map[string]map[string]string, string, error) {
+	if strings.HasPrefix(rest, "-") {
+		return nil, rest[1:], nil
+	}
+
+	result := map[string]map[string]string{}
+	for strings.HasPrefix(rest, "[") {
+		rest = rest[1:]
+		end := strings.IndexAny(rest, " ]")
+		if end <= 0 {
+			return nil, "", ErrInvalidMessage
+		}
+		params := map[string]string{}
+		result[rest[:end]] = params
+		rest = rest[end:]
+
+		for {
+			rest = strings.TrimLeft(rest, " ")
+			if strings.HasPrefix(rest, "]") {
+				rest = rest[1:]
+				break
+			}
+
+			eq := strings.Index(rest, `="`)
+			if eq <= 0 {
+				return nil, "", ErrInvalidMessage
+			}
+			name := rest[:eq]
+			rest = rest[eq+2:]
+
+			var value strings.Builder
+			closed := false
+			for i := 0; i < len(rest); i++ {
+				ch := rest[i]
+				if ch == '\\' && i+1 < len(rest) && strings.IndexByte(`"\]`, rest[i+1]) >= 0 {
+					value.WriteByte(rest[i+1])
+					i++
+					continue
+				}
+				if ch == '"' {
+					rest = rest[i+1:]
+					closed = true
+					break
+				}
+				value.WriteByte(ch)
+			}
+			if !closed {
+				return nil, "", ErrInvalidMessage
+			}
+			params[name] = value.String()
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, "", ErrInvalidMessage
+	}
+	return result, rest, nil
+}
+
+// parse3164 разбирает "Mmm dd hh:mm:ss HOSTNAME TAG[PID]: MSG".
+// Формат нестрогий: то, что не удалось распознать, остаётся в тексте сообщения.
+func parse3164(msg *Message, rest string, now time.Time) {
+	if len(rest) >= len(time.Stamp) {
+		if ts, err := time.ParseInLocation(time.Stamp, rest[:len(time.Stamp)], now.Location()); err == nil {
+			ts = ts.AddDate(now.Year(), 0, 0)
+			// Сообщение за декабрь, полученное в январе, относится к прошлому году
+			if ts.After(now.Add(24 * time.Hour)) {
+				ts = ts.AddDate(-1, 0, 0)
+			}
+			msg.Timestamp = &ts
+			rest = strings.TrimPrefix(rest[len(time.Stamp):], " ")
+
+			if host, tail, ok := strings.Cut(rest, " "); ok && host != "" && !strings.ContainsAny(host, ":[") {
+				msg.Hostname = host
+				rest = tail
+			}
+		}
+	}
+
+	if end := strings.IndexAny(rest, ":[ "); end > 0 && end <= 48 && (rest[end] == ':' || rest[end] == '[') {
+		tag := rest[:end]
+		tail := rest[end:]
+		if tail[0] == '[' {
+			if closeIdx := strings.IndexByte(tail, ']'); closeIdx > 0 {
+				msg.ProcID = tail[1:closeIdx]
+				tail = tail[closeIdx+1:]
+			}
+		}
+		if strings.HasPrefix(tail, ":") {
+			msg.AppName = tag
+			rest = strings.TrimPrefix(tail[1:], " ")
+		}
+	}
+
+	msg.Message = rest
+}
@@ -0,0 +1,164 @@
+package syslog
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2025, time.January, 5, 10, 0, 0, 0, time.UTC)
+	at := func(value string) *time.Time {
+		ts, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &ts
+	}
+
+	tests := []struct {
+		name string
+		data string
+		want *Message
+	}{
+		{
+			name: "rfc5424",
+			data: "<34>1 2003-10-11T22:14:15.003Z mymachine.example.com su - ID47 - \uFEFF'su root' failed on /dev/pts/8",
+			want: &Message{
+				Facility:  4,
+				Severity:  2,
+				Timestamp: at("2003-10-11T22:14:15.003Z"),
+				Hostname:  "mymachine.example.com",
+				AppName:   "su",
+				MsgID:     "ID47",
+				Message:   "'su root' failed on /dev/pts/8",
+				RFC5424:   true,
+			},
+		},
+		{
+			name: "rfc5424 structured data with escapes",
+			data: `<165>1 2003-10-11T22:14:15+03:00 host app 123 - [exampleSDID@32473 iut="3" eventSource="Application"][meta q="a\"b\]c\\"] started`,
+			want: &Message{
+				Facility:  20,
+				Severity:  5,
+				Timestamp: at("2003-10-11T22:14:15+03:00"),
+				Hostname:  "host",
+				AppName:   "app",
+				ProcID:    "123",
+				StructuredData: map[string]map[string]string{
+					"exampleSDID@32473": {"iut": "3", "eventSource": "Application"},
+					"meta":              {"q": `a"b]c\`},
+				},
+				Message: "started",
+				RFC5424: true,
+			},
+		},
+		{
+			name: "rfc5424 nil values without message",
+			data: "<13>1 - - - - - -",
+			want: &Message{Facility: 1, Severity: 5, RFC5424: true},
+		},
+		{
+			name: "trailing newline and nul",
+			data: "<13>1 - host app - - - done\r\n\x00",
+			want: &Message{Facility: 1, Severity: 5, Hostname: "host", AppName: "app", Message: "done", RFC5424: true},
+		},
+		{
+			name: "rfc3164 with host and pid",
+			data: "<11>Jan  5 09:00:00 web01 nginx[1234]: upstream timed out",
+			want: &Message{
+				Facility:  1,
+				Severity:  3,
+				Timestamp: at("2025-01-05T09:00:00Z"),
+				Hostname:  "web01",
+				AppName:   "nginx",
+				ProcID:    "1234",
+				Message:   "upstream timed out",
+			},
+		},
+		{
+			name: "rfc3164 december received in january",
+			data: "<13>Dec 31 23:59:59 web01 cron: job done",
+			want: &Message{
+				Facility:  1,
+				Severity:  5,
+				Timestamp: at("2024-12-31T23:59:59Z"),
+				Hostname:  "web01",
+				AppName:   "cron",
+				Message:   "job done",
+			},
+		},
+		{
+			name: "rfc3164 without header",
+			data: "<14>just some text",
+			want: &Message{Facility: 1, Severity: 6, Message: "just some text"},
+		},
+		{
+			name: "rfc3164 tag without host",
+			data: "<14>app: hello",
+			want: &Message{Facility: 1, Severity: 6, AppName: "app", Message: "hello"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.data), now)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "empty", data: ""},
+		{name: "no priority", data: "1 - host app - - - msg"},
+		{name: "unterminated priority", data: "<13 msg"},
+		{name: "empty priority", data: "<>msg"},
+		{name: "priority too long", data: "<0013>msg"},
+		{name: "priority out of range", data: "<192>msg"},
+		{name: "non-numeric priority", data: "<1a>msg"},
+		{name: "rfc5424 missing fields", data: "<13>1 2003-10-11T22:14:15Z host app"},
+		{name: "rfc5424 invalid timestamp", data: "<13>1 yesterday host app - - - msg"},
+		{name: "rfc5424 missing structured data", data: "<13>1 - host app - - msg"},
+		{name: "structured data without id", data: `<13>1 - host app - - [ a="b"] msg`},
+		{name: "structured data param without value", data: "<13>1 - host app - - [id a] msg"},
+		{name: "unterminated structured data value", data: `<13>1 - host app - - [id a="b msg`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.data), time.Now()); !errors.Is(err, ErrInvalidMessage) {
+				t.Fatalf("Parse() error = %v, want %v", err, ErrInvalidMessage)
+			}
+		})
+	}
+}
+
+func TestLevel(t *testing.T) {
+	want := []string{"Critical", "Critical", "Critical", "Error", "Warning", "Info", "Info", "Debug"}
+	for severity, level := range want {
+		if got := Level(severity); got != level {
+			t.Errorf("Level(%d) = %q, want %q", severity, got, level)
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add([]byte("<34>1 2003-10-11T22:14:15.003Z host su - ID47 [id a=\"b\"] msg"))
+	f.Add([]byte("<13>Dec 31 23:59:59 web01 cron[1]: job done"))
+	f.Add([]byte("<13>1 - - - - - [id a=\"\\"))
+
+	now := time.Date(2025, time.January, 5, 10, 0, 0, 0, time.UTC)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = Parse(data, now)
+	})
+}
@@ -0,0 +1,182 @@
+package wal
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	segmentExt = ".wal"
+	// maxSegmentBytes — размер сегмента, после которого запись продолжается в новый файл
+	maxSegmentBytes = 64 << 20
+	// maxRecordBytes — ограничение на длину записи при чтении сегмента
+	maxRecordBytes = 16 << 20
+)
+
+// WAL — журнал на локальном диске из сегментов с записями по одной на строку.
+// Записи не должны содержать перевод строки (например, JSON).
+// Append дописывает в активный сегмент, Replay отдаёт записи закрытых сегментов и удаляет их.
+type WAL struct {
+	dir string
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// Open открывает журнал в каталоге dir, создавая каталог при необходимости.
+// Сегменты, оставшиеся от прошлого запуска, будут отданы первым вызовом Replay.
+func Open(dir string) (*WAL, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create wal dir: %w", err)
+	}
+	return &WAL{dir: dir}, nil
+}
+
+// Append дописывает записи и сбрасывает их на диск
+func (w *WAL) Append(records [][]byte) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, record := range records {
+		buf.Write(record)
+		buf.WriteByte('\n')
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil || w.size >= maxSegmentBytes {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+		name := filepath.Join(w.dir, fmt.Sprintf("%020d%s", time.Now().UnixNano(), segmentExt))
+		file, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to create wal segment: %w", err)
+		}
+		w.file = file
+		w.size = 0
+	}
+
+	n, err := w.file.Write(buf.Bytes())
+	w.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write wal segment: %w", err)
+	}
+	if err := w.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync wal segment: %w", err)
+	}
+
+	return nil
+}
+
+// Replay закрывает активный сегмент и по порядку передаёт в fn записи всех закрытых сегментов
+// пачками не больше batchSize. Сегмент удаляется после успешной обработки всех его записей;
+// при ошибке fn сегмент остаётся и будет отдан повторно (часть записей — второй раз).
+func (w *WAL) Replay(batchSize int, fn func(records [][]byte) error) error {
+	w.mu.Lock()
+	err := w.rotate()
+	w.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	segments, err := w.segments()
+	if err != nil {
+		return err
+	}
+
+	for _, segment := range segments {
+		if err := replaySegment(segment, batchSize, fn); err != nil {
+			return err
+		}
+		if err := os.Remove(segment); err != nil {
+			return fmt.Errorf("failed to remove wal segment: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Close закрывает активный сегмент
+func (w *WAL) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rotate()
+}
+
+// rotate закрывает активный сегмент; вызывается под mu
+func (w *WAL) rotate() error {
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	w.size = 0
+	if err != nil {
+		return fmt.Errorf("failed to close wal segment: %w", err)
+	}
+	return nil
+}
+
+func (w *WAL) segments() ([]string, error) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wal dir: %w", err)
+	}
+
+	var segments []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), segmentExt) {
+			continue
+		}
+		segments = append(segments, filepath.Join(w.dir, entry.Name()))
+	}
+	sort.Strings(segments)
+
+	return segments, nil
+}
+
+func replaySegment(name string, batchSize int, fn func(records [][]byte) error) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("failed to open wal segment: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64<<10), maxRecordBytes)
+
+	batch := make([][]byte, 0, batchSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		batch = append(batch, bytes.Clone(line))
+		if len(batch) >= batchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([][]byte, 0, batchSize)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read wal segment: %w", err)
+	}
+
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
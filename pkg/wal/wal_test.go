@@ -0,0 +1,211 @@
+package wal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func records(values ...string) [][]byte {
+	result := make([][]byte, len(values))
+	for i, value := range values {
+		result[i] = []byte(value)
+	}
+	return result
+}
+
+// collect воспроизводит журнал и возвращает полученные пачки
+func collect(t *testing.T, w *WAL, batchSize int) [][]string {
+	t.Helper()
+
+	var batches [][]string
+	err := w.Replay(batchSize, func(records [][]byte) error {
+		var batch []string
+		for _, record := range records {
+			batch = append(batch, string(record))
+		}
+		batches = append(batches, batch)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	return batches
+}
+
+func segmentCount(t *testing.T, dir string) int {
+	t.Helper()
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(matches)
+}
+
+func TestReplayBatches(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if err := w.Append(records(`{"n":1}`, `{"n":2}`, `{"n":3}`)); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := w.Append(records(`{"n":4}`, `{"n":5}`)); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	want := [][]string{{`{"n":1}`, `{"n":2}`}, {`{"n":3}`, `{"n":4}`}, {`{"n":5}`}}
+	if got := collect(t, w, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("batches = %q, want %q", got, want)
+	}
+	if n := segmentCount(t, dir); n != 0 {
+		t.Errorf("segments after replay = %d, want 0", n)
+	}
+	if got := collect(t, w, 2); got != nil {
+		t.Errorf("second replay = %q, want nothing", got)
+	}
+
+	// После Replay запись продолжается в новый сегмент
+	if err := w.Append(records("after")); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if got, want := collect(t, w, 10), [][]string{{"after"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("batches = %q, want %q", got, want)
+	}
+}
+
+func TestReplayAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	w, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	for _, value := range []string{"first", "second", "third"} {
+		if err := w.Append(records(value)); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		// Закрытие сегмента: следующая запись идёт в новый файл
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+	if n := segmentCount(t, dir); n != 3 {
+		t.Fatalf("segments = %d, want 3", n)
+	}
+
+	restarted, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	want := [][]string{{"first"}, {"second"}, {"third"}}
+	if got := collect(t, restarted, 10); !reflect.DeepEqual(got, want) {
+		t.Errorf("batches = %q, want %q", got, want)
+	}
+}
+
+func TestReplayErrorKeepsSegment(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := w.Append(records("a", "b", "c")); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	errFailed := errors.New("database is down")
+	calls := 0
+	err = w.Replay(2, func(records [][]byte) error {
+		calls++
+		if calls == 2 {
+			return errFailed
+		}
+		return nil
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("Replay() error = %v, want %v", err, errFailed)
+	}
+	if n := segmentCount(t, dir); n != 1 {
+		t.Fatalf("segments after failed replay = %d, want 1", n)
+	}
+
+	// Сегмент отдаётся повторно целиком, в том числе уже обработанные записи
+	want := [][]string{{"a", "b"}, {"c"}}
+	if got := collect(t, w, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("batches = %q, want %q", got, want)
+	}
+}
+
+func TestReplaySegmentContents(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    [][]string
+	}{
+		{name: "empty segment", content: "", want: nil},
+		{name: "empty lines skipped", content: "a\n\n\nb\n", want: [][]string{{"a", "b"}}},
+		{name: "torn last record", content: "a\n{\"msg\":\"cut", want: [][]string{{"a", `{"msg":"cut`}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "00000000000000000001"+segmentExt), []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			w, err := Open(dir)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			if got := collect(t, w, 10); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("batches = %q, want %q", got, tt.want)
+			}
+			if n := segmentCount(t, dir); n != 0 {
+				t.Errorf("segments after replay = %d, want 0", n)
+			}
+		})
+	}
+}
+
+func TestReplayIgnoresOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	other := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(other, []byte("not a segment\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "nested"+segmentExt), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if got := collect(t, w, 10); got != nil {
+		t.Errorf("batches = %q, want nothing", got)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("other file removed: %v", err)
+	}
+}
+
+func TestOpenCreatesDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "spill", "logs")
+	w, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := w.Append(records("a")); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if n := segmentCount(t, dir); n != 1 {
+		t.Errorf("segments = %d, want 1", n)
+	}
+}